@@ -0,0 +1,42 @@
+package main
+
+import "time"
+
+// AcademicRules - configured rules of the academic year shared by storage backends.
+// Zero values fall back to defaults: academic location, interval semester switch policy and zero totals last.
+type AcademicRules struct {
+	location         *time.Location
+	zeroTotalsMode   ZeroTotalsMode
+	passingTotal     float32
+	admissionTotal   float32
+	absenceRiskRatio float32
+	semesterSwitch   SemesterSwitchPolicyInterface
+}
+
+func newAcademicRules(config Config, semesterSwitch SemesterSwitchPolicyInterface) AcademicRules {
+	return AcademicRules{
+		location:         config.timezone,
+		zeroTotalsMode:   config.zeroTotalsMode,
+		passingTotal:     config.passingTotal,
+		admissionTotal:   config.admissionTotal,
+		absenceRiskRatio: config.absenceRatio,
+		semesterSwitch:   semesterSwitch,
+	}
+}
+
+// academicLocation - timezone of the university schedule, lesson dates are midnights of it
+func (rules AcademicRules) academicLocation() *time.Location {
+	if rules.location == nil {
+		return defaultAcademicLocation
+	}
+
+	return rules.location
+}
+
+func (rules AcademicRules) semesterSwitchPolicy() SemesterSwitchPolicyInterface {
+	if rules.semesterSwitch == nil {
+		return IntervalSemesterSwitchPolicy{maxUpdatedInterval: MaxSemesterUpdatedInterval}
+	}
+
+	return rules.semesterSwitch
+}
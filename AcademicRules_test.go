@@ -0,0 +1,50 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func getTestAcademicRules() AcademicRules {
+	return AcademicRules{
+		location:         defaultAcademicLocation,
+		zeroTotalsMode:   ZeroTotalsLast,
+		passingTotal:     DefaultPassingTotal,
+		admissionTotal:   DefaultAdmissionTotal,
+		absenceRiskRatio: DefaultAbsenceRiskRatio,
+	}
+}
+
+func TestNewAcademicRules(t *testing.T) {
+	location, _ := time.LoadLocation("Asia/Tokyo")
+	policy := ThresholdSemesterSwitchPolicy{threshold: 3}
+
+	rules := newAcademicRules(Config{
+		timezone:       location,
+		zeroTotalsMode: ZeroTotalsShared,
+		passingTotal:   51,
+		admissionTotal: 30,
+		absenceRatio:   0.5,
+	}, policy)
+
+	assert.Equal(t, AcademicRules{
+		location:         location,
+		zeroTotalsMode:   ZeroTotalsShared,
+		passingTotal:     51,
+		admissionTotal:   30,
+		absenceRiskRatio: 0.5,
+		semesterSwitch:   policy,
+	}, rules)
+	assert.Equal(t, location, rules.academicLocation())
+	assert.Equal(t, policy, rules.semesterSwitchPolicy())
+}
+
+func TestAcademicRulesDefaults(t *testing.T) {
+	rules := AcademicRules{}
+
+	assert.Equal(t, defaultAcademicLocation, rules.academicLocation())
+	assert.Equal(
+		t, IntervalSemesterSwitchPolicy{maxUpdatedInterval: MaxSemesterUpdatedInterval}, rules.semesterSwitchPolicy(),
+	)
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"errors"
+	"sort"
+	"sync"
+)
+
+const anomalySamplesLimit = 10
+
+type AnomalySample struct {
+	Key   string `json:"key"`
+	Field string `json:"field,omitempty"`
+	Value string `json:"value"`
+	Error string `json:"error"`
+}
+
+type AnomalyKindReport struct {
+	Kind    string          `json:"kind"`
+	Count   int             `json:"count"`
+	Samples []AnomalySample `json:"samples"`
+}
+
+type AnomalyReport struct {
+	Total int                 `json:"total"`
+	Kinds []AnomalyKindReport `json:"kinds"`
+}
+
+// AnomalyCounter counts malformed Redis entries skipped while reading, grouped by kind of encoding,
+// and keeps the latest samples of each kind to find the broken keys.
+type AnomalyCounter struct {
+	mutex   sync.Mutex
+	counts  map[string]int
+	samples map[string][]AnomalySample
+}
+
+func (counter *AnomalyCounter) report(key string, field string, err error) {
+	if counter == nil {
+		return
+	}
+
+	kind := err.Error()
+	value := ""
+
+	var codecError *CodecError
+	if errors.As(err, &codecError) {
+		kind = codecError.Kind.Error()
+		value = codecError.Value
+	}
+
+	counter.mutex.Lock()
+	defer counter.mutex.Unlock()
+
+	if counter.counts == nil {
+		counter.counts = make(map[string]int)
+		counter.samples = make(map[string][]AnomalySample)
+	}
+
+	counter.counts[kind]++
+
+	samples := append(counter.samples[kind], AnomalySample{
+		Key:   key,
+		Field: field,
+		Value: value,
+		Error: err.Error(),
+	})
+	if len(samples) > anomalySamplesLimit {
+		samples = samples[len(samples)-anomalySamplesLimit:]
+	}
+	counter.samples[kind] = samples
+}
+
+func (counter *AnomalyCounter) getReport() AnomalyReport {
+	report := AnomalyReport{
+		Kinds: make([]AnomalyKindReport, 0),
+	}
+
+	if counter == nil {
+		return report
+	}
+
+	counter.mutex.Lock()
+	defer counter.mutex.Unlock()
+
+	for kind, count := range counter.counts {
+		report.Total += count
+		report.Kinds = append(report.Kinds, AnomalyKindReport{
+			Kind:    kind,
+			Count:   count,
+			Samples: append([]AnomalySample{}, counter.samples[kind]...),
+		})
+	}
+
+	sort.Slice(report.Kinds, func(i, j int) bool {
+		return report.Kinds[i].Kind < report.Kinds[j].Kind
+	})
+
+	return report
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"testing"
+)
+
+func TestAnomalyCounter(t *testing.T) {
+	t.Run("report", func(t *testing.T) {
+		counter := &AnomalyCounter{}
+		codec := RedisCodec{}
+
+		for index := 0; index < anomalySamplesLimit+2; index++ {
+			_, err := codec.decodeScoreEntry("245:1", "abc"+strconv.Itoa(index))
+			counter.report("2026:1:scores:1200:199", "245:1", err)
+		}
+
+		_, err := codec.decodeId("abc")
+		counter.report("2026:1:lessons:199", "abc", err)
+		counter.report("2026:1:lessons:199", "", errors.New("other error"))
+
+		report := counter.getReport()
+
+		assert.Equal(t, anomalySamplesLimit+4, report.Total)
+		assert.Len(t, report.Kinds, 3)
+
+		assert.Equal(t, ErrMalformedId.Error(), report.Kinds[0].Kind)
+		assert.Equal(t, []AnomalySample{
+			{
+				Key:   "2026:1:lessons:199",
+				Field: "abc",
+				Value: "abc",
+				Error: `malformed id "abc": positive integer expected`,
+			},
+		}, report.Kinds[0].Samples)
+
+		assert.Equal(t, ErrMalformedScoreValue.Error(), report.Kinds[1].Kind)
+		assert.Equal(t, anomalySamplesLimit+2, report.Kinds[1].Count)
+		assert.Len(t, report.Kinds[1].Samples, anomalySamplesLimit)
+		assert.Equal(t, "abc2", report.Kinds[1].Samples[0].Value)
+		assert.Equal(t, "abc"+strconv.Itoa(anomalySamplesLimit+1), report.Kinds[1].Samples[anomalySamplesLimit-1].Value)
+
+		assert.Equal(t, "other error", report.Kinds[2].Kind)
+		assert.Equal(t, 1, report.Kinds[2].Count)
+	})
+
+	t.Run("nil_counter", func(t *testing.T) {
+		var counter *AnomalyCounter
+
+		counter.report("key", "", errors.New("error"))
+
+		assert.Equal(t, AnomalyReport{Kinds: []AnomalyKindReport{}}, counter.getReport())
+	})
+}
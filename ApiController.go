@@ -1,27 +1,105 @@
 package main
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"github.com/gin-gonic/gin"
 	scoreApi "github.com/kneu-messenger-pigeon/score-api"
 	"io"
 	"net/http"
 	"strconv"
+	"time"
 )
 
 type ApiController struct {
-	out     io.Writer
-	storage StorageInterface
+	out              io.Writer
+	storage          StorageInterface
+	groupRatings     GroupRatingLoaderInterface
+	summaries        StudentSummaryLoaderInterface
+	risks            DisciplineRiskLoaderInterface
+	changes          StudentChangesLoaderInterface
+	translator       *Translator
+	gradeScaler      *GradeScaler
+	eventHub         *StudentEventHub
+	location         *time.Location
+	dateFormat       LessonDateFormat
+	changesRetention time.Duration
 }
 
-func (controller *ApiController) getStudentDisciplines(c *gin.Context) {
+func (controller *ApiController) presenter() ResultPresenter {
+	return ResultPresenter{translator: controller.translator, dateFormat: controller.dateFormat}
+}
+
+func (controller *ApiController) getStudentProfile(c *gin.Context) {
+	studentId, _ := strconv.Atoi(c.Param("student_id"))
+	if studentId <= 0 {
+		c.JSON(http.StatusBadRequest, scoreApi.ErrorResponse{
+			Error: controller.translator.message(c, messageIncorrectStudentId, c.Param("student_id")),
+		})
+
+	} else {
+		studentProfile, err := controller.storage.getStudentProfile(studentId)
+
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, scoreApi.ErrorResponse{
+				Error: err.Error(),
+			})
+
+		} else if studentProfile.Id == 0 {
+			c.JSON(http.StatusNotFound, scoreApi.ErrorResponse{
+				Error: controller.translator.message(c, messageStudentNotExists, c.Param("student_id")),
+			})
+
+		} else {
+			c.JSON(http.StatusOK, studentProfile)
+		}
+	}
+}
+
+// requireKnownStudent precedes handlers of the student routes: incorrect student_id is answered with 400
+// and the student without any data is answered with 404 instead of empty results
+func (controller *ApiController) requireKnownStudent(c *gin.Context) {
 	studentId, _ := strconv.Atoi(c.Param("student_id"))
 	if studentId <= 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, scoreApi.ErrorResponse{
+			Error: controller.translator.message(c, messageIncorrectStudentId, c.Param("student_id")),
+		})
+
+	} else if exists, err := controller.storage.studentExists(studentId); err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, scoreApi.ErrorResponse{
+			Error: err.Error(),
+		})
+
+	} else if !exists {
+		c.AbortWithStatusJSON(http.StatusNotFound, scoreApi.ErrorResponse{
+			Error: controller.translator.message(c, messageStudentNotExists, c.Param("student_id")),
+		})
+	}
+}
+
+func (controller *ApiController) getStudentDisciplines(c *gin.Context) {
+	studentId, _ := strconv.Atoi(c.Param("student_id"))
+	scales, scalesErr := controller.gradeScaler.selectScales(c)
+	withRisk, withRiskErr := parseRiskFlag(c)
+
+	if scalesErr != nil {
 		c.JSON(http.StatusBadRequest, scoreApi.ErrorResponse{
-			Error: "Incorrect student_id: " + c.Param("student_id"),
+			Error: controller.translator.errorMessage(c, scalesErr),
+		})
+	} else if withRiskErr != nil {
+		c.JSON(http.StatusBadRequest, scoreApi.ErrorResponse{
+			Error: controller.translator.errorMessage(c, withRiskErr),
 		})
 
 	} else {
 		disciplineScoreResults, err := controller.storage.getDisciplineScoreResultsByStudentId(studentId)
+		if err == nil && withRisk {
+			var risks []DisciplineRiskResult
+			risks, err = controller.risks.getStudentDisciplinesRisks(studentId)
+			attachRisks(disciplineScoreResults, risks)
+		}
 
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, scoreApi.ErrorResponse{
@@ -29,6 +107,8 @@ func (controller *ApiController) getStudentDisciplines(c *gin.Context) {
 			})
 
 		} else {
+			controller.presenter().presentDisciplineScoreResults(controller.translator.language(c), disciplineScoreResults)
+			scales.gradeResults(disciplineScoreResults)
 			c.JSON(http.StatusOK, disciplineScoreResults)
 		}
 	}
@@ -37,14 +117,20 @@ func (controller *ApiController) getStudentDisciplines(c *gin.Context) {
 func (controller *ApiController) getStudentDiscipline(c *gin.Context) {
 	studentId, _ := strconv.Atoi(c.Param("student_id"))
 	disciplineId, _ := strconv.Atoi(c.Param("discipline_id"))
+	scoreFilter, scoreFilterErr := parseScoreFilter(c, controller.location)
+	scales, scalesErr := controller.gradeScaler.selectScales(c)
 
-	if studentId <= 0 {
+	if disciplineId <= 0 {
 		c.JSON(http.StatusBadRequest, scoreApi.ErrorResponse{
-			Error: "Incorrect student_id: " + c.Param("student_id"),
+			Error: controller.translator.message(c, messageIncorrectDisciplineId, c.Param("discipline_id")),
 		})
-	} else if disciplineId <= 0 {
+	} else if scoreFilterErr != nil {
+		c.JSON(http.StatusBadRequest, scoreApi.ErrorResponse{
+			Error: controller.translator.errorMessage(c, scoreFilterErr),
+		})
+	} else if scalesErr != nil {
 		c.JSON(http.StatusBadRequest, scoreApi.ErrorResponse{
-			Error: "Incorrect discipline_Id: " + c.Param("discipline_id"),
+			Error: controller.translator.errorMessage(c, scalesErr),
 		})
 
 	} else {
@@ -57,10 +143,13 @@ func (controller *ApiController) getStudentDiscipline(c *gin.Context) {
 
 		} else if disciplineScoreResult.Discipline.Id == 0 {
 			c.JSON(http.StatusNotFound, scoreApi.ErrorResponse{
-				Error: "Discipline not exists: " + c.Param("discipline_id"),
+				Error: controller.translator.message(c, messageDisciplineNotExists, c.Param("discipline_id")),
 			})
 
 		} else {
+			disciplineScoreResult.Scores, disciplineScoreResult.NextCursor = scoreFilter.apply(disciplineScoreResult.Scores)
+			controller.presenter().presentDisciplineScoreResult(controller.translator.language(c), &disciplineScoreResult)
+			disciplineScoreResult.Grades = scales.grades(disciplineScoreResult.ScoreRating.Total)
 			c.JSON(http.StatusOK, disciplineScoreResult)
 		}
 	}
@@ -71,17 +160,13 @@ func (controller *ApiController) getStudentDisciplineScore(c *gin.Context) {
 	disciplineId, _ := strconv.Atoi(c.Param("discipline_id"))
 	lessonId, _ := strconv.Atoi(c.Param("lesson_id"))
 
-	if studentId <= 0 {
-		c.JSON(http.StatusBadRequest, scoreApi.ErrorResponse{
-			Error: "Incorrect student_id: " + c.Param("student_id"),
-		})
-	} else if disciplineId <= 0 {
+	if disciplineId <= 0 {
 		c.JSON(http.StatusBadRequest, scoreApi.ErrorResponse{
-			Error: "Incorrect discipline_Id: " + c.Param("discipline_id"),
+			Error: controller.translator.message(c, messageIncorrectDisciplineId, c.Param("discipline_id")),
 		})
 	} else if lessonId <= 0 {
 		c.JSON(http.StatusBadRequest, scoreApi.ErrorResponse{
-			Error: "Incorrect lesson_id: " + c.Param("lesson_id"),
+			Error: controller.translator.message(c, messageIncorrectLessonId, c.Param("lesson_id")),
 		})
 	} else {
 		disciplineScore, err := controller.storage.getDisciplineScore(studentId, disciplineId, lessonId)
@@ -93,16 +178,365 @@ func (controller *ApiController) getStudentDisciplineScore(c *gin.Context) {
 
 		} else if disciplineScore.Discipline.Id == 0 {
 			c.JSON(http.StatusNotFound, scoreApi.ErrorResponse{
-				Error: "Discipline not exists: " + c.Param("discipline_id"),
+				Error: controller.translator.message(c, messageDisciplineNotExists, c.Param("discipline_id")),
 			})
 
 		} else if disciplineScore.Score.Lesson.Id == 0 {
 			c.JSON(http.StatusNotFound, scoreApi.ErrorResponse{
-				Error: "Lesson not exists: " + c.Param("lesson_id"),
+				Error: controller.translator.message(c, messageLessonNotExists, c.Param("lesson_id")),
 			})
 
 		} else {
+			controller.presenter().presentDisciplineScore(controller.translator.language(c), &disciplineScore)
 			c.JSON(http.StatusOK, disciplineScore)
 		}
 	}
 }
+
+func (controller *ApiController) getStudentSummary(c *gin.Context) {
+	studentId, _ := strconv.Atoi(c.Param("student_id"))
+	studentSummary, err := controller.summaries.getStudentSummary(studentId)
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, scoreApi.ErrorResponse{
+			Error: err.Error(),
+		})
+
+	} else {
+		c.JSON(http.StatusOK, studentSummary)
+	}
+}
+
+func (controller *ApiController) getStudentChanges(c *gin.Context) {
+	studentId, _ := strconv.Atoi(c.Param("student_id"))
+	since, sinceErr := parseChangesSince(c)
+
+	if sinceErr != nil {
+		c.JSON(http.StatusBadRequest, scoreApi.ErrorResponse{
+			Error: controller.translator.errorMessage(c, sinceErr),
+		})
+
+	} else if isChangesSinceExpired(since, time.Now(), controller.changesRetention) {
+		c.JSON(http.StatusGone, scoreApi.ErrorResponse{
+			Error: controller.translator.message(c, messageChangesExpired, c.Query("since")),
+		})
+
+	} else {
+		studentChanges, err := controller.changes.getStudentChanges(studentId, since)
+
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, scoreApi.ErrorResponse{
+				Error: err.Error(),
+			})
+
+		} else {
+			for index := range studentChanges.Disciplines {
+				controller.translator.localizeScores(c, studentChanges.Disciplines[index].Scores)
+				controller.dateFormat.formatScores(studentChanges.Disciplines[index].Scores)
+			}
+			c.JSON(http.StatusOK, studentChanges)
+		}
+	}
+}
+
+func (controller *ApiController) getStudentAttendance(c *gin.Context) {
+	studentId, _ := strconv.Atoi(c.Param("student_id"))
+	studentAttendance, err := controller.storage.getStudentAttendance(studentId)
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, scoreApi.ErrorResponse{
+			Error: err.Error(),
+		})
+
+	} else {
+		controller.translator.localizeAttendance(c, studentAttendance)
+		controller.dateFormat.formatStudentAttendance(&studentAttendance)
+		c.JSON(http.StatusOK, studentAttendance)
+	}
+}
+
+func (controller *ApiController) getStudentDisciplineTimeline(c *gin.Context) {
+	studentId, _ := strconv.Atoi(c.Param("student_id"))
+	disciplineId, _ := strconv.Atoi(c.Param("discipline_id"))
+
+	if disciplineId <= 0 {
+		c.JSON(http.StatusBadRequest, scoreApi.ErrorResponse{
+			Error: controller.translator.message(c, messageIncorrectDisciplineId, c.Param("discipline_id")),
+		})
+
+	} else {
+		disciplineTimeline, err := controller.storage.getDisciplineTimeline(studentId, disciplineId)
+
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, scoreApi.ErrorResponse{
+				Error: err.Error(),
+			})
+
+		} else if disciplineTimeline.Discipline.Id == 0 {
+			c.JSON(http.StatusNotFound, scoreApi.ErrorResponse{
+				Error: controller.translator.message(c, messageDisciplineNotExists, c.Param("discipline_id")),
+			})
+
+		} else {
+			controller.translator.localizeLessonTypes(c, disciplineTimeline.LessonTypes)
+			controller.dateFormat.formatTimelinePoints(disciplineTimeline.Points)
+			c.JSON(http.StatusOK, disciplineTimeline)
+		}
+	}
+}
+
+func (controller *ApiController) exportStudentTranscriptCsv(c *gin.Context) {
+	controller.exportStudentTranscript(c, "csv", "text/csv; charset=utf-8", writeTranscriptCsv)
+}
+
+func (controller *ApiController) exportStudentTranscriptXlsx(c *gin.Context) {
+	controller.exportStudentTranscript(
+		c, "xlsx", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", writeTranscriptXlsx,
+	)
+}
+
+func (controller *ApiController) exportStudentTranscript(
+	c *gin.Context, extension string, contentType string, write func(io.Writer, Transcript) error,
+) {
+	studentId, _ := strconv.Atoi(c.Param("student_id"))
+	transcript, err := controller.storage.getStudentTranscript(studentId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, scoreApi.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	buffer := &bytes.Buffer{}
+	err = write(buffer, transcript)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, scoreApi.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="`+transcript.filename(extension)+`"`)
+	c.Data(http.StatusOK, contentType, buffer.Bytes())
+}
+
+func (controller *ApiController) getStudentCalendar(c *gin.Context) {
+	studentId, _ := strconv.Atoi(c.Param("student_id"))
+	disciplinesLessons, err := controller.storage.getStudentLessons(studentId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, scoreApi.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	buffer := &bytes.Buffer{}
+	err = writeLessonsCalendar(buffer, studentId, disciplinesLessons, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, scoreApi.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	c.Header("Content-Disposition", `inline; filename="lessons-`+strconv.Itoa(studentId)+`.ics"`)
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", buffer.Bytes())
+}
+
+func (controller *ApiController) getStudentDisciplineDeletedLessons(c *gin.Context) {
+	studentId, _ := strconv.Atoi(c.Param("student_id"))
+	disciplineId, _ := strconv.Atoi(c.Param("discipline_id"))
+
+	if disciplineId <= 0 {
+		c.JSON(http.StatusBadRequest, scoreApi.ErrorResponse{
+			Error: controller.translator.message(c, messageIncorrectDisciplineId, c.Param("discipline_id")),
+		})
+
+	} else {
+		disciplineDeletedLessons, err := controller.storage.getDisciplineDeletedLessons(studentId, disciplineId)
+
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, scoreApi.ErrorResponse{
+				Error: err.Error(),
+			})
+
+		} else if disciplineDeletedLessons.Discipline.Id == 0 {
+			c.JSON(http.StatusNotFound, scoreApi.ErrorResponse{
+				Error: controller.translator.message(c, messageDisciplineNotExists, c.Param("discipline_id")),
+			})
+
+		} else {
+			controller.translator.localizeScores(c, disciplineDeletedLessons.Lessons)
+			controller.dateFormat.formatScores(disciplineDeletedLessons.Lessons)
+			c.JSON(http.StatusOK, disciplineDeletedLessons)
+		}
+	}
+}
+
+func (controller *ApiController) getGroupLeaderboard(c *gin.Context) {
+	groupId, _ := strconv.Atoi(c.Param("group_id"))
+	disciplineId, _ := strconv.Atoi(c.Param("discipline_id"))
+
+	if groupId <= 0 {
+		c.JSON(http.StatusBadRequest, scoreApi.ErrorResponse{
+			Error: controller.translator.message(c, messageIncorrectGroupId, c.Param("group_id")),
+		})
+	} else if disciplineId <= 0 {
+		c.JSON(http.StatusBadRequest, scoreApi.ErrorResponse{
+			Error: controller.translator.message(c, messageIncorrectDisciplineId, c.Param("discipline_id")),
+		})
+
+	} else {
+		groupLeaderboard, err := controller.groupRatings.getGroupLeaderboard(groupId, disciplineId)
+
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, scoreApi.ErrorResponse{
+				Error: err.Error(),
+			})
+
+		} else if groupLeaderboard.Discipline.Id == 0 {
+			c.JSON(http.StatusNotFound, scoreApi.ErrorResponse{
+				Error: controller.translator.message(c, messageDisciplineNotExists, c.Param("discipline_id")),
+			})
+
+		} else if groupLeaderboard.GroupId == 0 {
+			c.JSON(http.StatusNotFound, scoreApi.ErrorResponse{
+				Error: controller.translator.message(c, messageGroupNotExists, c.Param("group_id")),
+			})
+
+		} else {
+			c.JSON(http.StatusOK, groupLeaderboard)
+		}
+	}
+}
+
+func (controller *ApiController) getStudentDisciplineRisk(c *gin.Context) {
+	studentId, _ := strconv.Atoi(c.Param("student_id"))
+	disciplineId, _ := strconv.Atoi(c.Param("discipline_id"))
+
+	if disciplineId <= 0 {
+		c.JSON(http.StatusBadRequest, scoreApi.ErrorResponse{
+			Error: controller.translator.message(c, messageIncorrectDisciplineId, c.Param("discipline_id")),
+		})
+
+	} else {
+		disciplineRisk, err := controller.risks.getDisciplineRisk(studentId, disciplineId)
+
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, scoreApi.ErrorResponse{
+				Error: err.Error(),
+			})
+
+		} else if disciplineRisk.Discipline.Id == 0 {
+			c.JSON(http.StatusNotFound, scoreApi.ErrorResponse{
+				Error: controller.translator.message(c, messageDisciplineNotExists, c.Param("discipline_id")),
+			})
+
+		} else {
+			c.JSON(http.StatusOK, disciplineRisk)
+		}
+	}
+}
+
+func (controller *ApiController) getDisciplineAtRiskStudents(c *gin.Context) {
+	disciplineId, _ := strconv.Atoi(c.Param("discipline_id"))
+
+	if disciplineId <= 0 {
+		c.JSON(http.StatusBadRequest, scoreApi.ErrorResponse{
+			Error: controller.translator.message(c, messageIncorrectDisciplineId, c.Param("discipline_id")),
+		})
+
+	} else {
+		disciplineAtRisk, err := controller.risks.getDisciplineAtRiskStudents(disciplineId)
+
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, scoreApi.ErrorResponse{
+				Error: err.Error(),
+			})
+
+		} else if disciplineAtRisk.Discipline.Id == 0 {
+			c.JSON(http.StatusNotFound, scoreApi.ErrorResponse{
+				Error: controller.translator.message(c, messageDisciplineNotExists, c.Param("discipline_id")),
+			})
+
+		} else {
+			c.JSON(http.StatusOK, disciplineAtRisk)
+		}
+	}
+}
+
+// getStudentEvents streams score and rating changes of the student as Server-Sent Events
+func (controller *ApiController) getStudentEvents(c *gin.Context) {
+	studentId, _ := strconv.Atoi(c.Param("student_id"))
+	lastEventId, _ := strconv.ParseUint(c.GetHeader("Last-Event-ID"), 10, 64)
+
+	stream, replay, err := controller.eventHub.subscribe(studentId, lastEventId)
+
+	if errors.Is(err, ErrTooManyStreams) {
+		c.Header("Retry-After", strconv.Itoa(int(controller.eventHub.heartbeat.Seconds())))
+		c.JSON(http.StatusServiceUnavailable, scoreApi.ErrorResponse{
+			Error: controller.translator.message(c, messageTooManyStreams),
+		})
+
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, scoreApi.ErrorResponse{
+			Error: err.Error(),
+		})
+
+	} else {
+		defer controller.eventHub.unsubscribe(stream)
+		controller.streamStudentEvents(c, stream, replay)
+	}
+}
+
+func (controller *ApiController) streamStudentEvents(c *gin.Context, stream *StudentEventStream, replay []StudentEvent) {
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	for _, event := range replay {
+		controller.writeStudentEvent(c, event)
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(controller.eventHub.heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+
+		case event, isOpen := <-stream.events:
+			if !isOpen {
+				return
+			}
+			controller.writeStudentEvent(c, event)
+
+		case <-heartbeat.C:
+			_, _ = c.Writer.WriteString(": heartbeat\n\n")
+		}
+
+		c.Writer.Flush()
+	}
+}
+
+func (controller *ApiController) writeStudentEvent(c *gin.Context, event StudentEvent) {
+	data := event.Data
+	if disciplineScore, isScore := data.(DisciplineScore); isScore {
+		disciplineScore.Score.Lesson.Type = controller.translator.lessonType(
+			controller.translator.language(c), disciplineScore.Score.Lesson.Type,
+		)
+		controller.dateFormat.formatScore(&disciplineScore.Score)
+		data = disciplineScore
+	}
+
+	encoded, _ := json.Marshal(data)
+	_, _ = fmt.Fprintf(c.Writer, "id: %d\nevent: %s\ndata: %s\n\n", event.Id, event.Type, encoded)
+}
+
+func (controller *ApiController) getAnomalies(c *gin.Context) {
+	c.JSON(http.StatusOK, controller.storage.getAnomalies())
+}
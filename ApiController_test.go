@@ -2,57 +2,238 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redismock/v9"
 	scoreApi "github.com/kneu-messenger-pigeon/score-api"
 	"github.com/stretchr/testify/assert"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 )
 
+// newTestRouterDependencies returns dependencies with default translator and grade scaler, optional ones are unset
+func newTestRouterDependencies(out io.Writer, storage StorageInterface) RouterDependencies {
+	translator, _ := NewTranslator(nil, context.Background())
+
+	return RouterDependencies{
+		out:              out,
+		storage:          storage,
+		translator:       translator,
+		gradeScaler:      getTestGradeScaler(),
+		location:         defaultAcademicLocation,
+		changesRetention: time.Hour * 24 * DefaultStudentChangesRetentionDays,
+	}
+}
+
+// newKnownStudentStorage returns storage mock which passes the student existence check of the student routes
+func newKnownStudentStorage(t *testing.T, studentId int) *MockStorageInterface {
+	storage := NewMockStorageInterface(t)
+	storage.On("studentExists", studentId).Return(true, nil)
+
+	return storage
+}
+
+func setupTestRouter(out io.Writer, storage StorageInterface) *gin.Engine {
+	return setupRouter(newTestRouterDependencies(out, storage))
+}
+
+func TestGetStudentProfile(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		expectedProfile := StudentProfile{
+			Id:               1200,
+			Name:             "Іван Франко",
+			Group:            "ЕК-201",
+			Faculty:          "Факультет економіки та управління",
+			StudyForm:        "денна",
+			Semesters:        []YearSemester{{Year: 2026, Semester: 1}},
+			DisciplinesCount: 2,
+		}
+
+		storage := NewMockStorageInterface(t)
+		storage.On("getStudentProfile", 1200).Return(expectedProfile, nil)
+
+		expectedBody, err := json.Marshal(expectedProfile)
+		assert.NoError(t, err)
+
+		router := setupTestRouter(out, storage)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/v1/students/1200", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, expectedBody, w.Body.Bytes())
+	})
+
+	t.Run("not_found", func(t *testing.T) {
+		out := &bytes.Buffer{}
+
+		storage := NewMockStorageInterface(t)
+		storage.On("getStudentProfile", 1200).Return(StudentProfile{}, nil)
+
+		router := setupTestRouter(out, storage)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/v1/students/1200", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.JSONEq(t, `{"error":"Student not exists: 1200"}`, w.Body.String())
+	})
+
+	t.Run("storage_error", func(t *testing.T) {
+		out := &bytes.Buffer{}
+
+		storage := NewMockStorageInterface(t)
+		storage.On("getStudentProfile", 1200).Return(StudentProfile{}, errors.New("expected error"))
+
+		router := setupTestRouter(out, storage)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/v1/students/1200", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.JSONEq(t, `{"error":"expected error"}`, w.Body.String())
+	})
+
+	t.Run("wrong student id ", func(t *testing.T) {
+		out := &bytes.Buffer{}
+
+		storage := NewMockStorageInterface(t)
+		router := setupTestRouter(out, storage)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/v1/students/-1", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error":"Incorrect student_id: -1"}`, w.Body.String())
+	})
+}
+
+func TestStudentRoutesUnknownStudent(t *testing.T) {
+	routes := []string{
+		"/v1/students/1200/disciplines",
+		"/v1/students/1200/disciplines/199",
+		"/v1/students/1200/disciplines/199/scores/245",
+		"/v1/students/1200/disciplines/199/timeline",
+		"/v1/students/1200/disciplines/199/deleted-lessons",
+		"/v1/students/1200/disciplines/199/risk",
+		"/v1/students/1200/summary",
+		"/v1/students/1200/attendance",
+		"/v1/students/1200/changes",
+		"/v1/students/1200/export/csv",
+		"/v1/students/1200/export/xlsx",
+		"/v1/students/1200/calendar.ics",
+		"/v1/students/1200/events",
+	}
+
+	setupRouterWithEvents := func(t *testing.T, storage StorageInterface) *gin.Engine {
+		redisClient, _ := redismock.NewClientMock()
+		dependencies := newTestRouterDependencies(&bytes.Buffer{}, storage)
+		dependencies.eventHub = newTestStudentEventHub(redisClient, NewMockScoreRatingLoaderInterface(t), 10)
+
+		return setupRouter(dependencies)
+	}
+
+	t.Run("not_found", func(t *testing.T) {
+		for _, route := range routes {
+			storage := NewMockStorageInterface(t)
+			storage.On("studentExists", 1200).Return(false, nil)
+
+			router := setupRouterWithEvents(t, storage)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, route, nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusNotFound, w.Code, route)
+			assert.JSONEq(t, `{"error":"Student not exists: 1200"}`, w.Body.String(), route)
+		}
+	})
+
+	t.Run("storage_error", func(t *testing.T) {
+		for _, route := range routes {
+			storage := NewMockStorageInterface(t)
+			storage.On("studentExists", 1200).Return(false, errors.New("expected error"))
+
+			router := setupRouterWithEvents(t, storage)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, route, nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusInternalServerError, w.Code, route)
+			assert.JSONEq(t, `{"error":"expected error"}`, w.Body.String(), route)
+		}
+	})
+
+	t.Run("wrong_student_id", func(t *testing.T) {
+		router := setupRouterWithEvents(t, NewMockStorageInterface(t))
+
+		for _, route := range routes {
+			route = strings.Replace(route, "1200", "abc", 1)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, route, nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code, route)
+			assert.JSONEq(t, `{"error":"Incorrect student_id: abc"}`, w.Body.String(), route)
+		}
+	})
+}
+
 func TestGetStudentDisciplines(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		out := &bytes.Buffer{}
 
-		expectedResults := scoreApi.DisciplineScoreResults{
-			scoreApi.DisciplineScoreResult{
+		expectedResults := DisciplineScoreResults{
+			DisciplineScoreResult{
 				Discipline: scoreApi.Discipline{
 					Id:   100,
 					Name: "Капітал!",
 				},
-				ScoreRating: scoreApi.ScoreRating{
+				ScoreRating: ScoreRating{ScoreRating: scoreApi.ScoreRating{
 					Total:         17,
 					StudentsCount: 25,
 					Rating:        8,
 					MinTotal:      10,
 					MaxTotal:      20,
-				},
+				}},
 			},
-			scoreApi.DisciplineScoreResult{
+			DisciplineScoreResult{
 				Discipline: scoreApi.Discipline{
 					Id:   110,
 					Name: "Гроші та лихварство",
 				},
-				ScoreRating: scoreApi.ScoreRating{
+				ScoreRating: ScoreRating{ScoreRating: scoreApi.ScoreRating{
 					Total:         12,
 					StudentsCount: 25,
 					Rating:        12,
 					MinTotal:      7,
 					MaxTotal:      17,
-				},
+				}},
 			},
 		}
 
 		storage := NewMockStorageInterface(t)
+		storage.On("studentExists", 23).Return(true, nil)
 		storage.On("getDisciplineScoreResultsByStudentId", 23).Return(expectedResults, nil)
 
 		expectedBody, err := json.Marshal(expectedResults)
 		assert.NoError(t, err)
 
-		router := setupRouter(out, storage)
+		router := setupTestRouter(out, storage)
 
 		w := httptest.NewRecorder()
 		req, _ := http.NewRequest(http.MethodGet, "/v1/students/23/disciplines", nil)
@@ -62,15 +243,117 @@ func TestGetStudentDisciplines(t *testing.T) {
 		assert.Equal(t, expectedBody, w.Body.Bytes())
 	})
 
+	t.Run("grades", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		results := DisciplineScoreResults{
+			{ScoreRating: ScoreRating{ScoreRating: scoreApi.ScoreRating{Total: 95}}},
+			{ScoreRating: ScoreRating{ScoreRating: scoreApi.ScoreRating{Total: 40}}},
+		}
+
+		storage := NewMockStorageInterface(t)
+		storage.On("studentExists", 23).Return(true, nil)
+		storage.On("getDisciplineScoreResultsByStudentId", 23).Return(results, nil)
+
+		gradeScales, _ := loadGradeScales("")
+		dependencies := newTestRouterDependencies(out, storage)
+		dependencies.gradeScaler = NewGradeScaler(nil, gradeScales, context.Background())
+		router := setupRouter(dependencies)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/v1/students/23/disciplines", nil)
+		router.ServeHTTP(w, req)
+
+		actualResults := DisciplineScoreResults{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &actualResults))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, map[string]string{"ects": "A", "national": "5"}, actualResults[0].Grades)
+		assert.Equal(t, map[string]string{"ects": "FX", "national": "2"}, actualResults[1].Grades)
+	})
+
+	t.Run("wrong scales", func(t *testing.T) {
+		storage := newKnownStudentStorage(t, 23)
+		router := setupTestRouter(&bytes.Buffer{}, storage)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/v1/students/23/disciplines?scales=ects", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error":"Incorrect scales: ects"}`, w.Body.String())
+	})
+
+	t.Run("risk", func(t *testing.T) {
+		results := DisciplineScoreResults{
+			{Discipline: scoreApi.Discipline{Id: 100}},
+			{Discipline: scoreApi.Discipline{Id: 110}},
+		}
+		risks := []DisciplineRiskResult{
+			{Discipline: scoreApi.Discipline{Id: 110}, Risk: DisciplineRisk{Level: RiskHigh, ProjectedTotal: 20}},
+		}
+
+		storage := NewMockStorageInterface(t)
+		storage.On("studentExists", 23).Return(true, nil)
+		storage.On("getDisciplineScoreResultsByStudentId", 23).Return(results, nil)
+		riskLoader := NewMockDisciplineRiskLoaderInterface(t)
+		riskLoader.On("getStudentDisciplinesRisks", 23).Return(risks, nil)
+
+		dependencies := newTestRouterDependencies(&bytes.Buffer{}, storage)
+		dependencies.risks = riskLoader
+		router := setupRouter(dependencies)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/v1/students/23/disciplines?risk=true", nil)
+		router.ServeHTTP(w, req)
+
+		actualResults := DisciplineScoreResults{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &actualResults))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Nil(t, actualResults[0].Risk)
+		assert.Equal(t, RiskHigh, actualResults[1].Risk.Level)
+		assert.Equal(t, float32(20), actualResults[1].Risk.ProjectedTotal)
+	})
+
+	t.Run("risk_storage_error", func(t *testing.T) {
+		storage := NewMockStorageInterface(t)
+		storage.On("studentExists", 23).Return(true, nil)
+		storage.On("getDisciplineScoreResultsByStudentId", 23).Return(DisciplineScoreResults{}, nil)
+		riskLoader := NewMockDisciplineRiskLoaderInterface(t)
+		riskLoader.On("getStudentDisciplinesRisks", 23).Return(nil, errors.New("expected error"))
+
+		dependencies := newTestRouterDependencies(&bytes.Buffer{}, storage)
+		dependencies.risks = riskLoader
+		router := setupRouter(dependencies)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/v1/students/23/disciplines?risk=1", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.JSONEq(t, `{"error":"expected error"}`, w.Body.String())
+	})
+
+	t.Run("wrong risk", func(t *testing.T) {
+		storage := newKnownStudentStorage(t, 23)
+		router := setupTestRouter(&bytes.Buffer{}, storage)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/v1/students/23/disciplines?risk=maybe", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error":"Incorrect risk: maybe"}`, w.Body.String())
+	})
+
 	t.Run("Storage_error", func(t *testing.T) {
 		out := &bytes.Buffer{}
 		expectedError := errors.New("expected error")
 
 		storage := NewMockStorageInterface(t)
+		storage.On("studentExists", 23).Return(true, nil)
 		storage.On("getDisciplineScoreResultsByStudentId", 23).
-			Return(scoreApi.DisciplineScoreResults{}, expectedError)
+			Return(DisciplineScoreResults{}, expectedError)
 
-		router := setupRouter(out, storage)
+		router := setupTestRouter(out, storage)
 
 		w := httptest.NewRecorder()
 		req, _ := http.NewRequest(http.MethodGet, "/v1/students/23/disciplines", nil)
@@ -88,7 +371,7 @@ func TestGetStudentDisciplines(t *testing.T) {
 		out := &bytes.Buffer{}
 
 		storage := NewMockStorageInterface(t)
-		router := setupRouter(out, storage)
+		router := setupTestRouter(out, storage)
 
 		w := httptest.NewRecorder()
 		req, _ := http.NewRequest(http.MethodGet, "/v1/students/-99/disciplines", nil)
@@ -106,43 +389,45 @@ func TestGetStudentDisciplines(t *testing.T) {
 func TestGetStudentDiscipline(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		out := &bytes.Buffer{}
-		expectedResult := scoreApi.DisciplineScoreResult{
+		expectedResult := DisciplineScoreResult{
 			Discipline: scoreApi.Discipline{
 				Id:   199,
 				Name: "Капітал!",
 			},
-			ScoreRating: scoreApi.ScoreRating{
+			ScoreRating: ScoreRating{ScoreRating: scoreApi.ScoreRating{
 				Total:         17,
 				StudentsCount: 25,
 				Rating:        8,
 				MinTotal:      10,
 				MaxTotal:      20,
-			},
-			Scores: []scoreApi.Score{
+			}},
+			Scores: []Score{
 				{
-					Lesson: scoreApi.Lesson{
-						Id:   245,
-						Date: time.Date(2023, time.Month(2), 12, 0, 0, 0, 0, time.Local),
-						Type: scoreApi.LessonType{
-							Id:        5,
-							ShortName: "МК",
-							LongName:  "Модульний контроль.",
+					Score: scoreApi.Score{
+						Lesson: scoreApi.Lesson{
+							Id:   245,
+							Date: time.Date(2023, time.Month(2), 12, 0, 0, 0, 0, defaultAcademicLocation),
+							Type: scoreApi.LessonType{
+								Id:        5,
+								ShortName: "МК",
+								LongName:  "Модульний контроль.",
+							},
 						},
+						FirstScore:  floatPointer(4.5),
+						SecondScore: nil,
+						IsAbsent:    true,
 					},
-					FirstScore:  floatPointer(4.5),
-					SecondScore: nil,
-					IsAbsent:    true,
 				},
 			},
 		}
 
-		storage := NewMockStorageInterface(t)
+		storage := newKnownStudentStorage(t, 23)
 		storage.On("getDisciplineScoreResultByStudentId", 23, 199).Return(expectedResult, nil)
 
 		expectedBody, err := json.Marshal(expectedResult)
 		assert.NoError(t, err)
 
-		router := setupRouter(out, storage)
+		router := setupTestRouter(out, storage)
 
 		w := httptest.NewRecorder()
 		req, _ := http.NewRequest(http.MethodGet, "/v1/students/23/disciplines/199", nil)
@@ -152,19 +437,88 @@ func TestGetStudentDiscipline(t *testing.T) {
 		assert.Equal(t, expectedBody, w.Body.Bytes())
 	})
 
+	t.Run("grades", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		result := DisciplineScoreResult{
+			Discipline: scoreApi.Discipline{
+				Id:   199,
+				Name: "Капітал!",
+			},
+			ScoreRating: ScoreRating{ScoreRating: scoreApi.ScoreRating{
+				Total:         83,
+				StudentsCount: 25,
+				Rating:        2,
+			}},
+		}
+
+		storage := newKnownStudentStorage(t, 23)
+		storage.On("getDisciplineScoreResultByStudentId", 23, 199).Return(result, nil)
+
+		gradeScales, _ := loadGradeScales("")
+		dependencies := newTestRouterDependencies(out, storage)
+		dependencies.gradeScaler = NewGradeScaler(nil, gradeScales, context.Background())
+		router := setupRouter(dependencies)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/v1/students/23/disciplines/199?scales=ects", nil)
+		router.ServeHTTP(w, req)
+
+		actualResult := DisciplineScoreResult{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &actualResult))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, map[string]string{"ects": "B"}, actualResult.Grades)
+
+		w = httptest.NewRecorder()
+		req, _ = http.NewRequest(http.MethodGet, "/v1/students/23/disciplines/199?scales=usa", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error":"Incorrect scales: usa"}`, w.Body.String())
+	})
+
+	t.Run("date_only", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		date := time.Date(2023, time.Month(2), 12, 0, 0, 0, 0, defaultAcademicLocation)
+		result := DisciplineScoreResult{
+			Discipline: scoreApi.Discipline{
+				Id:   199,
+				Name: "Капітал!",
+			},
+			Attendance: Attendance{TotalLessons: 1, Absent: 1, AbsentDates: LessonDates{{Time: date}}},
+			Scores: []Score{
+				{Score: scoreApi.Score{Lesson: scoreApi.Lesson{Id: 245, Date: date}, IsAbsent: true}},
+			},
+		}
+
+		storage := newKnownStudentStorage(t, 23)
+		storage.On("getDisciplineScoreResultByStudentId", 23, 199).Return(result, nil)
+
+		dependencies := newTestRouterDependencies(out, storage)
+		dependencies.dateOnlyJson = true
+		router := setupRouter(dependencies)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/v1/students/23/disciplines/199", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"absentDates":["2023-02-12"]`)
+		assert.Contains(t, w.Body.String(), `"date":"2023-02-12"`)
+	})
+
 	t.Run("not_exist_discipline", func(t *testing.T) {
 		out := &bytes.Buffer{}
-		expectedResult := scoreApi.DisciplineScoreResult{
+		expectedResult := DisciplineScoreResult{
 			Discipline: scoreApi.Discipline{
 				Id:   0,
 				Name: "",
 			},
 		}
 
-		storage := NewMockStorageInterface(t)
+		storage := newKnownStudentStorage(t, 23)
 		storage.On("getDisciplineScoreResultByStudentId", 23, 199).Return(expectedResult, nil)
 
-		router := setupRouter(out, storage)
+		router := setupTestRouter(out, storage)
 
 		w := httptest.NewRecorder()
 		req, _ := http.NewRequest(http.MethodGet, "/v1/students/23/disciplines/199", nil)
@@ -182,10 +536,10 @@ func TestGetStudentDiscipline(t *testing.T) {
 		out := &bytes.Buffer{}
 		expectedError := errors.New("expected error")
 
-		storage := NewMockStorageInterface(t)
-		storage.On("getDisciplineScoreResultByStudentId", 23, 199).Return(scoreApi.DisciplineScoreResult{}, expectedError)
+		storage := newKnownStudentStorage(t, 23)
+		storage.On("getDisciplineScoreResultByStudentId", 23, 199).Return(DisciplineScoreResult{}, expectedError)
 
-		router := setupRouter(out, storage)
+		router := setupTestRouter(out, storage)
 
 		w := httptest.NewRecorder()
 		req, _ := http.NewRequest(http.MethodGet, "/v1/students/23/disciplines/199", nil)
@@ -203,7 +557,7 @@ func TestGetStudentDiscipline(t *testing.T) {
 		out := &bytes.Buffer{}
 
 		storage := NewMockStorageInterface(t)
-		router := setupRouter(out, storage)
+		router := setupTestRouter(out, storage)
 
 		w := httptest.NewRecorder()
 		req, _ := http.NewRequest(http.MethodGet, "/v1/students/-99/disciplines/199", nil)
@@ -220,8 +574,8 @@ func TestGetStudentDiscipline(t *testing.T) {
 	t.Run("wrong discipline id ", func(t *testing.T) {
 		out := &bytes.Buffer{}
 
-		storage := NewMockStorageInterface(t)
-		router := setupRouter(out, storage)
+		storage := newKnownStudentStorage(t, 650)
+		router := setupTestRouter(out, storage)
 
 		w := httptest.NewRecorder()
 		req, _ := http.NewRequest(http.MethodGet, "/v1/students/650/disciplines/0", nil)
@@ -234,171 +588,876 @@ func TestGetStudentDiscipline(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 		assert.Contains(t, actualBody, "error")
 	})
-}
 
-func TestGetStudentDisciplineScore(t *testing.T) {
-	t.Run("success", func(t *testing.T) {
+	t.Run("filtered", func(t *testing.T) {
 		out := &bytes.Buffer{}
-		expectedResult := scoreApi.DisciplineScore{
+		scores := getTestFilterScores()
+		storageResult := DisciplineScoreResult{
 			Discipline: scoreApi.Discipline{
 				Id:   199,
 				Name: "Капітал!",
 			},
-			Score: scoreApi.Score{
-				Lesson: scoreApi.Lesson{
-					Id:   245,
-					Date: time.Date(2023, time.Month(2), 12, 0, 0, 0, 0, time.Local),
-					Type: scoreApi.LessonType{
-						Id:        5,
-						ShortName: "МК",
-						LongName:  "Модульний контроль.",
-					},
-				},
-				FirstScore:  floatPointer(4.5),
-				SecondScore: nil,
-				IsAbsent:    true,
-			},
+			Scores: scores,
 		}
 
-		storage := NewMockStorageInterface(t)
-		storage.On("getDisciplineScore", 23, 199, 245).Return(expectedResult, nil)
+		expectedResult := storageResult
+		expectedResult.Scores = []Score{scores[3]}
+		expectedResult.NextCursor = encodeScoreCursor(scores[3])
+
+		storage := newKnownStudentStorage(t, 23)
+		storage.On("getDisciplineScoreResultByStudentId", 23, 199).Return(storageResult, nil)
 
 		expectedBody, err := json.Marshal(expectedResult)
 		assert.NoError(t, err)
 
-		router := setupRouter(out, storage)
+		router := setupTestRouter(out, storage)
 
 		w := httptest.NewRecorder()
-		req, _ := http.NewRequest(http.MethodGet, "/v1/students/23/disciplines/199/scores/245", nil)
+		req, _ := http.NewRequest(http.MethodGet, "/v1/students/23/disciplines/199?lessonType=5,лаб&limit=1", nil)
 		router.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusOK, w.Code)
 		assert.Equal(t, expectedBody, w.Body.Bytes())
 	})
 
-	t.Run("not_exist_discipline", func(t *testing.T) {
+	t.Run("wrong filter", func(t *testing.T) {
 		out := &bytes.Buffer{}
-		expectedResult := scoreApi.DisciplineScore{
-			Discipline: scoreApi.Discipline{
-				Id:   0,
-				Name: "",
-			},
-		}
 
-		storage := NewMockStorageInterface(t)
-		storage.On("getDisciplineScore", 23, 199, 245).Return(expectedResult, nil)
-
-		router := setupRouter(out, storage)
+		storage := newKnownStudentStorage(t, 23)
+		router := setupTestRouter(out, storage)
 
 		w := httptest.NewRecorder()
-		req, _ := http.NewRequest(http.MethodGet, "/v1/students/23/disciplines/199/scores/245", nil)
+		req, _ := http.NewRequest(http.MethodGet, "/v1/students/23/disciplines/199?from=12.02.2023", nil)
 		router.ServeHTTP(w, req)
 
 		actualBody := gin.H{}
 		err := json.Unmarshal(w.Body.Bytes(), &actualBody)
 
 		assert.NoError(t, err)
-		assert.Equal(t, http.StatusNotFound, w.Code)
-		assert.Contains(t, actualBody, "error")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "Incorrect from: 12.02.2023", actualBody["error"])
 	})
 
-	t.Run("not_exist_lesson", func(t *testing.T) {
+	t.Run("localized", func(t *testing.T) {
 		out := &bytes.Buffer{}
-		expectedResult := scoreApi.DisciplineScore{
+		lessonTypes := GetTestLessonTypes()
+		storageResult := DisciplineScoreResult{
 			Discipline: scoreApi.Discipline{
 				Id:   199,
 				Name: "Капітал!",
 			},
-			Score: scoreApi.Score{
-				Lesson:      scoreApi.Lesson{},
-				FirstScore:  nil,
-				SecondScore: nil,
-				IsAbsent:    false,
+			Scores: []Score{
+				{Score: scoreApi.Score{Lesson: scoreApi.Lesson{Id: 245, Type: lessonTypes[15]}, FirstScore: floatPointer(4.5)}},
 			},
 		}
 
-		storage := NewMockStorageInterface(t)
-		storage.On("getDisciplineScore", 23, 199, 245).Return(expectedResult, nil)
+		storage := newKnownStudentStorage(t, 23)
+		storage.On("getDisciplineScoreResultByStudentId", 23, 199).Return(storageResult, nil)
 
-		router := setupRouter(out, storage)
+		router := setupTestRouter(out, storage)
 
 		w := httptest.NewRecorder()
-		req, _ := http.NewRequest(http.MethodGet, "/v1/students/23/disciplines/199/scores/245", nil)
+		req, _ := http.NewRequest(http.MethodGet, "/v1/students/23/disciplines/199", nil)
+		req.Header.Set("Accept-Language", "en-GB,en;q=0.9,uk;q=0.8")
 		router.ServeHTTP(w, req)
 
-		actualBody := gin.H{}
-		err := json.Unmarshal(w.Body.Bytes(), &actualBody)
+		actualResult := DisciplineScoreResult{}
+		err := json.Unmarshal(w.Body.Bytes(), &actualResult)
 
 		assert.NoError(t, err)
-		assert.Equal(t, http.StatusNotFound, w.Code)
-		assert.Contains(t, actualBody, "error")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, scoreApi.LessonType{Id: 15, ShortName: "MT", LongName: "Module test"}, actualResult.Scores[0].Lesson.Type)
 	})
 
-	t.Run("storage_error", func(t *testing.T) {
+	t.Run("localized_error", func(t *testing.T) {
 		out := &bytes.Buffer{}
-		expectedError := errors.New("expected error")
-
-		storage := NewMockStorageInterface(t)
-		storage.On("getDisciplineScore", 23, 199, 245).Return(scoreApi.DisciplineScore{}, expectedError)
 
-		router := setupRouter(out, storage)
+		storage := newKnownStudentStorage(t, 23)
+		router := setupTestRouter(out, storage)
 
 		w := httptest.NewRecorder()
-		req, _ := http.NewRequest(http.MethodGet, "/v1/students/23/disciplines/199/scores/245", nil)
+		req, _ := http.NewRequest(http.MethodGet, "/v1/students/23/disciplines/abc?lang=uk", nil)
 		router.ServeHTTP(w, req)
 
 		actualBody := gin.H{}
 		err := json.Unmarshal(w.Body.Bytes(), &actualBody)
 
 		assert.NoError(t, err)
-		assert.Equal(t, http.StatusInternalServerError, w.Code)
-		assert.Contains(t, actualBody, "error")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "Некоректний discipline_id: abc", actualBody["error"])
 	})
+}
 
-	t.Run("wrong student id ", func(t *testing.T) {
+func TestGetStudentDisciplineScore(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
 		out := &bytes.Buffer{}
+		expectedResult := DisciplineScore{
+			Discipline: scoreApi.Discipline{
+				Id:   199,
+				Name: "Капітал!",
+			},
+			Score: Score{
+				Score: scoreApi.Score{
+					Lesson: scoreApi.Lesson{
+						Id:   245,
+						Date: time.Date(2023, time.Month(2), 12, 0, 0, 0, 0, defaultAcademicLocation),
+						Type: scoreApi.LessonType{
+							Id:        5,
+							ShortName: "МК",
+							LongName:  "Модульний контроль.",
+						},
+					},
+					FirstScore:  floatPointer(4.5),
+					SecondScore: nil,
+					IsAbsent:    true,
+				},
+			},
+		}
 
-		storage := NewMockStorageInterface(t)
-		router := setupRouter(out, storage)
+		storage := newKnownStudentStorage(t, 23)
+		storage.On("getDisciplineScore", 23, 199, 245).Return(expectedResult, nil)
+
+		expectedBody, err := json.Marshal(expectedResult)
+		assert.NoError(t, err)
+
+		router := setupTestRouter(out, storage)
 
 		w := httptest.NewRecorder()
-		req, _ := http.NewRequest(http.MethodGet, "/v1/students/-99/disciplines/199/scores/245", nil)
+		req, _ := http.NewRequest(http.MethodGet, "/v1/students/23/disciplines/199/scores/245", nil)
 		router.ServeHTTP(w, req)
 
-		actualBody := gin.H{}
-		err := json.Unmarshal(w.Body.Bytes(), &actualBody)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, expectedBody, w.Body.Bytes())
+	})
+
+	t.Run("not_exist_discipline", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		expectedResult := DisciplineScore{
+			Discipline: scoreApi.Discipline{
+				Id:   0,
+				Name: "",
+			},
+		}
+
+		storage := newKnownStudentStorage(t, 23)
+		storage.On("getDisciplineScore", 23, 199, 245).Return(expectedResult, nil)
+
+		router := setupTestRouter(out, storage)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/v1/students/23/disciplines/199/scores/245", nil)
+		router.ServeHTTP(w, req)
+
+		actualBody := gin.H{}
+		err := json.Unmarshal(w.Body.Bytes(), &actualBody)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, actualBody, "error")
+	})
+
+	t.Run("not_exist_lesson", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		expectedResult := DisciplineScore{
+			Discipline: scoreApi.Discipline{
+				Id:   199,
+				Name: "Капітал!",
+			},
+			Score: Score{
+				Score: scoreApi.Score{
+					Lesson:      scoreApi.Lesson{},
+					FirstScore:  nil,
+					SecondScore: nil,
+					IsAbsent:    false,
+				},
+			},
+		}
+
+		storage := newKnownStudentStorage(t, 23)
+		storage.On("getDisciplineScore", 23, 199, 245).Return(expectedResult, nil)
+
+		router := setupTestRouter(out, storage)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/v1/students/23/disciplines/199/scores/245", nil)
+		router.ServeHTTP(w, req)
+
+		actualBody := gin.H{}
+		err := json.Unmarshal(w.Body.Bytes(), &actualBody)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, actualBody, "error")
+	})
+
+	t.Run("storage_error", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		expectedError := errors.New("expected error")
+
+		storage := newKnownStudentStorage(t, 23)
+		storage.On("getDisciplineScore", 23, 199, 245).Return(DisciplineScore{}, expectedError)
+
+		router := setupTestRouter(out, storage)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/v1/students/23/disciplines/199/scores/245", nil)
+		router.ServeHTTP(w, req)
+
+		actualBody := gin.H{}
+		err := json.Unmarshal(w.Body.Bytes(), &actualBody)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Contains(t, actualBody, "error")
+	})
+
+	t.Run("wrong student id ", func(t *testing.T) {
+		out := &bytes.Buffer{}
+
+		storage := NewMockStorageInterface(t)
+		router := setupTestRouter(out, storage)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/v1/students/-99/disciplines/199/scores/245", nil)
+		router.ServeHTTP(w, req)
+
+		actualBody := gin.H{}
+		err := json.Unmarshal(w.Body.Bytes(), &actualBody)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, actualBody, "error")
+	})
+
+	t.Run("wrong discipline id ", func(t *testing.T) {
+		out := &bytes.Buffer{}
+
+		storage := newKnownStudentStorage(t, 650)
+		router := setupTestRouter(out, storage)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/v1/students/650/disciplines/0/scores/123", nil)
+		router.ServeHTTP(w, req)
+
+		actualBody := gin.H{}
+		err := json.Unmarshal(w.Body.Bytes(), &actualBody)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, actualBody, "error")
+	})
+
+	t.Run("wrong lesson id ", func(t *testing.T) {
+		out := &bytes.Buffer{}
+
+		storage := newKnownStudentStorage(t, 650)
+		router := setupTestRouter(out, storage)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/v1/students/650/disciplines/445/scores/-3", nil)
+		router.ServeHTTP(w, req)
+
+		actualBody := gin.H{}
+		err := json.Unmarshal(w.Body.Bytes(), &actualBody)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, actualBody, "error")
+	})
+}
+
+func TestGetStudentDisciplineTimeline(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		lessonType := scoreApi.LessonType{
+			Id:        5,
+			ShortName: "МК",
+			LongName:  "Модульний контроль.",
+		}
+
+		expectedResult := DisciplineTimeline{
+			Discipline: scoreApi.Discipline{
+				Id:   199,
+				Name: "Капітал!",
+			},
+			LessonTypes: []scoreApi.LessonType{lessonType},
+			Points: []TimelinePoint{
+				{
+					Date:             time.Date(2023, time.Month(2), 12, 0, 0, 0, 0, defaultAcademicLocation),
+					Total:            4.5,
+					LessonTypeTotals: map[int]float32{5: 4.5},
+					AverageTotal:     3,
+				},
+			},
+		}
+
+		storage := newKnownStudentStorage(t, 23)
+		storage.On("getDisciplineTimeline", 23, 199).Return(expectedResult, nil)
+
+		expectedBody, err := json.Marshal(expectedResult)
+		assert.NoError(t, err)
+
+		router := setupTestRouter(out, storage)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/v1/students/23/disciplines/199/timeline", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, expectedBody, w.Body.Bytes())
+	})
+
+	t.Run("not_exist_discipline", func(t *testing.T) {
+		out := &bytes.Buffer{}
+
+		storage := newKnownStudentStorage(t, 23)
+		storage.On("getDisciplineTimeline", 23, 199).Return(DisciplineTimeline{}, nil)
+
+		router := setupTestRouter(out, storage)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/v1/students/23/disciplines/199/timeline", nil)
+		router.ServeHTTP(w, req)
+
+		actualBody := gin.H{}
+		err := json.Unmarshal(w.Body.Bytes(), &actualBody)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, actualBody, "error")
+	})
+
+	t.Run("storage_error", func(t *testing.T) {
+		out := &bytes.Buffer{}
+
+		storage := newKnownStudentStorage(t, 23)
+		storage.On("getDisciplineTimeline", 23, 199).Return(DisciplineTimeline{}, errors.New("expected error"))
+
+		router := setupTestRouter(out, storage)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/v1/students/23/disciplines/199/timeline", nil)
+		router.ServeHTTP(w, req)
+
+		actualBody := gin.H{}
+		err := json.Unmarshal(w.Body.Bytes(), &actualBody)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Contains(t, actualBody, "error")
+	})
+
+	t.Run("wrong discipline id ", func(t *testing.T) {
+		out := &bytes.Buffer{}
+
+		storage := newKnownStudentStorage(t, 650)
+		router := setupTestRouter(out, storage)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/v1/students/650/disciplines/0/timeline", nil)
+		router.ServeHTTP(w, req)
+
+		actualBody := gin.H{}
+		err := json.Unmarshal(w.Body.Bytes(), &actualBody)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, actualBody, "error")
+	})
+}
+
+func TestGetStudentDisciplineDeletedLessons(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		expectedResult := DisciplineDeletedLessons{
+			Discipline: scoreApi.Discipline{
+				Id:   199,
+				Name: "Капітал!",
+			},
+			Lessons: []Score{
+				{
+					Score: scoreApi.Score{
+						Lesson: scoreApi.Lesson{
+							Id:   240,
+							Date: time.Date(2023, time.Month(2), 10, 0, 0, 0, 0, defaultAcademicLocation),
+						},
+						FirstScore: floatPointer(3),
+					},
+					Deleted: true,
+				},
+			},
+		}
+
+		storage := newKnownStudentStorage(t, 23)
+		storage.On("getDisciplineDeletedLessons", 23, 199).Return(expectedResult, nil)
+
+		expectedBody, err := json.Marshal(expectedResult)
+		assert.NoError(t, err)
+
+		router := setupTestRouter(out, storage)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/v1/students/23/disciplines/199/deleted-lessons", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, expectedBody, w.Body.Bytes())
+		assert.Contains(t, w.Body.String(), `"deleted":true`)
+	})
+
+	t.Run("not_exist_discipline", func(t *testing.T) {
+		out := &bytes.Buffer{}
+
+		storage := newKnownStudentStorage(t, 23)
+		storage.On("getDisciplineDeletedLessons", 23, 199).Return(DisciplineDeletedLessons{}, nil)
+
+		router := setupTestRouter(out, storage)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/v1/students/23/disciplines/199/deleted-lessons", nil)
+		router.ServeHTTP(w, req)
+
+		actualBody := gin.H{}
+		err := json.Unmarshal(w.Body.Bytes(), &actualBody)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, actualBody, "error")
+	})
+
+	t.Run("storage_error", func(t *testing.T) {
+		out := &bytes.Buffer{}
+
+		storage := newKnownStudentStorage(t, 23)
+		storage.On("getDisciplineDeletedLessons", 23, 199).Return(DisciplineDeletedLessons{}, errors.New("expected error"))
+
+		router := setupTestRouter(out, storage)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/v1/students/23/disciplines/199/deleted-lessons", nil)
+		router.ServeHTTP(w, req)
+
+		actualBody := gin.H{}
+		err := json.Unmarshal(w.Body.Bytes(), &actualBody)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Contains(t, actualBody, "error")
+	})
+
+	t.Run("wrong student id ", func(t *testing.T) {
+		out := &bytes.Buffer{}
+
+		storage := NewMockStorageInterface(t)
+		router := setupTestRouter(out, storage)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/v1/students/-99/disciplines/199/deleted-lessons", nil)
+		router.ServeHTTP(w, req)
+
+		actualBody := gin.H{}
+		err := json.Unmarshal(w.Body.Bytes(), &actualBody)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, actualBody, "error")
+	})
+}
+
+func TestGetStudentSummary(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		disciplines := []DisciplineSummary{
+			{
+				Discipline: scoreApi.Discipline{Id: 199, Name: "Капітал!"},
+				Semester:   1,
+				Credits:    4.5,
+				ScoreRating: ScoreRating{ScoreRating: scoreApi.ScoreRating{
+					Total:         80,
+					StudentsCount: 25,
+					Rating:        3,
+				}},
+			},
+		}
+		expectedSummary := StudentSummary{
+			StudentId:           1200,
+			Year:                2026,
+			WeightedAverage:     80,
+			Credits:             4.5,
+			PassingTotal:        60,
+			AboveThresholdCount: 1,
+			Best:                &disciplines[0],
+			Worst:               &disciplines[0],
+			Disciplines:         disciplines,
+		}
+
+		storage := NewMockStorageInterface(t)
+		storage.On("studentExists", 1200).Return(true, nil)
+		summaryLoader := NewMockStudentSummaryLoaderInterface(t)
+		summaryLoader.On("getStudentSummary", 1200).Return(expectedSummary, nil)
+
+		expectedBody, err := json.Marshal(expectedSummary)
+		assert.NoError(t, err)
+
+		dependencies := newTestRouterDependencies(out, storage)
+		dependencies.summaries = summaryLoader
+		router := setupRouter(dependencies)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/v1/students/1200/summary", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, expectedBody, w.Body.Bytes())
+	})
+
+	t.Run("storage_error", func(t *testing.T) {
+		out := &bytes.Buffer{}
+
+		storage := NewMockStorageInterface(t)
+		storage.On("studentExists", 1200).Return(true, nil)
+		summaryLoader := NewMockStudentSummaryLoaderInterface(t)
+		summaryLoader.On("getStudentSummary", 1200).Return(StudentSummary{}, errors.New("expected error"))
+
+		dependencies := newTestRouterDependencies(out, storage)
+		dependencies.summaries = summaryLoader
+		router := setupRouter(dependencies)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/v1/students/1200/summary", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.JSONEq(t, `{"error":"expected error"}`, w.Body.String())
+	})
+
+	t.Run("wrong student id ", func(t *testing.T) {
+		out := &bytes.Buffer{}
+
+		storage := NewMockStorageInterface(t)
+		router := setupTestRouter(out, storage)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/v1/students/abc/summary", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error":"Incorrect student_id: abc"}`, w.Body.String())
+	})
+}
+
+func TestGetStudentAttendance(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		expectedResult := StudentAttendance{
+			Attendance: Attendance{
+				TotalLessons: 4,
+				Attended:     3,
+				Absent:       1,
+				Percentage:   75,
+				AbsentDates: LessonDates{
+					{Time: time.Date(2023, time.Month(2), 12, 0, 0, 0, 0, defaultAcademicLocation)},
+				},
+			},
+			LessonTypes: []LessonTypeAttendance{
+				{
+					LessonType: scoreApi.LessonType{
+						Id:        5,
+						ShortName: "МК",
+						LongName:  "Модульний контроль.",
+					},
+					Attendance: Attendance{
+						TotalLessons: 4,
+						Attended:     3,
+						Absent:       1,
+						Percentage:   75,
+						AbsentDates: LessonDates{
+							{Time: time.Date(2023, time.Month(2), 12, 0, 0, 0, 0, defaultAcademicLocation)},
+						},
+					},
+				},
+			},
+		}
+
+		storage := NewMockStorageInterface(t)
+		storage.On("studentExists", 23).Return(true, nil)
+		storage.On("getStudentAttendance", 23).Return(expectedResult, nil)
+
+		expectedBody, err := json.Marshal(expectedResult)
+		assert.NoError(t, err)
+
+		router := setupTestRouter(out, storage)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/v1/students/23/attendance", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, expectedBody, w.Body.Bytes())
+	})
+
+	t.Run("storage_error", func(t *testing.T) {
+		out := &bytes.Buffer{}
+
+		storage := NewMockStorageInterface(t)
+		storage.On("studentExists", 23).Return(true, nil)
+		storage.On("getStudentAttendance", 23).Return(StudentAttendance{}, errors.New("expected error"))
+
+		router := setupTestRouter(out, storage)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/v1/students/23/attendance", nil)
+		router.ServeHTTP(w, req)
+
+		actualBody := gin.H{}
+		err := json.Unmarshal(w.Body.Bytes(), &actualBody)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Contains(t, actualBody, "error")
+	})
+
+	t.Run("wrong student id ", func(t *testing.T) {
+		out := &bytes.Buffer{}
+
+		storage := NewMockStorageInterface(t)
+		router := setupTestRouter(out, storage)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/v1/students/-99/attendance", nil)
+		router.ServeHTTP(w, req)
+
+		actualBody := gin.H{}
+		err := json.Unmarshal(w.Body.Bytes(), &actualBody)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, actualBody, "error")
+	})
+}
+
+func TestExportStudentTranscript(t *testing.T) {
+	t.Run("csv", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		transcript := getTestTranscript()
+
+		expectedBody := &bytes.Buffer{}
+		assert.NoError(t, writeTranscriptCsv(expectedBody, transcript))
+
+		storage := NewMockStorageInterface(t)
+		storage.On("studentExists", 1200).Return(true, nil)
+		storage.On("getStudentTranscript", 1200).Return(transcript, nil)
+
+		router := setupTestRouter(out, storage)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/v1/students/1200/export/csv", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Equal(t, `attachment; filename="scores-1200-2026-2.csv"`, w.Header().Get("Content-Disposition"))
+		assert.Equal(t, expectedBody.Bytes(), w.Body.Bytes())
+	})
+
+	t.Run("xlsx", func(t *testing.T) {
+		out := &bytes.Buffer{}
+
+		storage := NewMockStorageInterface(t)
+		storage.On("studentExists", 1200).Return(true, nil)
+		storage.On("getStudentTranscript", 1200).Return(getTestTranscript(), nil)
+
+		router := setupTestRouter(out, storage)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/v1/students/1200/export/xlsx", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", w.Header().Get("Content-Type"))
+		assert.Equal(t, `attachment; filename="scores-1200-2026-2.xlsx"`, w.Header().Get("Content-Disposition"))
+		assert.NotEmpty(t, w.Body.Bytes())
+	})
+
+	t.Run("storage_error", func(t *testing.T) {
+		out := &bytes.Buffer{}
+
+		storage := NewMockStorageInterface(t)
+		storage.On("studentExists", 1200).Return(true, nil)
+		storage.On("getStudentTranscript", 1200).Return(Transcript{}, errors.New("expected error"))
+
+		router := setupTestRouter(out, storage)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/v1/students/1200/export/csv", nil)
+		router.ServeHTTP(w, req)
+
+		actualBody := gin.H{}
+		err := json.Unmarshal(w.Body.Bytes(), &actualBody)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Contains(t, actualBody, "error")
+	})
+
+	t.Run("wrong student id ", func(t *testing.T) {
+		out := &bytes.Buffer{}
+
+		storage := NewMockStorageInterface(t)
+		router := setupTestRouter(out, storage)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/v1/students/-99/export/xlsx", nil)
+		router.ServeHTTP(w, req)
+
+		actualBody := gin.H{}
+		err := json.Unmarshal(w.Body.Bytes(), &actualBody)
 
 		assert.NoError(t, err)
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 		assert.Contains(t, actualBody, "error")
 	})
+}
+
+func TestGetAnomalies(t *testing.T) {
+	expectedReport := AnomalyReport{
+		Total: 1,
+		Kinds: []AnomalyKindReport{
+			{
+				Kind:  ErrMalformedLessonValue.Error(),
+				Count: 1,
+				Samples: []AnomalySample{
+					{
+						Key:   "2026:1:lessons:199",
+						Field: "245",
+						Value: "23",
+						Error: `malformed lesson value "23": too short`,
+					},
+				},
+			},
+		},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		storage := NewMockStorageInterface(t)
+		storage.On("getAnomalies").Return(expectedReport)
+
+		expectedBody, err := json.Marshal(expectedReport)
+		assert.NoError(t, err)
+
+		dependencies := newTestRouterDependencies(&bytes.Buffer{}, storage)
+		dependencies.adminToken = "admin-secret"
+		router := setupRouter(dependencies)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/admin/anomalies", nil)
+		req.Header.Set("Authorization", "Bearer admin-secret")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, expectedBody, w.Body.Bytes())
+	})
+
+	t.Run("wrong_token", func(t *testing.T) {
+		dependencies := newTestRouterDependencies(&bytes.Buffer{}, NewMockStorageInterface(t))
+		dependencies.adminToken = "admin-secret"
+		router := setupRouter(dependencies)
+
+		for _, authorization := range []string{"", "Bearer wrong", "admin-secret"} {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/admin/anomalies", nil)
+			req.Header.Set("Authorization", authorization)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusUnauthorized, w.Code, authorization)
+			assert.Equal(t, `{"error":"Unauthorized"}`, w.Body.String(), authorization)
+		}
+	})
+
+	t.Run("admin_token_not_configured", func(t *testing.T) {
+		router := setupTestRouter(&bytes.Buffer{}, NewMockStorageInterface(t))
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/admin/anomalies", nil)
+		req.Header.Set("Authorization", "Bearer ")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestGetStudentCalendar(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		disciplinesLessons := []DisciplineLessons{
+			{
+				Year:     2026,
+				Semester: 1,
+				Discipline: scoreApi.Discipline{
+					Id:   199,
+					Name: "Капітал!",
+				},
+				Lessons: []Score{
+					{
+						Score: scoreApi.Score{
+							Lesson: scoreApi.Lesson{
+								Id:   245,
+								Date: time.Date(2023, time.Month(2), 12, 0, 0, 0, 0, defaultAcademicLocation),
+							},
+							FirstScore: floatPointer(4.5),
+						},
+					},
+				},
+			},
+		}
+
+		storage := NewMockStorageInterface(t)
+		storage.On("studentExists", 23).Return(true, nil)
+		storage.On("getStudentLessons", 23).Return(disciplinesLessons, nil)
+
+		router := setupTestRouter(out, storage)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/v1/students/23/calendar.ics", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/calendar; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Body.String(), "BEGIN:VCALENDAR\r\n")
+		assert.Contains(t, w.Body.String(), "UID:2026-1-199-245-23@score-storage-api.kneu-messenger-pigeon\r\n")
+		assert.Contains(t, w.Body.String(), "SUMMARY:Капітал!\r\n")
+		assert.Contains(t, w.Body.String(), "DESCRIPTION:Перша оцінка: 4\\,5\r\n")
+	})
 
-	t.Run("wrong discipline id ", func(t *testing.T) {
+	t.Run("storage_error", func(t *testing.T) {
 		out := &bytes.Buffer{}
 
 		storage := NewMockStorageInterface(t)
-		router := setupRouter(out, storage)
+		storage.On("studentExists", 23).Return(true, nil)
+		storage.On("getStudentLessons", 23).Return(nil, errors.New("expected error"))
+
+		router := setupTestRouter(out, storage)
 
 		w := httptest.NewRecorder()
-		req, _ := http.NewRequest(http.MethodGet, "/v1/students/650/disciplines/0/scores/123", nil)
+		req, _ := http.NewRequest(http.MethodGet, "/v1/students/23/calendar.ics", nil)
 		router.ServeHTTP(w, req)
 
 		actualBody := gin.H{}
 		err := json.Unmarshal(w.Body.Bytes(), &actualBody)
 
 		assert.NoError(t, err)
-		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
 		assert.Contains(t, actualBody, "error")
 	})
 
-	t.Run("wrong lesson id ", func(t *testing.T) {
+	t.Run("wrong student id ", func(t *testing.T) {
 		out := &bytes.Buffer{}
 
 		storage := NewMockStorageInterface(t)
-		router := setupRouter(out, storage)
+		router := setupTestRouter(out, storage)
 
 		w := httptest.NewRecorder()
-		req, _ := http.NewRequest(http.MethodGet, "/v1/students/650/disciplines/445/scores/-3", nil)
+		req, _ := http.NewRequest(http.MethodGet, "/v1/students/-99/calendar.ics", nil)
 		router.ServeHTTP(w, req)
 
 		actualBody := gin.H{}
@@ -410,12 +1469,285 @@ func TestGetStudentDisciplineScore(t *testing.T) {
 	})
 }
 
+func TestGetGroupLeaderboard(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		expectedLeaderboard := GroupLeaderboard{
+			GroupId: 17,
+			DisciplineRating: DisciplineRating{
+				Discipline: scoreApi.Discipline{
+					Id:   199,
+					Name: "Капітал!",
+				},
+				Year:     2026,
+				Semester: 1,
+				Students: []StudentTotal{
+					{StudentId: 1300, Total: 13, Rating: 1},
+					{StudentId: 1200, Total: 7.5, Rating: 2},
+				},
+			},
+		}
+
+		storage := NewMockStorageInterface(t)
+		groupRatingLoader := NewMockGroupRatingLoaderInterface(t)
+		groupRatingLoader.On("getGroupLeaderboard", 17, 199).Return(expectedLeaderboard, nil)
+
+		expectedBody, err := json.Marshal(expectedLeaderboard)
+		assert.NoError(t, err)
+
+		dependencies := newTestRouterDependencies(out, storage)
+		dependencies.groupRatings = groupRatingLoader
+		router := setupRouter(dependencies)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/v1/groups/17/disciplines/199/leaderboard", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, expectedBody, w.Body.Bytes())
+		assert.Contains(t, w.Body.String(), `{"groupId":17,"discipline":`)
+	})
+
+	t.Run("not_found", func(t *testing.T) {
+		testCases := map[string]GroupLeaderboard{
+			"Discipline not exists: 199": {},
+			"Group not exists: 17": {
+				DisciplineRating: DisciplineRating{Discipline: scoreApi.Discipline{Id: 199}},
+			},
+		}
+
+		for expectedError, leaderboard := range testCases {
+			storage := NewMockStorageInterface(t)
+			groupRatingLoader := NewMockGroupRatingLoaderInterface(t)
+			groupRatingLoader.On("getGroupLeaderboard", 17, 199).Return(leaderboard, nil)
+
+			dependencies := newTestRouterDependencies(&bytes.Buffer{}, storage)
+			dependencies.groupRatings = groupRatingLoader
+			router := setupRouter(dependencies)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/v1/groups/17/disciplines/199/leaderboard", nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusNotFound, w.Code)
+			assert.JSONEq(t, `{"error":"`+expectedError+`"}`, w.Body.String())
+		}
+	})
+
+	t.Run("storage_error", func(t *testing.T) {
+		out := &bytes.Buffer{}
+
+		storage := NewMockStorageInterface(t)
+		groupRatingLoader := NewMockGroupRatingLoaderInterface(t)
+		groupRatingLoader.On("getGroupLeaderboard", 17, 199).Return(GroupLeaderboard{}, errors.New("expected error"))
+
+		dependencies := newTestRouterDependencies(out, storage)
+		dependencies.groupRatings = groupRatingLoader
+		router := setupRouter(dependencies)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/v1/groups/17/disciplines/199/leaderboard", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.JSONEq(t, `{"error":"expected error"}`, w.Body.String())
+	})
+
+	t.Run("wrong ids", func(t *testing.T) {
+		for _, path := range []string{"/v1/groups/abc/disciplines/199/leaderboard", "/v1/groups/17/disciplines/0/leaderboard"} {
+			storage := NewMockStorageInterface(t)
+			router := setupTestRouter(&bytes.Buffer{}, storage)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, path, nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Contains(t, w.Body.String(), "Incorrect")
+		}
+	})
+}
+
+func TestGetStudentDisciplineRisk(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		expectedResult := DisciplineRiskResult{
+			Discipline: scoreApi.Discipline{
+				Id:   199,
+				Name: "Капітал!",
+			},
+			Risk: DisciplineRisk{
+				Level:            RiskLow,
+				Reasons:          []string{RiskReasonProjectedBelowPassing},
+				Total:            50,
+				ProjectedTotal:   55,
+				AdmissionTotal:   DefaultAdmissionTotal,
+				AveragePerLesson: 5,
+				GradedLessons:    10,
+				HeldLessons:      10,
+				RemainingLessons: 1,
+				RemainingGraded:  1,
+			},
+		}
+
+		storage := newKnownStudentStorage(t, 1200)
+		riskLoader := NewMockDisciplineRiskLoaderInterface(t)
+		riskLoader.On("getDisciplineRisk", 1200, 199).Return(expectedResult, nil)
+
+		expectedBody, err := json.Marshal(expectedResult)
+		assert.NoError(t, err)
+
+		dependencies := newTestRouterDependencies(&bytes.Buffer{}, storage)
+		dependencies.risks = riskLoader
+		router := setupRouter(dependencies)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/v1/students/1200/disciplines/199/risk", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, expectedBody, w.Body.Bytes())
+		assert.Contains(t, w.Body.String(), `"risk":{"level":"low","reasons":["projected_below_passing"]`)
+	})
+
+	t.Run("not_found", func(t *testing.T) {
+		storage := newKnownStudentStorage(t, 1200)
+		riskLoader := NewMockDisciplineRiskLoaderInterface(t)
+		riskLoader.On("getDisciplineRisk", 1200, 199).Return(DisciplineRiskResult{}, nil)
+
+		dependencies := newTestRouterDependencies(&bytes.Buffer{}, storage)
+		dependencies.risks = riskLoader
+		router := setupRouter(dependencies)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/v1/students/1200/disciplines/199/risk", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.JSONEq(t, `{"error":"Discipline not exists: 199"}`, w.Body.String())
+	})
+
+	t.Run("storage_error", func(t *testing.T) {
+		storage := newKnownStudentStorage(t, 1200)
+		riskLoader := NewMockDisciplineRiskLoaderInterface(t)
+		riskLoader.On("getDisciplineRisk", 1200, 199).Return(DisciplineRiskResult{}, errors.New("expected error"))
+
+		dependencies := newTestRouterDependencies(&bytes.Buffer{}, storage)
+		dependencies.risks = riskLoader
+		router := setupRouter(dependencies)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/v1/students/1200/disciplines/199/risk", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.JSONEq(t, `{"error":"expected error"}`, w.Body.String())
+	})
+
+	t.Run("wrong ids", func(t *testing.T) {
+		storages := map[string]*MockStorageInterface{
+			"/v1/students/abc/disciplines/199/risk": NewMockStorageInterface(t),
+			"/v1/students/1200/disciplines/0/risk":  newKnownStudentStorage(t, 1200),
+		}
+		for path, storage := range storages {
+			router := setupTestRouter(&bytes.Buffer{}, storage)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, path, nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Contains(t, w.Body.String(), "Incorrect")
+		}
+	})
+}
+
+func TestGetDisciplineAtRiskStudents(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		expectedResult := DisciplineAtRisk{
+			Discipline: scoreApi.Discipline{
+				Id:   199,
+				Name: "Капітал!",
+			},
+			Year:     2026,
+			Semester: 1,
+			Students: []StudentRisk{
+				{StudentId: 1400, Risk: DisciplineRisk{Level: RiskHigh, Reasons: []string{RiskReasonProjectedBelowAdmission}}},
+				{StudentId: 1200, Risk: DisciplineRisk{Level: RiskMedium, Reasons: []string{RiskReasonHighAbsenceRatio}}},
+			},
+		}
+
+		storage := NewMockStorageInterface(t)
+		riskLoader := NewMockDisciplineRiskLoaderInterface(t)
+		riskLoader.On("getDisciplineAtRiskStudents", 199).Return(expectedResult, nil)
+
+		expectedBody, err := json.Marshal(expectedResult)
+		assert.NoError(t, err)
+
+		dependencies := newTestRouterDependencies(&bytes.Buffer{}, storage)
+		dependencies.risks = riskLoader
+		router := setupRouter(dependencies)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/v1/disciplines/199/at-risk", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, expectedBody, w.Body.Bytes())
+	})
+
+	t.Run("not_found", func(t *testing.T) {
+		storage := NewMockStorageInterface(t)
+		riskLoader := NewMockDisciplineRiskLoaderInterface(t)
+		riskLoader.On("getDisciplineAtRiskStudents", 199).Return(DisciplineAtRisk{}, nil)
+
+		dependencies := newTestRouterDependencies(&bytes.Buffer{}, storage)
+		dependencies.risks = riskLoader
+		router := setupRouter(dependencies)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/v1/disciplines/199/at-risk", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.JSONEq(t, `{"error":"Discipline not exists: 199"}`, w.Body.String())
+	})
+
+	t.Run("storage_error", func(t *testing.T) {
+		storage := NewMockStorageInterface(t)
+		riskLoader := NewMockDisciplineRiskLoaderInterface(t)
+		riskLoader.On("getDisciplineAtRiskStudents", 199).Return(DisciplineAtRisk{}, errors.New("expected error"))
+
+		dependencies := newTestRouterDependencies(&bytes.Buffer{}, storage)
+		dependencies.risks = riskLoader
+		router := setupRouter(dependencies)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/v1/disciplines/199/at-risk", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.JSONEq(t, `{"error":"expected error"}`, w.Body.String())
+	})
+
+	t.Run("wrong discipline id", func(t *testing.T) {
+		storage := NewMockStorageInterface(t)
+		router := setupTestRouter(&bytes.Buffer{}, storage)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/v1/disciplines/abc/at-risk", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error":"Incorrect discipline_Id: abc"}`, w.Body.String())
+	})
+}
+
 func TestPingRoute(t *testing.T) {
 	out := &bytes.Buffer{}
 
 	storage := NewMockStorageInterface(t)
 
-	router := setupRouter(out, storage)
+	router := setupTestRouter(out, storage)
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest(http.MethodGet, "/healthcheck", nil)
@@ -424,3 +1756,214 @@ func TestPingRoute(t *testing.T) {
 	assert.Equal(t, http.StatusOK, w.Code)
 	assert.Equal(t, "health", w.Body.String())
 }
+
+func TestGetStudentEvents(t *testing.T) {
+	newTestEventHub := func(t *testing.T, maxStreams int) *StudentEventHub {
+		redisClient, redisMock := redismock.NewClientMock()
+		scoreRatingLoader := NewMockScoreRatingLoaderInterface(t)
+		if maxStreams > 0 {
+			expectTestStudentWatch(redisMock, scoreRatingLoader)
+		}
+
+		return newTestStudentEventHub(redisClient, scoreRatingLoader, maxStreams)
+	}
+
+	t.Run("success", func(t *testing.T) {
+		eventHub := newTestEventHub(t, 10)
+		eventHub.heartbeat = time.Millisecond * 30
+		eventHub.buffer = append(eventHub.buffer,
+			StudentEvent{Id: 99, StudentId: 1200, Type: StudentEventRating, Data: StudentRatingEvent{}},
+			StudentEvent{Id: 100, StudentId: 1300, Type: StudentEventRating, Data: StudentRatingEvent{}},
+			StudentEvent{Id: 100, StudentId: 1200, Type: StudentEventRating, Data: StudentRatingEvent{
+				Discipline:  scoreApi.Discipline{Id: 199, Name: "Капітал!"},
+				ScoreRating: makeTestEventRating(4.5, 10),
+			}},
+		)
+
+		dependencies := newTestRouterDependencies(&bytes.Buffer{}, newKnownStudentStorage(t, 1200))
+		dependencies.eventHub = eventHub
+		router := setupRouter(dependencies)
+
+		go func() {
+			time.Sleep(time.Millisecond * 10)
+			eventHub.mutex.Lock()
+			defer eventHub.mutex.Unlock()
+			eventHub.publish(StudentEvent{
+				StudentId: 1200,
+				Type:      StudentEventScore,
+				Data:      DisciplineScore{Discipline: scoreApi.Discipline{Id: 199, Name: "Капітал!"}},
+			})
+		}()
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+		defer cancel()
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/v1/students/1200/events", nil)
+		req.Header.Set("Last-Event-ID", "99")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+		assert.Equal(t, "no-cache", w.Header().Get("Cache-Control"))
+
+		body := w.Body.String()
+		assert.True(t, strings.HasPrefix(body, "id: 100\nevent: rating\ndata: "+
+			`{"discipline":{"id":199,"name":"Капітал!"},"scoreRating":{"total":4.5,"minTotal":0,"maxTotal":0,"rating":10,"studentsCount":25,"percentile":0,"tieSize":0,"nonZeroCount":0}}`+
+			"\n\nid: 101\nevent: score\ndata: {\"discipline\":{\"id\":199,\"name\":\"Капітал!\"},"), body)
+		assert.Contains(t, body, ": heartbeat\n\n")
+		assert.Equal(t, 0, eventHub.streamsCount)
+	})
+
+	t.Run("too_many_streams", func(t *testing.T) {
+		eventHub := newTestEventHub(t, 0)
+		dependencies := newTestRouterDependencies(&bytes.Buffer{}, newKnownStudentStorage(t, 1200))
+		dependencies.eventHub = eventHub
+		router := setupRouter(dependencies)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/v1/students/1200/events", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Equal(t, "15", w.Header().Get("Retry-After"))
+		assert.JSONEq(t, `{"error":"Too many event streams, retry later"}`, w.Body.String())
+	})
+
+	t.Run("storage_error", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.ExpectSMembers("2026:1:student_disciplines:1200").SetErr(errors.New("expected error"))
+		eventHub := newTestStudentEventHub(redisClient, NewMockScoreRatingLoaderInterface(t), 10)
+
+		dependencies := newTestRouterDependencies(&bytes.Buffer{}, newKnownStudentStorage(t, 1200))
+		dependencies.eventHub = eventHub
+		router := setupRouter(dependencies)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/v1/students/1200/events", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.JSONEq(t, `{"error":"expected error"}`, w.Body.String())
+	})
+
+	t.Run("wrong student id", func(t *testing.T) {
+		eventHub := newTestEventHub(t, 0)
+		dependencies := newTestRouterDependencies(&bytes.Buffer{}, NewMockStorageInterface(t))
+		dependencies.eventHub = eventHub
+		router := setupRouter(dependencies)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/v1/students/abc/events", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error":"Incorrect student_id: abc"}`, w.Body.String())
+	})
+
+	t.Run("without event hub", func(t *testing.T) {
+		router := setupTestRouter(&bytes.Buffer{}, NewMockStorageInterface(t))
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/v1/students/1200/events", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestGetStudentChanges(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		since := time.Now().Add(-time.Hour).UnixMilli()
+		expectedChanges := StudentChanges{
+			Since:  since,
+			Cursor: since + 1000,
+			Disciplines: []DisciplineChanges{
+				{
+					Discipline: scoreApi.Discipline{Id: 199, Name: "Капітал!"},
+					Scores: []Score{{Score: scoreApi.Score{
+						Lesson: scoreApi.Lesson{
+							Id:   245,
+							Date: time.Date(2023, time.Month(2), 12, 0, 0, 0, 0, time.UTC),
+							Type: GetTestLessonTypes()[1],
+						},
+						FirstScore: floatPointer(4.5),
+					}}},
+				},
+			},
+		}
+
+		storage := newKnownStudentStorage(t, 1200)
+		changesLoader := NewMockStudentChangesLoaderInterface(t)
+		changesLoader.On("getStudentChanges", 1200, time.UnixMilli(since)).Return(expectedChanges, nil)
+
+		expectedBody, err := json.Marshal(expectedChanges)
+		assert.NoError(t, err)
+
+		dependencies := newTestRouterDependencies(&bytes.Buffer{}, storage)
+		dependencies.changes = changesLoader
+		router := setupRouter(dependencies)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/v1/students/1200/changes?since=%d", since), nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, expectedBody, w.Body.Bytes())
+	})
+
+	t.Run("expired_since", func(t *testing.T) {
+		storage := newKnownStudentStorage(t, 1200)
+		router := setupTestRouter(&bytes.Buffer{}, storage)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/v1/students/1200/changes?since=1000", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusGone, w.Code)
+		assert.JSONEq(t, `{"error":"Changes since 1000 are not available anymore, reload all scores"}`, w.Body.String())
+	})
+
+	t.Run("wrong_since", func(t *testing.T) {
+		storage := newKnownStudentStorage(t, 1200)
+		router := setupTestRouter(&bytes.Buffer{}, storage)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/v1/students/1200/changes", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error":"Incorrect since: "}`, w.Body.String())
+	})
+
+	t.Run("storage_error", func(t *testing.T) {
+		since := time.Now().Add(-time.Hour).UnixMilli()
+
+		storage := newKnownStudentStorage(t, 1200)
+		changesLoader := NewMockStudentChangesLoaderInterface(t)
+		changesLoader.On("getStudentChanges", 1200, time.UnixMilli(since)).Return(StudentChanges{}, errors.New("expected error"))
+
+		dependencies := newTestRouterDependencies(&bytes.Buffer{}, storage)
+		dependencies.changes = changesLoader
+		router := setupRouter(dependencies)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/v1/students/1200/changes?since=%d", since), nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.JSONEq(t, `{"error":"expected error"}`, w.Body.String())
+	})
+
+	t.Run("wrong student id", func(t *testing.T) {
+		storage := NewMockStorageInterface(t)
+		router := setupTestRouter(&bytes.Buffer{}, storage)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/v1/students/abc/changes?since=1000", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error":"Incorrect student_id: abc"}`, w.Body.String())
+	})
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	scoreApi "github.com/kneu-messenger-pigeon/score-api"
+	"sort"
+	"time"
+)
+
+type Attendance struct {
+	TotalLessons int         `json:"totalLessons"`
+	Attended     int         `json:"attended"`
+	Absent       int         `json:"absent"`
+	Percentage   float32     `json:"percentage"`
+	AbsentDates  LessonDates `json:"absentDates"`
+}
+
+type LessonTypeAttendance struct {
+	LessonType scoreApi.LessonType `json:"lessonType"`
+	Attendance
+}
+
+type StudentAttendance struct {
+	Attendance
+	LessonTypes []LessonTypeAttendance `json:"lessonTypes"`
+}
+
+// AttendanceByLessonType - attendance of one discipline grouped by lesson type id
+type AttendanceByLessonType map[int]Attendance
+
+func NewAttendance() Attendance {
+	return Attendance{
+		AbsentDates: make(LessonDates, 0),
+	}
+}
+
+func (attendance *Attendance) merge(other Attendance) {
+	attendance.TotalLessons += other.TotalLessons
+	attendance.Absent += other.Absent
+	attendance.Attended = attendance.TotalLessons - attendance.Absent
+
+	attendance.AbsentDates = append(attendance.AbsentDates, other.AbsentDates...)
+	sort.SliceStable(attendance.AbsentDates, func(i, j int) bool {
+		return attendance.AbsentDates[i].Before(attendance.AbsentDates[j].Time)
+	})
+
+	attendance.Percentage = 0
+	if attendance.TotalLessons > 0 {
+		attendance.Percentage = float32(attendance.Attended) * 100 / float32(attendance.TotalLessons)
+	}
+}
+
+func (byLessonType AttendanceByLessonType) total() Attendance {
+	attendance := NewAttendance()
+	for _, lessonTypeAttendance := range byLessonType {
+		attendance.merge(lessonTypeAttendance)
+	}
+
+	return attendance
+}
+
+// calculateAttendance counts every lesson of the discipline dated not after now as held and every absent mark on it
+// as an absence. now is expected in the academic location, so the lesson of today is already held.
+// Absent marks on lessons that are not in the lessons list (e.g. deleted lessons) are ignored.
+func calculateAttendance(lessons []scoreApi.Lesson, absentLessonIds map[int]bool, now time.Time) AttendanceByLessonType {
+	byLessonType := make(AttendanceByLessonType)
+
+	for _, lesson := range lessons {
+		if lesson.Date.After(now) {
+			continue
+		}
+
+		lessonAttendance := Attendance{
+			TotalLessons: 1,
+		}
+
+		if absentLessonIds[lesson.Id] {
+			lessonAttendance.Absent = 1
+			lessonAttendance.AbsentDates = LessonDates{{Time: lesson.Date}}
+		}
+
+		attendance, exists := byLessonType[lesson.Type.Id]
+		if !exists {
+			attendance = NewAttendance()
+		}
+
+		attendance.merge(lessonAttendance)
+		byLessonType[lesson.Type.Id] = attendance
+	}
+
+	return byLessonType
+}
+
+func makeStudentAttendance(disciplinesAttendance []AttendanceByLessonType, lessonTypes map[int]scoreApi.LessonType) StudentAttendance {
+	lessonTypesAttendance := make(AttendanceByLessonType)
+	for _, disciplineAttendance := range disciplinesAttendance {
+		for lessonTypeId, attendance := range disciplineAttendance {
+			lessonTypeAttendance, exists := lessonTypesAttendance[lessonTypeId]
+			if !exists {
+				lessonTypeAttendance = NewAttendance()
+			}
+
+			lessonTypeAttendance.merge(attendance)
+			lessonTypesAttendance[lessonTypeId] = lessonTypeAttendance
+		}
+	}
+
+	studentAttendance := StudentAttendance{
+		Attendance:  lessonTypesAttendance.total(),
+		LessonTypes: make([]LessonTypeAttendance, 0, len(lessonTypesAttendance)),
+	}
+
+	for lessonTypeId, attendance := range lessonTypesAttendance {
+		lessonType, exists := lessonTypes[lessonTypeId]
+		if !exists {
+			lessonType = scoreApi.LessonType{Id: lessonTypeId}
+		}
+
+		studentAttendance.LessonTypes = append(studentAttendance.LessonTypes, LessonTypeAttendance{
+			LessonType: lessonType,
+			Attendance: attendance,
+		})
+	}
+
+	sort.Slice(studentAttendance.LessonTypes, func(i, j int) bool {
+		return studentAttendance.LessonTypes[i].LessonType.Id < studentAttendance.LessonTypes[j].LessonType.Id
+	})
+
+	return studentAttendance
+}
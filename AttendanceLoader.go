@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	scoreApi "github.com/kneu-messenger-pigeon/score-api"
+	"github.com/redis/go-redis/v9"
+	"time"
+)
+
+type AttendanceLoaderInterface interface {
+	load(year int, semester int, disciplineId int, studentId int) AttendanceByLessonType
+}
+
+type AttendanceLoader struct {
+	redis     *redis.Client
+	codec     RedisCodec
+	anomalies *AnomalyCounter
+}
+
+func (loader *AttendanceLoader) load(year int, semester int, disciplineId int, studentId int) AttendanceByLessonType {
+	ctx := context.Background()
+	disciplineLessonsKey := fmt.Sprintf("%d:%d:lessons:%d", year, semester, disciplineId)
+	studentDisciplineScoresKey := fmt.Sprintf("%d:%d:scores:%d:%d", year, semester, studentId, disciplineId)
+
+	rawLessons := loader.redis.HGetAll(ctx, disciplineLessonsKey).Val()
+	lessons := make([]scoreApi.Lesson, 0, len(rawLessons))
+
+	for lessonIdString, lessonValue := range rawLessons {
+		lessonId, err := loader.codec.decodeId(lessonIdString)
+		if err != nil {
+			loader.anomalies.report(disciplineLessonsKey, lessonIdString, err)
+			continue
+		}
+
+		lessonDate, lessonTypeId, err := loader.codec.decodeLessonValue(lessonValue)
+		if err != nil {
+			loader.anomalies.report(disciplineLessonsKey, lessonIdString, err)
+			continue
+		}
+
+		lessons = append(lessons, scoreApi.Lesson{
+			Id:   lessonId,
+			Date: lessonDate,
+			Type: scoreApi.LessonType{Id: lessonTypeId},
+		})
+	}
+
+	absentLessonIds := make(map[int]bool)
+	if len(lessons) != 0 {
+		for field, value := range loader.redis.HGetAll(ctx, studentDisciplineScoresKey).Val() {
+			scoreEntry, err := loader.codec.decodeScoreEntry(field, value)
+			if err != nil {
+				loader.anomalies.report(studentDisciplineScoresKey, field, err)
+			} else if scoreEntry.isAbsent() {
+				absentLessonIds[scoreEntry.LessonId] = true
+			}
+		}
+	}
+
+	return calculateAttendance(lessons, absentLessonIds, time.Now().In(loader.codec.academicLocation()))
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"github.com/go-redis/redismock/v9"
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestAttendanceLoader(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		absentScoreString := strconv.FormatFloat(float64(IsAbsentScoreValue), 'f', -1, 64)
+
+		expectedAttendance := AttendanceByLessonType{
+			1: {
+				TotalLessons: 3,
+				Attended:     2,
+				Absent:       1,
+				Percentage:   float32(2) * 100 / 3,
+				AbsentDates: LessonDates{
+					{Time: time.Date(2023, time.Month(2), 12, 0, 0, 0, 0, defaultAcademicLocation)},
+				},
+			},
+			15: {
+				TotalLessons: 1,
+				Attended:     1,
+				Absent:       0,
+				Percentage:   100,
+				AbsentDates:  LessonDates{},
+			},
+		}
+
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.MatchExpectationsInOrder(true)
+
+		redisMock.ExpectHGetAll("2023:2:lessons:300").SetVal(map[string]string{
+			"245": "2302121",
+			"247": "2302131",
+			"250": "2302141",
+			"255": "23021515",
+		})
+
+		redisMock.ExpectHGetAll("2023:2:scores:1200:300").SetVal(map[string]string{
+			"245:1": absentScoreString,
+			"247:1": "4",
+			"255:2": "10",
+			"199:1": absentScoreString, // score of deleted lesson
+		})
+
+		attendanceLoader := AttendanceLoader{
+			redis: redisClient,
+		}
+
+		actualAttendance := attendanceLoader.load(2023, 2, 300, 1200)
+
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+		assert.Equal(t, expectedAttendance, actualAttendance)
+	})
+
+	t.Run("no_lessons", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.MatchExpectationsInOrder(true)
+
+		redisMock.ExpectHGetAll("2023:2:lessons:300").RedisNil()
+
+		attendanceLoader := AttendanceLoader{
+			redis: redisClient,
+		}
+
+		actualAttendance := attendanceLoader.load(2023, 2, 300, 1200)
+
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+		assert.Empty(t, actualAttendance)
+		assert.Equal(t, NewAttendance(), actualAttendance.total())
+	})
+
+	t.Run("future_lessons", func(t *testing.T) {
+		absentScoreString := strconv.FormatFloat(float64(IsAbsentScoreValue), 'f', -1, 64)
+
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.MatchExpectationsInOrder(true)
+
+		redisMock.ExpectHGetAll("2023:2:lessons:300").SetVal(map[string]string{
+			"245": "2302121",
+			"290": "6812311", // scheduled lesson is not held yet
+		})
+
+		redisMock.ExpectHGetAll("2023:2:scores:1200:300").SetVal(map[string]string{
+			"290:1": absentScoreString,
+		})
+
+		attendanceLoader := AttendanceLoader{
+			redis: redisClient,
+		}
+
+		actualAttendance := attendanceLoader.load(2023, 2, 300, 1200)
+
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+		assert.Equal(t, AttendanceByLessonType{
+			1: {
+				TotalLessons: 1,
+				Attended:     1,
+				Absent:       0,
+				Percentage:   100,
+				AbsentDates:  LessonDates{},
+			},
+		}, actualAttendance)
+	})
+
+	t.Run("malformed_entries", func(t *testing.T) {
+		absentScoreString := strconv.FormatFloat(float64(IsAbsentScoreValue), 'f', -1, 64)
+
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.MatchExpectationsInOrder(true)
+
+		redisMock.ExpectHGetAll("2023:2:lessons:300").SetVal(map[string]string{
+			"245": "2302121",
+			"247": "2302",
+			"x":   "2302131",
+		})
+
+		redisMock.ExpectHGetAll("2023:2:scores:1200:300").SetVal(map[string]string{
+			"245:1": absentScoreString,
+			"2451":  absentScoreString,
+			"247:1": "-",
+		})
+
+		anomalies := &AnomalyCounter{}
+		attendanceLoader := AttendanceLoader{
+			redis:     redisClient,
+			anomalies: anomalies,
+		}
+
+		actualAttendance := attendanceLoader.load(2023, 2, 300, 1200)
+
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+		assert.Equal(t, AttendanceByLessonType{
+			1: {
+				TotalLessons: 1,
+				Attended:     0,
+				Absent:       1,
+				Percentage:   0,
+				AbsentDates: LessonDates{
+					{Time: time.Date(2023, time.Month(2), 12, 0, 0, 0, 0, defaultAcademicLocation)},
+				},
+			},
+		}, actualAttendance)
+		assert.Equal(t, 4, anomalies.getReport().Total)
+	})
+}
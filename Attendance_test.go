@@ -0,0 +1,123 @@
+package main
+
+import (
+	scoreApi "github.com/kneu-messenger-pigeon/score-api"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestCalculateAttendance(t *testing.T) {
+	location, _ := time.LoadLocation("Asia/Tokyo")
+	today := time.Date(2023, time.Month(3), 1, 0, 0, 0, 0, location)
+	yesterday := today.AddDate(0, 0, -1)
+	tomorrow := today.AddDate(0, 0, 1)
+
+	lessons := []scoreApi.Lesson{
+		{Id: 1, Date: yesterday, Type: scoreApi.LessonType{Id: 1}},
+		{Id: 2, Date: today, Type: scoreApi.LessonType{Id: 1}},
+		{Id: 3, Date: tomorrow, Type: scoreApi.LessonType{Id: 1}},
+		{Id: 4, Date: tomorrow, Type: scoreApi.LessonType{Id: 15}},
+	}
+	absentLessonIds := map[int]bool{2: true, 3: true}
+
+	// 08:00 of Tokyo is still yesterday in UTC, the lesson of today is held anyway
+	now := today.Add(time.Hour * 8).In(location)
+
+	assert.Equal(t, AttendanceByLessonType{
+		1: {
+			TotalLessons: 2,
+			Attended:     1,
+			Absent:       1,
+			Percentage:   50,
+			AbsentDates:  LessonDates{{Time: today}},
+		},
+	}, calculateAttendance(lessons, absentLessonIds, now))
+
+	assert.Empty(t, calculateAttendance(lessons, absentLessonIds, yesterday.Add(-time.Second)))
+}
+
+func TestMakeStudentAttendance(t *testing.T) {
+	lessonTypes := GetTestLessonTypes()
+
+	firstDate := time.Date(2023, time.Month(2), 12, 0, 0, 0, 0, defaultAcademicLocation)
+	secondDate := time.Date(2023, time.Month(3), 1, 0, 0, 0, 0, defaultAcademicLocation)
+
+	disciplinesAttendance := []AttendanceByLessonType{
+		{
+			1: {
+				TotalLessons: 4,
+				Attended:     3,
+				Absent:       1,
+				Percentage:   75,
+				AbsentDates:  LessonDates{{Time: secondDate}},
+			},
+			15: {
+				TotalLessons: 1,
+				Attended:     1,
+				Percentage:   100,
+				AbsentDates:  LessonDates{},
+			},
+		},
+		{
+			1: {
+				TotalLessons: 4,
+				Attended:     3,
+				Absent:       1,
+				Percentage:   75,
+				AbsentDates:  LessonDates{{Time: firstDate}},
+			},
+			// lesson type which is not in the lessonTypes map
+			7: {
+				TotalLessons: 1,
+				Attended:     1,
+				Percentage:   100,
+				AbsentDates:  LessonDates{},
+			},
+		},
+	}
+
+	expectedStudentAttendance := StudentAttendance{
+		Attendance: Attendance{
+			TotalLessons: 10,
+			Attended:     8,
+			Absent:       2,
+			Percentage:   80,
+			AbsentDates:  LessonDates{{Time: firstDate}, {Time: secondDate}},
+		},
+		LessonTypes: []LessonTypeAttendance{
+			{
+				LessonType: lessonTypes[1],
+				Attendance: Attendance{
+					TotalLessons: 8,
+					Attended:     6,
+					Absent:       2,
+					Percentage:   75,
+					AbsentDates:  LessonDates{{Time: firstDate}, {Time: secondDate}},
+				},
+			},
+			{
+				LessonType: scoreApi.LessonType{Id: 7},
+				Attendance: Attendance{
+					TotalLessons: 1,
+					Attended:     1,
+					Percentage:   100,
+					AbsentDates:  LessonDates{},
+				},
+			},
+			{
+				LessonType: lessonTypes[15],
+				Attendance: Attendance{
+					TotalLessons: 1,
+					Attended:     1,
+					Percentage:   100,
+					AbsentDates:  LessonDates{},
+				},
+			},
+		},
+	}
+
+	actualStudentAttendance := makeStudentAttendance(disciplinesAttendance, lessonTypes)
+
+	assert.Equal(t, expectedStudentAttendance, actualStudentAttendance)
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	scoreApi "github.com/kneu-messenger-pigeon/score-api"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const icalDateLayout = "20060102"
+const icalDateTimeLayout = "20060102T150405Z"
+const icalMaxLineLength = 75
+const icalUidDomain = "score-storage-api.kneu-messenger-pigeon"
+
+// writeLessonsCalendar renders lessons as all-day events of iCalendar (RFC 5545) feed.
+// UID is built from year, semester, discipline, lesson and student ids,
+// so calendar apps update already imported events instead of duplicating them.
+func writeLessonsCalendar(writer io.Writer, studentId int, disciplines []DisciplineLessons, now time.Time) error {
+	buffer := &bytes.Buffer{}
+
+	writeIcalLine(buffer, "BEGIN:VCALENDAR")
+	writeIcalLine(buffer, "VERSION:2.0")
+	writeIcalLine(buffer, "PRODID:-//kneu-messenger-pigeon//score-storage-api//UK")
+	writeIcalLine(buffer, "CALSCALE:GREGORIAN")
+	writeIcalLine(buffer, "METHOD:PUBLISH")
+
+	dtStamp := now.UTC().Format(icalDateTimeLayout)
+
+	for _, discipline := range disciplines {
+		for _, lesson := range discipline.Lessons {
+			if lesson.Lesson.Date.IsZero() {
+				continue
+			}
+
+			writeIcalLine(buffer, "BEGIN:VEVENT")
+			writeIcalLine(buffer, fmt.Sprintf(
+				"UID:%d-%d-%d-%d-%d@%s",
+				discipline.Year, discipline.Semester, discipline.Discipline.Id, lesson.Lesson.Id, studentId, icalUidDomain,
+			))
+			writeIcalLine(buffer, "DTSTAMP:"+dtStamp)
+			writeIcalLine(buffer, "DTSTART;VALUE=DATE:"+lesson.Lesson.Date.Format(icalDateLayout))
+			writeIcalLine(buffer, "DTEND;VALUE=DATE:"+lesson.Lesson.Date.AddDate(0, 0, 1).Format(icalDateLayout))
+			writeIcalLine(buffer, "SUMMARY:"+escapeIcalText(makeLessonEventSummary(discipline.Discipline, lesson.Lesson)))
+
+			description := makeLessonEventDescription(lesson)
+			if description != "" {
+				writeIcalLine(buffer, "DESCRIPTION:"+escapeIcalText(description))
+			}
+
+			writeIcalLine(buffer, "TRANSP:TRANSPARENT")
+			writeIcalLine(buffer, "END:VEVENT")
+		}
+	}
+
+	writeIcalLine(buffer, "END:VCALENDAR")
+
+	_, err := buffer.WriteTo(writer)
+	return err
+}
+
+func makeLessonEventSummary(discipline scoreApi.Discipline, lesson scoreApi.Lesson) string {
+	lessonTypeName := lesson.Type.LongName
+	if lessonTypeName == "" {
+		lessonTypeName = lesson.Type.ShortName
+	}
+
+	if lessonTypeName == "" {
+		return discipline.Name
+	}
+
+	return discipline.Name + " — " + lessonTypeName
+}
+
+func makeLessonEventDescription(score Score) string {
+	lines := make([]string, 0, 2)
+
+	if score.IsAbsent {
+		lines = append(lines, "Відсутність")
+	}
+
+	if score.FirstScore != nil {
+		lines = append(lines, "Перша оцінка: "+formatUkrainianNumber(*score.FirstScore))
+	}
+
+	if score.SecondScore != nil {
+		lines = append(lines, "Друга оцінка: "+formatUkrainianNumber(*score.SecondScore))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func escapeIcalText(text string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(text)
+}
+
+// writeIcalLine folds content lines longer than 75 octets without breaking multibyte characters
+func writeIcalLine(buffer *bytes.Buffer, line string) {
+	lineLength := 0
+	for _, r := range line {
+		runeLength := utf8.RuneLen(r)
+		if lineLength+runeLength > icalMaxLineLength {
+			buffer.WriteString("\r\n ")
+			lineLength = 1
+		}
+
+		buffer.WriteRune(r)
+		lineLength += runeLength
+	}
+
+	buffer.WriteString("\r\n")
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	scoreApi "github.com/kneu-messenger-pigeon/score-api"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteLessonsCalendar(t *testing.T) {
+	lessonTypes := GetTestLessonTypes()
+
+	disciplines := []DisciplineLessons{
+		{
+			Year:     2026,
+			Semester: 1,
+			Discipline: scoreApi.Discipline{
+				Id:   199,
+				Name: "Гроші, кредит; банки",
+			},
+			Lessons: []Score{
+				{
+					Score: scoreApi.Score{
+						Lesson: scoreApi.Lesson{
+							Id:   245,
+							Date: time.Date(2023, time.Month(2), 12, 0, 0, 0, 0, defaultAcademicLocation),
+							Type: lessonTypes[1],
+						},
+						FirstScore:  floatPointer(4.5),
+						SecondScore: floatPointer(2),
+					},
+				},
+				{
+					Score: scoreApi.Score{
+						Lesson: scoreApi.Lesson{
+							Id:   255,
+							Date: time.Date(2023, time.Month(2), 28, 0, 0, 0, 0, defaultAcademicLocation),
+							Type: lessonTypes[15],
+						},
+					},
+				},
+				{
+					Score: scoreApi.Score{
+						// lesson without date
+						Lesson: scoreApi.Lesson{
+							Id: 260,
+						},
+						IsAbsent: true,
+					},
+				},
+			},
+		},
+	}
+
+	expectedCalendar := "BEGIN:VCALENDAR\r\n" +
+		"VERSION:2.0\r\n" +
+		"PRODID:-//kneu-messenger-pigeon//score-storage-api//UK\r\n" +
+		"CALSCALE:GREGORIAN\r\n" +
+		"METHOD:PUBLISH\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"UID:2026-1-199-245-1200@score-storage-api.kneu-messenger-pigeon\r\n" +
+		"DTSTAMP:20260110T100000Z\r\n" +
+		"DTSTART;VALUE=DATE:20230212\r\n" +
+		"DTEND;VALUE=DATE:20230213\r\n" +
+		"SUMMARY:Гроші\\, кредит\\; банки — Практичне за\r\n" +
+		" н.\r\n" +
+		"DESCRIPTION:Перша оцінка: 4\\,5\\nДруга оцінка: 2\r\n" +
+		"TRANSP:TRANSPARENT\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"UID:2026-1-199-255-1200@score-storage-api.kneu-messenger-pigeon\r\n" +
+		"DTSTAMP:20260110T100000Z\r\n" +
+		"DTSTART;VALUE=DATE:20230228\r\n" +
+		"DTEND;VALUE=DATE:20230301\r\n" +
+		"SUMMARY:Гроші\\, кредит\\; банки — Модульний ко\r\n" +
+		" нтроль.\r\n" +
+		"TRANSP:TRANSPARENT\r\n" +
+		"END:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+
+	buffer := &bytes.Buffer{}
+	err := writeLessonsCalendar(buffer, 1200, disciplines, time.Date(2026, time.Month(1), 10, 10, 0, 0, 0, time.UTC))
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedCalendar, buffer.String())
+}
+
+func TestWriteIcalLine(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	writeIcalLine(buffer, "SUMMARY:"+strings.Repeat("Ї", 40))
+
+	lines := strings.Split(strings.TrimSuffix(buffer.String(), "\r\n"), "\r\n")
+
+	assert.Equal(t, []string{
+		"SUMMARY:" + strings.Repeat("Ї", 33),
+		" " + strings.Repeat("Ї", 7),
+	}, lines)
+
+	for _, line := range lines {
+		assert.LessOrEqual(t, len(line), icalMaxLineLength)
+	}
+}
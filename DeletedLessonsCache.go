@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	scoreApi "github.com/kneu-messenger-pigeon/score-api"
+	"sync"
+	"time"
+)
+
+// DeletedLessonsCacheTTL - deleted lessons index of discipline is reused for this time instead of loading it
+// on every request, lessons events of the discipline invalidate it earlier
+const DeletedLessonsCacheTTL = time.Minute * 5
+
+type deletedLessonsCacheEntry struct {
+	lessons   map[int]scoreApi.Lesson
+	expiresAt time.Time
+}
+
+// DeletedLessonsCache keeps deleted lessons of disciplines by `{year}:{semester}:{disciplineId}`.
+// Nil cache never hits, so storages created without it load deleted lessons every time.
+type DeletedLessonsCache struct {
+	mutex   sync.Mutex
+	ttl     time.Duration
+	entries map[string]deletedLessonsCacheEntry
+}
+
+func (cache *DeletedLessonsCache) get(year int, semester int, disciplineId int, now time.Time) (map[int]scoreApi.Lesson, bool) {
+	if cache == nil {
+		return nil, false
+	}
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	entry, exists := cache.entries[makeDeletedLessonsCacheKey(year, semester, disciplineId)]
+	if !exists || !now.Before(entry.expiresAt) {
+		return nil, false
+	}
+
+	return entry.lessons, true
+}
+
+func (cache *DeletedLessonsCache) set(
+	year int, semester int, disciplineId int, lessons map[int]scoreApi.Lesson, now time.Time,
+) {
+	if cache == nil {
+		return
+	}
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	for key, entry := range cache.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(cache.entries, key)
+		}
+	}
+
+	cache.entries[makeDeletedLessonsCacheKey(year, semester, disciplineId)] = deletedLessonsCacheEntry{
+		lessons:   lessons,
+		expiresAt: now.Add(cache.ttl),
+	}
+}
+
+// invalidate drops deleted lessons of the discipline, so the next request loads them again
+func (cache *DeletedLessonsCache) invalidate(year int, semester int, disciplineId int) {
+	if cache == nil {
+		return
+	}
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	delete(cache.entries, makeDeletedLessonsCacheKey(year, semester, disciplineId))
+}
+
+func makeDeletedLessonsCacheKey(year int, semester int, disciplineId int) string {
+	return fmt.Sprintf("%d:%d:%d", year, semester, disciplineId)
+}
+
+func NewDeletedLessonsCache(ttl time.Duration) *DeletedLessonsCache {
+	return &DeletedLessonsCache{
+		ttl:     ttl,
+		entries: make(map[string]deletedLessonsCacheEntry),
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	scoreApi "github.com/kneu-messenger-pigeon/score-api"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestDeletedLessonsCache(t *testing.T) {
+	now := time.Date(2026, time.Month(3), 15, 12, 0, 0, 0, time.UTC)
+	lessons := map[int]scoreApi.Lesson{240: {Id: 240}}
+
+	t.Run("hit_until_expired", func(t *testing.T) {
+		cache := NewDeletedLessonsCache(time.Minute)
+
+		_, hit := cache.get(2026, 1, 199, now)
+		assert.False(t, hit)
+
+		cache.set(2026, 1, 199, lessons, now)
+
+		actual, hit := cache.get(2026, 1, 199, now.Add(time.Second*59))
+		assert.True(t, hit)
+		assert.Equal(t, lessons, actual)
+
+		_, hit = cache.get(2026, 2, 199, now)
+		assert.False(t, hit)
+
+		_, hit = cache.get(2026, 1, 199, now.Add(time.Minute))
+		assert.False(t, hit)
+	})
+
+	t.Run("expired_entries_removed", func(t *testing.T) {
+		cache := NewDeletedLessonsCache(time.Minute)
+
+		cache.set(2026, 1, 199, lessons, now)
+		cache.set(2026, 1, 200, lessons, now.Add(time.Minute))
+
+		assert.Len(t, cache.entries, 1)
+		assert.Contains(t, cache.entries, "2026:1:200")
+	})
+
+	t.Run("invalidate", func(t *testing.T) {
+		cache := NewDeletedLessonsCache(time.Minute)
+
+		cache.set(2026, 1, 199, lessons, now)
+		cache.set(2026, 1, 200, lessons, now)
+		cache.invalidate(2026, 1, 199)
+
+		_, hit := cache.get(2026, 1, 199, now)
+		assert.False(t, hit)
+
+		_, hit = cache.get(2026, 1, 200, now)
+		assert.True(t, hit)
+	})
+
+	t.Run("nil_cache", func(t *testing.T) {
+		var cache *DeletedLessonsCache
+
+		cache.set(2026, 1, 199, lessons, now)
+		cache.invalidate(2026, 1, 199)
+		_, hit := cache.get(2026, 1, 199, now)
+
+		assert.False(t, hit)
+	})
+}
@@ -0,0 +1,11 @@
+package main
+
+import scoreApi "github.com/kneu-messenger-pigeon/score-api"
+
+// DisciplineLessons - all lessons of discipline, each with student score (if any)
+type DisciplineLessons struct {
+	Year       int                 `json:"year"`
+	Semester   int                 `json:"semester"`
+	Discipline scoreApi.Discipline `json:"discipline"`
+	Lessons    []Score             `json:"lessons"`
+}
@@ -0,0 +1,52 @@
+package main
+
+import scoreApi "github.com/kneu-messenger-pigeon/score-api"
+
+// DisciplineRating - every student of discipline totals ordered from the best total
+type DisciplineRating struct {
+	Discipline scoreApi.Discipline `json:"discipline"`
+	Year       int                 `json:"year"`
+	Semester   int                 `json:"semester"`
+	Students   []StudentTotal      `json:"students"`
+}
+
+type StudentTotal struct {
+	StudentId int     `json:"studentId"`
+	Total     float32 `json:"total"`
+	Rating    int     `json:"rating"`
+}
+
+// makeStudentTotals calculates rating position the same way as ScoreRatingLoader, including zero totals mode.
+// Totals have to be ordered from the greatest.
+func (mode ZeroTotalsMode) makeStudentTotals(studentIds []int, totals []float32) []StudentTotal {
+	counts := RatingCounts{
+		Students: len(totals),
+	}
+	for _, total := range totals {
+		if total > 0 {
+			counts.NonZero++
+		}
+	}
+
+	studentTotals := make([]StudentTotal, len(studentIds))
+	for index, studentId := range studentIds {
+		if index == 0 || totals[index-1] != totals[index] {
+			counts.Greater = index
+			counts.Tied = 0
+			for _, total := range totals[index:] {
+				if total != totals[index] {
+					break
+				}
+				counts.Tied++
+			}
+		}
+
+		studentTotals[index] = StudentTotal{
+			StudentId: studentId,
+			Total:     totals[index],
+			Rating:    mode.makeRating(totals[index], 0, 0, counts).Rating,
+		}
+	}
+
+	return studentTotals
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestMakeStudentTotals(t *testing.T) {
+	assert.Equal(t, []StudentTotal{}, ZeroTotalsLast.makeStudentTotals([]int{}, []float32{}))
+
+	assert.Equal(t, []StudentTotal{
+		{StudentId: 10, Total: 3, Rating: 1},
+		{StudentId: 11, Total: 3, Rating: 1},
+		{StudentId: 12, Total: 1, Rating: 3},
+		{StudentId: 13, Total: 0, Rating: 5},
+		{StudentId: 14, Total: 0, Rating: 5},
+	}, ZeroTotalsLast.makeStudentTotals([]int{10, 11, 12, 13, 14}, []float32{3, 3, 1, 0, 0}))
+
+	assert.Equal(t, []StudentTotal{
+		{StudentId: 10, Total: 3, Rating: 1},
+		{StudentId: 13, Total: 0, Rating: 2},
+		{StudentId: 14, Total: 0, Rating: 2},
+	}, ZeroTotalsShared.makeStudentTotals([]int{10, 13, 14}, []float32{3, 0, 0}))
+}
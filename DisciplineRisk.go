@@ -0,0 +1,203 @@
+package main
+
+import (
+	"github.com/gin-gonic/gin"
+	scoreApi "github.com/kneu-messenger-pigeon/score-api"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+type RiskLevel string
+
+const (
+	RiskNone   RiskLevel = "none"
+	RiskLow    RiskLevel = "low"
+	RiskMedium RiskLevel = "medium"
+	RiskHigh   RiskLevel = "high"
+)
+
+const (
+	RiskReasonProjectedBelowAdmission = "projected_below_admission"
+	RiskReasonTotalBelowAdmission     = "total_below_admission"
+	RiskReasonHighAbsenceRatio        = "high_absence_ratio"
+	RiskReasonProjectedBelowPassing   = "projected_below_passing"
+)
+
+const DefaultAdmissionTotal = float32(35)
+const DefaultAbsenceRiskRatio = float32(0.3)
+
+var riskLevelSeverity = map[RiskLevel]int{
+	RiskNone:   0,
+	RiskLow:    1,
+	RiskMedium: 2,
+	RiskHigh:   3,
+}
+
+// DisciplineRisk - exam admission forecast of the student in the discipline. ProjectedTotal assumes the student
+// keeps the average per graded lesson on remaining lessons of the types the student was graded on, e.g. not lectures.
+type DisciplineRisk struct {
+	Level            RiskLevel `json:"level"`
+	Reasons          []string  `json:"reasons"`
+	Total            float32   `json:"total"`
+	ProjectedTotal   float32   `json:"projectedTotal"`
+	AdmissionTotal   float32   `json:"admissionTotal"`
+	AveragePerLesson float32   `json:"averagePerLesson"`
+	GradedLessons    int       `json:"gradedLessons"`
+	HeldLessons      int       `json:"heldLessons"`
+	RemainingLessons int       `json:"remainingLessons"`
+	RemainingGraded  int       `json:"remainingGradedLessons"`
+	AbsenceRatio     float32   `json:"absenceRatio"`
+}
+
+type DisciplineRiskResult struct {
+	Discipline scoreApi.Discipline `json:"discipline"`
+	Risk       DisciplineRisk      `json:"risk"`
+}
+
+type StudentRisk struct {
+	StudentId int            `json:"studentId"`
+	Risk      DisciplineRisk `json:"risk"`
+}
+
+// DisciplineAtRisk - students of the discipline with risk level above none, the most endangered first
+type DisciplineAtRisk struct {
+	Discipline scoreApi.Discipline `json:"discipline"`
+	Year       int                 `json:"year"`
+	Semester   int                 `json:"semester"`
+	Students   []StudentRisk       `json:"students"`
+}
+
+// assessDisciplineRisk forecasts total by actual lessons and student score entries.
+// Lessons after today are remaining, others are held. Scores and absences on deleted lessons are ignored.
+// Level is high when projected total is below admission, medium when current total is below admission
+// or absence ratio reaches absenceRiskRatio, low when projected total is below passingTotal.
+func (rules AcademicRules) assessDisciplineRisk(
+	total float32, scoreEntries []ScoreEntry, lessons map[int]scoreApi.Lesson, now time.Time,
+) DisciplineRisk {
+	risk := DisciplineRisk{
+		Level:          RiskNone,
+		Reasons:        make([]string, 0),
+		Total:          total,
+		AdmissionTotal: rules.admissionTotal,
+	}
+
+	nowDate := now.In(rules.academicLocation())
+	today := time.Date(nowDate.Year(), nowDate.Month(), nowDate.Day(), 0, 0, 0, 0, rules.academicLocation())
+
+	var scoresSum float32
+	gradedLessonIds := make(map[int]bool)
+	gradedLessonTypeIds := make(map[int]bool)
+	absentLessonIds := make(map[int]bool)
+	for _, scoreEntry := range scoreEntries {
+		lesson, isActual := lessons[scoreEntry.LessonId]
+		if !isActual {
+			continue
+		}
+
+		if !scoreEntry.isAbsent() {
+			scoresSum += scoreEntry.Value
+			gradedLessonIds[scoreEntry.LessonId] = true
+			gradedLessonTypeIds[lesson.Type.Id] = true
+		} else if !lesson.Date.After(today) {
+			absentLessonIds[scoreEntry.LessonId] = true
+		}
+	}
+
+	for _, lesson := range lessons {
+		if !lesson.Date.After(today) {
+			risk.HeldLessons++
+		} else {
+			risk.RemainingLessons++
+			if gradedLessonTypeIds[lesson.Type.Id] {
+				risk.RemainingGraded++
+			}
+		}
+	}
+
+	risk.GradedLessons = len(gradedLessonIds)
+	if risk.GradedLessons != 0 {
+		risk.AveragePerLesson = roundRiskValue(scoresSum/float32(risk.GradedLessons), 100)
+	}
+
+	if risk.HeldLessons != 0 {
+		risk.AbsenceRatio = roundRiskValue(float32(len(absentLessonIds))/float32(risk.HeldLessons), 100)
+	}
+
+	risk.ProjectedTotal = roundRiskValue(total+risk.AveragePerLesson*float32(risk.RemainingGraded), 10)
+
+	if risk.ProjectedTotal < rules.admissionTotal {
+		risk.raise(RiskHigh, RiskReasonProjectedBelowAdmission)
+	}
+
+	if total < rules.admissionTotal {
+		risk.raise(RiskMedium, RiskReasonTotalBelowAdmission)
+	}
+
+	if risk.HeldLessons != 0 && risk.AbsenceRatio >= rules.absenceRiskRatio {
+		risk.raise(RiskMedium, RiskReasonHighAbsenceRatio)
+	}
+
+	if risk.ProjectedTotal < rules.passingTotal {
+		risk.raise(RiskLow, RiskReasonProjectedBelowPassing)
+	}
+
+	return risk
+}
+
+// raise adds reason and keeps the most severe level
+func (risk *DisciplineRisk) raise(level RiskLevel, reason string) {
+	risk.Reasons = append(risk.Reasons, reason)
+	if riskLevelSeverity[level] > riskLevelSeverity[risk.Level] {
+		risk.Level = level
+	}
+}
+
+// sortStudentRisks orders by level from the most severe, then by projected total and student id
+func sortStudentRisks(studentRisks []StudentRisk) {
+	sort.SliceStable(studentRisks, func(i, j int) bool {
+		first, second := studentRisks[i], studentRisks[j]
+		if first.Risk.Level != second.Risk.Level {
+			return riskLevelSeverity[first.Risk.Level] > riskLevelSeverity[second.Risk.Level]
+		}
+
+		if first.Risk.ProjectedTotal != second.Risk.ProjectedTotal {
+			return first.Risk.ProjectedTotal < second.Risk.ProjectedTotal
+		}
+
+		return first.StudentId < second.StudentId
+	})
+}
+
+func roundRiskValue(value float32, precision float64) float32 {
+	return float32(math.Round(float64(value)*precision) / precision)
+}
+
+// parseRiskFlag reads optional `risk` query parameter of the discipline list
+func parseRiskFlag(c *gin.Context) (bool, error) {
+	if c.Query("risk") == "" {
+		return false, nil
+	}
+
+	withRisk, err := strconv.ParseBool(c.Query("risk"))
+	if err != nil {
+		return false, MessageError{Format: messageIncorrectParameter, Args: []interface{}{"risk", c.Query("risk")}}
+	}
+
+	return withRisk, nil
+}
+
+// attachRisks sets risk block of the results by discipline id
+func attachRisks(results DisciplineScoreResults, risks []DisciplineRiskResult) {
+	disciplineRisks := make(map[int]DisciplineRisk, len(risks))
+	for _, risk := range risks {
+		disciplineRisks[risk.Discipline.Id] = risk.Risk
+	}
+
+	for index := range results {
+		if risk, exists := disciplineRisks[results[index].Discipline.Id]; exists {
+			results[index].Risk = &risk
+		}
+	}
+}
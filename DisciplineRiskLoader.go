@@ -0,0 +1,228 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	scoreApi "github.com/kneu-messenger-pigeon/score-api"
+	"github.com/redis/go-redis/v9"
+	"strconv"
+	"time"
+)
+
+type DisciplineRiskLoaderInterface interface {
+	getDisciplineRisk(studentId int, disciplineId int) (DisciplineRiskResult, error)
+	getStudentDisciplinesRisks(studentId int) ([]DisciplineRiskResult, error)
+	getDisciplineAtRiskStudents(disciplineId int) (DisciplineAtRisk, error)
+}
+
+type DisciplineRiskLoader struct {
+	storage *Storage
+}
+
+func (loader *DisciplineRiskLoader) getDisciplineRisk(studentId int, disciplineId int) (DisciplineRiskResult, error) {
+	semester, err := loader.storage.getSemesterByDisciplineId(disciplineId)
+	if err != nil || semester == 0 {
+		return DisciplineRiskResult{}, err
+	}
+
+	risk, err := loader.loadDisciplineRisk(semester, disciplineId, studentId, time.Now())
+	if err != nil {
+		return DisciplineRiskResult{}, err
+	}
+
+	return DisciplineRiskResult{
+		Discipline: scoreApi.Discipline{
+			Id:   disciplineId,
+			Name: loader.storage.getDisciplineName(disciplineId),
+		},
+		Risk: risk,
+	}, nil
+}
+
+func (loader *DisciplineRiskLoader) getStudentDisciplinesRisks(studentId int) ([]DisciplineRiskResult, error) {
+	disciplines, err := loader.storage.getActualStudentDisciplines(studentId)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	results := make([]DisciplineRiskResult, len(disciplines))
+	for index, discipline := range disciplines {
+		results[index].Risk, err = loader.loadDisciplineRisk(discipline.Semester, discipline.DisciplineId, studentId, now)
+		if err != nil {
+			return nil, err
+		}
+
+		results[index].Discipline = scoreApi.Discipline{
+			Id:   discipline.DisciplineId,
+			Name: loader.storage.getDisciplineName(discipline.DisciplineId),
+		}
+	}
+
+	return results, nil
+}
+
+func (loader *DisciplineRiskLoader) loadDisciplineRisk(semester int, disciplineId int, studentId int, now time.Time) (DisciplineRisk, error) {
+	ctx := context.Background()
+	disciplineTotalsKey := fmt.Sprintf("%d:%d:totals:%d", loader.storage.year, semester, disciplineId)
+	total, err := loader.storage.redis.ZScore(ctx, disciplineTotalsKey, strconv.Itoa(studentId)).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return DisciplineRisk{}, err
+	}
+
+	studentDisciplineScoresKey := fmt.Sprintf("%d:%d:scores:%d:%d", loader.storage.year, semester, studentId, disciplineId)
+	scoreEntries := loader.storage.decodeScoreEntries(
+		studentDisciplineScoresKey, loader.storage.redis.HGetAll(ctx, studentDisciplineScoresKey).Val(),
+	)
+
+	return loader.storage.rules.assessDisciplineRisk(float32(total), scoreEntries, loader.storage.getDisciplineLessons(semester, disciplineId), now), nil
+}
+
+// getDisciplineAtRiskStudents assesses every student from the discipline totals
+// and enrolled students of the same groups who have no score at all
+func (loader *DisciplineRiskLoader) getDisciplineAtRiskStudents(disciplineId int) (DisciplineAtRisk, error) {
+	semester, err := loader.storage.getSemesterByDisciplineId(disciplineId)
+	if err != nil || semester == 0 {
+		return DisciplineAtRisk{}, err
+	}
+
+	ctx := context.Background()
+	disciplineTotalsKey := fmt.Sprintf("%d:%d:totals:%d", loader.storage.year, semester, disciplineId)
+	members, err := loader.storage.redis.ZRangeWithScores(ctx, disciplineTotalsKey, 0, -1).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return DisciplineAtRisk{}, err
+	}
+
+	studentIds := make([]int, 0, len(members))
+	totals := make([]float32, 0, len(members))
+	for _, member := range members {
+		studentIdString, _ := member.Member.(string)
+		studentId, err := loader.storage.codec.decodeId(studentIdString)
+		if err != nil {
+			loader.storage.anomalies.report(disciplineTotalsKey, studentIdString, err)
+			continue
+		}
+
+		studentIds = append(studentIds, studentId)
+		totals = append(totals, float32(member.Score))
+	}
+
+	lessons := loader.storage.getDisciplineLessons(semester, disciplineId)
+
+	pipeline := loader.storage.redis.Pipeline()
+	scoresKeys := make([]string, len(studentIds))
+	rawScoresCommands := make([]*redis.MapStringStringCmd, len(studentIds))
+	for index, studentId := range studentIds {
+		scoresKeys[index] = fmt.Sprintf("%d:%d:scores:%d:%d", loader.storage.year, semester, studentId, disciplineId)
+		rawScoresCommands[index] = pipeline.HGetAll(ctx, scoresKeys[index])
+	}
+
+	if len(rawScoresCommands) != 0 {
+		if _, err = pipeline.Exec(ctx); err != nil {
+			return DisciplineAtRisk{}, err
+		}
+	}
+
+	unscoredStudentIds, err := loader.getUnscoredEnrolledStudentIds(semester, disciplineId, studentIds)
+	if err != nil {
+		return DisciplineAtRisk{}, err
+	}
+
+	now := time.Now()
+	studentRisks := make([]StudentRisk, 0)
+	for index, rawScoresCommand := range rawScoresCommands {
+		scoreEntries := loader.storage.decodeScoreEntries(scoresKeys[index], rawScoresCommand.Val())
+		risk := loader.storage.rules.assessDisciplineRisk(totals[index], scoreEntries, lessons, now)
+		if risk.Level != RiskNone {
+			studentRisks = append(studentRisks, StudentRisk{StudentId: studentIds[index], Risk: risk})
+		}
+	}
+
+	for _, studentId := range unscoredStudentIds {
+		risk := loader.storage.rules.assessDisciplineRisk(0, []ScoreEntry{}, lessons, now)
+		studentRisks = append(studentRisks, StudentRisk{StudentId: studentId, Risk: risk})
+	}
+
+	sortStudentRisks(studentRisks)
+
+	return DisciplineAtRisk{
+		Discipline: scoreApi.Discipline{
+			Id:   disciplineId,
+			Name: loader.storage.getDisciplineName(disciplineId),
+		},
+		Year:     loader.storage.year,
+		Semester: semester,
+		Students: studentRisks,
+	}, nil
+}
+
+// getUnscoredEnrolledStudentIds finds students enrolled in the discipline without any score.
+// There is no index of discipline students, so candidates are taken from the groups of scored students.
+func (loader *DisciplineRiskLoader) getUnscoredEnrolledStudentIds(semester int, disciplineId int, scoredStudentIds []int) ([]int, error) {
+	if len(scoredStudentIds) == 0 {
+		return []int{}, nil
+	}
+
+	ctx := context.Background()
+	scoredStudents := make(map[int]bool, len(scoredStudentIds))
+	pipeline := loader.storage.redis.Pipeline()
+	studentGroupCommands := make([]*redis.StringCmd, len(scoredStudentIds))
+	for index, studentId := range scoredStudentIds {
+		scoredStudents[studentId] = true
+		studentGroupCommands[index] = pipeline.Get(ctx, fmt.Sprintf("%d:student_group:%d", loader.storage.year, studentId))
+	}
+
+	if _, err := pipeline.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	groupIds := make([]int, 0)
+	seenGroupIds := make(map[int]bool)
+	for _, studentGroupCommand := range studentGroupCommands {
+		groupId, err := loader.storage.codec.decodeId(studentGroupCommand.Val())
+		if err == nil && !seenGroupIds[groupId] {
+			seenGroupIds[groupId] = true
+			groupIds = append(groupIds, groupId)
+		}
+	}
+
+	candidateIds := make([]int, 0)
+	for _, groupId := range groupIds {
+		groupStudentIds, err := loader.storage.getGroupStudentIds(groupId)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, studentId := range groupStudentIds {
+			if !scoredStudents[studentId] {
+				scoredStudents[studentId] = true
+				candidateIds = append(candidateIds, studentId)
+			}
+		}
+	}
+
+	if len(candidateIds) == 0 {
+		return candidateIds, nil
+	}
+
+	pipeline = loader.storage.redis.Pipeline()
+	enrolledCommands := make([]*redis.BoolCmd, len(candidateIds))
+	for index, studentId := range candidateIds {
+		studentDisciplinesKey := fmt.Sprintf("%d:%d:student_disciplines:%d", loader.storage.year, semester, studentId)
+		enrolledCommands[index] = pipeline.SIsMember(ctx, studentDisciplinesKey, strconv.Itoa(disciplineId))
+	}
+
+	if _, err := pipeline.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	unscoredStudentIds := make([]int, 0)
+	for index, enrolledCommand := range enrolledCommands {
+		if enrolledCommand.Val() {
+			unscoredStudentIds = append(unscoredStudentIds, candidateIds[index])
+		}
+	}
+
+	return unscoredStudentIds, nil
+}
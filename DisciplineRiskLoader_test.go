@@ -0,0 +1,319 @@
+package main
+
+import (
+	"github.com/go-redis/redismock/v9"
+	scoreApi "github.com/kneu-messenger-pigeon/score-api"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDisciplineRiskLoaderGetDisciplineRisk(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.MatchExpectationsInOrder(true)
+
+		redisMock.ExpectGet("2026:discipline_semester_updated_at:199").SetVal("21676152800")
+		redisMock.ExpectZScore("2026:2:totals:199", "1200").SetVal(10)
+		redisMock.ExpectHGetAll("2026:2:scores:1200:199").SetVal(map[string]string{
+			"245:1": "5",
+			"246:1": "5",
+			"247:1": "-999999",
+			"999:1": "20", // score of deleted lesson
+		})
+		redisMock.ExpectHGetAll("2026:2:lessons:199").SetVal(map[string]string{
+			"245": "2302121",
+			"246": "2302131",
+			"247": "2302141",
+			"248": "2302151",
+			"249": "4502131",
+			"250": "4502141",
+		})
+		redisMock.ExpectHGet("2026:discipline:199", "name").SetVal("Капітал!")
+
+		storage := Storage{
+			redis: redisClient,
+			year:  2026,
+			rules: getTestAcademicRules(),
+		}
+		loader := DisciplineRiskLoader{storage: &storage}
+
+		disciplineRisk, err := loader.getDisciplineRisk(1200, 199)
+
+		assert.NoError(t, err)
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+		assert.Equal(t, DisciplineRiskResult{
+			Discipline: scoreApi.Discipline{
+				Id:   199,
+				Name: "Капітал!",
+			},
+			Risk: DisciplineRisk{
+				Level: RiskHigh,
+				Reasons: []string{
+					RiskReasonProjectedBelowAdmission,
+					RiskReasonTotalBelowAdmission,
+					RiskReasonProjectedBelowPassing,
+				},
+				Total:            10,
+				ProjectedTotal:   20,
+				AdmissionTotal:   DefaultAdmissionTotal,
+				AveragePerLesson: 5,
+				GradedLessons:    2,
+				HeldLessons:      4,
+				RemainingLessons: 2,
+				RemainingGraded:  2,
+				AbsenceRatio:     0.25,
+			},
+		}, disciplineRisk)
+	})
+
+	t.Run("no_total", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.ExpectGet("2026:discipline_semester_updated_at:199").SetVal("21676152800")
+		redisMock.ExpectZScore("2026:2:totals:199", "1200").RedisNil()
+		redisMock.ExpectHGetAll("2026:2:scores:1200:199").SetVal(map[string]string{})
+		redisMock.ExpectHGetAll("2026:2:lessons:199").SetVal(map[string]string{})
+		redisMock.ExpectHGet("2026:discipline:199", "name").SetVal("Капітал!")
+
+		storage := Storage{
+			redis: redisClient,
+			year:  2026,
+			rules: getTestAcademicRules(),
+		}
+		loader := DisciplineRiskLoader{storage: &storage}
+
+		disciplineRisk, err := loader.getDisciplineRisk(1200, 199)
+
+		assert.NoError(t, err)
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+		assert.Equal(t, RiskHigh, disciplineRisk.Risk.Level)
+		assert.Equal(t, float32(0), disciplineRisk.Risk.Total)
+	})
+
+	t.Run("discipline_not_exists", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.ExpectGet("2026:discipline_semester_updated_at:199").RedisNil()
+
+		storage := Storage{
+			redis: redisClient,
+			year:  2026,
+			rules: getTestAcademicRules(),
+		}
+		loader := DisciplineRiskLoader{storage: &storage}
+
+		disciplineRisk, err := loader.getDisciplineRisk(1200, 199)
+
+		assert.NoError(t, err)
+		assert.Empty(t, disciplineRisk)
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+	})
+
+	t.Run("redis_error", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.ExpectGet("2026:discipline_semester_updated_at:199").SetVal("21676152800")
+		redisMock.ExpectZScore("2026:2:totals:199", "1200").SetErr(assert.AnError)
+
+		storage := Storage{
+			redis: redisClient,
+			year:  2026,
+			rules: getTestAcademicRules(),
+		}
+		loader := DisciplineRiskLoader{storage: &storage}
+
+		disciplineRisk, err := loader.getDisciplineRisk(1200, 199)
+
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Empty(t, disciplineRisk)
+	})
+}
+
+func TestDisciplineRiskLoaderGetStudentDisciplinesRisks(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.MatchExpectationsInOrder(true)
+
+		redisMock.ExpectSMembers("2026:1:student_disciplines:1200").SetVal([]string{})
+		redisMock.ExpectSMembers("2026:2:student_disciplines:1200").SetVal([]string{"199"})
+		redisMock.ExpectZScore("2026:2:totals:199", "1200").SetVal(50)
+		redisMock.ExpectHGetAll("2026:2:scores:1200:199").SetVal(map[string]string{
+			"245:1": "25",
+			"246:1": "25",
+		})
+		redisMock.ExpectHGetAll("2026:2:lessons:199").SetVal(map[string]string{
+			"245": "2302121",
+			"246": "2302131",
+		})
+		redisMock.ExpectHGet("2026:discipline:199", "name").SetVal("Капітал!")
+
+		storage := Storage{
+			redis: redisClient,
+			year:  2026,
+			rules: getTestAcademicRules(),
+		}
+		loader := DisciplineRiskLoader{storage: &storage}
+
+		results, err := loader.getStudentDisciplinesRisks(1200)
+
+		assert.NoError(t, err)
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+		assert.Len(t, results, 1)
+		assert.Equal(t, scoreApi.Discipline{Id: 199, Name: "Капітал!"}, results[0].Discipline)
+		assert.Equal(t, RiskLow, results[0].Risk.Level)
+		assert.Equal(t, float32(50), results[0].Risk.ProjectedTotal)
+	})
+
+	t.Run("redis_error", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.ExpectSMembers("2026:1:student_disciplines:1200").SetVal([]string{})
+		redisMock.ExpectSMembers("2026:2:student_disciplines:1200").SetVal([]string{"199"})
+		redisMock.ExpectZScore("2026:2:totals:199", "1200").SetErr(assert.AnError)
+
+		storage := Storage{
+			redis: redisClient,
+			year:  2026,
+			rules: getTestAcademicRules(),
+		}
+		loader := DisciplineRiskLoader{storage: &storage}
+
+		results, err := loader.getStudentDisciplinesRisks(1200)
+
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Nil(t, results)
+	})
+}
+
+func TestDisciplineRiskLoaderGetDisciplineAtRiskStudents(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.MatchExpectationsInOrder(true)
+
+		redisMock.ExpectGet("2026:discipline_semester_updated_at:199").SetVal("21676152800")
+		redisMock.ExpectZRangeWithScores("2026:2:totals:199", 0, -1).SetVal([]redis.Z{
+			{Member: "1200", Score: 10},
+			{Member: "1300", Score: 50},
+			{Member: "1400", Score: 90},
+		})
+		redisMock.ExpectHGetAll("2026:2:lessons:199").SetVal(map[string]string{
+			"245": "2302121",
+			"246": "2302131",
+		})
+		redisMock.ExpectHGetAll("2026:2:scores:1200:199").SetVal(map[string]string{"245:1": "10"})
+		redisMock.ExpectHGetAll("2026:2:scores:1300:199").SetVal(map[string]string{"245:1": "25", "246:1": "25"})
+		redisMock.ExpectHGetAll("2026:2:scores:1400:199").SetVal(map[string]string{"245:1": "45", "246:1": "45"})
+		redisMock.ExpectGet("2026:student_group:1200").SetVal("42")
+		redisMock.ExpectGet("2026:student_group:1300").SetVal("42")
+		redisMock.ExpectGet("2026:student_group:1400").RedisNil()
+		redisMock.ExpectSMembers("2026:group:42").SetVal([]string{"1200", "1300", "1500", "1600"})
+		redisMock.ExpectSIsMember("2026:2:student_disciplines:1500", "199").SetVal(true)
+		redisMock.ExpectSIsMember("2026:2:student_disciplines:1600", "199").SetVal(false)
+		redisMock.ExpectHGet("2026:discipline:199", "name").SetVal("Капітал!")
+
+		storage := Storage{
+			redis: redisClient,
+			year:  2026,
+			rules: getTestAcademicRules(),
+		}
+		loader := DisciplineRiskLoader{storage: &storage}
+
+		disciplineAtRisk, err := loader.getDisciplineAtRiskStudents(199)
+
+		assert.NoError(t, err)
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+		assert.Equal(t, scoreApi.Discipline{Id: 199, Name: "Капітал!"}, disciplineAtRisk.Discipline)
+		assert.Equal(t, 2026, disciplineAtRisk.Year)
+		assert.Equal(t, 2, disciplineAtRisk.Semester)
+		assert.Len(t, disciplineAtRisk.Students, 3)
+		assert.Equal(t, 1500, disciplineAtRisk.Students[0].StudentId)
+		assert.Equal(t, RiskHigh, disciplineAtRisk.Students[0].Risk.Level)
+		assert.Equal(t, 0, disciplineAtRisk.Students[0].Risk.GradedLessons)
+		assert.Equal(t, float32(0), disciplineAtRisk.Students[0].Risk.ProjectedTotal)
+		assert.Equal(t, 1200, disciplineAtRisk.Students[1].StudentId)
+		assert.Equal(t, RiskHigh, disciplineAtRisk.Students[1].Risk.Level)
+		assert.Equal(t, 1, disciplineAtRisk.Students[1].Risk.GradedLessons)
+		assert.Equal(t, 1300, disciplineAtRisk.Students[2].StudentId)
+		assert.Equal(t, RiskLow, disciplineAtRisk.Students[2].Risk.Level)
+	})
+
+	t.Run("no_students", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.ExpectGet("2026:discipline_semester_updated_at:199").SetVal("21676152800")
+		redisMock.ExpectZRangeWithScores("2026:2:totals:199", 0, -1).SetVal([]redis.Z{})
+		redisMock.ExpectHGetAll("2026:2:lessons:199").SetVal(map[string]string{})
+		redisMock.ExpectHGet("2026:discipline:199", "name").SetVal("Капітал!")
+
+		storage := Storage{
+			redis: redisClient,
+			year:  2026,
+			rules: getTestAcademicRules(),
+		}
+		loader := DisciplineRiskLoader{storage: &storage}
+
+		disciplineAtRisk, err := loader.getDisciplineAtRiskStudents(199)
+
+		assert.NoError(t, err)
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+		assert.Equal(t, 199, disciplineAtRisk.Discipline.Id)
+		assert.Empty(t, disciplineAtRisk.Students)
+	})
+
+	t.Run("discipline_not_exists", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.ExpectGet("2026:discipline_semester_updated_at:199").RedisNil()
+
+		storage := Storage{
+			redis: redisClient,
+			year:  2026,
+			rules: getTestAcademicRules(),
+		}
+		loader := DisciplineRiskLoader{storage: &storage}
+
+		disciplineAtRisk, err := loader.getDisciplineAtRiskStudents(199)
+
+		assert.NoError(t, err)
+		assert.Empty(t, disciplineAtRisk)
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+	})
+
+	t.Run("redis_error", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.ExpectGet("2026:discipline_semester_updated_at:199").SetVal("21676152800")
+		redisMock.ExpectZRangeWithScores("2026:2:totals:199", 0, -1).SetErr(assert.AnError)
+
+		storage := Storage{
+			redis: redisClient,
+			year:  2026,
+			rules: getTestAcademicRules(),
+		}
+		loader := DisciplineRiskLoader{storage: &storage}
+
+		disciplineAtRisk, err := loader.getDisciplineAtRiskStudents(199)
+
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Empty(t, disciplineAtRisk)
+	})
+
+	t.Run("group_redis_error", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.MatchExpectationsInOrder(true)
+
+		redisMock.ExpectGet("2026:discipline_semester_updated_at:199").SetVal("21676152800")
+		redisMock.ExpectZRangeWithScores("2026:2:totals:199", 0, -1).SetVal([]redis.Z{{Member: "1200", Score: 10}})
+		redisMock.ExpectHGetAll("2026:2:lessons:199").SetVal(map[string]string{})
+		redisMock.ExpectHGetAll("2026:2:scores:1200:199").SetVal(map[string]string{"245:1": "10"})
+		redisMock.ExpectGet("2026:student_group:1200").SetVal("42")
+		redisMock.ExpectSMembers("2026:group:42").SetErr(assert.AnError)
+
+		storage := Storage{
+			redis: redisClient,
+			year:  2026,
+			rules: getTestAcademicRules(),
+		}
+		loader := DisciplineRiskLoader{storage: &storage}
+
+		disciplineAtRisk, err := loader.getDisciplineAtRiskStudents(199)
+
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Empty(t, disciplineAtRisk)
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"github.com/gin-gonic/gin"
+	scoreApi "github.com/kneu-messenger-pigeon/score-api"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func getTestRiskNow() time.Time {
+	return time.Date(2026, time.Month(3), 15, 12, 0, 0, 0, defaultAcademicLocation)
+}
+
+// getTestRiskLessons returns lessons 1-4 held before or on 2026-03-15 and lessons 5-6 remaining
+func getTestRiskLessons() map[int]scoreApi.Lesson {
+	lessons := make(map[int]scoreApi.Lesson)
+	for lessonId, day := range map[int]int{1: 1, 2: 5, 3: 10, 4: 15, 5: 16, 6: 20} {
+		lessons[lessonId] = scoreApi.Lesson{
+			Id:   lessonId,
+			Date: time.Date(2026, time.Month(3), day, 0, 0, 0, 0, defaultAcademicLocation),
+		}
+	}
+
+	return lessons
+}
+
+func TestAssessDisciplineRisk(t *testing.T) {
+	t.Run("on_track", func(t *testing.T) {
+		scoreEntries := []ScoreEntry{
+			{LessonId: 1, Half: 1, Value: 10},
+			{LessonId: 2, Half: 1, Value: 6},
+			{LessonId: 2, Half: 2, Value: 4},
+			{LessonId: 3, Half: 1, Value: 10},
+			{LessonId: 4, Half: 1, Value: 10},
+		}
+
+		risk := getTestAcademicRules().assessDisciplineRisk(40, scoreEntries, getTestRiskLessons(), getTestRiskNow())
+
+		assert.Equal(t, DisciplineRisk{
+			Level:            RiskNone,
+			Reasons:          []string{},
+			Total:            40,
+			ProjectedTotal:   60,
+			AdmissionTotal:   DefaultAdmissionTotal,
+			AveragePerLesson: 10,
+			GradedLessons:    4,
+			HeldLessons:      4,
+			RemainingLessons: 2,
+			RemainingGraded:  2,
+			AbsenceRatio:     0,
+		}, risk)
+	})
+
+	t.Run("high", func(t *testing.T) {
+		scoreEntries := []ScoreEntry{
+			{LessonId: 1, Half: 1, Value: 5},
+			{LessonId: 2, Half: 1, Value: 5},
+			{LessonId: 3, Half: 1, Value: IsAbsentScoreValue},
+			{LessonId: 4, Half: 2, Value: IsAbsentScoreValue},
+			{LessonId: 99, Half: 1, Value: 20},
+		}
+
+		risk := getTestAcademicRules().assessDisciplineRisk(10, scoreEntries, getTestRiskLessons(), getTestRiskNow())
+
+		assert.Equal(t, RiskHigh, risk.Level)
+		assert.Equal(t, []string{
+			RiskReasonProjectedBelowAdmission,
+			RiskReasonTotalBelowAdmission,
+			RiskReasonHighAbsenceRatio,
+			RiskReasonProjectedBelowPassing,
+		}, risk.Reasons)
+		assert.Equal(t, float32(5), risk.AveragePerLesson)
+		assert.Equal(t, 2, risk.GradedLessons)
+		assert.Equal(t, float32(0.5), risk.AbsenceRatio)
+		assert.Equal(t, float32(20), risk.ProjectedTotal)
+	})
+
+	t.Run("medium_absence_ratio", func(t *testing.T) {
+		scoreEntries := []ScoreEntry{
+			{LessonId: 1, Half: 1, Value: IsAbsentScoreValue},
+			{LessonId: 2, Half: 2, Value: IsAbsentScoreValue},
+			{LessonId: 3, Half: 1, Value: 20},
+			{LessonId: 4, Half: 1, Value: 25},
+		}
+
+		risk := getTestAcademicRules().assessDisciplineRisk(70, scoreEntries, getTestRiskLessons(), getTestRiskNow())
+
+		assert.Equal(t, RiskMedium, risk.Level)
+		assert.Equal(t, []string{RiskReasonHighAbsenceRatio}, risk.Reasons)
+		assert.Equal(t, float32(0.5), risk.AbsenceRatio)
+		assert.Equal(t, float32(22.5), risk.AveragePerLesson)
+		assert.Equal(t, float32(115), risk.ProjectedTotal)
+	})
+
+	t.Run("low", func(t *testing.T) {
+		lessons := getTestRiskLessons()
+		delete(lessons, 5)
+		delete(lessons, 6)
+		scoreEntries := []ScoreEntry{
+			{LessonId: 1, Half: 1, Value: 25},
+			{LessonId: 2, Half: 1, Value: 25},
+		}
+
+		risk := getTestAcademicRules().assessDisciplineRisk(50, scoreEntries, lessons, getTestRiskNow())
+
+		assert.Equal(t, RiskLow, risk.Level)
+		assert.Equal(t, []string{RiskReasonProjectedBelowPassing}, risk.Reasons)
+		assert.Equal(t, float32(50), risk.ProjectedTotal)
+		assert.Equal(t, 0, risk.RemainingLessons)
+	})
+
+	t.Run("configured_thresholds", func(t *testing.T) {
+		rules := getTestAcademicRules()
+		rules.admissionTotal = 50
+		rules.absenceRiskRatio = 0.2
+
+		scoreEntries := []ScoreEntry{
+			{LessonId: 1, Half: 1, Value: IsAbsentScoreValue},
+			{LessonId: 2, Half: 1, Value: 15},
+			{LessonId: 3, Half: 1, Value: 15},
+			{LessonId: 4, Half: 1, Value: 15},
+		}
+
+		risk := rules.assessDisciplineRisk(45, scoreEntries, getTestRiskLessons(), getTestRiskNow())
+
+		assert.Equal(t, RiskMedium, risk.Level)
+		assert.Equal(t, []string{RiskReasonTotalBelowAdmission, RiskReasonHighAbsenceRatio}, risk.Reasons)
+		assert.Equal(t, float32(50), risk.AdmissionTotal)
+		assert.Equal(t, float32(75), risk.ProjectedTotal)
+	})
+
+	t.Run("lectures_are_not_projected", func(t *testing.T) {
+		lessons := getTestRiskLessons()
+		lecture := scoreApi.LessonType{Id: 1, ShortName: "Лек"}
+		for _, lessonId := range []int{1, 5} {
+			lesson := lessons[lessonId]
+			lesson.Type = lecture
+			lessons[lessonId] = lesson
+		}
+		scoreEntries := []ScoreEntry{
+			{LessonId: 2, Half: 1, Value: 10},
+			{LessonId: 3, Half: 1, Value: 10},
+			{LessonId: 4, Half: 1, Value: 10},
+		}
+
+		risk := getTestAcademicRules().assessDisciplineRisk(30, scoreEntries, lessons, getTestRiskNow())
+
+		assert.Equal(t, 2, risk.RemainingLessons)
+		assert.Equal(t, 1, risk.RemainingGraded)
+		assert.Equal(t, float32(40), risk.ProjectedTotal)
+	})
+
+	t.Run("no_lessons", func(t *testing.T) {
+		risk := getTestAcademicRules().assessDisciplineRisk(0, []ScoreEntry{}, map[int]scoreApi.Lesson{}, getTestRiskNow())
+
+		assert.Equal(t, RiskHigh, risk.Level)
+		assert.Equal(t, []string{
+			RiskReasonProjectedBelowAdmission,
+			RiskReasonTotalBelowAdmission,
+			RiskReasonProjectedBelowPassing,
+		}, risk.Reasons)
+		assert.Equal(t, float32(0), risk.AbsenceRatio)
+		assert.Equal(t, float32(0), risk.ProjectedTotal)
+	})
+}
+
+func TestSortStudentRisks(t *testing.T) {
+	studentRisks := []StudentRisk{
+		{StudentId: 1300, Risk: DisciplineRisk{Level: RiskLow, ProjectedTotal: 50}},
+		{StudentId: 1200, Risk: DisciplineRisk{Level: RiskHigh, ProjectedTotal: 30}},
+		{StudentId: 1100, Risk: DisciplineRisk{Level: RiskLow, ProjectedTotal: 50}},
+		{StudentId: 1400, Risk: DisciplineRisk{Level: RiskHigh, ProjectedTotal: 20}},
+		{StudentId: 1500, Risk: DisciplineRisk{Level: RiskMedium, ProjectedTotal: 70}},
+	}
+
+	sortStudentRisks(studentRisks)
+
+	studentIds := make([]int, len(studentRisks))
+	for index, studentRisk := range studentRisks {
+		studentIds[index] = studentRisk.StudentId
+	}
+	assert.Equal(t, []int{1400, 1200, 1500, 1100, 1300}, studentIds)
+}
+
+func TestParseRiskFlag(t *testing.T) {
+	parseTestRiskFlag := func(query string) (bool, error) {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request, _ = http.NewRequest(http.MethodGet, "/?"+query, nil)
+
+		return parseRiskFlag(c)
+	}
+
+	withRisk, err := parseTestRiskFlag("")
+	assert.NoError(t, err)
+	assert.False(t, withRisk)
+
+	withRisk, err = parseTestRiskFlag("risk=true")
+	assert.NoError(t, err)
+	assert.True(t, withRisk)
+
+	withRisk, err = parseTestRiskFlag("risk=0")
+	assert.NoError(t, err)
+	assert.False(t, withRisk)
+
+	_, err = parseTestRiskFlag("risk=maybe")
+	assert.Equal(t, MessageError{Format: messageIncorrectParameter, Args: []interface{}{"risk", "maybe"}}, err)
+}
+
+func TestAttachRisks(t *testing.T) {
+	results := DisciplineScoreResults{
+		{Discipline: scoreApi.Discipline{Id: 199}},
+		{Discipline: scoreApi.Discipline{Id: 200}},
+	}
+
+	attachRisks(results, []DisciplineRiskResult{
+		{Discipline: scoreApi.Discipline{Id: 200}, Risk: DisciplineRisk{Level: RiskLow}},
+	})
+
+	assert.Nil(t, results[0].Risk)
+	assert.Equal(t, &DisciplineRisk{Level: RiskLow}, results[1].Risk)
+}
@@ -0,0 +1,14 @@
+package main
+
+import scoreApi "github.com/kneu-messenger-pigeon/score-api"
+
+// DisciplineScore mirrors scoreApi.DisciplineScore with extended Score
+type DisciplineScore struct {
+	Discipline scoreApi.Discipline `json:"discipline"`
+	Score      Score               `json:"score"`
+}
+
+type DisciplineDeletedLessons struct {
+	Discipline scoreApi.Discipline `json:"discipline"`
+	Lessons    []Score             `json:"lessons"`
+}
@@ -0,0 +1,18 @@
+package main
+
+import scoreApi "github.com/kneu-messenger-pigeon/score-api"
+
+// DisciplineScoreResult mirrors scoreApi.DisciplineScoreResult and extends it with data
+// that is not part of the shared score-api contract.
+type DisciplineScoreResult struct {
+	Discipline  scoreApi.Discipline `json:"discipline"`
+	ScoreRating ScoreRating         `json:"scoreRating"`
+	GroupRating *GroupScoreRating   `json:"groupRating,omitempty"`
+	Grades      map[string]string   `json:"grades,omitempty"`
+	Risk        *DisciplineRisk     `json:"risk,omitempty"`
+	Attendance  Attendance          `json:"attendance"`
+	Scores      []Score             `json:"scores,omitempty"`
+	NextCursor  string              `json:"nextCursor,omitempty"`
+}
+
+type DisciplineScoreResults []DisciplineScoreResult
@@ -15,3 +15,27 @@ func (disciplines DisciplineSemesters) Has(disciplineId int) bool {
 	}
 	return false
 }
+
+// without returns disciplines which are absent in other
+func (disciplines DisciplineSemesters) without(other DisciplineSemesters) DisciplineSemesters {
+	result := make(DisciplineSemesters, 0, len(disciplines))
+	for _, discipline := range disciplines {
+		if !other.Has(discipline.DisciplineId) {
+			result = append(result, discipline)
+		}
+	}
+
+	return result
+}
+
+// within returns disciplines which are present in other
+func (disciplines DisciplineSemesters) within(other DisciplineSemesters) DisciplineSemesters {
+	result := make(DisciplineSemesters, 0, len(disciplines))
+	for _, discipline := range disciplines {
+		if other.Has(discipline.DisciplineId) {
+			result = append(result, discipline)
+		}
+	}
+
+	return result
+}
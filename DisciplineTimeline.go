@@ -0,0 +1,148 @@
+package main
+
+import (
+	"encoding/json"
+	scoreApi "github.com/kneu-messenger-pigeon/score-api"
+	"sort"
+	"time"
+)
+
+type TimelinePoint struct {
+	Date             time.Time       `json:"date"`
+	Total            float32         `json:"total"`
+	LessonTypeTotals map[int]float32 `json:"lessonTypeTotals"`
+	AverageTotal     float32         `json:"averageTotal"`
+	dateOnly         bool
+}
+
+func (point TimelinePoint) MarshalJSON() ([]byte, error) {
+	type timelinePointAlias TimelinePoint
+
+	return json.Marshal(struct {
+		Date LessonDate `json:"date"`
+		timelinePointAlias
+	}{
+		Date:               LessonDate{Time: point.Date, dateOnly: point.dateOnly},
+		timelinePointAlias: timelinePointAlias(point),
+	})
+}
+
+type DisciplineTimeline struct {
+	Discipline  scoreApi.Discipline   `json:"discipline"`
+	LessonTypes []scoreApi.LessonType `json:"lessonTypes"`
+	Points      []TimelinePoint       `json:"points"`
+}
+
+type datedScoreValue struct {
+	date  time.Time
+	value float32
+}
+
+// makeTimelinePoints walks date-sorted scores and emits one point per lesson date with the running total
+// of the student, the running totals split by lesson type and the running average total of other students.
+// Scores of deleted lessons and scores without a lesson date are skipped.
+func makeTimelinePoints(scores []Score, otherStudentsScores [][]Score) []TimelinePoint {
+	points := make([]TimelinePoint, 0)
+
+	lessonTypeTotals := make(map[int]float32)
+	for _, score := range scores {
+		if isTimelineScore(score) {
+			lessonTypeTotals[score.Lesson.Type.Id] = 0
+		}
+	}
+
+	otherScoreValues := make([]datedScoreValue, 0)
+	for _, otherStudentScores := range otherStudentsScores {
+		for _, otherScore := range otherStudentScores {
+			if isTimelineScore(otherScore) {
+				otherScoreValues = append(otherScoreValues, datedScoreValue{
+					date:  otherScore.Lesson.Date,
+					value: getScoreValue(otherScore),
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(otherScoreValues, func(i, j int) bool {
+		return otherScoreValues[i].date.Before(otherScoreValues[j].date)
+	})
+
+	var total float32
+	var otherStudentsTotal float32
+	otherIndex := 0
+
+	for _, score := range scores {
+		if !isTimelineScore(score) {
+			continue
+		}
+
+		value := getScoreValue(score)
+		total += value
+		lessonTypeTotals[score.Lesson.Type.Id] += value
+
+		if len(points) != 0 && points[len(points)-1].Date.Equal(score.Lesson.Date) {
+			points[len(points)-1].Total = total
+			points[len(points)-1].LessonTypeTotals[score.Lesson.Type.Id] = lessonTypeTotals[score.Lesson.Type.Id]
+			continue
+		}
+
+		for otherIndex < len(otherScoreValues) && !otherScoreValues[otherIndex].date.After(score.Lesson.Date) {
+			otherStudentsTotal += otherScoreValues[otherIndex].value
+			otherIndex++
+		}
+
+		point := TimelinePoint{
+			Date:             score.Lesson.Date,
+			Total:            total,
+			LessonTypeTotals: make(map[int]float32, len(lessonTypeTotals)),
+		}
+
+		for lessonTypeId, lessonTypeTotal := range lessonTypeTotals {
+			point.LessonTypeTotals[lessonTypeId] = lessonTypeTotal
+		}
+
+		if len(otherStudentsScores) != 0 {
+			point.AverageTotal = otherStudentsTotal / float32(len(otherStudentsScores))
+		}
+
+		points = append(points, point)
+	}
+
+	return points
+}
+
+func makeTimelineLessonTypes(scores []Score) []scoreApi.LessonType {
+	lessonTypesMap := make(map[int]scoreApi.LessonType)
+	for _, score := range scores {
+		if isTimelineScore(score) {
+			lessonTypesMap[score.Lesson.Type.Id] = score.Lesson.Type
+		}
+	}
+
+	lessonTypes := make([]scoreApi.LessonType, 0, len(lessonTypesMap))
+	for _, lessonType := range lessonTypesMap {
+		lessonTypes = append(lessonTypes, lessonType)
+	}
+
+	sort.Slice(lessonTypes, func(i, j int) bool {
+		return lessonTypes[i].Id < lessonTypes[j].Id
+	})
+
+	return lessonTypes
+}
+
+func isTimelineScore(score Score) bool {
+	return !score.Deleted && !score.Lesson.Date.IsZero()
+}
+
+func getScoreValue(score Score) (value float32) {
+	if score.FirstScore != nil {
+		value += *score.FirstScore
+	}
+
+	if score.SecondScore != nil {
+		value += *score.SecondScore
+	}
+
+	return value
+}
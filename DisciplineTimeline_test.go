@@ -0,0 +1,105 @@
+package main
+
+import (
+	scoreApi "github.com/kneu-messenger-pigeon/score-api"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestMakeTimelinePoints(t *testing.T) {
+	lessonTypes := GetTestLessonTypes()
+
+	firstDate := time.Date(2023, time.Month(2), 12, 0, 0, 0, 0, defaultAcademicLocation)
+	secondDate := time.Date(2023, time.Month(2), 14, 0, 0, 0, 0, defaultAcademicLocation)
+	thirdDate := time.Date(2023, time.Month(2), 20, 0, 0, 0, 0, defaultAcademicLocation)
+
+	makeScore := func(lessonId int, date time.Time, lessonTypeId int, firstScore *float32, secondScore *float32) Score {
+		return Score{
+			Score: scoreApi.Score{
+				Lesson: scoreApi.Lesson{
+					Id:   lessonId,
+					Date: date,
+					Type: lessonTypes[lessonTypeId],
+				},
+				FirstScore:  firstScore,
+				SecondScore: secondScore,
+			},
+		}
+	}
+
+	t.Run("success", func(t *testing.T) {
+		deletedLessonScore := makeScore(258, thirdDate, 1, floatPointer(20), nil)
+		deletedLessonScore.Deleted = true
+
+		scores := []Score{
+			makeScore(245, firstDate, 1, floatPointer(4.5), floatPointer(2)),
+			makeScore(247, firstDate, 15, floatPointer(10), nil),
+			{
+				Score: scoreApi.Score{
+					Lesson:   scoreApi.Lesson{Id: 250, Date: secondDate, Type: lessonTypes[1]},
+					IsAbsent: true,
+				},
+			},
+			makeScore(255, thirdDate, 1, floatPointer(3), nil),
+			deletedLessonScore,
+			// score of lesson without date
+			makeScore(260, time.Time{}, 0, floatPointer(50), nil),
+		}
+
+		otherStudentsScores := [][]Score{
+			{
+				makeScore(245, firstDate, 1, floatPointer(5), nil),
+				makeScore(255, thirdDate, 1, floatPointer(4), nil),
+			},
+			{
+				makeScore(250, secondDate, 1, floatPointer(2), floatPointer(1)),
+			},
+		}
+
+		expectedPoints := []TimelinePoint{
+			{
+				Date:             firstDate,
+				Total:            16.5,
+				LessonTypeTotals: map[int]float32{1: 6.5, 15: 10},
+				AverageTotal:     2.5,
+			},
+			{
+				Date:             secondDate,
+				Total:            16.5,
+				LessonTypeTotals: map[int]float32{1: 6.5, 15: 10},
+				AverageTotal:     4,
+			},
+			{
+				Date:             thirdDate,
+				Total:            19.5,
+				LessonTypeTotals: map[int]float32{1: 9.5, 15: 10},
+				AverageTotal:     6,
+			},
+		}
+
+		assert.Equal(t, expectedPoints, makeTimelinePoints(scores, otherStudentsScores))
+		assert.Equal(t, []scoreApi.LessonType{lessonTypes[1], lessonTypes[15]}, makeTimelineLessonTypes(scores))
+	})
+
+	t.Run("without_other_students", func(t *testing.T) {
+		scores := []Score{
+			makeScore(245, firstDate, 1, floatPointer(4.5), nil),
+		}
+
+		expectedPoints := []TimelinePoint{
+			{
+				Date:             firstDate,
+				Total:            4.5,
+				LessonTypeTotals: map[int]float32{1: 4.5},
+			},
+		}
+
+		assert.Equal(t, expectedPoints, makeTimelinePoints(scores, nil))
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		assert.Equal(t, []TimelinePoint{}, makeTimelinePoints([]Score{}, nil))
+		assert.Equal(t, []scoreApi.LessonType{}, makeTimelineLessonTypes([]Score{}))
+	})
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"os"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const gradeScalesRedisKey = "gradeScales"
+
+//go:embed gradeScales.json
+var embeddedGradeScalesJSON []byte
+
+// GradeRange - the lowest total of the grade, the grade lasts until MinTotal of the next higher grade
+type GradeRange struct {
+	Grade    string  `json:"grade"`
+	MinTotal float32 `json:"minTotal"`
+}
+
+// GradeScale - grade ranges ordered from the highest MinTotal
+type GradeScale []GradeRange
+
+// GradeScales - scales keyed by name, e.g. `ects` or `national`
+type GradeScales map[string]GradeScale
+
+// convert returns the grade of the highest range reached by total, totals below every range get the lowest grade.
+// Empty scale has no grades.
+func (scale GradeScale) convert(total float32) string {
+	if len(scale) == 0 {
+		return ""
+	}
+
+	for _, gradeRange := range scale {
+		if total >= gradeRange.MinTotal {
+			return gradeRange.Grade
+		}
+	}
+
+	return scale[len(scale)-1].Grade
+}
+
+// normalize validates scale and orders ranges from the highest MinTotal
+func (scale GradeScale) normalize() (GradeScale, error) {
+	if len(scale) == 0 {
+		return nil, errors.New("grades expected")
+	}
+
+	normalized := make(GradeScale, len(scale))
+	copy(normalized, scale)
+	sort.SliceStable(normalized, func(i, j int) bool {
+		return normalized[i].MinTotal > normalized[j].MinTotal
+	})
+
+	for index, gradeRange := range normalized {
+		if gradeRange.Grade == "" {
+			return nil, errors.New("empty grade")
+		}
+
+		if index != 0 && normalized[index-1].MinTotal == gradeRange.MinTotal {
+			return nil, fmt.Errorf("grades %s and %s have the same min total", normalized[index-1].Grade, gradeRange.Grade)
+		}
+	}
+
+	return normalized, nil
+}
+
+// mergeGradeScales replaces base scales by override ones with the same name, scale names are lower-cased
+func mergeGradeScales(base GradeScales, override GradeScales) (GradeScales, error) {
+	merged := make(GradeScales, len(base)+len(override))
+
+	for _, scales := range []GradeScales{base, override} {
+		for name, scale := range scales {
+			normalized, err := scale.normalize()
+			if err != nil {
+				return nil, fmt.Errorf("grade scale %s: %w", name, err)
+			}
+
+			merged[strings.ToLower(name)] = normalized
+		}
+	}
+
+	return merged, nil
+}
+
+// loadGradeScales merges scales of JSON file over embedded ones, empty filename means only embedded scales
+func loadGradeScales(filename string) (GradeScales, error) {
+	var embedded, configured GradeScales
+	if err := json.Unmarshal(embeddedGradeScalesJSON, &embedded); err != nil {
+		return nil, fmt.Errorf("failed to parse embedded grade scales: %w", err)
+	}
+
+	if filename != "" {
+		content, err := os.ReadFile(filename)
+		if err == nil {
+			err = json.Unmarshal(content, &configured)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("Wrong GRADE_SCALES_FILE %s: %w", filename, err)
+		}
+	}
+
+	return mergeGradeScales(embedded, configured)
+}
+
+// GradeScaler converts discipline totals to grades of configured scales.
+// Scales stored in Redis `gradeScales` key as JSON override configured ones with the same name.
+// Scales are replaced by the hourly update while requests are graded, so they are stored atomically
+// and every request is graded by scales selected from one loaded snapshot.
+type GradeScaler struct {
+	redis      *redis.Client
+	configured GradeScales
+	scales     atomic.Pointer[GradeScales]
+}
+
+// selectScales returns scales requested by comma separated `scales` query parameter, all scales without it
+func (scaler *GradeScaler) selectScales(c *gin.Context) (GradeScales, error) {
+	scales := *scaler.scales.Load()
+	value, exists := c.GetQuery("scales")
+	if !exists {
+		return scales, nil
+	}
+
+	selected := make(GradeScales)
+	for _, name := range strings.Split(value, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		scale, exists := scales[name]
+		if !exists {
+			return nil, MessageError{Format: messageIncorrectParameter, Args: []interface{}{"scales", value}}
+		}
+		selected[name] = scale
+	}
+
+	return selected, nil
+}
+
+// grades converts total by every scale, without scales there are no grades
+func (scales GradeScales) grades(total float32) map[string]string {
+	if len(scales) == 0 {
+		return nil
+	}
+
+	grades := make(map[string]string, len(scales))
+	for name, scale := range scales {
+		grades[name] = scale.convert(total)
+	}
+
+	return grades
+}
+
+func (scales GradeScales) gradeResults(results DisciplineScoreResults) {
+	for index := range results {
+		results[index].Grades = scales.grades(results[index].ScoreRating.Total)
+	}
+}
+
+func (scaler *GradeScaler) periodicallyUpdateScales(ctx context.Context) {
+	for ctx.Err() == nil {
+		scaler.updateScales()
+		time.Sleep(time.Hour)
+	}
+}
+
+// updateScales merges scales stored in Redis over configured ones, removed Redis key restores configured scales.
+// Invalid Redis scales and Redis errors keep previous state.
+func (scaler *GradeScaler) updateScales() {
+	var redisScales GradeScales
+
+	scalesJSON, err := scaler.redis.Get(context.Background(), gradeScalesRedisKey).Bytes()
+	if errors.Is(err, redis.Nil) {
+		scaler.scales.Store(&scaler.configured)
+
+	} else if len(scalesJSON) > 1 && json.Unmarshal(scalesJSON, &redisScales) == nil {
+		if merged, err := mergeGradeScales(scaler.configured, redisScales); err == nil {
+			scaler.scales.Store(&merged)
+		}
+	}
+}
+
+func NewGradeScaler(redis *redis.Client, configured GradeScales, ctx context.Context) *GradeScaler {
+	scaler := &GradeScaler{
+		redis:      redis,
+		configured: configured,
+	}
+	scaler.scales.Store(&scaler.configured)
+
+	if redis != nil {
+		go scaler.periodicallyUpdateScales(ctx)
+	}
+
+	return scaler
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redismock/v9"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+// getTestGradeScaler returns scaler without scales, so responses stay without grades
+func getTestGradeScaler() *GradeScaler {
+	return NewGradeScaler(nil, GradeScales{}, context.Background())
+}
+
+func getTestGradeScalerContext(query string) *gin.Context {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request, _ = http.NewRequest(http.MethodGet, "/?"+query, nil)
+
+	return c
+}
+
+func TestGradeScaleConvert(t *testing.T) {
+	gradeScales, err := loadGradeScales("")
+	assert.NoError(t, err)
+
+	testCases := map[float32][2]string{
+		100:  {"A", "5"},
+		90:   {"A", "5"},
+		89.9: {"B", "4"},
+		82:   {"B", "4"},
+		74:   {"C", "4"},
+		64:   {"D", "3"},
+		60:   {"E", "3"},
+		59.5: {"FX", "2"},
+		35:   {"FX", "2"},
+		34:   {"F", "2"},
+		0:    {"F", "2"},
+		-1:   {"F", "2"},
+	}
+
+	for total, expectedGrades := range testCases {
+		assert.Equal(t, expectedGrades[0], gradeScales["ects"].convert(total), total)
+		assert.Equal(t, expectedGrades[1], gradeScales["national"].convert(total), total)
+	}
+
+	assert.Equal(t, "", GradeScale{}.convert(100))
+	assert.Equal(t, "", gradeScales["removed"].convert(100))
+}
+
+func TestLoadGradeScales(t *testing.T) {
+	t.Run("file", func(t *testing.T) {
+		filename := "TestLoadGradeScales.json"
+		err := os.WriteFile(filename, []byte(`{
+			"NATIONAL": [{"grade": "зараховано", "minTotal": 60}, {"grade": "не зараховано", "minTotal": 0}],
+			"custom": [{"grade": "low", "minTotal": 0}, {"grade": "high", "minTotal": 50}]
+		}`), 0644)
+		assert.NoError(t, err)
+		defer os.Remove(filename)
+
+		gradeScales, err := loadGradeScales(filename)
+
+		assert.NoError(t, err)
+		assert.Len(t, gradeScales, 3)
+		assert.Equal(t, "A", gradeScales["ects"].convert(95))
+		assert.Equal(t, "зараховано", gradeScales["national"].convert(95))
+		assert.Equal(t, GradeScale{{Grade: "high", MinTotal: 50}, {Grade: "low", MinTotal: 0}}, gradeScales["custom"])
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		testCases := map[string]string{
+			"grade scale custom: grades expected":                        `{"custom": []}`,
+			"grade scale custom: empty grade":                            `{"custom": [{"minTotal": 0}]}`,
+			"grade scale custom: grades B and C have the same min total": `{"custom": [{"grade": "B", "minTotal": 1}, {"grade": "C", "minTotal": 1}]}`,
+		}
+
+		filename := "TestLoadGradeScalesErrors.json"
+		defer os.Remove(filename)
+
+		for expectedError, content := range testCases {
+			assert.NoError(t, os.WriteFile(filename, []byte(content), 0644))
+
+			gradeScales, err := loadGradeScales(filename)
+
+			assert.EqualError(t, err, expectedError)
+			assert.Nil(t, gradeScales)
+		}
+
+		_, err := loadGradeScales("not-exists.json")
+		assert.ErrorContains(t, err, "Wrong GRADE_SCALES_FILE not-exists.json")
+	})
+}
+
+func TestGradeScaler(t *testing.T) {
+	gradeScales, _ := loadGradeScales("")
+	scaler := NewGradeScaler(nil, gradeScales, context.Background())
+
+	t.Run("selectScales", func(t *testing.T) {
+		scales, err := scaler.selectScales(getTestGradeScalerContext(""))
+		assert.NoError(t, err)
+		assert.Equal(t, gradeScales, scales)
+
+		scales, err = scaler.selectScales(getTestGradeScalerContext("scales=ECTS"))
+		assert.NoError(t, err)
+		assert.Equal(t, GradeScales{"ects": gradeScales["ects"]}, scales)
+
+		scales, err = scaler.selectScales(getTestGradeScalerContext("scales="))
+		assert.NoError(t, err)
+		assert.Empty(t, scales)
+
+		_, err = scaler.selectScales(getTestGradeScalerContext("scales=ects,usa"))
+		assert.EqualError(t, err, "Incorrect scales: ects,usa")
+	})
+
+	t.Run("grades", func(t *testing.T) {
+		assert.Equal(t, map[string]string{"ects": "B", "national": "4"}, gradeScales.grades(85))
+		assert.Nil(t, GradeScales{}.grades(85))
+		assert.Equal(t, map[string]string{"empty": ""}, GradeScales{"empty": nil}.grades(85))
+
+		results := DisciplineScoreResults{
+			{ScoreRating: ScoreRating{}},
+		}
+		GradeScales{"ects": gradeScales["ects"]}.gradeResults(results)
+		assert.Equal(t, map[string]string{"ects": "F"}, results[0].Grades)
+	})
+
+	t.Run("updateScales", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.ExpectGet(gradeScalesRedisKey).SetVal(`{"ects": [{"grade": "pass", "minTotal": 60}, {"grade": "fail", "minTotal": 0}], "pass": [{"grade": "P", "minTotal": 0}]}`)
+		redisMock.ExpectGet(gradeScalesRedisKey).SetVal(`{"ects": []}`)
+		redisMock.ExpectGet(gradeScalesRedisKey).SetErr(errors.New("connection refused"))
+		redisMock.ExpectGet(gradeScalesRedisKey).RedisNil()
+
+		redisScaler := NewGradeScaler(nil, gradeScales, context.Background())
+		redisScaler.redis = redisClient
+		selectedBefore, _ := redisScaler.selectScales(getTestGradeScalerContext(""))
+
+		redisScaler.updateScales()
+		scales, _ := redisScaler.selectScales(getTestGradeScalerContext(""))
+		assert.Equal(t, "pass", scales["ects"].convert(60))
+		assert.Equal(t, "3", scales["national"].convert(60))
+		assert.Equal(t, "P", scales["pass"].convert(60))
+		// selected snapshot is not changed by the update
+		assert.Equal(t, "E", selectedBefore["ects"].convert(60))
+
+		// invalid scales and Redis errors keep previous state
+		redisScaler.updateScales()
+		redisScaler.updateScales()
+		scales, _ = redisScaler.selectScales(getTestGradeScalerContext(""))
+		assert.Equal(t, "pass", scales["ects"].convert(60))
+
+		// removed key restores configured scales
+		redisScaler.updateScales()
+		scales, _ = redisScaler.selectScales(getTestGradeScalerContext(""))
+		assert.Equal(t, gradeScales, scales)
+		assert.NotContains(t, scales, "pass")
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,30 @@
+package main
+
+import "sort"
+
+// GroupScoreRating - rating of the student among members of the student academic group only
+type GroupScoreRating struct {
+	GroupId int `json:"groupId"`
+	ScoreRating
+}
+
+// GroupLeaderboard - totals of academic group members in the discipline ordered from the best total
+type GroupLeaderboard struct {
+	GroupId int `json:"groupId"`
+	DisciplineRating
+}
+
+// makeGroupStudentTotals orders group members totals from the greatest, students with the same total by id
+func (mode ZeroTotalsMode) makeGroupStudentTotals(totals map[int]float32) []StudentTotal {
+	studentIds := sortedStudentIds(totals)
+	sort.SliceStable(studentIds, func(i, j int) bool {
+		return totals[studentIds[i]] > totals[studentIds[j]]
+	})
+
+	orderedTotals := make([]float32, len(studentIds))
+	for index, studentId := range studentIds {
+		orderedTotals[index] = totals[studentId]
+	}
+
+	return mode.makeStudentTotals(studentIds, orderedTotals)
+}
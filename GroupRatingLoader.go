@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	scoreApi "github.com/kneu-messenger-pigeon/score-api"
+	"github.com/redis/go-redis/v9"
+	"strconv"
+)
+
+type GroupRatingLoaderInterface interface {
+	getGroupLeaderboard(groupId int, disciplineId int) (GroupLeaderboard, error)
+}
+
+// GroupRatingLoader - ratings among members of academic groups, group members are stored in `{year}:group:{groupId}` set
+type GroupRatingLoader struct {
+	storage *Storage
+}
+
+// load calculates student rating among totals of the student group members only.
+// Nil is returned when the student is not a member of any group.
+func (loader *GroupRatingLoader) load(semester int, disciplineId int, studentId int) (*GroupScoreRating, error) {
+	groupId, err := loader.getStudentGroupId(studentId)
+	if err != nil || groupId == 0 {
+		return nil, err
+	}
+
+	studentIds, err := loader.storage.getGroupStudentIds(groupId)
+	if err != nil {
+		return nil, err
+	}
+
+	totals, err := loader.getGroupTotals(semester, disciplineId, studentIds)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GroupScoreRating{
+		GroupId:     groupId,
+		ScoreRating: loader.storage.rules.zeroTotalsMode.makeScoreRating(totals, studentId),
+	}, nil
+}
+
+// getGroupLeaderboard returns empty leaderboard when discipline not exists
+// and leaderboard without group id when the group has no members.
+func (loader *GroupRatingLoader) getGroupLeaderboard(groupId int, disciplineId int) (GroupLeaderboard, error) {
+	semester, err := loader.storage.getSemesterByDisciplineId(disciplineId)
+	if err != nil || semester == 0 {
+		return GroupLeaderboard{}, err
+	}
+
+	leaderboard := GroupLeaderboard{
+		DisciplineRating: DisciplineRating{
+			Discipline: scoreApi.Discipline{
+				Id:   disciplineId,
+				Name: loader.storage.getDisciplineName(disciplineId),
+			},
+			Year:     loader.storage.year,
+			Semester: semester,
+			Students: []StudentTotal{},
+		},
+	}
+
+	studentIds, err := loader.storage.getGroupStudentIds(groupId)
+	if err != nil {
+		return GroupLeaderboard{}, err
+	}
+
+	if len(studentIds) == 0 {
+		return leaderboard, nil
+	}
+
+	totals, err := loader.getGroupTotals(semester, disciplineId, studentIds)
+	if err != nil {
+		return GroupLeaderboard{}, err
+	}
+
+	leaderboard.GroupId = groupId
+	leaderboard.Students = loader.storage.rules.zeroTotalsMode.makeGroupStudentTotals(totals)
+
+	return leaderboard, nil
+}
+
+func (loader *GroupRatingLoader) getStudentGroupId(studentId int) (int, error) {
+	studentGroupKey := fmt.Sprintf("%d:student_group:%d", loader.storage.year, studentId)
+	groupIdString, err := loader.storage.redis.Get(context.Background(), studentGroupKey).Result()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+
+	groupId, err := loader.storage.codec.decodeId(groupIdString)
+	if err != nil {
+		loader.storage.anomalies.report(studentGroupKey, "", err)
+		return 0, nil
+	}
+
+	return groupId, nil
+}
+
+// getGroupTotals loads discipline totals of given students, students without total are not enrolled and skipped
+func (loader *GroupRatingLoader) getGroupTotals(semester int, disciplineId int, studentIds []int) (map[int]float32, error) {
+	ctx := context.Background()
+	disciplineTotalsKey := fmt.Sprintf("%d:%d:totals:%d", loader.storage.year, semester, disciplineId)
+
+	pipeline := loader.storage.redis.Pipeline()
+	totalCommands := make([]*redis.FloatCmd, len(studentIds))
+	for index, studentId := range studentIds {
+		totalCommands[index] = pipeline.ZScore(ctx, disciplineTotalsKey, strconv.Itoa(studentId))
+	}
+
+	if len(totalCommands) != 0 {
+		_, err := pipeline.Exec(ctx)
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return nil, err
+		}
+	}
+
+	totals := make(map[int]float32, len(studentIds))
+	for index, totalCommand := range totalCommands {
+		if totalCommand.Err() == nil {
+			totals[studentIds[index]] = float32(totalCommand.Val())
+		}
+	}
+
+	return totals, nil
+}
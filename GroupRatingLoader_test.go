@@ -0,0 +1,198 @@
+package main
+
+import (
+	"github.com/go-redis/redismock/v9"
+	scoreApi "github.com/kneu-messenger-pigeon/score-api"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestGroupRatingLoaderLoad(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.MatchExpectationsInOrder(true)
+
+		redisMock.ExpectGet("2026:student_group:1200").SetVal("17")
+		redisMock.ExpectSMembers("2026:group:17").SetVal([]string{"1400", "bad", "1300", "1200"})
+		redisMock.ExpectZScore("2026:1:totals:199", "1200").SetVal(7.5)
+		redisMock.ExpectZScore("2026:1:totals:199", "1300").SetVal(13)
+		redisMock.ExpectZScore("2026:1:totals:199", "1400").RedisNil()
+
+		storage := Storage{
+			redis:     redisClient,
+			year:      2026,
+			anomalies: &AnomalyCounter{},
+		}
+		loader := GroupRatingLoader{storage: &storage}
+
+		groupRating, err := loader.load(1, 199, 1200)
+
+		assert.NoError(t, err)
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+		assert.Equal(t, &GroupScoreRating{
+			GroupId: 17,
+			ScoreRating: ScoreRating{
+				ScoreRating: scoreApi.ScoreRating{
+					Total:         7.5,
+					StudentsCount: 2,
+					Rating:        2,
+					MinTotal:      7.5,
+					MaxTotal:      13,
+				},
+				Percentile:   25,
+				TieSize:      1,
+				NonZeroCount: 2,
+			},
+		}, groupRating)
+		assert.Equal(t, 1, storage.getAnomalies().Total)
+	})
+
+	t.Run("no_group", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.ExpectGet("2026:student_group:1200").RedisNil()
+
+		storage := Storage{
+			redis: redisClient,
+			year:  2026,
+		}
+		loader := GroupRatingLoader{storage: &storage}
+
+		groupRating, err := loader.load(1, 199, 1200)
+
+		assert.NoError(t, err)
+		assert.Nil(t, groupRating)
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+	})
+
+	t.Run("malformed_group_id", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.ExpectGet("2026:student_group:1200").SetVal("abc")
+
+		storage := Storage{
+			redis:     redisClient,
+			year:      2026,
+			anomalies: &AnomalyCounter{},
+		}
+		loader := GroupRatingLoader{storage: &storage}
+
+		groupRating, err := loader.load(1, 199, 1200)
+
+		assert.NoError(t, err)
+		assert.Nil(t, groupRating)
+		assert.Equal(t, 1, storage.getAnomalies().Total)
+	})
+
+	t.Run("redis_error", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.ExpectGet("2026:student_group:1200").SetVal("17")
+		redisMock.ExpectSMembers("2026:group:17").SetVal([]string{"1200"})
+		redisMock.ExpectZScore("2026:1:totals:199", "1200").SetErr(assert.AnError)
+
+		storage := Storage{
+			redis: redisClient,
+			year:  2026,
+		}
+		loader := GroupRatingLoader{storage: &storage}
+
+		_, err := loader.load(1, 199, 1200)
+
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+}
+
+func TestGroupRatingLoaderGetGroupLeaderboard(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.MatchExpectationsInOrder(true)
+
+		redisMock.ExpectGet("2026:discipline_semester_updated_at:199").SetVal("21676152800")
+		redisMock.ExpectHGet("2026:discipline:199", "name").SetVal("Капітал!")
+		redisMock.ExpectSMembers("2026:group:17").SetVal([]string{"1500", "1400", "1300", "1200"})
+		redisMock.ExpectZScore("2026:2:totals:199", "1200").SetVal(5)
+		redisMock.ExpectZScore("2026:2:totals:199", "1300").SetVal(7.5)
+		redisMock.ExpectZScore("2026:2:totals:199", "1400").SetVal(5)
+		redisMock.ExpectZScore("2026:2:totals:199", "1500").RedisNil()
+
+		storage := Storage{
+			redis: redisClient,
+			year:  2026,
+		}
+		loader := GroupRatingLoader{storage: &storage}
+
+		leaderboard, err := loader.getGroupLeaderboard(17, 199)
+
+		assert.NoError(t, err)
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+		assert.Equal(t, GroupLeaderboard{
+			GroupId: 17,
+			DisciplineRating: DisciplineRating{
+				Discipline: scoreApi.Discipline{
+					Id:   199,
+					Name: "Капітал!",
+				},
+				Year:     2026,
+				Semester: 2,
+				Students: []StudentTotal{
+					{StudentId: 1300, Total: 7.5, Rating: 1},
+					{StudentId: 1200, Total: 5, Rating: 2},
+					{StudentId: 1400, Total: 5, Rating: 2},
+				},
+			},
+		}, leaderboard)
+	})
+
+	t.Run("group_not_exists", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.ExpectGet("2026:discipline_semester_updated_at:199").SetVal("21676152800")
+		redisMock.ExpectHGet("2026:discipline:199", "name").SetVal("Капітал!")
+		redisMock.ExpectSMembers("2026:group:17").SetVal([]string{})
+
+		storage := Storage{
+			redis: redisClient,
+			year:  2026,
+		}
+		loader := GroupRatingLoader{storage: &storage}
+
+		leaderboard, err := loader.getGroupLeaderboard(17, 199)
+
+		assert.NoError(t, err)
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+		assert.Equal(t, 0, leaderboard.GroupId)
+		assert.Equal(t, 199, leaderboard.Discipline.Id)
+		assert.Empty(t, leaderboard.Students)
+	})
+
+	t.Run("discipline_not_exists", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.ExpectGet("2026:discipline_semester_updated_at:199").RedisNil()
+
+		storage := Storage{
+			redis: redisClient,
+			year:  2026,
+		}
+		loader := GroupRatingLoader{storage: &storage}
+
+		leaderboard, err := loader.getGroupLeaderboard(17, 199)
+
+		assert.NoError(t, err)
+		assert.Empty(t, leaderboard)
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+	})
+
+	t.Run("redis_error", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.ExpectGet("2026:discipline_semester_updated_at:199").SetVal("21676152800")
+		redisMock.ExpectHGet("2026:discipline:199", "name").SetVal("Капітал!")
+		redisMock.ExpectSMembers("2026:group:17").SetErr(assert.AnError)
+
+		storage := Storage{
+			redis: redisClient,
+			year:  2026,
+		}
+		loader := GroupRatingLoader{storage: &storage}
+
+		_, err := loader.getGroupLeaderboard(17, 199)
+
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+}
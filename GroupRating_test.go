@@ -0,0 +1,17 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestMakeGroupStudentTotals(t *testing.T) {
+	assert.Equal(t, []StudentTotal{}, ZeroTotalsLast.makeGroupStudentTotals(map[int]float32{}))
+
+	assert.Equal(t, []StudentTotal{
+		{StudentId: 12, Total: 9, Rating: 1},
+		{StudentId: 10, Total: 3, Rating: 2},
+		{StudentId: 11, Total: 3, Rating: 2},
+		{StudentId: 13, Total: 0, Rating: 4},
+	}, ZeroTotalsLast.makeGroupStudentTotals(map[int]float32{11: 3, 13: 0, 10: 3, 12: 9}))
+}
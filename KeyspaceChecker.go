@@ -0,0 +1,392 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	CheckSeverityWarning = "warning"
+	CheckSeverityError   = "error"
+)
+
+const (
+	CheckMissingLesson              = "missing_lesson"
+	CheckMalformedScore             = "malformed_score"
+	CheckMalformedKey               = "malformed_key"
+	CheckTotalMismatch              = "total_mismatch"
+	CheckStudentWithoutDisciplines  = "student_without_disciplines"
+	CheckDisciplineWithoutName      = "discipline_without_name"
+	CheckMalformedSemesterUpdatedAt = "malformed_semester_updated_at"
+)
+
+const totalMismatchTolerance = 0.001
+
+type CheckIssue struct {
+	Check    string `json:"check"`
+	Severity string `json:"severity"`
+	Key      string `json:"key"`
+	Message  string `json:"message"`
+}
+
+type CheckReport struct {
+	Year     int          `json:"year"`
+	Errors   int          `json:"errors"`
+	Warnings int          `json:"warnings"`
+	Issues   []CheckIssue `json:"issues"`
+}
+
+func (report *CheckReport) add(check string, severity string, key string, message string) {
+	if severity == CheckSeverityError {
+		report.Errors++
+	} else {
+		report.Warnings++
+	}
+
+	report.Issues = append(report.Issues, CheckIssue{
+		Check:    check,
+		Severity: severity,
+		Key:      key,
+		Message:  message,
+	})
+}
+
+// studentDisciplineKey identifies student discipline in semester
+type studentDisciplineKey struct {
+	semester     int
+	studentId    int
+	disciplineId int
+}
+
+// KeyspaceChecker scans all keys of one year and reports inconsistencies between them
+type KeyspaceChecker struct {
+	redis *redis.Client
+	codec RedisCodec
+	year  int
+
+	report            CheckReport
+	disciplineIds     map[int]bool
+	studentDiscipline map[studentDisciplineKey]bool
+	scoreSums         map[studentDisciplineKey]float64
+	lessonsCache      map[string]map[string]string
+}
+
+func (checker *KeyspaceChecker) check(ctx context.Context) (CheckReport, error) {
+	checker.report = CheckReport{Year: checker.year, Issues: make([]CheckIssue, 0)}
+	checker.disciplineIds = make(map[int]bool)
+	checker.studentDiscipline = make(map[studentDisciplineKey]bool)
+	checker.scoreSums = make(map[studentDisciplineKey]float64)
+	checker.lessonsCache = make(map[string]map[string]string)
+
+	for _, step := range []func(ctx context.Context) error{
+		checker.checkSemesterUpdatedAt,
+		checker.loadStudentDisciplines,
+		checker.checkScores,
+		checker.checkTotals,
+		checker.checkDisciplineNames,
+	} {
+		if err := step(ctx); err != nil {
+			return CheckReport{}, err
+		}
+	}
+
+	return checker.report, nil
+}
+
+func (checker *KeyspaceChecker) checkSemesterUpdatedAt(ctx context.Context) error {
+	keyPrefix := fmt.Sprintf("%d:discipline_semester_updated_at:", checker.year)
+	keys, err := checker.scanKeys(ctx, keyPrefix+"*")
+	if err != nil || len(keys) == 0 {
+		return err
+	}
+
+	values, err := checker.redis.MGet(ctx, keys...).Result()
+	if err != nil {
+		return err
+	}
+
+	for index, key := range keys {
+		disciplineId, err := checker.codec.decodeId(key[len(keyPrefix):])
+		if err != nil {
+			checker.report.add(CheckMalformedKey, CheckSeverityError, key, err.Error())
+			continue
+		}
+		checker.disciplineIds[disciplineId] = true
+
+		value, _ := values[index].(string)
+		if _, _, err = checker.codec.decodeSemesterUpdatedAt(value); err != nil {
+			checker.report.add(CheckMalformedSemesterUpdatedAt, CheckSeverityError, key, err.Error())
+		}
+	}
+
+	return nil
+}
+
+func (checker *KeyspaceChecker) loadStudentDisciplines(ctx context.Context) error {
+	keys, err := checker.scanKeys(ctx, fmt.Sprintf("%d:*:student_disciplines:*", checker.year))
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		ids, ok := checker.parseKeyIds(key, "student_disciplines", 1)
+		if !ok {
+			continue
+		}
+
+		members, err := checker.redis.SMembers(ctx, key).Result()
+		if err != nil {
+			return err
+		}
+
+		for _, member := range members {
+			disciplineId, err := checker.codec.decodeId(member)
+			if err != nil {
+				checker.report.add(CheckMalformedKey, CheckSeverityError, key, err.Error())
+				continue
+			}
+
+			checker.disciplineIds[disciplineId] = true
+			checker.studentDiscipline[studentDisciplineKey{
+				semester:     ids[0],
+				studentId:    ids[1],
+				disciplineId: disciplineId,
+			}] = true
+		}
+	}
+
+	return nil
+}
+
+func (checker *KeyspaceChecker) checkScores(ctx context.Context) error {
+	keys, err := checker.scanKeys(ctx, fmt.Sprintf("%d:*:scores:*", checker.year))
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		ids, ok := checker.parseKeyIds(key, "scores", 2)
+		if !ok {
+			continue
+		}
+
+		semester, studentId, disciplineId := ids[0], ids[1], ids[2]
+		checker.disciplineIds[disciplineId] = true
+
+		rawScores, err := checker.redis.HGetAll(ctx, key).Result()
+		if err != nil {
+			return err
+		}
+
+		lessons, err := checker.getLessons(ctx, semester, disciplineId)
+		if err != nil {
+			return err
+		}
+
+		var sum float64
+		checkedLessonIds := make(map[int]bool)
+		for _, field := range sortedMapKeys(rawScores) {
+			scoreEntry, err := checker.codec.decodeScoreEntry(field, rawScores[field])
+			if err != nil {
+				checker.report.add(CheckMalformedScore, CheckSeverityError, key, err.Error())
+				continue
+			}
+
+			if !scoreEntry.isAbsent() {
+				sum += float64(scoreEntry.Value)
+			}
+
+			if checkedLessonIds[scoreEntry.LessonId] {
+				continue
+			}
+			checkedLessonIds[scoreEntry.LessonId] = true
+
+			if _, exists := lessons[strconv.Itoa(scoreEntry.LessonId)]; exists {
+				continue
+			}
+
+			deletedLessonKey := fmt.Sprintf("%d:%d:deleted-lessons:%d:%d", checker.year, semester, disciplineId, scoreEntry.LessonId)
+			deletedExists, err := checker.redis.Exists(ctx, deletedLessonKey).Result()
+			if err != nil {
+				return err
+			}
+
+			if deletedExists == 0 {
+				checker.report.add(CheckMissingLesson, CheckSeverityError, key, fmt.Sprintf(
+					"lesson %d is missing from both lessons and deleted-lessons", scoreEntry.LessonId,
+				))
+			}
+		}
+
+		checker.scoreSums[studentDisciplineKey{semester: semester, studentId: studentId, disciplineId: disciplineId}] = sum
+	}
+
+	return nil
+}
+
+func (checker *KeyspaceChecker) checkTotals(ctx context.Context) error {
+	keys, err := checker.scanKeys(ctx, fmt.Sprintf("%d:*:totals:*", checker.year))
+	if err != nil {
+		return err
+	}
+
+	comparedSums := make(map[studentDisciplineKey]bool)
+
+	for _, key := range keys {
+		ids, ok := checker.parseKeyIds(key, "totals", 1)
+		if !ok {
+			continue
+		}
+
+		semester, disciplineId := ids[0], ids[1]
+		checker.disciplineIds[disciplineId] = true
+
+		totals, err := checker.redis.ZRangeWithScores(ctx, key, 0, -1).Result()
+		if err != nil {
+			return err
+		}
+
+		for _, total := range totals {
+			member, _ := total.Member.(string)
+			studentId, err := checker.codec.decodeId(member)
+			if err != nil {
+				checker.report.add(CheckMalformedKey, CheckSeverityError, key, err.Error())
+				continue
+			}
+
+			studentDiscipline := studentDisciplineKey{semester: semester, studentId: studentId, disciplineId: disciplineId}
+			comparedSums[studentDiscipline] = true
+
+			if !checker.studentDiscipline[studentDiscipline] {
+				checker.report.add(CheckStudentWithoutDisciplines, CheckSeverityWarning, key, fmt.Sprintf(
+					"student %d has total but discipline %d is missing in %d:%d:student_disciplines:%d",
+					studentId, disciplineId, checker.year, semester, studentId,
+				))
+			}
+
+			if sum := checker.scoreSums[studentDiscipline]; math.Abs(sum-total.Score) > totalMismatchTolerance {
+				checker.report.add(CheckTotalMismatch, CheckSeverityError, key, fmt.Sprintf(
+					"student %d total %s does not match sum of scores %s",
+					studentId, formatCheckNumber(total.Score), formatCheckNumber(sum),
+				))
+			}
+		}
+	}
+
+	for _, studentDiscipline := range sortedStudentDisciplineKeys(checker.scoreSums) {
+		sum := checker.scoreSums[studentDiscipline]
+		if !comparedSums[studentDiscipline] && math.Abs(sum) > totalMismatchTolerance {
+			checker.report.add(CheckTotalMismatch, CheckSeverityError, fmt.Sprintf(
+				"%d:%d:totals:%d", checker.year, studentDiscipline.semester, studentDiscipline.disciplineId,
+			), fmt.Sprintf(
+				"student %d has no total but sum of scores is %s",
+				studentDiscipline.studentId, formatCheckNumber(sum),
+			))
+		}
+	}
+
+	return nil
+}
+
+func (checker *KeyspaceChecker) checkDisciplineNames(ctx context.Context) error {
+	disciplineIds := make([]int, 0, len(checker.disciplineIds))
+	for disciplineId := range checker.disciplineIds {
+		disciplineIds = append(disciplineIds, disciplineId)
+	}
+	sort.Ints(disciplineIds)
+
+	for _, disciplineId := range disciplineIds {
+		key := fmt.Sprintf("%d:discipline:%d", checker.year, disciplineId)
+		name, err := checker.redis.HGet(ctx, key, "name").Result()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return err
+		}
+
+		if name == "" {
+			checker.report.add(CheckDisciplineWithoutName, CheckSeverityWarning, key, fmt.Sprintf(
+				"discipline %d has no name", disciplineId,
+			))
+		}
+	}
+
+	return nil
+}
+
+func (checker *KeyspaceChecker) getLessons(ctx context.Context, semester int, disciplineId int) (map[string]string, error) {
+	key := fmt.Sprintf("%d:%d:lessons:%d", checker.year, semester, disciplineId)
+	if lessons, exists := checker.lessonsCache[key]; exists {
+		return lessons, nil
+	}
+
+	lessons, err := checker.redis.HGetAll(ctx, key).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	checker.lessonsCache[key] = lessons
+	return lessons, nil
+}
+
+// parseKeyIds parses `{year}:{semester}:{name}:{id}...` key and returns semester followed by idsCount ids
+func (checker *KeyspaceChecker) parseKeyIds(key string, name string, idsCount int) ([]int, bool) {
+	parts := strings.Split(key, ":")
+	if len(parts) != 3+idsCount || parts[2] != name {
+		checker.report.add(CheckMalformedKey, CheckSeverityError, key, "unexpected key format")
+		return nil, false
+	}
+
+	ids := make([]int, 0, 1+idsCount)
+	for _, part := range append([]string{parts[1]}, parts[3:]...) {
+		id, err := checker.codec.decodeId(part)
+		if err != nil {
+			checker.report.add(CheckMalformedKey, CheckSeverityError, key, err.Error())
+			return nil, false
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, true
+}
+
+func (checker *KeyspaceChecker) scanKeys(ctx context.Context, pattern string) ([]string, error) {
+	return scanSortedKeys(ctx, checker.redis, pattern)
+}
+
+func sortedMapKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func sortedStudentDisciplineKeys(values map[studentDisciplineKey]float64) []studentDisciplineKey {
+	keys := make([]studentDisciplineKey, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].semester != keys[j].semester {
+			return keys[i].semester < keys[j].semester
+		}
+		if keys[i].disciplineId != keys[j].disciplineId {
+			return keys[i].disciplineId < keys[j].disciplineId
+		}
+		return keys[i].studentId < keys[j].studentId
+	})
+
+	return keys
+}
+
+func formatCheckNumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
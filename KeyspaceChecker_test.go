@@ -0,0 +1,204 @@
+package main
+
+import (
+	"context"
+	"github.com/go-redis/redismock/v9"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestKeyspaceChecker(t *testing.T) {
+	t.Run("issues", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.MatchExpectationsInOrder(true)
+
+		redisMock.ExpectScan(0, "2026:discipline_semester_updated_at:*", 1000).SetVal([]string{
+			"2026:discipline_semester_updated_at:x",
+			"2026:discipline_semester_updated_at:200",
+			"2026:discipline_semester_updated_at:199",
+		}, 0)
+		redisMock.ExpectMGet(
+			"2026:discipline_semester_updated_at:199",
+			"2026:discipline_semester_updated_at:200",
+			"2026:discipline_semester_updated_at:x",
+		).SetVal([]interface{}{"11676152800", "3abc", "1"})
+
+		redisMock.ExpectScan(0, "2026:*:student_disciplines:*", 1000).SetVal([]string{
+			"2026:1:student_disciplines:1200",
+		}, 0)
+		redisMock.ExpectSMembers("2026:1:student_disciplines:1200").SetVal([]string{"199"})
+
+		redisMock.ExpectScan(0, "2026:*:scores:*", 1000).SetVal([]string{
+			"2026:1:scores:1500:199",
+			"2026:1:scores:1300:199",
+			"2026:1:scores:1200:199",
+		}, 0)
+
+		redisMock.ExpectHGetAll("2026:1:scores:1200:199").SetVal(map[string]string{
+			"245:1": "4.5",
+			"245:2": "2",
+			"250:1": "1",
+			"251:1": "-999999",
+			"bad":   "1",
+		})
+		redisMock.ExpectHGetAll("2026:1:lessons:199").SetVal(map[string]string{
+			"245": "2302121",
+		})
+		redisMock.ExpectExists("2026:1:deleted-lessons:199:250").SetVal(1)
+		redisMock.ExpectExists("2026:1:deleted-lessons:199:251").SetVal(0)
+
+		redisMock.ExpectHGetAll("2026:1:scores:1300:199").SetVal(map[string]string{
+			"245:1": "3",
+		})
+		redisMock.ExpectHGetAll("2026:1:scores:1500:199").SetVal(map[string]string{
+			"245:1": "2",
+		})
+
+		redisMock.ExpectScan(0, "2026:*:totals:*", 1000).SetVal([]string{
+			"2026:1:totals:199",
+		}, 0)
+		redisMock.ExpectZRangeWithScores("2026:1:totals:199", 0, -1).SetVal([]redis.Z{
+			{Member: "1200", Score: 7.5},
+			{Member: "1300", Score: 5},
+			{Member: "1400", Score: 0},
+		})
+
+		redisMock.ExpectHGet("2026:discipline:199", "name").SetVal("Капітал!")
+		redisMock.ExpectHGet("2026:discipline:200", "name").RedisNil()
+
+		checker := KeyspaceChecker{
+			redis: redisClient,
+			year:  2026,
+		}
+
+		report, err := checker.check(context.Background())
+
+		assert.NoError(t, err)
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+
+		assert.Equal(t, CheckReport{
+			Year:     2026,
+			Errors:   6,
+			Warnings: 3,
+			Issues: []CheckIssue{
+				{
+					Check:    CheckMalformedSemesterUpdatedAt,
+					Severity: CheckSeverityError,
+					Key:      "2026:discipline_semester_updated_at:200",
+					Message:  `malformed discipline semester updated at "3abc": semester 1 or 2 expected`,
+				},
+				{
+					Check:    CheckMalformedKey,
+					Severity: CheckSeverityError,
+					Key:      "2026:discipline_semester_updated_at:x",
+					Message:  `malformed id "x": positive integer expected`,
+				},
+				{
+					Check:    CheckMissingLesson,
+					Severity: CheckSeverityError,
+					Key:      "2026:1:scores:1200:199",
+					Message:  "lesson 251 is missing from both lessons and deleted-lessons",
+				},
+				{
+					Check:    CheckMalformedScore,
+					Severity: CheckSeverityError,
+					Key:      "2026:1:scores:1200:199",
+					Message:  `malformed score field "bad": separator expected`,
+				},
+				{
+					Check:    CheckStudentWithoutDisciplines,
+					Severity: CheckSeverityWarning,
+					Key:      "2026:1:totals:199",
+					Message:  "student 1300 has total but discipline 199 is missing in 2026:1:student_disciplines:1300",
+				},
+				{
+					Check:    CheckTotalMismatch,
+					Severity: CheckSeverityError,
+					Key:      "2026:1:totals:199",
+					Message:  "student 1300 total 5 does not match sum of scores 3",
+				},
+				{
+					Check:    CheckStudentWithoutDisciplines,
+					Severity: CheckSeverityWarning,
+					Key:      "2026:1:totals:199",
+					Message:  "student 1400 has total but discipline 199 is missing in 2026:1:student_disciplines:1400",
+				},
+				{
+					Check:    CheckTotalMismatch,
+					Severity: CheckSeverityError,
+					Key:      "2026:1:totals:199",
+					Message:  "student 1500 has no total but sum of scores is 2",
+				},
+				{
+					Check:    CheckDisciplineWithoutName,
+					Severity: CheckSeverityWarning,
+					Key:      "2026:discipline:200",
+					Message:  "discipline 200 has no name",
+				},
+			},
+		}, report)
+	})
+
+	t.Run("clean", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.MatchExpectationsInOrder(true)
+
+		redisMock.ExpectScan(0, "2026:discipline_semester_updated_at:*", 1000).SetVal([]string{}, 0)
+		redisMock.ExpectScan(0, "2026:*:student_disciplines:*", 1000).SetVal([]string{}, 0)
+		redisMock.ExpectScan(0, "2026:*:scores:*", 1000).SetVal([]string{}, 0)
+		redisMock.ExpectScan(0, "2026:*:totals:*", 1000).SetVal([]string{}, 0)
+
+		checker := KeyspaceChecker{
+			redis: redisClient,
+			year:  2026,
+		}
+
+		report, err := checker.check(context.Background())
+
+		assert.NoError(t, err)
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+		assert.Equal(t, CheckReport{Year: 2026, Issues: []CheckIssue{}}, report)
+	})
+
+	t.Run("malformed_keys", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.MatchExpectationsInOrder(true)
+
+		redisMock.ExpectScan(0, "2026:discipline_semester_updated_at:*", 1000).SetVal([]string{}, 0)
+		redisMock.ExpectScan(0, "2026:*:student_disciplines:*", 1000).SetVal([]string{
+			"2026:x:student_disciplines:1200",
+		}, 0)
+		redisMock.ExpectScan(0, "2026:*:scores:*", 1000).SetVal([]string{
+			"2026:1:scores:1200",
+		}, 0)
+		redisMock.ExpectScan(0, "2026:*:totals:*", 1000).SetVal([]string{}, 0)
+
+		checker := KeyspaceChecker{
+			redis: redisClient,
+			year:  2026,
+		}
+
+		report, err := checker.check(context.Background())
+
+		assert.NoError(t, err)
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+		assert.Equal(t, 2, report.Errors)
+		assert.Equal(t, "2026:x:student_disciplines:1200", report.Issues[0].Key)
+		assert.Equal(t, "unexpected key format", report.Issues[1].Message)
+	})
+
+	t.Run("redis_error", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.ExpectScan(0, "2026:discipline_semester_updated_at:*", 1000).SetErr(assert.AnError)
+
+		checker := KeyspaceChecker{
+			redis: redisClient,
+			year:  2026,
+		}
+
+		_, err := checker.check(context.Background())
+
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"time"
+	_ "time/tzdata"
+)
+
+const defaultTimezone = "Europe/Kyiv"
+const dateOnlyLayout = "2006-01-02"
+
+// defaultAcademicLocation is loaded from the embedded tz database, so it does not depend on the host zoneinfo.
+// Lesson dates are midnights of the configured academic location regardless of the host timezone.
+var defaultAcademicLocation, _ = time.LoadLocation(defaultTimezone)
+
+// LessonDate renders lesson date as RFC 3339 midnight timestamp or as `2006-01-02` when dateOnly is set
+type LessonDate struct {
+	time.Time
+	dateOnly bool
+}
+
+func (date LessonDate) MarshalJSON() ([]byte, error) {
+	if date.dateOnly {
+		return []byte(`"` + date.Format(dateOnlyLayout) + `"`), nil
+	}
+
+	return date.Time.MarshalJSON()
+}
+
+type LessonDates []LessonDate
+
+// LessonDateFormat sets the format of lesson dates in API responses, configured by DATE_ONLY_JSON.
+// Responses are formatted in place, like they are localized by Translator.
+type LessonDateFormat struct {
+	dateOnly bool
+}
+
+func (format LessonDateFormat) formatScore(score *Score) {
+	score.dateOnly = format.dateOnly
+}
+
+func (format LessonDateFormat) formatScores(scores []Score) {
+	for index := range scores {
+		format.formatScore(&scores[index])
+	}
+}
+
+func (format LessonDateFormat) formatAttendance(attendance *Attendance) {
+	for index := range attendance.AbsentDates {
+		attendance.AbsentDates[index].dateOnly = format.dateOnly
+	}
+}
+
+func (format LessonDateFormat) formatStudentAttendance(studentAttendance *StudentAttendance) {
+	format.formatAttendance(&studentAttendance.Attendance)
+	for index := range studentAttendance.LessonTypes {
+		format.formatAttendance(&studentAttendance.LessonTypes[index].Attendance)
+	}
+}
+
+func (format LessonDateFormat) formatTimelinePoints(points []TimelinePoint) {
+	for index := range points {
+		points[index].dateOnly = format.dateOnly
+	}
+}
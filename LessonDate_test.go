@@ -0,0 +1,137 @@
+package main
+
+import (
+	"encoding/json"
+	"github.com/go-redis/redismock/v9"
+	scoreApi "github.com/kneu-messenger-pigeon/score-api"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+var testHostTimezones = []string{"UTC", "America/New_York", "Europe/Kyiv", "Asia/Tokyo", "Pacific/Kiritimati"}
+
+func withHostTimezone(t *testing.T, timezone string, test func(t *testing.T)) {
+	hostLocation, err := time.LoadLocation(timezone)
+	assert.NoError(t, err)
+
+	previousLocal := time.Local
+	time.Local = hostLocation
+	defer func() {
+		time.Local = previousLocal
+	}()
+
+	t.Run(timezone, test)
+}
+
+func TestLessonDateHostTimezones(t *testing.T) {
+	for _, timezone := range testHostTimezones {
+		withHostTimezone(t, timezone, func(t *testing.T) {
+			date, typeId, err := RedisCodec{}.decodeLessonValue("2302121")
+
+			assert.NoError(t, err)
+			assert.Equal(t, 1, typeId)
+			assert.Equal(t, time.Date(2023, time.Month(2), 12, 0, 0, 0, 0, defaultAcademicLocation), date)
+			assert.Equal(t, "2023-02-12T00:00:00+02:00", date.Format(time.RFC3339))
+
+			score := Score{Score: scoreApi.Score{Lesson: scoreApi.Lesson{Id: 245, Date: date}}}
+
+			scoreJson, err := json.Marshal(score)
+			assert.NoError(t, err)
+			assert.Contains(t, string(scoreJson), `"date":"2023-02-12T00:00:00+02:00"`)
+
+			LessonDateFormat{dateOnly: true}.formatScore(&score)
+			scoreJson, err = json.Marshal(score)
+			assert.NoError(t, err)
+			assert.Contains(t, string(scoreJson), `"date":"2023-02-12"`)
+		})
+	}
+}
+
+func TestUpdatedAtHostTimezones(t *testing.T) {
+	for _, timezone := range testHostTimezones {
+		withHostTimezone(t, timezone, func(t *testing.T) {
+			redisClient, redisMock := redismock.NewClientMock()
+			redisMock.ExpectGet("2026:discipline_semester_updated_at:100").SetVal("21676152800")
+
+			storage := Storage{redis: redisClient, year: 2026}
+			semester, updatedAt, err := storage.getDisciplineSemesterAndUpdatedAt(100)
+
+			assert.NoError(t, err)
+			assert.NoError(t, redisMock.ExpectationsWereMet())
+			assert.Equal(t, 2, semester)
+			assert.Equal(t, defaultAcademicLocation, updatedAt.Location())
+			assert.Equal(t, "2023-02-12T00:00:00+02:00", updatedAt.Format(time.RFC3339))
+		})
+	}
+}
+
+func TestLessonDateJson(t *testing.T) {
+	date := time.Date(2023, time.Month(2), 12, 0, 0, 0, 0, defaultAcademicLocation)
+
+	t.Run("score", func(t *testing.T) {
+		score := Score{
+			Score: scoreApi.Score{
+				Lesson: scoreApi.Lesson{
+					Id:   245,
+					Date: date,
+					Type: scoreApi.LessonType{Id: 15, ShortName: "МК", LongName: "Модульний контроль."},
+				},
+				FirstScore: floatPointer(4.5),
+				IsAbsent:   true,
+			},
+			Deleted: true,
+		}
+
+		expectedJson := `{"lesson":{"id":245,"date":"2023-02-12T00:00:00+02:00","type":{"id":15,"shortName":"МК","longName":"Модульний контроль."}},"firstScore":4.5,"secondScore":null,"isAbsent":true,"deleted":true}`
+		actualJson, err := json.Marshal(score)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedJson, string(actualJson))
+
+		scores := []Score{score}
+		LessonDateFormat{dateOnly: true}.formatScores(scores)
+		actualJson, err = json.Marshal(scores[0])
+		assert.NoError(t, err)
+		assert.Equal(t, `{"lesson":{"id":245,"date":"2023-02-12","type":{"id":15,"shortName":"МК","longName":"Модульний контроль."}},"firstScore":4.5,"secondScore":null,"isAbsent":true,"deleted":true}`, string(actualJson))
+	})
+
+	t.Run("attendance", func(t *testing.T) {
+		studentAttendance := StudentAttendance{
+			Attendance: Attendance{TotalLessons: 1, Absent: 1, AbsentDates: LessonDates{{Time: date}}},
+			LessonTypes: []LessonTypeAttendance{
+				{
+					LessonType: scoreApi.LessonType{Id: 15},
+					Attendance: Attendance{TotalLessons: 1, Absent: 1, AbsentDates: LessonDates{{Time: date}}},
+				},
+			},
+		}
+
+		actualJson, err := json.Marshal(studentAttendance)
+		assert.NoError(t, err)
+		assert.Contains(t, string(actualJson), `"absentDates":["2023-02-12T00:00:00+02:00"]`)
+
+		LessonDateFormat{dateOnly: true}.formatStudentAttendance(&studentAttendance)
+		actualJson, err = json.Marshal(studentAttendance)
+		assert.NoError(t, err)
+		assert.Equal(t, `{"totalLessons":1,"attended":0,"absent":1,"percentage":0,"absentDates":["2023-02-12"],"lessonTypes":[{"lessonType":{"id":15,"shortName":"","longName":""},"totalLessons":1,"attended":0,"absent":1,"percentage":0,"absentDates":["2023-02-12"]}]}`, string(actualJson))
+
+		actualJson, err = json.Marshal(Attendance{})
+		assert.NoError(t, err)
+		assert.Contains(t, string(actualJson), `"absentDates":null`)
+	})
+
+	t.Run("timeline", func(t *testing.T) {
+		point := TimelinePoint{Date: date, Total: 4.5, LessonTypeTotals: map[int]float32{15: 4.5}, AverageTotal: 3}
+
+		expectedJson := `{"date":"2023-02-12T00:00:00+02:00","total":4.5,"lessonTypeTotals":{"15":4.5},"averageTotal":3}`
+		actualJson, err := json.Marshal(point)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedJson, string(actualJson))
+
+		points := []TimelinePoint{point}
+		LessonDateFormat{dateOnly: true}.formatTimelinePoints(points)
+		actualJson, err = json.Marshal(points[0])
+		assert.NoError(t, err)
+		assert.Equal(t, `{"date":"2023-02-12","total":4.5,"lessonTypeTotals":{"15":4.5},"averageTotal":3}`, string(actualJson))
+	})
+}
@@ -0,0 +1,598 @@
+package main
+
+import (
+	"fmt"
+	scoreApi "github.com/kneu-messenger-pigeon/score-api"
+	"sort"
+	"time"
+)
+
+// MemoryStorage serves StorageInterface from fixture loaded into memory, so API could run without Redis
+type MemoryStorage struct {
+	year               int
+	lessonTypes        map[int]scoreApi.LessonType
+	disciplines        map[int]*memoryDiscipline
+	studentDisciplines map[int]map[int]DisciplineSemesters
+	studentGroups      map[int]int
+	groups             map[int][]int
+	studentProfiles    map[int]map[string]string
+	rules              AcademicRules
+}
+
+type memoryDiscipline struct {
+	name           string
+	semester       int
+	credits        float32
+	updatedAt      time.Time
+	lessons        map[int]scoreApi.Lesson
+	deletedLessons map[int]scoreApi.Lesson
+	scoreEntries   map[int][]ScoreEntry
+	totals         map[int]float32
+}
+
+func loadMemoryStorage(filename string, rules AcademicRules) (*MemoryStorage, error) {
+	fixture, err := loadStorageFixture(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewMemoryStorage(fixture, rules)
+}
+
+// NewMemoryStorage validates fixture and indexes it the same way as data is stored in Redis.
+// Discipline totals are sums of all student scores, like ones written by score writer.
+func NewMemoryStorage(fixture StorageFixture, rules AcademicRules) (*MemoryStorage, error) {
+	if fixture.Year <= 0 {
+		return nil, fmt.Errorf("fixture year is missing")
+	}
+
+	storage := &MemoryStorage{
+		year:               fixture.Year,
+		lessonTypes:        make(map[int]scoreApi.LessonType, len(fixture.LessonTypes)),
+		disciplines:        make(map[int]*memoryDiscipline, len(fixture.Disciplines)),
+		studentDisciplines: make(map[int]map[int]DisciplineSemesters, len(fixture.Students)),
+		studentGroups:      make(map[int]int),
+		groups:             make(map[int][]int),
+		studentProfiles:    make(map[int]map[string]string),
+		rules:              rules,
+	}
+
+	for _, lessonType := range fixture.LessonTypes {
+		storage.lessonTypes[lessonType.Id] = scoreApi.LessonType{
+			Id:        lessonType.Id,
+			ShortName: lessonType.ShortName,
+			LongName:  lessonType.LongName,
+		}
+	}
+
+	for _, fixtureDiscipline := range fixture.Disciplines {
+		discipline, err := storage.makeDiscipline(fixtureDiscipline)
+		if err != nil {
+			return nil, fmt.Errorf("fixture discipline %d: %w", fixtureDiscipline.Id, err)
+		}
+
+		storage.disciplines[fixtureDiscipline.Id] = discipline
+	}
+
+	for _, student := range fixture.Students {
+		if student.Id <= 0 {
+			return nil, fmt.Errorf("fixture student %d: positive id expected", student.Id)
+		}
+
+		storage.studentProfiles[student.Id] = make(map[string]string)
+		for field, value := range map[string]string{
+			"name":       student.Name,
+			"group":      student.GroupName,
+			"faculty":    student.Faculty,
+			"study_form": student.StudyForm,
+		} {
+			if value != "" {
+				storage.studentProfiles[student.Id][field] = value
+			}
+		}
+
+		if student.Group < 0 {
+			return nil, fmt.Errorf("fixture student %d: not negative group expected", student.Id)
+		} else if student.Group != 0 {
+			storage.studentGroups[student.Id] = student.Group
+			storage.groups[student.Group] = append(storage.groups[student.Group], student.Id)
+		}
+
+		for _, disciplineId := range student.Disciplines {
+			discipline, exists := storage.disciplines[disciplineId]
+			if !exists {
+				return nil, fmt.Errorf("fixture student %d: unknown discipline %d", student.Id, disciplineId)
+			}
+
+			if storage.studentDisciplines[student.Id] == nil {
+				storage.studentDisciplines[student.Id] = make(map[int]DisciplineSemesters)
+			}
+
+			storage.studentDisciplines[student.Id][discipline.semester] = append(
+				storage.studentDisciplines[student.Id][discipline.semester],
+				DisciplineSemester{Semester: discipline.semester, DisciplineId: disciplineId},
+			)
+
+			if _, exists = discipline.totals[student.Id]; !exists {
+				discipline.totals[student.Id] = 0
+			}
+		}
+	}
+
+	return storage, nil
+}
+
+func (storage *MemoryStorage) makeDiscipline(fixtureDiscipline FixtureDiscipline) (*memoryDiscipline, error) {
+	if fixtureDiscipline.Id <= 0 {
+		return nil, fmt.Errorf("positive id expected")
+	}
+
+	if _, exists := storage.disciplines[fixtureDiscipline.Id]; exists {
+		return nil, fmt.Errorf("duplicated id")
+	}
+
+	if fixtureDiscipline.Semester != 1 && fixtureDiscipline.Semester != 2 {
+		return nil, fmt.Errorf("semester 1 or 2 expected, got %d", fixtureDiscipline.Semester)
+	}
+
+	if fixtureDiscipline.Credits < 0 {
+		return nil, fmt.Errorf("not negative credits expected")
+	}
+
+	discipline := &memoryDiscipline{
+		name:           fixtureDiscipline.Name,
+		semester:       fixtureDiscipline.Semester,
+		credits:        fixtureDiscipline.Credits,
+		updatedAt:      fixtureDiscipline.UpdatedAt,
+		lessons:        make(map[int]scoreApi.Lesson),
+		deletedLessons: make(map[int]scoreApi.Lesson),
+		scoreEntries:   make(map[int][]ScoreEntry),
+		totals:         make(map[int]float32),
+	}
+
+	if discipline.credits == 0 {
+		discipline.credits = DefaultDisciplineCredits
+	}
+
+	for _, fixtureLesson := range fixtureDiscipline.Lessons {
+		lessonType, exists := storage.lessonTypes[fixtureLesson.Type]
+		if !exists {
+			return nil, fmt.Errorf("lesson %d has unknown type %d", fixtureLesson.Id, fixtureLesson.Type)
+		}
+
+		lessonDate, err := time.ParseInLocation(dateOnlyLayout, fixtureLesson.Date, storage.rules.academicLocation())
+		if err != nil {
+			return nil, fmt.Errorf("lesson %d has wrong date: %w", fixtureLesson.Id, err)
+		}
+
+		_, isActual := discipline.lessons[fixtureLesson.Id]
+		_, isDeleted := discipline.deletedLessons[fixtureLesson.Id]
+		if fixtureLesson.Id <= 0 || isActual || isDeleted {
+			return nil, fmt.Errorf("lesson %d has duplicated or not positive id", fixtureLesson.Id)
+		}
+
+		lesson := scoreApi.Lesson{
+			Id:   fixtureLesson.Id,
+			Date: lessonDate,
+			Type: lessonType,
+		}
+
+		if fixtureLesson.Deleted {
+			discipline.deletedLessons[lesson.Id] = lesson
+		} else {
+			discipline.lessons[lesson.Id] = lesson
+		}
+	}
+
+	for _, fixtureScore := range fixtureDiscipline.Scores {
+		_, isActual := discipline.lessons[fixtureScore.Lesson]
+		_, isDeleted := discipline.deletedLessons[fixtureScore.Lesson]
+		if !isActual && !isDeleted {
+			return nil, fmt.Errorf("score of student %d has unknown lesson %d", fixtureScore.Student, fixtureScore.Lesson)
+		}
+
+		if fixtureScore.Student <= 0 {
+			return nil, fmt.Errorf("score of lesson %d has not positive student id", fixtureScore.Lesson)
+		}
+
+		scoreEntries := discipline.scoreEntries[fixtureScore.Student]
+		if fixtureScore.Absent {
+			scoreEntries = append(scoreEntries, ScoreEntry{LessonId: fixtureScore.Lesson, Half: 1, Value: IsAbsentScoreValue})
+		}
+
+		for half, value := range []*float32{fixtureScore.First, fixtureScore.Second} {
+			if value != nil {
+				scoreEntries = append(scoreEntries, ScoreEntry{LessonId: fixtureScore.Lesson, Half: half + 1, Value: *value})
+				discipline.totals[fixtureScore.Student] += *value
+			}
+		}
+
+		discipline.scoreEntries[fixtureScore.Student] = scoreEntries
+		if _, exists := discipline.totals[fixtureScore.Student]; !exists {
+			discipline.totals[fixtureScore.Student] = 0
+		}
+	}
+
+	return discipline, nil
+}
+
+func (storage *MemoryStorage) getDisciplineScoreResultsByStudentId(studentId int) (DisciplineScoreResults, error) {
+	disciplines, err := storage.getActualStudentDisciplines(studentId)
+
+	disciplineScoreResults := make(DisciplineScoreResults, len(disciplines))
+	for index, discipline := range disciplines {
+		disciplineScoreResults[index] = storage.makeDisciplineScoreResult(discipline.DisciplineId, studentId)
+	}
+
+	return disciplineScoreResults, err
+}
+
+func (storage *MemoryStorage) getDisciplineScoreResultByStudentId(studentId int, disciplineId int) (DisciplineScoreResult, error) {
+	if storage.disciplines[disciplineId] == nil {
+		return DisciplineScoreResult{}, nil
+	}
+
+	disciplineScoreResult := storage.makeDisciplineScoreResult(disciplineId, studentId)
+	disciplineScoreResult.Scores = storage.getScores(disciplineId, studentId)
+
+	if groupId := storage.studentGroups[studentId]; groupId != 0 {
+		disciplineScoreResult.GroupRating = &GroupScoreRating{
+			GroupId:     groupId,
+			ScoreRating: storage.rules.zeroTotalsMode.makeScoreRating(storage.getGroupTotals(groupId, disciplineId), studentId),
+		}
+	}
+
+	return disciplineScoreResult, nil
+}
+
+func (storage *MemoryStorage) getDisciplineScore(studentId int, disciplineId int, lessonId int) (DisciplineScore, error) {
+	discipline := storage.disciplines[disciplineId]
+	if discipline == nil {
+		return DisciplineScore{}, nil
+	}
+
+	lesson, isActual := discipline.lessons[lessonId]
+	if !isActual {
+		lesson = discipline.deletedLessons[lessonId]
+	}
+
+	score := Score{}
+	if lesson.Id != 0 {
+		score = Score{
+			Score: scoreApi.Score{
+				Lesson: lesson,
+			},
+			Deleted: !isActual,
+		}
+
+		for _, scored := range storage.getScores(disciplineId, studentId) {
+			if scored.Lesson.Id == lessonId {
+				score = scored
+			}
+		}
+	}
+
+	return DisciplineScore{
+		Discipline: storage.getDiscipline(disciplineId),
+		Score:      score,
+	}, nil
+}
+
+func (storage *MemoryStorage) getDisciplineDeletedLessons(studentId int, disciplineId int) (DisciplineDeletedLessons, error) {
+	discipline := storage.disciplines[disciplineId]
+	if discipline == nil {
+		return DisciplineDeletedLessons{}, nil
+	}
+
+	lessons := make([]Score, 0, len(discipline.deletedLessons))
+	scoredLessonIds := make(map[int]bool)
+	for _, score := range storage.getScores(disciplineId, studentId) {
+		if score.Deleted {
+			scoredLessonIds[score.Lesson.Id] = true
+			lessons = append(lessons, score)
+		}
+	}
+
+	for lessonId, lesson := range discipline.deletedLessons {
+		if !scoredLessonIds[lessonId] {
+			lessons = append(lessons, Score{
+				Score: scoreApi.Score{
+					Lesson: lesson,
+				},
+				Deleted: true,
+			})
+		}
+	}
+
+	sortScores(lessons)
+
+	return DisciplineDeletedLessons{
+		Discipline: storage.getDiscipline(disciplineId),
+		Lessons:    lessons,
+	}, nil
+}
+
+func (storage *MemoryStorage) getStudentAttendance(studentId int) (StudentAttendance, error) {
+	disciplines, err := storage.getActualStudentDisciplines(studentId)
+
+	disciplinesAttendance := make([]AttendanceByLessonType, len(disciplines))
+	for index, discipline := range disciplines {
+		disciplinesAttendance[index] = storage.getAttendance(discipline.DisciplineId, studentId)
+	}
+
+	return makeStudentAttendance(disciplinesAttendance, storage.lessonTypes), err
+}
+
+func (storage *MemoryStorage) getDisciplineTimeline(studentId int, disciplineId int) (DisciplineTimeline, error) {
+	discipline := storage.disciplines[disciplineId]
+	if discipline == nil {
+		return DisciplineTimeline{}, nil
+	}
+
+	scores := storage.getScores(disciplineId, studentId)
+
+	var otherStudentsScores [][]Score
+	if len(scores) != 0 {
+		for _, otherStudentId := range sortedStudentIds(discipline.totals) {
+			if otherStudentId != studentId {
+				otherStudentsScores = append(otherStudentsScores, storage.getScores(disciplineId, otherStudentId))
+			}
+		}
+	}
+
+	return DisciplineTimeline{
+		Discipline:  storage.getDiscipline(disciplineId),
+		LessonTypes: makeTimelineLessonTypes(scores),
+		Points:      makeTimelinePoints(scores, otherStudentsScores),
+	}, nil
+}
+
+func (storage *MemoryStorage) getStudentTranscript(studentId int) (Transcript, error) {
+	disciplines, err := storage.getActualStudentDisciplines(studentId)
+
+	transcript := Transcript{
+		StudentId:   studentId,
+		Year:        storage.year,
+		Semester:    1,
+		Disciplines: make(DisciplineScoreResults, len(disciplines)),
+	}
+
+	for index, discipline := range disciplines {
+		if discipline.Semester > transcript.Semester {
+			transcript.Semester = discipline.Semester
+		}
+
+		transcript.Disciplines[index] = storage.makeDisciplineScoreResult(discipline.DisciplineId, studentId)
+		transcript.Disciplines[index].Scores = storage.getScores(discipline.DisciplineId, studentId)
+	}
+
+	return transcript, err
+}
+
+func (storage *MemoryStorage) getStudentLessons(studentId int) ([]DisciplineLessons, error) {
+	disciplines, err := storage.getActualStudentDisciplines(studentId)
+
+	disciplinesLessons := make([]DisciplineLessons, len(disciplines))
+	for index, discipline := range disciplines {
+		lessons := make([]Score, 0)
+		if disciplineData := storage.disciplines[discipline.DisciplineId]; len(disciplineData.lessons) != 0 {
+			lessons = makeLessonsWithScores(disciplineData.scoreEntries[studentId], disciplineData.lessons)
+		}
+
+		disciplinesLessons[index] = DisciplineLessons{
+			Year:       storage.year,
+			Semester:   discipline.Semester,
+			Discipline: storage.getDiscipline(discipline.DisciplineId),
+			Lessons:    lessons,
+		}
+	}
+
+	return disciplinesLessons, err
+}
+
+func (storage *MemoryStorage) getStudentProfile(studentId int) (StudentProfile, error) {
+	semesters := make([]YearSemester, 0, 2)
+	for semester := 1; semester <= 2; semester++ {
+		if len(storage.studentDisciplines[studentId][semester]) != 0 {
+			semesters = append(semesters, YearSemester{Year: storage.year, Semester: semester})
+		}
+	}
+
+	disciplines, err := storage.getActualStudentDisciplines(studentId)
+
+	return makeStudentProfile(studentId, storage.studentProfiles[studentId], semesters, len(disciplines)), err
+}
+
+func (storage *MemoryStorage) studentExists(studentId int) (bool, error) {
+	return len(storage.studentProfiles[studentId]) != 0 || len(storage.studentDisciplines[studentId][1]) != 0 ||
+		len(storage.studentDisciplines[studentId][2]) != 0, nil
+}
+
+func (storage *MemoryStorage) getStudentSummary(studentId int) (StudentSummary, error) {
+	disciplineSummaries := make([]DisciplineSummary, 0)
+	for semester := 1; semester <= 2; semester++ {
+		for _, discipline := range storage.studentDisciplines[studentId][semester] {
+			disciplineSummaries = append(disciplineSummaries, DisciplineSummary{
+				Discipline:  storage.getDiscipline(discipline.DisciplineId),
+				Semester:    semester,
+				Credits:     storage.disciplines[discipline.DisciplineId].credits,
+				ScoreRating: storage.rules.zeroTotalsMode.makeScoreRating(storage.disciplines[discipline.DisciplineId].totals, studentId),
+				HasScores:   len(storage.disciplines[discipline.DisciplineId].scoreEntries[studentId]) != 0,
+			})
+		}
+	}
+
+	return makeStudentSummary(studentId, storage.year, disciplineSummaries, storage.rules.passingTotal), nil
+}
+
+func (storage *MemoryStorage) getDisciplineRisk(studentId int, disciplineId int) (DisciplineRiskResult, error) {
+	if storage.disciplines[disciplineId] == nil {
+		return DisciplineRiskResult{}, nil
+	}
+
+	return DisciplineRiskResult{
+		Discipline: storage.getDiscipline(disciplineId),
+		Risk:       storage.assessRisk(disciplineId, studentId, time.Now()),
+	}, nil
+}
+
+func (storage *MemoryStorage) getStudentDisciplinesRisks(studentId int) ([]DisciplineRiskResult, error) {
+	disciplines, err := storage.getActualStudentDisciplines(studentId)
+
+	now := time.Now()
+	results := make([]DisciplineRiskResult, len(disciplines))
+	for index, discipline := range disciplines {
+		results[index] = DisciplineRiskResult{
+			Discipline: storage.getDiscipline(discipline.DisciplineId),
+			Risk:       storage.assessRisk(discipline.DisciplineId, studentId, now),
+		}
+	}
+
+	return results, err
+}
+
+func (storage *MemoryStorage) getDisciplineAtRiskStudents(disciplineId int) (DisciplineAtRisk, error) {
+	discipline := storage.disciplines[disciplineId]
+	if discipline == nil {
+		return DisciplineAtRisk{}, nil
+	}
+
+	now := time.Now()
+	studentRisks := make([]StudentRisk, 0)
+	for _, studentId := range sortedStudentIds(discipline.totals) {
+		risk := storage.assessRisk(disciplineId, studentId, now)
+		if risk.Level != RiskNone {
+			studentRisks = append(studentRisks, StudentRisk{StudentId: studentId, Risk: risk})
+		}
+	}
+
+	sortStudentRisks(studentRisks)
+
+	return DisciplineAtRisk{
+		Discipline: storage.getDiscipline(disciplineId),
+		Year:       storage.year,
+		Semester:   discipline.semester,
+		Students:   studentRisks,
+	}, nil
+}
+
+func (storage *MemoryStorage) assessRisk(disciplineId int, studentId int, now time.Time) DisciplineRisk {
+	discipline := storage.disciplines[disciplineId]
+
+	return storage.rules.assessDisciplineRisk(discipline.totals[studentId], discipline.scoreEntries[studentId], discipline.lessons, now)
+}
+
+func (storage *MemoryStorage) getGroupLeaderboard(groupId int, disciplineId int) (GroupLeaderboard, error) {
+	discipline := storage.disciplines[disciplineId]
+	if discipline == nil {
+		return GroupLeaderboard{}, nil
+	}
+
+	leaderboard := GroupLeaderboard{
+		DisciplineRating: DisciplineRating{
+			Discipline: storage.getDiscipline(disciplineId),
+			Year:       storage.year,
+			Semester:   discipline.semester,
+			Students:   []StudentTotal{},
+		},
+	}
+
+	if len(storage.groups[groupId]) != 0 {
+		leaderboard.GroupId = groupId
+		leaderboard.Students = storage.rules.zeroTotalsMode.makeGroupStudentTotals(storage.getGroupTotals(groupId, disciplineId))
+	}
+
+	return leaderboard, nil
+}
+
+// getStudentChanges - fixture data never changes, so there are no changes since any time
+// and cursor moves like in StudentChangesLoader without changes
+func (storage *MemoryStorage) getStudentChanges(studentId int, since time.Time) (StudentChanges, error) {
+	return StudentChanges{
+		Since:       since.UnixMilli(),
+		Cursor:      max(since.UnixMilli(), time.Now().Add(-StudentChangesCursorLag).UnixMilli()),
+		Disciplines: make([]DisciplineChanges, 0),
+	}, nil
+}
+
+// getAnomalies returns empty report, fixture is validated on load and can not contain malformed values
+func (storage *MemoryStorage) getAnomalies() AnomalyReport {
+	return (*AnomalyCounter)(nil).getReport()
+}
+
+func (storage *MemoryStorage) getActualStudentDisciplines(studentId int) ([]DisciplineSemester, error) {
+	return storage.rules.selectActualDisciplines(
+		storage.studentDisciplines[studentId][1], storage.studentDisciplines[studentId][2],
+		func(disciplineId int) (time.Time, error) {
+			return storage.disciplines[disciplineId].updatedAt, nil
+		},
+	)
+}
+
+func (storage *MemoryStorage) makeDisciplineScoreResult(disciplineId int, studentId int) DisciplineScoreResult {
+	return DisciplineScoreResult{
+		Discipline:  storage.getDiscipline(disciplineId),
+		ScoreRating: storage.rules.zeroTotalsMode.makeScoreRating(storage.disciplines[disciplineId].totals, studentId),
+		Attendance:  storage.getAttendance(disciplineId, studentId).total(),
+	}
+}
+
+func (storage *MemoryStorage) getDiscipline(disciplineId int) scoreApi.Discipline {
+	return scoreApi.Discipline{
+		Id:   disciplineId,
+		Name: storage.disciplines[disciplineId].name,
+	}
+}
+
+func (storage *MemoryStorage) getScores(disciplineId int, studentId int) []Score {
+	discipline := storage.disciplines[disciplineId]
+
+	return makeScores(discipline.scoreEntries[studentId], discipline.lessons, discipline.deletedLessons)
+}
+
+// getAttendance matches AttendanceLoader: only lesson type id is filled, absences on deleted lessons are ignored
+func (storage *MemoryStorage) getAttendance(disciplineId int, studentId int) AttendanceByLessonType {
+	discipline := storage.disciplines[disciplineId]
+
+	lessons := make([]scoreApi.Lesson, 0, len(discipline.lessons))
+	for _, lesson := range discipline.lessons {
+		lessons = append(lessons, scoreApi.Lesson{
+			Id:   lesson.Id,
+			Date: lesson.Date,
+			Type: scoreApi.LessonType{Id: lesson.Type.Id},
+		})
+	}
+
+	absentLessonIds := make(map[int]bool)
+	for _, scoreEntry := range discipline.scoreEntries[studentId] {
+		if scoreEntry.isAbsent() {
+			absentLessonIds[scoreEntry.LessonId] = true
+		}
+	}
+
+	return calculateAttendance(lessons, absentLessonIds, time.Now().In(storage.rules.academicLocation()))
+}
+
+// getGroupTotals picks discipline totals of group members, members not enrolled to the discipline are skipped
+func (storage *MemoryStorage) getGroupTotals(groupId int, disciplineId int) map[int]float32 {
+	disciplineTotals := storage.disciplines[disciplineId].totals
+
+	totals := make(map[int]float32)
+	for _, studentId := range storage.groups[groupId] {
+		if total, exists := disciplineTotals[studentId]; exists {
+			totals[studentId] = total
+		}
+	}
+
+	return totals
+}
+
+func sortedStudentIds(totals map[int]float32) []int {
+	studentIds := make([]int, 0, len(totals))
+	for studentId := range totals {
+		studentIds = append(studentIds, studentId)
+	}
+	sort.Ints(studentIds)
+
+	return studentIds
+}
@@ -0,0 +1,391 @@
+package main
+
+import (
+	scoreApi "github.com/kneu-messenger-pigeon/score-api"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func getTestMemoryStorage(t *testing.T) *MemoryStorage {
+	storage, err := loadMemoryStorage("fixture.example.yaml", getTestAcademicRules())
+	assert.NoError(t, err)
+
+	return storage
+}
+
+func TestMemoryStorage(t *testing.T) {
+	lessonTypes := map[int]scoreApi.LessonType{
+		1:  {Id: 1, ShortName: "Лек", LongName: "Лекція"},
+		2:  {Id: 2, ShortName: "ПрЗн", LongName: "Практичне зан."},
+		15: {Id: 15, ShortName: "МК", LongName: "Модульний контроль."},
+	}
+	lesson245 := scoreApi.Lesson{Id: 245, Date: time.Date(2026, time.Month(9), 8, 0, 0, 0, 0, defaultAcademicLocation), Type: lessonTypes[2]}
+	lesson246 := scoreApi.Lesson{Id: 246, Date: time.Date(2026, time.Month(9), 15, 0, 0, 0, 0, defaultAcademicLocation), Type: lessonTypes[2]}
+	lesson247 := scoreApi.Lesson{Id: 247, Date: time.Date(2026, time.Month(9), 22, 0, 0, 0, 0, defaultAcademicLocation), Type: lessonTypes[1]}
+	lesson248 := scoreApi.Lesson{Id: 248, Date: time.Date(2026, time.Month(9), 29, 0, 0, 0, 0, defaultAcademicLocation), Type: lessonTypes[15]}
+	capital := scoreApi.Discipline{Id: 199, Name: "Капітал!"}
+
+	t.Run("getDisciplineScoreResultsByStudentId", func(t *testing.T) {
+		results, err := getTestMemoryStorage(t).getDisciplineScoreResultsByStudentId(1200)
+
+		assert.NoError(t, err)
+		assert.Len(t, results, 2)
+		assert.Equal(t, capital, results[0].Discipline)
+		assert.Equal(t, scoreApi.ScoreRating{
+			Total:         7.5,
+			StudentsCount: 3,
+			Rating:        2,
+			MinTotal:      7.5,
+			MaxTotal:      13,
+		}, results[0].ScoreRating.ScoreRating)
+		assert.Equal(t, float32(50), results[0].ScoreRating.Percentile)
+		assert.Equal(t, 3, results[0].Attendance.TotalLessons)
+		assert.Equal(t, 1, results[0].Attendance.Absent)
+		assert.Equal(t, LessonDates{{Time: lesson246.Date}}, results[0].Attendance.AbsentDates)
+		assert.Nil(t, results[0].Scores)
+		assert.Equal(t, "Політична економія", results[1].Discipline.Name)
+	})
+
+	t.Run("getDisciplineScoreResultByStudentId", func(t *testing.T) {
+		storage := getTestMemoryStorage(t)
+
+		result, err := storage.getDisciplineScoreResultByStudentId(1200, 199)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []Score{
+			{Score: scoreApi.Score{Lesson: lesson245, FirstScore: floatPointer(4.5), SecondScore: floatPointer(2)}},
+			{Score: scoreApi.Score{Lesson: lesson246, IsAbsent: true}},
+			{Score: scoreApi.Score{Lesson: lesson247, FirstScore: floatPointer(1)}, Deleted: true},
+		}, result.Scores)
+
+		assert.Equal(t, 17, result.GroupRating.GroupId)
+		assert.Equal(t, scoreApi.ScoreRating{
+			Total:         7.5,
+			StudentsCount: 2,
+			Rating:        2,
+			MinTotal:      7.5,
+			MaxTotal:      13,
+		}, result.GroupRating.ScoreRating.ScoreRating)
+
+		result, err = storage.getDisciplineScoreResultByStudentId(1400, 199)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, result.ScoreRating.Rating)
+		assert.Nil(t, result.GroupRating)
+		assert.Empty(t, result.Scores)
+
+		result, err = storage.getDisciplineScoreResultByStudentId(1200, 999)
+		assert.NoError(t, err)
+		assert.Empty(t, result)
+	})
+
+	t.Run("getDisciplineScore", func(t *testing.T) {
+		storage := getTestMemoryStorage(t)
+
+		disciplineScore, err := storage.getDisciplineScore(1200, 199, 247)
+		assert.NoError(t, err)
+		assert.Equal(t, DisciplineScore{
+			Discipline: capital,
+			Score:      Score{Score: scoreApi.Score{Lesson: lesson247, FirstScore: floatPointer(1)}, Deleted: true},
+		}, disciplineScore)
+
+		disciplineScore, _ = storage.getDisciplineScore(1200, 199, 248)
+		assert.Equal(t, Score{Score: scoreApi.Score{Lesson: lesson248}}, disciplineScore.Score)
+
+		disciplineScore, _ = storage.getDisciplineScore(1200, 199, 999)
+		assert.Equal(t, capital, disciplineScore.Discipline)
+		assert.Empty(t, disciplineScore.Score)
+
+		disciplineScore, _ = storage.getDisciplineScore(1200, 999, 245)
+		assert.Empty(t, disciplineScore)
+	})
+
+	t.Run("getDisciplineDeletedLessons", func(t *testing.T) {
+		storage := getTestMemoryStorage(t)
+
+		deletedLessons, err := storage.getDisciplineDeletedLessons(1300, 199)
+
+		assert.NoError(t, err)
+		assert.Equal(t, DisciplineDeletedLessons{
+			Discipline: capital,
+			Lessons:    []Score{{Score: scoreApi.Score{Lesson: lesson247}, Deleted: true}},
+		}, deletedLessons)
+
+		deletedLessons, _ = storage.getDisciplineDeletedLessons(1300, 999)
+		assert.Empty(t, deletedLessons)
+	})
+
+	t.Run("getStudentAttendance", func(t *testing.T) {
+		attendance, err := getTestMemoryStorage(t).getStudentAttendance(1200)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 4, attendance.TotalLessons)
+		assert.Equal(t, 3, attendance.Attended)
+		assert.Len(t, attendance.LessonTypes, 3)
+		assert.Equal(t, lessonTypes[1], attendance.LessonTypes[0].LessonType)
+	})
+
+	t.Run("getDisciplineTimeline", func(t *testing.T) {
+		storage := getTestMemoryStorage(t)
+
+		timeline, err := storage.getDisciplineTimeline(1200, 199)
+
+		assert.NoError(t, err)
+		assert.Equal(t, capital, timeline.Discipline)
+		assert.Equal(t, []scoreApi.LessonType{lessonTypes[2]}, timeline.LessonTypes)
+		assert.NotEmpty(t, timeline.Points)
+
+		timeline, _ = storage.getDisciplineTimeline(1200, 999)
+		assert.Empty(t, timeline)
+	})
+
+	t.Run("getStudentTranscript", func(t *testing.T) {
+		transcript, err := getTestMemoryStorage(t).getStudentTranscript(1200)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2026, transcript.Year)
+		assert.Equal(t, 1, transcript.Semester)
+		assert.Len(t, transcript.Disciplines, 2)
+		assert.Len(t, transcript.Disciplines[0].Scores, 3)
+	})
+
+	t.Run("getStudentLessons", func(t *testing.T) {
+		disciplinesLessons, err := getTestMemoryStorage(t).getStudentLessons(1200)
+
+		assert.NoError(t, err)
+		assert.Len(t, disciplinesLessons, 2)
+		assert.Equal(t, []Score{
+			{Score: scoreApi.Score{Lesson: lesson245, FirstScore: floatPointer(4.5), SecondScore: floatPointer(2)}},
+			{Score: scoreApi.Score{Lesson: lesson246, IsAbsent: true}},
+			{Score: scoreApi.Score{Lesson: lesson248}},
+		}, disciplinesLessons[0].Lessons)
+	})
+
+	t.Run("getStudentProfile", func(t *testing.T) {
+		storage := getTestMemoryStorage(t)
+
+		profile, err := storage.getStudentProfile(1200)
+
+		assert.NoError(t, err)
+		assert.Equal(t, StudentProfile{
+			Id:               1200,
+			Name:             "Іван Франко",
+			Group:            "ЕК-201",
+			Faculty:          "Факультет економіки та управління",
+			StudyForm:        "денна",
+			Semesters:        []YearSemester{{Year: 2026, Semester: 1}},
+			DisciplinesCount: 2,
+		}, profile)
+
+		profile, err = storage.getStudentProfile(9999)
+		assert.NoError(t, err)
+		assert.Empty(t, profile)
+	})
+
+	t.Run("studentExists", func(t *testing.T) {
+		storage := getTestMemoryStorage(t)
+
+		exists, err := storage.studentExists(1200)
+		assert.NoError(t, err)
+		assert.True(t, exists)
+
+		exists, err = storage.studentExists(9999)
+		assert.NoError(t, err)
+		assert.False(t, exists)
+	})
+
+	t.Run("getStudentChanges", func(t *testing.T) {
+		changes, err := getTestMemoryStorage(t).getStudentChanges(1200, time.UnixMilli(1773576000000))
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1773576000000), changes.Since)
+		assert.InDelta(t, time.Now().Add(-StudentChangesCursorLag).UnixMilli(), changes.Cursor, 1000)
+		assert.Equal(t, []DisciplineChanges{}, changes.Disciplines)
+
+		changes, err = getTestMemoryStorage(t).getStudentChanges(1200, time.Now())
+
+		assert.NoError(t, err)
+		assert.Equal(t, changes.Since, changes.Cursor)
+	})
+
+	t.Run("getStudentSummary", func(t *testing.T) {
+		summary, err := getTestMemoryStorage(t).getStudentSummary(1200)
+
+		assert.NoError(t, err)
+		assert.Len(t, summary.Disciplines, 2)
+		assert.Equal(t, float32(4.5), summary.Disciplines[0].Credits)
+		assert.Equal(t, DefaultDisciplineCredits, summary.Disciplines[1].Credits)
+		// (7.5*4.5 + 0*1) / 5.5
+		assert.Equal(t, float32(6.14), summary.WeightedAverage)
+		assert.True(t, summary.Disciplines[0].HasScores)
+		assert.False(t, summary.Disciplines[1].HasScores)
+		assert.Equal(t, 1, summary.BelowThresholdCount)
+		assert.Equal(t, capital, summary.Best.Discipline)
+		assert.Equal(t, capital, summary.Worst.Discipline)
+	})
+
+	t.Run("getGroupLeaderboard", func(t *testing.T) {
+		storage := getTestMemoryStorage(t)
+
+		leaderboard, err := storage.getGroupLeaderboard(17, 199)
+
+		assert.NoError(t, err)
+		assert.Equal(t, GroupLeaderboard{
+			GroupId: 17,
+			DisciplineRating: DisciplineRating{
+				Discipline: capital,
+				Year:       2026,
+				Semester:   1,
+				Students: []StudentTotal{
+					{StudentId: 1300, Total: 13, Rating: 1},
+					{StudentId: 1200, Total: 7.5, Rating: 2},
+				},
+			},
+		}, leaderboard)
+
+		leaderboard, err = storage.getGroupLeaderboard(18, 199)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, leaderboard.GroupId)
+		assert.Equal(t, capital, leaderboard.Discipline)
+
+		leaderboard, err = storage.getGroupLeaderboard(17, 999)
+		assert.NoError(t, err)
+		assert.Empty(t, leaderboard)
+	})
+
+	t.Run("getDisciplineRisk", func(t *testing.T) {
+		storage := getTestMemoryStorage(t)
+
+		result, err := storage.getDisciplineRisk(1200, 199)
+
+		assert.NoError(t, err)
+		assert.Equal(t, capital, result.Discipline)
+		assert.Equal(t, RiskHigh, result.Risk.Level)
+		assert.Equal(t, float32(7.5), result.Risk.Total)
+		assert.Equal(t, float32(7.5), result.Risk.ProjectedTotal)
+		assert.Equal(t, float32(6.5), result.Risk.AveragePerLesson)
+		assert.Equal(t, 3, result.Risk.HeldLessons)
+		assert.Equal(t, float32(0.33), result.Risk.AbsenceRatio)
+
+		result, err = storage.getDisciplineRisk(1200, 999)
+		assert.NoError(t, err)
+		assert.Empty(t, result)
+	})
+
+	t.Run("getStudentDisciplinesRisks", func(t *testing.T) {
+		results, err := getTestMemoryStorage(t).getStudentDisciplinesRisks(1200)
+
+		assert.NoError(t, err)
+		assert.Len(t, results, 2)
+		assert.Equal(t, capital, results[0].Discipline)
+		assert.Equal(t, "Політична економія", results[1].Discipline.Name)
+		assert.Equal(t, RiskHigh, results[1].Risk.Level)
+	})
+
+	t.Run("getDisciplineAtRiskStudents", func(t *testing.T) {
+		storage := getTestMemoryStorage(t)
+
+		disciplineAtRisk, err := storage.getDisciplineAtRiskStudents(199)
+
+		assert.NoError(t, err)
+		assert.Equal(t, capital, disciplineAtRisk.Discipline)
+		assert.Equal(t, 2026, disciplineAtRisk.Year)
+		assert.Equal(t, 1, disciplineAtRisk.Semester)
+		studentIds := make([]int, len(disciplineAtRisk.Students))
+		for index, studentRisk := range disciplineAtRisk.Students {
+			studentIds[index] = studentRisk.StudentId
+		}
+		assert.Equal(t, []int{1400, 1200, 1300}, studentIds)
+
+		disciplineAtRisk, err = storage.getDisciplineAtRiskStudents(999)
+		assert.NoError(t, err)
+		assert.Empty(t, disciplineAtRisk)
+	})
+
+	t.Run("getAnomalies", func(t *testing.T) {
+		assert.Equal(t, AnomalyReport{Kinds: []AnomalyKindReport{}}, getTestMemoryStorage(t).getAnomalies())
+	})
+
+	t.Run("semester_switch", func(t *testing.T) {
+		storage, err := NewMemoryStorage(StorageFixture{
+			Year: 2026,
+			Disciplines: []FixtureDiscipline{
+				{Id: 1, Semester: 1, UpdatedAt: time.Now().Add(-MaxSemesterUpdatedInterval - time.Hour)},
+				{Id: 2, Semester: 1, UpdatedAt: time.Now()},
+				{Id: 3, Semester: 2, UpdatedAt: time.Now()},
+			},
+			Students: []FixtureStudent{{Id: 1200, Disciplines: []int{1, 2, 3}}},
+		}, getTestAcademicRules())
+		assert.NoError(t, err)
+
+		disciplines, err := storage.getActualStudentDisciplines(1200)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []DisciplineSemester{
+			{Semester: 1, DisciplineId: 2},
+			{Semester: 2, DisciplineId: 3},
+		}, disciplines)
+	})
+}
+
+func TestNewMemoryStorageErrors(t *testing.T) {
+	lessonTypes := []FixtureLessonType{{Id: 1, ShortName: "Лек", LongName: "Лекція"}}
+
+	testCases := map[string]StorageFixture{
+		"fixture year is missing": {},
+		"fixture discipline 0: positive id expected": {
+			Year: 2026, Disciplines: []FixtureDiscipline{{Semester: 1}},
+		},
+		"fixture discipline 199: duplicated id": {
+			Year: 2026, Disciplines: []FixtureDiscipline{{Id: 199, Semester: 1}, {Id: 199, Semester: 1}},
+		},
+		"fixture discipline 199: not negative credits expected": {
+			Year: 2026, Disciplines: []FixtureDiscipline{{Id: 199, Semester: 1, Credits: -1}},
+		},
+		"fixture discipline 199: semester 1 or 2 expected, got 3": {
+			Year: 2026, Disciplines: []FixtureDiscipline{{Id: 199, Semester: 3}},
+		},
+		"fixture discipline 199: lesson 245 has unknown type 2": {
+			Year: 2026, LessonTypes: lessonTypes,
+			Disciplines: []FixtureDiscipline{{Id: 199, Semester: 1, Lessons: []FixtureLesson{{Id: 245, Date: "2026-09-08", Type: 2}}}},
+		},
+		`fixture discipline 199: lesson 245 has wrong date: parsing time "08.09.2026" as "2006-01-02": cannot parse "08.09.2026" as "2006"`: {
+			Year: 2026, LessonTypes: lessonTypes,
+			Disciplines: []FixtureDiscipline{{Id: 199, Semester: 1, Lessons: []FixtureLesson{{Id: 245, Date: "08.09.2026", Type: 1}}}},
+		},
+		"fixture discipline 199: lesson 245 has duplicated or not positive id": {
+			Year: 2026, LessonTypes: lessonTypes,
+			Disciplines: []FixtureDiscipline{{Id: 199, Semester: 1, Lessons: []FixtureLesson{
+				{Id: 245, Date: "2026-09-08", Type: 1},
+				{Id: 245, Date: "2026-09-08", Type: 1, Deleted: true},
+			}}},
+		},
+		"fixture discipline 199: score of student 1200 has unknown lesson 245": {
+			Year: 2026, Disciplines: []FixtureDiscipline{{Id: 199, Semester: 1, Scores: []FixtureScore{{Student: 1200, Lesson: 245}}}},
+		},
+		"fixture discipline 199: score of lesson 245 has not positive student id": {
+			Year: 2026, LessonTypes: lessonTypes,
+			Disciplines: []FixtureDiscipline{{
+				Id: 199, Semester: 1,
+				Lessons: []FixtureLesson{{Id: 245, Date: "2026-09-08", Type: 1}},
+				Scores:  []FixtureScore{{Lesson: 245, Absent: true}},
+			}},
+		},
+		"fixture student 0: positive id expected": {
+			Year: 2026, Students: []FixtureStudent{{}},
+		},
+		"fixture student 1200: not negative group expected": {
+			Year: 2026, Students: []FixtureStudent{{Id: 1200, Group: -1}},
+		},
+		"fixture student 1200: unknown discipline 199": {
+			Year: 2026, Students: []FixtureStudent{{Id: 1200, Disciplines: []int{199}}},
+		},
+	}
+
+	for expectedError, fixture := range testCases {
+		storage, err := NewMemoryStorage(fixture, getTestAcademicRules())
+
+		assert.EqualError(t, err, expectedError)
+		assert.Nil(t, storage)
+	}
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const lessonDateLayout = "060102"
+
+var ErrMalformedId = errors.New("malformed id")
+var ErrMalformedLessonValue = errors.New("malformed lesson value")
+var ErrMalformedScoreField = errors.New("malformed score field")
+var ErrMalformedScoreValue = errors.New("malformed score value")
+var ErrMalformedSemesterUpdatedAt = errors.New("malformed discipline semester updated at")
+var ErrMalformedCredits = errors.New("malformed discipline credits")
+
+// CodecError describes a value which does not follow its Redis encoding. Kind is one of ErrMalformed* errors.
+type CodecError struct {
+	Kind   error
+	Value  string
+	Reason string
+}
+
+func (err *CodecError) Error() string {
+	return fmt.Sprintf("%s %q: %s", err.Kind, err.Value, err.Reason)
+}
+
+func (err *CodecError) Unwrap() error {
+	return err.Kind
+}
+
+// ScoreEntry - decoded field and value of student discipline scores hash
+type ScoreEntry struct {
+	LessonId int
+	Half     int
+	Value    float32
+}
+
+func (entry ScoreEntry) isAbsent() bool {
+	return entry.Value == IsAbsentScoreValue
+}
+
+// RedisCodec decodes and encodes compact values written to Redis by score storage:
+//   - ids of disciplines, lessons and students: positive integers
+//   - lesson value: `YYMMDD<lessonTypeId>`, e.g. `2302121`
+//   - score field: `<lessonId>:<half>`, e.g. `245:1`
+//   - score value: float, IsAbsentScoreValue means absence
+//   - discipline semester updated at: `<semester><unix timestamp>`, e.g. `21676152800`
+//   - discipline credits: positive float, e.g. `4.5`
+type RedisCodec struct {
+	location *time.Location
+}
+
+// academicLocation - timezone of decoded dates, codec without location uses the default one
+func (codec RedisCodec) academicLocation() *time.Location {
+	if codec.location == nil {
+		return defaultAcademicLocation
+	}
+
+	return codec.location
+}
+
+func (codec RedisCodec) decodeId(value string) (int, error) {
+	id, err := strconv.Atoi(value)
+	if err != nil || id <= 0 {
+		return 0, &CodecError{Kind: ErrMalformedId, Value: value, Reason: "positive integer expected"}
+	}
+
+	return id, nil
+}
+
+func (codec RedisCodec) decodeLessonValue(value string) (date time.Time, typeId int, err error) {
+	if len(value) < len(lessonDateLayout)+1 {
+		return time.Time{}, 0, &CodecError{Kind: ErrMalformedLessonValue, Value: value, Reason: "too short"}
+	}
+
+	date, err = time.ParseInLocation(lessonDateLayout, value[:len(lessonDateLayout)], codec.academicLocation())
+	if err != nil {
+		return time.Time{}, 0, &CodecError{Kind: ErrMalformedLessonValue, Value: value, Reason: "wrong date"}
+	}
+
+	typeId, err = strconv.Atoi(value[len(lessonDateLayout):])
+	if err != nil || typeId <= 0 {
+		return time.Time{}, 0, &CodecError{Kind: ErrMalformedLessonValue, Value: value, Reason: "wrong lesson type id"}
+	}
+
+	return date, typeId, nil
+}
+
+func (codec RedisCodec) encodeLessonValue(date time.Time, typeId int) string {
+	return date.Format(lessonDateLayout) + strconv.Itoa(typeId)
+}
+
+func (codec RedisCodec) decodeScoreEntry(field string, value string) (ScoreEntry, error) {
+	lessonIdString, halfString, found := strings.Cut(field, ":")
+	if !found {
+		return ScoreEntry{}, &CodecError{Kind: ErrMalformedScoreField, Value: field, Reason: "separator expected"}
+	}
+
+	lessonId, err := strconv.Atoi(lessonIdString)
+	if err != nil || lessonId <= 0 {
+		return ScoreEntry{}, &CodecError{Kind: ErrMalformedScoreField, Value: field, Reason: "wrong lesson id"}
+	}
+
+	if halfString != "1" && halfString != "2" {
+		return ScoreEntry{}, &CodecError{Kind: ErrMalformedScoreField, Value: field, Reason: "lesson half 1 or 2 expected"}
+	}
+
+	scoreValue, err := codec.decodeScoreValue(value)
+	if err != nil {
+		return ScoreEntry{}, err
+	}
+
+	return ScoreEntry{
+		LessonId: lessonId,
+		Half:     int(halfString[0] - '0'),
+		Value:    scoreValue,
+	}, nil
+}
+
+func (codec RedisCodec) encodeScoreField(lessonId int, half int) string {
+	return strconv.Itoa(lessonId) + ":" + strconv.Itoa(half)
+}
+
+func (codec RedisCodec) decodeScoreValue(value string) (float32, error) {
+	scoreValue, err := strconv.ParseFloat(value, 32)
+	if err != nil || math.IsNaN(scoreValue) || math.IsInf(scoreValue, 0) {
+		return 0, &CodecError{Kind: ErrMalformedScoreValue, Value: value, Reason: "number expected"}
+	}
+
+	return float32(scoreValue), nil
+}
+
+func (codec RedisCodec) encodeScoreValue(value float32) string {
+	return strconv.FormatFloat(float64(value), 'f', -1, 32)
+}
+
+func (codec RedisCodec) decodeCredits(value string) (float32, error) {
+	credits, err := strconv.ParseFloat(value, 32)
+	if err != nil || math.IsNaN(credits) || math.IsInf(credits, 0) || credits <= 0 {
+		return 0, &CodecError{Kind: ErrMalformedCredits, Value: value, Reason: "positive number expected"}
+	}
+
+	return float32(credits), nil
+}
+
+func (codec RedisCodec) decodeSemesterUpdatedAt(value string) (semester int, updatedAt time.Time, err error) {
+	if len(value) < 2 {
+		return 0, time.Time{}, &CodecError{Kind: ErrMalformedSemesterUpdatedAt, Value: value, Reason: "too short"}
+	}
+
+	if value[0] != '1' && value[0] != '2' {
+		return 0, time.Time{}, &CodecError{Kind: ErrMalformedSemesterUpdatedAt, Value: value, Reason: "semester 1 or 2 expected"}
+	}
+
+	unixTimestamp, err := strconv.ParseInt(value[1:], 10, 64)
+	if err != nil || unixTimestamp < 0 {
+		return 0, time.Time{}, &CodecError{Kind: ErrMalformedSemesterUpdatedAt, Value: value, Reason: "wrong timestamp"}
+	}
+
+	return int(value[0] - '0'), time.Unix(unixTimestamp, 0).In(codec.academicLocation()), nil
+}
+
+func (codec RedisCodec) encodeSemesterUpdatedAt(semester int, updatedAt time.Time) string {
+	return strconv.Itoa(semester) + strconv.FormatInt(updatedAt.Unix(), 10)
+}
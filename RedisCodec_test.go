@@ -0,0 +1,121 @@
+package main
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestRedisCodecDecodeId(t *testing.T) {
+	codec := RedisCodec{}
+
+	id, err := codec.decodeId("245")
+	assert.NoError(t, err)
+	assert.Equal(t, 245, id)
+
+	for _, value := range []string{"", "0", "-5", "24a", "1.5"} {
+		_, err = codec.decodeId(value)
+		assert.ErrorIs(t, err, ErrMalformedId, value)
+	}
+}
+
+func TestRedisCodecLessonValue(t *testing.T) {
+	codec := RedisCodec{}
+
+	t.Run("success", func(t *testing.T) {
+		date, typeId, err := codec.decodeLessonValue("23021415")
+
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2023, time.Month(2), 14, 0, 0, 0, 0, defaultAcademicLocation), date)
+		assert.Equal(t, 15, typeId)
+		assert.Equal(t, "23021415", codec.encodeLessonValue(date, typeId))
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		for value, reason := range map[string]string{
+			"":         "too short",
+			"230214":   "too short",
+			"2302141x": "wrong lesson type id",
+			"2302140":  "wrong lesson type id",
+			"2313011":  "wrong date",
+			"2302301":  "wrong date",
+			"aa02141":  "wrong date",
+		} {
+			_, _, err := codec.decodeLessonValue(value)
+
+			var codecError *CodecError
+			assert.True(t, errors.As(err, &codecError), value)
+			assert.Equal(t, ErrMalformedLessonValue, codecError.Kind, value)
+			assert.Equal(t, value, codecError.Value)
+			assert.Equal(t, reason, codecError.Reason, value)
+		}
+	})
+}
+
+func TestRedisCodecScoreEntry(t *testing.T) {
+	codec := RedisCodec{}
+
+	t.Run("success", func(t *testing.T) {
+		scoreEntry, err := codec.decodeScoreEntry("245:2", "4.5")
+
+		assert.NoError(t, err)
+		assert.Equal(t, ScoreEntry{LessonId: 245, Half: 2, Value: 4.5}, scoreEntry)
+		assert.False(t, scoreEntry.isAbsent())
+		assert.Equal(t, "245:2", codec.encodeScoreField(245, 2))
+		assert.Equal(t, "4.5", codec.encodeScoreValue(4.5))
+
+		scoreEntry, err = codec.decodeScoreEntry("245:1", codec.encodeScoreValue(IsAbsentScoreValue))
+
+		assert.NoError(t, err)
+		assert.True(t, scoreEntry.isAbsent())
+	})
+
+	t.Run("malformed_field", func(t *testing.T) {
+		for _, field := range []string{"", "5", "2451", "abc:1", "0:1", "245:3", "245:", "245:1:2"} {
+			_, err := codec.decodeScoreEntry(field, "1")
+			assert.ErrorIs(t, err, ErrMalformedScoreField, field)
+		}
+	})
+
+	t.Run("malformed_value", func(t *testing.T) {
+		for _, value := range []string{"", "abc", "NaN", "+Inf", "1e100"} {
+			_, err := codec.decodeScoreEntry("245:1", value)
+			assert.ErrorIs(t, err, ErrMalformedScoreValue, value)
+		}
+	})
+}
+
+func TestRedisCodecSemesterUpdatedAt(t *testing.T) {
+	codec := RedisCodec{}
+
+	t.Run("success", func(t *testing.T) {
+		semester, updatedAt, err := codec.decodeSemesterUpdatedAt("21676152800")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, semester)
+		assert.Equal(t, int64(1676152800), updatedAt.Unix())
+		assert.Equal(t, defaultAcademicLocation, updatedAt.Location())
+		assert.Equal(t, "21676152800", codec.encodeSemesterUpdatedAt(semester, updatedAt))
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		for _, value := range []string{"", "1", "31676152800", "a1676152800", "1abc", "1-5"} {
+			_, _, err := codec.decodeSemesterUpdatedAt(value)
+			assert.ErrorIs(t, err, ErrMalformedSemesterUpdatedAt, value)
+		}
+	})
+}
+
+func TestRedisCodecCredits(t *testing.T) {
+	codec := RedisCodec{}
+
+	credits, err := codec.decodeCredits("4.5")
+	assert.NoError(t, err)
+	assert.Equal(t, float32(4.5), credits)
+
+	for _, value := range []string{"", "0", "-3", "abc", "NaN", "+Inf"} {
+		_, err = codec.decodeCredits(value)
+		assert.ErrorIs(t, err, ErrMalformedCredits, value)
+	}
+}
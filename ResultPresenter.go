@@ -0,0 +1,30 @@
+package main
+
+// ResultPresenter localizes lesson types to the language and formats lesson dates of results,
+// so API handlers and inspect commands output the same JSON
+type ResultPresenter struct {
+	translator *Translator
+	dateFormat LessonDateFormat
+}
+
+func (presenter ResultPresenter) presentDisciplineScoreResults(language string, results DisciplineScoreResults) {
+	for index := range results {
+		presenter.presentDisciplineScoreResult(language, &results[index])
+	}
+}
+
+func (presenter ResultPresenter) presentDisciplineScoreResult(language string, result *DisciplineScoreResult) {
+	presenter.translator.loaded().localizeScores(language, result.Scores)
+	presenter.dateFormat.formatScores(result.Scores)
+	presenter.dateFormat.formatAttendance(&result.Attendance)
+}
+
+func (presenter ResultPresenter) presentDisciplineScore(language string, disciplineScore *DisciplineScore) {
+	disciplineScore.Score.Lesson.Type = presenter.translator.lessonType(language, disciplineScore.Score.Lesson.Type)
+	presenter.dateFormat.formatScore(&disciplineScore.Score)
+}
+
+func (presenter ResultPresenter) presentDisciplineLessons(language string, disciplineLessons *DisciplineLessons) {
+	presenter.translator.loaded().localizeScores(language, disciplineLessons.Lessons)
+	presenter.dateFormat.formatScores(disciplineLessons.Lessons)
+}
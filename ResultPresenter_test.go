@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	scoreApi "github.com/kneu-messenger-pigeon/score-api"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestResultPresenter(t *testing.T) {
+	translator, _ := NewTranslator(nil, context.Background())
+	presenter := ResultPresenter{translator: translator, dateFormat: LessonDateFormat{dateOnly: true}}
+	lessonTypes := GetTestLessonTypes()
+
+	makeScore := func() Score {
+		return Score{
+			Score: scoreApi.Score{
+				Lesson: scoreApi.Lesson{
+					Id:   245,
+					Date: time.Date(2023, time.Month(2), 12, 0, 0, 0, 0, defaultAcademicLocation),
+					Type: lessonTypes[1],
+				},
+			},
+		}
+	}
+
+	t.Run("discipline_score_results", func(t *testing.T) {
+		results := DisciplineScoreResults{
+			{
+				Scores: []Score{makeScore()},
+				Attendance: Attendance{
+					AbsentDates: LessonDates{{Time: time.Date(2023, time.Month(2), 12, 0, 0, 0, 0, defaultAcademicLocation)}},
+				},
+			},
+		}
+
+		presenter.presentDisciplineScoreResults("en", results)
+
+		assert.Equal(t, "Practical class", results[0].Scores[0].Lesson.Type.LongName)
+		scoreJson, _ := json.Marshal(results[0].Scores[0])
+		assert.Contains(t, string(scoreJson), `"date":"2023-02-12"`)
+		absentDatesJson, _ := json.Marshal(results[0].Attendance.AbsentDates)
+		assert.Equal(t, `["2023-02-12"]`, string(absentDatesJson))
+	})
+
+	t.Run("discipline_score", func(t *testing.T) {
+		disciplineScore := DisciplineScore{Score: makeScore()}
+
+		presenter.presentDisciplineScore("en", &disciplineScore)
+
+		assert.Equal(t, "Pr", disciplineScore.Score.Lesson.Type.ShortName)
+		scoreJson, _ := json.Marshal(disciplineScore.Score)
+		assert.Contains(t, string(scoreJson), `"date":"2023-02-12"`)
+	})
+
+	t.Run("discipline_lessons", func(t *testing.T) {
+		disciplineLessons := DisciplineLessons{Lessons: []Score{makeScore()}}
+
+		presenter.presentDisciplineLessons("", &disciplineLessons)
+
+		assert.Equal(t, lessonTypes[1], disciplineLessons.Lessons[0].Lesson.Type)
+		lessonsJson, _ := json.Marshal(disciplineLessons)
+		assert.Contains(t, string(lessonsJson), `"lessons":[{`)
+		assert.Contains(t, string(lessonsJson), `"date":"2023-02-12"`)
+	})
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"encoding/json"
+	scoreApi "github.com/kneu-messenger-pigeon/score-api"
+)
+
+// Score extends scoreApi.Score with the flag of lesson deleted after the score was set.
+// Lesson date is rendered by LessonDate, dateOnly is set by LessonDateFormat.
+type Score struct {
+	scoreApi.Score
+	Deleted  bool `json:"deleted"`
+	dateOnly bool
+}
+
+type lessonJson struct {
+	Id   int                 `json:"id"`
+	Date LessonDate          `json:"date"`
+	Type scoreApi.LessonType `json:"type"`
+}
+
+func (score Score) MarshalJSON() ([]byte, error) {
+	type scoreAlias Score
+
+	return json.Marshal(struct {
+		Lesson lessonJson `json:"lesson,omitempty"`
+		scoreAlias
+	}{
+		Lesson: lessonJson{
+			Id:   score.Lesson.Id,
+			Date: LessonDate{Time: score.Lesson.Date, dateOnly: score.dateOnly},
+			Type: score.Lesson.Type,
+		},
+		scoreAlias: scoreAlias(score),
+	})
+}
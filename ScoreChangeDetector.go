@@ -0,0 +1,389 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type ScoreChangeType string
+
+const (
+	ScoreChangeNewScore     ScoreChangeType = "new_score"
+	ScoreChangeChangedScore ScoreChangeType = "changed_score"
+	ScoreChangeNewAbsence   ScoreChangeType = "new_absence"
+	ScoreChangeTotal        ScoreChangeType = "changed_total"
+)
+
+// scoreChangeBaselinePrefix - prefix of keys with the last seen copy of scores hash or totals zset (as hash)
+const scoreChangeBaselinePrefix = "score_change_baseline:"
+
+// ScoreChangeBaselineTTL - baseline of the key not changed for this time is removed to free memory,
+// the next change of the key only seeds the baseline again
+const ScoreChangeBaselineTTL = time.Hour * 24 * 30
+
+var ErrNotScoreChangeKey = errors.New("not scores or totals key")
+
+// scoreChangeSwitchAttempts - attempts to switch baseline when the key or its baseline is changed concurrently
+const scoreChangeSwitchAttempts = 3
+
+// ScoreChange - single change in the student discipline scores, Value is empty for absence
+type ScoreChange struct {
+	Type          ScoreChangeType `json:"type"`
+	LessonId      int             `json:"lessonId,omitempty"`
+	Half          int             `json:"half,omitempty"`
+	Value         *float32        `json:"value,omitempty"`
+	PreviousValue *float32        `json:"previousValue,omitempty"`
+}
+
+// ScoreChangeEvent - changes of the student in the discipline detected by one key change, webhook payload
+type ScoreChangeEvent struct {
+	Id           string        `json:"id"`
+	Year         int           `json:"year"`
+	Semester     int           `json:"semester"`
+	StudentId    int           `json:"studentId"`
+	DisciplineId int           `json:"disciplineId"`
+	Changes      []ScoreChange `json:"changes"`
+	DetectedAt   time.Time     `json:"detectedAt"`
+}
+
+// ScoreChangeNotifierInterface queues notification about the event to the transaction switching the baseline
+type ScoreChangeNotifierInterface interface {
+	notify(pipe redis.Pipeliner, event ScoreChangeEvent) error
+}
+
+// ScoreChangeNotifiers queues every event to all notifiers, failure of any notifier discards the transaction
+type ScoreChangeNotifiers []ScoreChangeNotifierInterface
+
+func (notifiers ScoreChangeNotifiers) notify(pipe redis.Pipeliner, event ScoreChangeEvent) error {
+	errs := make([]error, 0, len(notifiers))
+	for _, notifier := range notifiers {
+		errs = append(errs, notifier.notify(pipe, event))
+	}
+
+	return errors.Join(errs...)
+}
+
+// scoreChangeKey - parsed `{year}:{semester}:scores:{studentId}:{disciplineId}` or `{year}:{semester}:totals:{disciplineId}` key,
+// StudentId is zero for totals key
+type scoreChangeKey struct {
+	Year         int
+	Semester     int
+	StudentId    int
+	DisciplineId int
+}
+
+// ScoreChangeDetector handles changes of scores and totals keys and notifies about the difference
+// with the baseline copy of the key.
+type ScoreChangeDetector struct {
+	out         io.Writer
+	redis       *redis.Client
+	codec       RedisCodec
+	notifier    ScoreChangeNotifierInterface
+	baselineTTL time.Duration
+}
+
+// listenScoreKeyChanges calls handle with the name of every changed scores or totals key until ctx is done.
+// Pub/sub notifications are not redelivered, so errors are only logged by handle.
+// Redis should be configured with `notify-keyspace-events Kghz`.
+func listenScoreKeyChanges(ctx context.Context, redisClient *redis.Client, handle func(key string) error) {
+	keyspacePrefix := fmt.Sprintf("__keyspace@%d__:", redisClient.Options().DB)
+	pubsub := redisClient.PSubscribe(ctx, keyspacePrefix+"*:scores:*", keyspacePrefix+"*:totals:*")
+
+	go func() {
+		<-ctx.Done()
+		_ = pubsub.Close()
+	}()
+
+	for message := range pubsub.Channel() {
+		_ = handle(strings.TrimPrefix(message.Channel, keyspacePrefix))
+	}
+}
+
+// handleKeyChange notifies about the difference and returns the logged error. Baseline is switched
+// in the same transaction with queued notifications, so a failed change is detected again by the repeated call.
+func (detector *ScoreChangeDetector) handleKeyChange(key string) error {
+	changeKey, err := parseScoreChangeKey(key)
+	if err != nil {
+		return nil
+	}
+
+	baselineKey := scoreChangeBaselinePrefix + key
+	for attempt := 0; attempt < scoreChangeSwitchAttempts; attempt++ {
+		err = detector.redis.Watch(context.Background(), func(tx *redis.Tx) error {
+			return detector.switchBaseline(tx, key, changeKey)
+		}, key, baselineKey)
+
+		if !errors.Is(err, redis.TxFailedErr) {
+			break
+		}
+	}
+
+	if err != nil {
+		_, _ = fmt.Fprintf(detector.out, "Failed to notify about change of %s: %s\n", key, err)
+	}
+
+	return err
+}
+
+// switchBaseline replaces baseline of the watched key by its current value and queues notifications
+// about the difference in the same transaction
+func (detector *ScoreChangeDetector) switchBaseline(tx *redis.Tx, key string, changeKey scoreChangeKey) error {
+	ctx := context.Background()
+	baselineKey := scoreChangeBaselinePrefix + key
+
+	current, previous, isNewStudent, err := detector.loadBaseline(tx, key, changeKey)
+	if err != nil {
+		return err
+	}
+
+	var events []ScoreChangeEvent
+	if changeKey.StudentId != 0 {
+		events = detector.detectScoresChanges(key, changeKey, current, previous, isNewStudent)
+	} else {
+		events = detector.detectTotalsChanges(key, changeKey, current, previous)
+	}
+
+	_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, baselineKey)
+		if len(current) != 0 {
+			pairs := make([]interface{}, 0, len(current)*2)
+			for _, field := range sortedMapKeys(current) {
+				pairs = append(pairs, field, current[field])
+			}
+			pipe.HSet(ctx, baselineKey, pairs...)
+			pipe.Expire(ctx, baselineKey, detector.baselineTTL)
+		}
+
+		for _, event := range events {
+			if err := detector.notifier.notify(pipe, event); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	return err
+}
+
+// loadBaseline loads current value of the key (totals zset as member-score pairs) and its baseline.
+// isNewStudent is set when scores baseline is missing because the student is new in the discipline
+// that is already tracked by totals baseline.
+func (detector *ScoreChangeDetector) loadBaseline(
+	tx *redis.Tx, key string, changeKey scoreChangeKey,
+) (current map[string]string, previous map[string]string, isNewStudent bool, err error) {
+	ctx := context.Background()
+
+	if changeKey.StudentId != 0 {
+		current, err = tx.HGetAll(ctx, key).Result()
+	} else {
+		var members []redis.Z
+		members, err = tx.ZRangeWithScores(ctx, key, 0, -1).Result()
+		current = make(map[string]string, len(members))
+		for _, member := range members {
+			studentIdString, _ := member.Member.(string)
+			current[studentIdString] = strconv.FormatFloat(member.Score, 'f', -1, 64)
+		}
+	}
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	previous, err = tx.HGetAll(ctx, scoreChangeBaselinePrefix+key).Result()
+	if err != nil || changeKey.StudentId == 0 || len(previous) != 0 {
+		return current, previous, false, err
+	}
+
+	totalsBaselineKey := scoreChangeBaselinePrefix +
+		fmt.Sprintf("%d:%d:totals:%d", changeKey.Year, changeKey.Semester, changeKey.DisciplineId)
+	totalsBaselineExists, err := tx.Exists(ctx, totalsBaselineKey).Result()
+	if err != nil || totalsBaselineExists == 0 {
+		return current, previous, false, err
+	}
+
+	isTrackedStudent, err := tx.HExists(ctx, totalsBaselineKey, strconv.Itoa(changeKey.StudentId)).Result()
+
+	return current, previous, !isTrackedStudent, err
+}
+
+// detectScoresChanges compares scores hash with baseline. Missing baseline (first change after the detector
+// was enabled) is seeded silently, except a new student of the tracked discipline whose every score is reported as new.
+func (detector *ScoreChangeDetector) detectScoresChanges(
+	key string, changeKey scoreChangeKey, current map[string]string, previous map[string]string, isNewStudent bool,
+) []ScoreChangeEvent {
+	if len(previous) == 0 && !isNewStudent {
+		return nil
+	}
+
+	changes := diffScoreEntries(detector.decodeScoreEntries(previous), detector.decodeScoreEntries(current))
+	if len(changes) == 0 {
+		return nil
+	}
+
+	return []ScoreChangeEvent{
+		newScoreChangeEvent(key, changeKey, changeKey.StudentId, changes),
+	}
+}
+
+// detectTotalsChanges compares discipline totals with baseline. The first seen totals only become the baseline,
+// new scores of the students are reported by scores keys.
+func (detector *ScoreChangeDetector) detectTotalsChanges(
+	key string, changeKey scoreChangeKey, current map[string]string, previous map[string]string,
+) []ScoreChangeEvent {
+	if len(previous) == 0 {
+		return nil
+	}
+
+	events := make([]ScoreChangeEvent, 0)
+	for _, studentIdString := range sortedMapKeys(current) {
+		studentId, idErr := detector.codec.decodeId(studentIdString)
+		total, totalErr := detector.codec.decodeScoreValue(current[studentIdString])
+		previousTotal, previousErr := detector.codec.decodeScoreValue(previous[studentIdString])
+		if idErr != nil || totalErr != nil || (previousErr == nil && previousTotal == total) {
+			continue
+		}
+
+		change := ScoreChange{
+			Type:  ScoreChangeTotal,
+			Value: &total,
+		}
+		if previousErr == nil {
+			change.PreviousValue = &previousTotal
+		}
+
+		events = append(events, newScoreChangeEvent(key, changeKey, studentId, []ScoreChange{change}))
+	}
+
+	return events
+}
+
+func (detector *ScoreChangeDetector) decodeScoreEntries(rawScores map[string]string) map[string]ScoreEntry {
+	scoreEntries := make(map[string]ScoreEntry, len(rawScores))
+	for field, value := range rawScores {
+		if scoreEntry, err := detector.codec.decodeScoreEntry(field, value); err == nil {
+			scoreEntries[field] = scoreEntry
+		}
+	}
+
+	return scoreEntries
+}
+
+// diffScoreEntries reports new scores, changed scores and new absences ordered by lesson and half. Removed entries are ignored.
+func diffScoreEntries(previous map[string]ScoreEntry, current map[string]ScoreEntry) []ScoreChange {
+	fields := make([]string, 0, len(current))
+	for field := range current {
+		fields = append(fields, field)
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		if current[fields[i]].LessonId != current[fields[j]].LessonId {
+			return current[fields[i]].LessonId < current[fields[j]].LessonId
+		}
+		return current[fields[i]].Half < current[fields[j]].Half
+	})
+
+	changes := make([]ScoreChange, 0)
+	for _, field := range fields {
+		entry := current[field]
+		previousEntry, existed := previous[field]
+
+		change := ScoreChange{
+			LessonId: entry.LessonId,
+			Half:     entry.Half,
+		}
+		if entry.isAbsent() {
+			if existed && previousEntry.isAbsent() {
+				continue
+			}
+			change.Type = ScoreChangeNewAbsence
+
+		} else if !existed || previousEntry.isAbsent() {
+			change.Type = ScoreChangeNewScore
+			change.Value = &entry.Value
+
+		} else if previousEntry.Value != entry.Value {
+			change.Type = ScoreChangeChangedScore
+			change.Value = &entry.Value
+			change.PreviousValue = &previousEntry.Value
+
+		} else {
+			continue
+		}
+
+		changes = append(changes, change)
+	}
+
+	return changes
+}
+
+func parseScoreChangeKey(key string) (scoreChangeKey, error) {
+	parts := strings.Split(key, ":")
+	isScoresKey := len(parts) == 5 && parts[2] == "scores"
+	isTotalsKey := len(parts) == 4 && parts[2] == "totals"
+	if !isScoresKey && !isTotalsKey {
+		return scoreChangeKey{}, ErrNotScoreChangeKey
+	}
+
+	ids := make([]int, len(parts))
+	for index, part := range parts {
+		if index == 2 {
+			continue
+		}
+
+		id, err := strconv.Atoi(part)
+		if err != nil || id <= 0 {
+			return scoreChangeKey{}, ErrNotScoreChangeKey
+		}
+		ids[index] = id
+	}
+
+	changeKey := scoreChangeKey{
+		Year:         ids[0],
+		Semester:     ids[1],
+		DisciplineId: ids[len(ids)-1],
+	}
+	if isScoresKey {
+		changeKey.StudentId = ids[3]
+	}
+
+	return changeKey, nil
+}
+
+func newScoreChangeEvent(key string, changeKey scoreChangeKey, studentId int, changes []ScoreChange) ScoreChangeEvent {
+	return ScoreChangeEvent{
+		Id:           makeScoreChangeEventId(key, studentId, changes),
+		Year:         changeKey.Year,
+		Semester:     changeKey.Semester,
+		StudentId:    studentId,
+		DisciplineId: changeKey.DisciplineId,
+		Changes:      changes,
+		DetectedAt:   time.Now().UTC(),
+	}
+}
+
+// makeScoreChangeEventId derives event id from the key and the difference,
+// so receivers can drop the same change delivered by several replicas
+func makeScoreChangeEventId(key string, studentId int, changes []ScoreChange) string {
+	encodedChanges, _ := json.Marshal(changes)
+	hash := sha256.Sum256([]byte(key + ":" + strconv.Itoa(studentId) + ":" + string(encodedChanges)))
+
+	return hex.EncodeToString(hash[:16])
+}
+
+func NewScoreChangeDetector(out io.Writer, redis *redis.Client, notifier ScoreChangeNotifierInterface) *ScoreChangeDetector {
+	return &ScoreChangeDetector{
+		out:         out,
+		redis:       redis,
+		notifier:    notifier,
+		baselineTTL: ScoreChangeBaselineTTL,
+	}
+}
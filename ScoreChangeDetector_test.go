@@ -0,0 +1,396 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"github.com/go-redis/redismock/v9"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func TestParseScoreChangeKey(t *testing.T) {
+	changeKey, err := parseScoreChangeKey("2026:2:scores:1200:199")
+	assert.NoError(t, err)
+	assert.Equal(t, scoreChangeKey{Year: 2026, Semester: 2, StudentId: 1200, DisciplineId: 199}, changeKey)
+
+	changeKey, err = parseScoreChangeKey("2026:1:totals:199")
+	assert.NoError(t, err)
+	assert.Equal(t, scoreChangeKey{Year: 2026, Semester: 1, DisciplineId: 199}, changeKey)
+
+	for _, key := range []string{
+		"2026:1:totals:199:1",
+		"2026:1:scores:199",
+		"2026:1:lessons:199",
+		"2026:x:scores:1200:199",
+		"2026:1:scores:0:199",
+		"score_change_baseline:2026:1:totals:199",
+	} {
+		_, err = parseScoreChangeKey(key)
+		assert.ErrorIs(t, err, ErrNotScoreChangeKey, key)
+	}
+}
+
+func TestDiffScoreEntries(t *testing.T) {
+	previous := map[string]ScoreEntry{
+		"245:1": {LessonId: 245, Half: 1, Value: 4.5},
+		"245:2": {LessonId: 245, Half: 2, Value: 2},
+		"246:1": {LessonId: 246, Half: 1, Value: IsAbsentScoreValue},
+		"247:1": {LessonId: 247, Half: 1, Value: IsAbsentScoreValue},
+		"248:1": {LessonId: 248, Half: 1, Value: 3},
+	}
+	current := map[string]ScoreEntry{
+		"250:1": {LessonId: 250, Half: 1, Value: IsAbsentScoreValue},
+		"245:1": {LessonId: 245, Half: 1, Value: 5},
+		"245:2": {LessonId: 245, Half: 2, Value: 2},
+		"246:1": {LessonId: 246, Half: 1, Value: IsAbsentScoreValue},
+		"247:1": {LessonId: 247, Half: 1, Value: 1},
+		"249:2": {LessonId: 249, Half: 2, Value: 0},
+	}
+
+	assert.Equal(t, []ScoreChange{
+		{Type: ScoreChangeChangedScore, LessonId: 245, Half: 1, Value: floatPointer(5), PreviousValue: floatPointer(4.5)},
+		{Type: ScoreChangeNewScore, LessonId: 247, Half: 1, Value: floatPointer(1)},
+		{Type: ScoreChangeNewScore, LessonId: 249, Half: 2, Value: floatPointer(0)},
+		{Type: ScoreChangeNewAbsence, LessonId: 250, Half: 1},
+	}, diffScoreEntries(previous, current))
+
+	assert.Empty(t, diffScoreEntries(previous, previous))
+}
+
+// expectLoadBaseline expects watch of the scores or totals key of discipline 199 in 2026:2 with its baseline
+// and loading of both values
+func expectLoadBaseline(redisMock redismock.ClientMock, key string, current interface{}, previous map[string]string) {
+	redisMock.ExpectWatch(key, "score_change_baseline:"+key)
+	if totals, isTotals := current.([]redis.Z); isTotals {
+		redisMock.ExpectZRangeWithScores(key, 0, -1).SetVal(totals)
+	} else {
+		redisMock.ExpectHGetAll(key).SetVal(current.(map[string]string))
+	}
+	redisMock.ExpectHGetAll("score_change_baseline:" + key).SetVal(previous)
+}
+
+// expectSwitchBaseline expects the transaction which replaces baseline of the key by pairs with 1 hour TTL,
+// queued notifications are expected by queueNotifications
+func expectSwitchBaseline(redisMock redismock.ClientMock, key string, queueNotifications func(), pairs ...interface{}) *redismock.ExpectedSlice {
+	redisMock.ExpectTxPipeline()
+	redisMock.ExpectDel("score_change_baseline:" + key).SetVal(1)
+	if len(pairs) != 0 {
+		redisMock.ExpectHSet("score_change_baseline:"+key, pairs...).SetVal(int64(len(pairs) / 2))
+		redisMock.ExpectExpire("score_change_baseline:"+key, time.Hour).SetVal(true)
+	}
+	if queueNotifications != nil {
+		queueNotifications()
+	}
+
+	return redisMock.ExpectTxPipelineExec()
+}
+
+// queueTestNotification adds event id to webhook queue, like WebhookNotifier does
+func queueTestNotification(args mock.Arguments) {
+	event := args.Get(1).(ScoreChangeEvent)
+	args.Get(0).(redis.Pipeliner).ZAdd(context.Background(), webhookQueueKey, redis.Z{Score: 1, Member: event.Id})
+}
+
+func TestScoreChangeDetectorHandleKeyChange(t *testing.T) {
+	scoresKey := "2026:2:scores:1200:199"
+	totalsKey := "2026:2:totals:199"
+	totalsBaselineKey := "score_change_baseline:" + totalsKey
+
+	t.Run("scores", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.MatchExpectationsInOrder(true)
+
+		expectedChanges := []ScoreChange{
+			{Type: ScoreChangeChangedScore, LessonId: 245, Half: 1, Value: floatPointer(5), PreviousValue: floatPointer(4.5)},
+			{Type: ScoreChangeNewAbsence, LessonId: 246, Half: 1},
+		}
+		expectedEventId := makeScoreChangeEventId(scoresKey, 1200, expectedChanges)
+
+		expectLoadBaseline(
+			redisMock, scoresKey,
+			map[string]string{"245:1": "5", "246:1": "-999999", "wrong": "1"},
+			map[string]string{"245:1": "4.5"},
+		)
+		expectSwitchBaseline(redisMock, scoresKey, func() {
+			redisMock.ExpectZAdd(webhookQueueKey, redis.Z{Score: 1, Member: expectedEventId}).SetVal(1)
+		}, "245:1", "5", "246:1", "-999999", "wrong", "1")
+
+		notifier := NewMockScoreChangeNotifierInterface(t)
+		notifier.On("notify", mock.Anything, mock.MatchedBy(func(event ScoreChangeEvent) bool {
+			return assert.Equal(t, expectedEventId, event.Id) &&
+				assert.Len(t, event.Id, 32) &&
+				assert.WithinDuration(t, time.Now(), event.DetectedAt, time.Minute) &&
+				assert.Equal(t, 2026, event.Year) &&
+				assert.Equal(t, 2, event.Semester) &&
+				assert.Equal(t, 1200, event.StudentId) &&
+				assert.Equal(t, 199, event.DisciplineId) &&
+				assert.Equal(t, expectedChanges, event.Changes)
+		})).Run(queueTestNotification).Return(nil).Once()
+
+		out := &bytes.Buffer{}
+		detector := ScoreChangeDetector{
+			out:         out,
+			redis:       redisClient,
+			notifier:    notifier,
+			baselineTTL: time.Hour,
+		}
+
+		err := detector.handleKeyChange(scoresKey)
+
+		assert.NoError(t, err)
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+		assert.Empty(t, out.String())
+	})
+
+	t.Run("scores_without_baseline", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.MatchExpectationsInOrder(true)
+
+		expectLoadBaseline(redisMock, scoresKey, map[string]string{"245:1": "5"}, map[string]string{})
+		redisMock.ExpectExists(totalsBaselineKey).SetVal(0)
+		expectSwitchBaseline(redisMock, scoresKey, nil, "245:1", "5")
+
+		detector := ScoreChangeDetector{
+			out:         &bytes.Buffer{},
+			redis:       redisClient,
+			notifier:    NewMockScoreChangeNotifierInterface(t),
+			baselineTTL: time.Hour,
+		}
+
+		assert.NoError(t, detector.handleKeyChange(scoresKey))
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+	})
+
+	t.Run("scores_of_tracked_student_without_baseline", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.MatchExpectationsInOrder(true)
+
+		expectLoadBaseline(redisMock, scoresKey, map[string]string{"245:1": "5"}, map[string]string{})
+		redisMock.ExpectExists(totalsBaselineKey).SetVal(1)
+		redisMock.ExpectHExists(totalsBaselineKey, "1200").SetVal(true)
+		expectSwitchBaseline(redisMock, scoresKey, nil, "245:1", "5")
+
+		detector := ScoreChangeDetector{
+			out:         &bytes.Buffer{},
+			redis:       redisClient,
+			notifier:    NewMockScoreChangeNotifierInterface(t),
+			baselineTTL: time.Hour,
+		}
+
+		assert.NoError(t, detector.handleKeyChange(scoresKey))
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+	})
+
+	t.Run("scores_of_new_student", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.MatchExpectationsInOrder(true)
+
+		expectLoadBaseline(redisMock, scoresKey, map[string]string{"245:1": "5"}, map[string]string{})
+		redisMock.ExpectExists(totalsBaselineKey).SetVal(1)
+		redisMock.ExpectHExists(totalsBaselineKey, "1200").SetVal(false)
+		expectSwitchBaseline(redisMock, scoresKey, nil, "245:1", "5")
+
+		notifier := NewMockScoreChangeNotifierInterface(t)
+		notifier.On("notify", mock.Anything, mock.MatchedBy(func(event ScoreChangeEvent) bool {
+			return event.StudentId == 1200 && assert.Equal(t, []ScoreChange{
+				{Type: ScoreChangeNewScore, LessonId: 245, Half: 1, Value: floatPointer(5)},
+			}, event.Changes)
+		})).Return(nil).Once()
+
+		detector := ScoreChangeDetector{
+			out:         &bytes.Buffer{},
+			redis:       redisClient,
+			notifier:    notifier,
+			baselineTTL: time.Hour,
+		}
+
+		assert.NoError(t, detector.handleKeyChange(scoresKey))
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+	})
+
+	t.Run("scores_deleted", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.MatchExpectationsInOrder(true)
+
+		expectLoadBaseline(redisMock, scoresKey, map[string]string{}, map[string]string{"245:1": "4.5"})
+		expectSwitchBaseline(redisMock, scoresKey, nil)
+
+		detector := ScoreChangeDetector{
+			out:         &bytes.Buffer{},
+			redis:       redisClient,
+			notifier:    NewMockScoreChangeNotifierInterface(t),
+			baselineTTL: time.Hour,
+		}
+
+		assert.NoError(t, detector.handleKeyChange(scoresKey))
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+	})
+
+	t.Run("totals", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.MatchExpectationsInOrder(true)
+
+		expectLoadBaseline(
+			redisMock, totalsKey,
+			[]redis.Z{{Score: 7.5, Member: "1200"}, {Score: 13, Member: "1300"}, {Score: 2, Member: "1400"}},
+			map[string]string{"1200": "5", "1300": "13.0"},
+		)
+		expectSwitchBaseline(redisMock, totalsKey, nil, "1200", "7.5", "1300", "13", "1400", "2")
+
+		notifier := NewMockScoreChangeNotifierInterface(t)
+		notifier.On("notify", mock.Anything, mock.MatchedBy(func(event ScoreChangeEvent) bool {
+			return event.StudentId == 1200 && event.DisciplineId == 199 && assert.Equal(t, []ScoreChange{
+				{Type: ScoreChangeTotal, Value: floatPointer(7.5), PreviousValue: floatPointer(5)},
+			}, event.Changes)
+		})).Return(nil).Once()
+		notifier.On("notify", mock.Anything, mock.MatchedBy(func(event ScoreChangeEvent) bool {
+			return event.StudentId == 1400 && assert.Equal(t, []ScoreChange{
+				{Type: ScoreChangeTotal, Value: floatPointer(2)},
+			}, event.Changes)
+		})).Return(nil).Once()
+
+		detector := ScoreChangeDetector{
+			out:         &bytes.Buffer{},
+			redis:       redisClient,
+			notifier:    notifier,
+			baselineTTL: time.Hour,
+		}
+
+		assert.NoError(t, detector.handleKeyChange(totalsKey))
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+	})
+
+	t.Run("totals_without_baseline", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.MatchExpectationsInOrder(true)
+
+		expectLoadBaseline(redisMock, totalsKey, []redis.Z{{Score: 7.5, Member: "1200"}}, map[string]string{})
+		expectSwitchBaseline(redisMock, totalsKey, nil, "1200", "7.5")
+
+		detector := ScoreChangeDetector{
+			out:         &bytes.Buffer{},
+			redis:       redisClient,
+			notifier:    NewMockScoreChangeNotifierInterface(t),
+			baselineTTL: time.Hour,
+		}
+
+		assert.NoError(t, detector.handleKeyChange(totalsKey))
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+	})
+
+	t.Run("concurrent_switch", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.MatchExpectationsInOrder(true)
+
+		expectLoadBaseline(redisMock, scoresKey, map[string]string{"245:1": "5"}, map[string]string{"245:1": "4"})
+		expectSwitchBaseline(redisMock, scoresKey, func() {
+			redisMock.ExpectZAdd(webhookQueueKey, redis.Z{Score: 1, Member: makeScoreChangeEventId(scoresKey, 1200, []ScoreChange{
+				{Type: ScoreChangeChangedScore, LessonId: 245, Half: 1, Value: floatPointer(5), PreviousValue: floatPointer(4)},
+			})}).SetVal(1)
+		}, "245:1", "5").SetErr(redis.TxFailedErr)
+
+		expectLoadBaseline(redisMock, scoresKey, map[string]string{"245:1": "5"}, map[string]string{"245:1": "5"})
+		expectSwitchBaseline(redisMock, scoresKey, nil, "245:1", "5")
+
+		notifier := NewMockScoreChangeNotifierInterface(t)
+		notifier.On("notify", mock.Anything, mock.Anything).Run(queueTestNotification).Return(nil).Once()
+
+		out := &bytes.Buffer{}
+		detector := ScoreChangeDetector{
+			out:         out,
+			redis:       redisClient,
+			notifier:    notifier,
+			baselineTTL: time.Hour,
+		}
+
+		assert.NoError(t, detector.handleKeyChange(scoresKey))
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+		assert.Empty(t, out.String())
+	})
+
+	t.Run("not_score_change_key", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+
+		detector := ScoreChangeDetector{
+			out:         &bytes.Buffer{},
+			redis:       redisClient,
+			notifier:    NewMockScoreChangeNotifierInterface(t),
+			baselineTTL: time.Hour,
+		}
+
+		assert.NoError(t, detector.handleKeyChange("2026:2:lessons:199"))
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+	})
+
+	t.Run("redis_error", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.ExpectWatch(scoresKey, "score_change_baseline:"+scoresKey)
+		redisMock.ExpectHGetAll(scoresKey).SetErr(assert.AnError)
+
+		out := &bytes.Buffer{}
+		detector := ScoreChangeDetector{
+			out:         out,
+			redis:       redisClient,
+			notifier:    NewMockScoreChangeNotifierInterface(t),
+			baselineTTL: time.Hour,
+		}
+
+		err := detector.handleKeyChange(scoresKey)
+
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Equal(t, "Failed to notify about change of 2026:2:scores:1200:199: "+assert.AnError.Error()+"\n", out.String())
+	})
+
+	t.Run("notify_error", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.MatchExpectationsInOrder(true)
+		expectLoadBaseline(redisMock, scoresKey, map[string]string{"245:1": "5"}, map[string]string{"245:1": "4"})
+
+		notifier := NewMockScoreChangeNotifierInterface(t)
+		notifier.On("notify", mock.Anything, mock.Anything).Return(assert.AnError).Once()
+
+		out := &bytes.Buffer{}
+		detector := ScoreChangeDetector{
+			out:         out,
+			redis:       redisClient,
+			notifier:    notifier,
+			baselineTTL: time.Hour,
+		}
+
+		err := detector.handleKeyChange(scoresKey)
+
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.NoError(t, redisMock.ExpectationsWereMet(), "baseline is not switched without notification")
+		assert.Contains(t, out.String(), assert.AnError.Error())
+	})
+}
+
+func TestMakeScoreChangeEventId(t *testing.T) {
+	changes := []ScoreChange{{Type: ScoreChangeNewScore, LessonId: 245, Half: 1, Value: floatPointer(5)}}
+
+	eventId := makeScoreChangeEventId("2026:2:scores:1200:199", 1200, changes)
+
+	assert.Len(t, eventId, 32)
+	assert.Equal(t, eventId, makeScoreChangeEventId("2026:2:scores:1200:199", 1200, changes))
+	assert.NotEqual(t, eventId, makeScoreChangeEventId("2026:2:scores:1300:199", 1300, changes))
+	assert.NotEqual(t, eventId, makeScoreChangeEventId("2026:2:scores:1200:199", 1200, []ScoreChange{
+		{Type: ScoreChangeChangedScore, LessonId: 245, Half: 1, Value: floatPointer(4), PreviousValue: floatPointer(5)},
+	}))
+}
+
+func TestScoreChangeNotifiers(t *testing.T) {
+	event := ScoreChangeEvent{Id: "abc", StudentId: 1200}
+
+	first := NewMockScoreChangeNotifierInterface(t)
+	first.On("notify", nil, event).Return(assert.AnError).Once()
+	second := NewMockScoreChangeNotifierInterface(t)
+	second.On("notify", nil, event).Return(nil).Once()
+
+	err := ScoreChangeNotifiers{first, second}.notify(nil, event)
+
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.NoError(t, ScoreChangeNotifiers{}.notify(nil, event))
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/segmentio/kafka-go"
+	"io"
+	"time"
+)
+
+const DefaultScoreEventsTopic = "scores_changes_feed"
+
+// ScoreEventsConsumerGroupPrefix - prefix of the default consumer group, which is unique per instance (host),
+// so every instance receives all partitions and its event hub does not miss events of the other instances' partitions
+const ScoreEventsConsumerGroupPrefix = "score-storage-api-"
+const scoreEventRetryDelay = time.Second * 5
+
+// scoreEventMaxAttempts - attempts to process one event, the delay between them doubles from the retry delay
+const scoreEventMaxAttempts = 5
+
+// score event types, event without type is a scores event
+const (
+	ScoreEventTypeScores         = "scores"
+	ScoreEventTypeLessons        = "lessons"
+	ScoreEventTypeDeletedLessons = "deleted-lessons"
+)
+
+// KafkaReaderInterface - part of kafka.Reader used by ScoreEventConsumer
+type KafkaReaderInterface interface {
+	FetchMessage(ctx context.Context) (kafka.Message, error)
+	CommitMessages(ctx context.Context, messages ...kafka.Message) error
+	Close() error
+}
+
+// ScoreEvent - update published by the upstream writer after the scores hash of the student
+// or the lessons of the discipline were changed, StudentId is set only for scores events
+type ScoreEvent struct {
+	Type         string    `json:"type"`
+	Year         int       `json:"year"`
+	Semester     int       `json:"semester"`
+	StudentId    int       `json:"studentId"`
+	DisciplineId int       `json:"disciplineId"`
+	LessonId     int       `json:"lessonId"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+// ScoreEventConsumer reads score events from Kafka and passes changed scores and totals keys to handleKeyChange,
+// lessons events invalidate cached deleted lessons of the discipline.
+// Offset is committed only after handleKeyChange succeeded for both keys, so the event is processed again
+// after a failure or if the process dies.
+type ScoreEventConsumer struct {
+	out             io.Writer
+	reader          KafkaReaderInterface
+	storage         *Storage
+	handleKeyChange func(key string) error
+	retryDelay      time.Duration
+}
+
+func (consumer *ScoreEventConsumer) consume(ctx context.Context) {
+	defer consumer.reader.Close()
+
+	for ctx.Err() == nil {
+		message, err := consumer.reader.FetchMessage(ctx)
+		if err == nil {
+			err = consumer.processWithRetry(ctx, message)
+		}
+
+		if err == nil {
+			err = consumer.reader.CommitMessages(ctx, message)
+		}
+
+		if err != nil && ctx.Err() == nil {
+			_, _ = fmt.Fprintf(consumer.out, "Failed to consume score event: %s\n", err)
+			consumer.sleep(ctx, consumer.retryDelay)
+		}
+	}
+}
+
+// processWithRetry repeats processing of the message with growing delay, so the following messages
+// are not committed before it. The message is skipped after scoreEventMaxAttempts, so a persistent failure
+// does not stall the partition: baseline of the failed key is not switched, and the next event of the key reports it.
+func (consumer *ScoreEventConsumer) processWithRetry(ctx context.Context, message kafka.Message) error {
+	delay := consumer.retryDelay
+	for attempt := 1; ; attempt++ {
+		err := consumer.process(message)
+		if err == nil {
+			return nil
+		}
+
+		if attempt == scoreEventMaxAttempts {
+			_, _ = fmt.Fprintf(
+				consumer.out, "Skip score event at offset %d after %d failed attempts: %s\n",
+				message.Offset, attempt, err,
+			)
+			return nil
+		}
+
+		_, _ = fmt.Fprintf(
+			consumer.out, "Failed to process score event at offset %d: %s\n", message.Offset, err,
+		)
+		if !consumer.sleep(ctx, delay) {
+			return ctx.Err()
+		}
+		delay *= 2
+	}
+}
+
+func (consumer *ScoreEventConsumer) process(message kafka.Message) error {
+	event, err := decodeScoreEvent(message)
+	if err != nil {
+		_, _ = fmt.Fprintf(consumer.out, "Skip malformed score event at offset %d: %s\n", message.Offset, err)
+		return nil
+	}
+
+	// event of the next academic year means cached current year and lesson types are outdated
+	if event.Year > consumer.storage.year {
+		consumer.storage.updateGeneralData()
+	}
+
+	if event.Type != ScoreEventTypeScores {
+		consumer.storage.deletedLessons.invalidate(event.Year, event.Semester, event.DisciplineId)
+		return nil
+	}
+
+	err = consumer.handleKeyChange(fmt.Sprintf(
+		"%d:%d:scores:%d:%d", event.Year, event.Semester, event.StudentId, event.DisciplineId,
+	))
+	if err != nil {
+		return err
+	}
+
+	return consumer.handleKeyChange(fmt.Sprintf("%d:%d:totals:%d", event.Year, event.Semester, event.DisciplineId))
+}
+
+// sleep waits the delay and returns false if ctx is done earlier
+func (consumer *ScoreEventConsumer) sleep(ctx context.Context, delay time.Duration) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(delay):
+		return true
+	}
+}
+
+// decodeScoreEvent decodes message value, event without UpdatedAt gets the message time
+func decodeScoreEvent(message kafka.Message) (ScoreEvent, error) {
+	var event ScoreEvent
+	err := json.Unmarshal(message.Value, &event)
+	if err != nil {
+		return ScoreEvent{}, err
+	}
+
+	if event.Type == "" {
+		event.Type = ScoreEventTypeScores
+	}
+
+	if event.Type != ScoreEventTypeScores && event.Type != ScoreEventTypeLessons &&
+		event.Type != ScoreEventTypeDeletedLessons {
+		return ScoreEvent{}, errors.New("unknown score event type")
+	}
+
+	if event.Year < FirstAcademicYear || (event.Semester != 1 && event.Semester != 2) ||
+		(event.Type == ScoreEventTypeScores && event.StudentId <= 0) || event.DisciplineId <= 0 {
+		return ScoreEvent{}, errors.New("missing score event fields")
+	}
+
+	if event.UpdatedAt.IsZero() {
+		event.UpdatedAt = message.Time
+	}
+
+	return event, nil
+}
+
+// newKafkaReader creates reader of the group, new group starts from the latest events
+// instead of the whole topic history, because per-instance group is new after every host change
+func newKafkaReader(kafkaHost string, topic string, groupId string) *kafka.Reader {
+	return kafka.NewReader(kafka.ReaderConfig{
+		Brokers:     []string{kafkaHost},
+		GroupID:     groupId,
+		Topic:       topic,
+		StartOffset: kafka.LastOffset,
+		MinBytes:    1,
+		MaxBytes:    10e6,
+	})
+}
+
+func NewScoreEventConsumer(
+	out io.Writer, reader KafkaReaderInterface, storage *Storage, handleKeyChange func(key string) error, ctx context.Context,
+) *ScoreEventConsumer {
+	consumer := &ScoreEventConsumer{
+		out:             out,
+		reader:          reader,
+		storage:         storage,
+		handleKeyChange: handleKeyChange,
+		retryDelay:      scoreEventRetryDelay,
+	}
+
+	go consumer.consume(ctx)
+
+	return consumer
+}
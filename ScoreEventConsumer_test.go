@@ -0,0 +1,340 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"github.com/go-redis/redismock/v9"
+	scoreApi "github.com/kneu-messenger-pigeon/score-api"
+	"github.com/redis/go-redis/v9"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeKafkaBroker - in-process partition of the topic, which serves messages after the committed offset
+// to the consumer group and blocks on fetch when there are no more messages
+type fakeKafkaBroker struct {
+	mutex     sync.Mutex
+	messages  []kafka.Message
+	position  int
+	committed int64
+	closed    bool
+	fetchErr  error
+}
+
+func newFakeKafkaBroker(values ...string) *fakeKafkaBroker {
+	broker := &fakeKafkaBroker{committed: -1}
+	for _, value := range values {
+		broker.produce(value)
+	}
+
+	return broker
+}
+
+func (broker *fakeKafkaBroker) produce(value string) {
+	broker.mutex.Lock()
+	defer broker.mutex.Unlock()
+
+	broker.messages = append(broker.messages, kafka.Message{
+		Topic:  DefaultScoreEventsTopic,
+		Offset: int64(len(broker.messages)),
+		Value:  []byte(value),
+		Time:   time.Date(2026, time.Month(3), 15, 12, 0, 0, 0, time.UTC),
+	})
+}
+
+// rejoin emulates restart of the consumer: fetching continues from the committed offset
+func (broker *fakeKafkaBroker) rejoin() {
+	broker.mutex.Lock()
+	defer broker.mutex.Unlock()
+
+	broker.position = int(broker.committed + 1)
+	broker.closed = false
+}
+
+func (broker *fakeKafkaBroker) committedOffset() int64 {
+	broker.mutex.Lock()
+	defer broker.mutex.Unlock()
+
+	return broker.committed
+}
+
+func (broker *fakeKafkaBroker) FetchMessage(ctx context.Context) (kafka.Message, error) {
+	for {
+		broker.mutex.Lock()
+		if broker.fetchErr != nil {
+			err := broker.fetchErr
+			broker.fetchErr = nil
+			broker.mutex.Unlock()
+			return kafka.Message{}, err
+		}
+
+		if broker.position < len(broker.messages) {
+			message := broker.messages[broker.position]
+			broker.position++
+			broker.mutex.Unlock()
+			return message, nil
+		}
+		broker.mutex.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return kafka.Message{}, ctx.Err()
+		case <-time.After(time.Millisecond):
+		}
+	}
+}
+
+func (broker *fakeKafkaBroker) CommitMessages(_ context.Context, messages ...kafka.Message) error {
+	broker.mutex.Lock()
+	defer broker.mutex.Unlock()
+
+	for _, message := range messages {
+		broker.committed = max(broker.committed, message.Offset)
+	}
+
+	return nil
+}
+
+func (broker *fakeKafkaBroker) Close() error {
+	broker.mutex.Lock()
+	defer broker.mutex.Unlock()
+
+	broker.closed = true
+	return nil
+}
+
+// keyChangeRecorder records handled keys, calls fail with errs in order until they run out
+type keyChangeRecorder struct {
+	mutex sync.Mutex
+	keys  []string
+	errs  []error
+}
+
+func (recorder *keyChangeRecorder) handleKeyChange(key string) error {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+
+	recorder.keys = append(recorder.keys, key)
+	if len(recorder.errs) == 0 {
+		return nil
+	}
+
+	err := recorder.errs[0]
+	recorder.errs = recorder.errs[1:]
+	return err
+}
+
+func (recorder *keyChangeRecorder) failNext(errs ...error) {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+
+	recorder.errs = append(recorder.errs, errs...)
+}
+
+func (recorder *keyChangeRecorder) changedKeys() []string {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+
+	return append([]string{}, recorder.keys...)
+}
+
+// runScoreEventConsumer consumes until the broker commits expectedOffset and stops the consumer
+func runScoreEventConsumer(t *testing.T, consumer *ScoreEventConsumer, broker *fakeKafkaBroker, expectedOffset int64) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan bool)
+	go func() {
+		consumer.consume(ctx)
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool {
+		return broker.committedOffset() >= expectedOffset
+	}, time.Second, time.Millisecond)
+
+	cancel()
+	<-done
+}
+
+func TestScoreEventConsumer(t *testing.T) {
+	newConsumer := func(redisClient *redis.Client, broker *fakeKafkaBroker, recorder *keyChangeRecorder) (*ScoreEventConsumer, *bytes.Buffer) {
+		out := &bytes.Buffer{}
+		return &ScoreEventConsumer{
+			out:             out,
+			reader:          broker,
+			storage:         &Storage{redis: redisClient, year: 2026, anomalies: &AnomalyCounter{}},
+			handleKeyChange: recorder.handleKeyChange,
+			retryDelay:      time.Millisecond,
+		}, out
+	}
+
+	t.Run("success", func(t *testing.T) {
+		broker := newFakeKafkaBroker(
+			`{"year":2026,"semester":2,"studentId":1200,"disciplineId":199,"lessonId":245,"updatedAt":"2026-03-15T12:30:00Z"}`,
+			`{"year":2026,"semester":1,"studentId":1300,"disciplineId":200,"lessonId":250}`,
+		)
+
+		redisClient, redisMock := redismock.NewClientMock()
+
+		recorder := &keyChangeRecorder{}
+		consumer, out := newConsumer(redisClient, broker, recorder)
+
+		runScoreEventConsumer(t, consumer, broker, 1)
+
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+		assert.Equal(t, []string{
+			"2026:2:scores:1200:199", "2026:2:totals:199",
+			"2026:1:scores:1300:200", "2026:1:totals:200",
+		}, recorder.changedKeys())
+		assert.Equal(t, int64(1), broker.committedOffset())
+		assert.True(t, broker.closed)
+		assert.Empty(t, out.String())
+	})
+
+	t.Run("malformed_event", func(t *testing.T) {
+		broker := newFakeKafkaBroker(`{`, `{"year":2026,"semester":3,"studentId":1200,"disciplineId":199}`)
+
+		redisClient, redisMock := redismock.NewClientMock()
+		recorder := &keyChangeRecorder{}
+		consumer, out := newConsumer(redisClient, broker, recorder)
+
+		runScoreEventConsumer(t, consumer, broker, 1)
+
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+		assert.Empty(t, recorder.changedKeys())
+		assert.Contains(t, out.String(), "Skip malformed score event at offset 0: unexpected end of JSON input")
+		assert.Contains(t, out.String(), "Skip malformed score event at offset 1: missing score event fields")
+	})
+
+	t.Run("commit_after_processing", func(t *testing.T) {
+		broker := newFakeKafkaBroker(`{"year":2026,"semester":2,"studentId":1200,"disciplineId":199}`)
+
+		redisClient, _ := redismock.NewClientMock()
+		recorder := &keyChangeRecorder{}
+		recorder.failNext(nil, assert.AnError)
+		consumer, out := newConsumer(redisClient, broker, recorder)
+		consumer.retryDelay = time.Hour
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan bool)
+		go func() {
+			consumer.consume(ctx)
+			close(done)
+		}()
+
+		assert.Eventually(t, func() bool {
+			return len(recorder.changedKeys()) == 2
+		}, time.Second, time.Millisecond)
+		cancel()
+		<-done
+
+		assert.Equal(t, int64(-1), broker.committedOffset())
+		assert.Equal(t, []string{"2026:2:scores:1200:199", "2026:2:totals:199"}, recorder.changedKeys())
+		assert.Contains(t, out.String(), "Failed to process score event at offset 0: "+assert.AnError.Error())
+
+		broker.rejoin()
+		recorder.failNext(assert.AnError)
+		consumer.retryDelay = time.Millisecond
+
+		runScoreEventConsumer(t, consumer, broker, 0)
+
+		assert.Equal(t, int64(0), broker.committedOffset())
+		assert.Equal(t, []string{
+			"2026:2:scores:1200:199", "2026:2:totals:199",
+			"2026:2:scores:1200:199",
+			"2026:2:scores:1200:199", "2026:2:totals:199",
+		}, recorder.changedKeys())
+	})
+
+	t.Run("skip_after_max_attempts", func(t *testing.T) {
+		broker := newFakeKafkaBroker(
+			`{"year":2026,"semester":2,"studentId":1200,"disciplineId":199}`,
+			`{"year":2026,"semester":2,"studentId":1300,"disciplineId":199}`,
+		)
+
+		redisClient, _ := redismock.NewClientMock()
+		recorder := &keyChangeRecorder{}
+		recorder.failNext(assert.AnError, assert.AnError, assert.AnError, assert.AnError, assert.AnError)
+		consumer, out := newConsumer(redisClient, broker, recorder)
+
+		runScoreEventConsumer(t, consumer, broker, 1)
+
+		assert.Equal(t, []string{
+			"2026:2:scores:1200:199",
+			"2026:2:scores:1200:199",
+			"2026:2:scores:1200:199",
+			"2026:2:scores:1200:199",
+			"2026:2:scores:1200:199",
+			"2026:2:scores:1300:199", "2026:2:totals:199",
+		}, recorder.changedKeys())
+		assert.Equal(t, 4, strings.Count(out.String(), "Failed to process score event at offset 0: "))
+		assert.Contains(t, out.String(), "Skip score event at offset 0 after 5 failed attempts: "+assert.AnError.Error())
+	})
+
+	t.Run("lessons_events", func(t *testing.T) {
+		broker := newFakeKafkaBroker(
+			`{"type":"lessons","year":2026,"semester":2,"disciplineId":199}`,
+			`{"type":"deleted-lessons","year":2026,"semester":1,"disciplineId":200,"lessonId":240}`,
+			`{"type":"unknown","year":2026,"semester":1,"disciplineId":200}`,
+		)
+
+		now := time.Now()
+		deletedLessons := NewDeletedLessonsCache(time.Hour)
+		deletedLessons.set(2026, 2, 199, map[int]scoreApi.Lesson{}, now)
+		deletedLessons.set(2026, 1, 200, map[int]scoreApi.Lesson{}, now)
+		deletedLessons.set(2026, 1, 201, map[int]scoreApi.Lesson{}, now)
+
+		redisClient, redisMock := redismock.NewClientMock()
+		recorder := &keyChangeRecorder{}
+		consumer, out := newConsumer(redisClient, broker, recorder)
+		consumer.storage.deletedLessons = deletedLessons
+
+		runScoreEventConsumer(t, consumer, broker, 2)
+
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+		assert.Empty(t, recorder.changedKeys())
+		assert.Equal(t, "Skip malformed score event at offset 2: unknown score event type\n", out.String())
+
+		_, hit := deletedLessons.get(2026, 2, 199, now)
+		assert.False(t, hit)
+		_, hit = deletedLessons.get(2026, 1, 200, now)
+		assert.False(t, hit)
+		_, hit = deletedLessons.get(2026, 1, 201, now)
+		assert.True(t, hit)
+	})
+
+	t.Run("next_year_event", func(t *testing.T) {
+		broker := newFakeKafkaBroker(`{"year":2027,"semester":1,"studentId":1200,"disciplineId":199}`)
+
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.MatchExpectationsInOrder(true)
+		redisMock.ExpectGet("currentYear").SetVal("2027")
+		redisMock.ExpectGet("lessonTypes").RedisNil()
+
+		recorder := &keyChangeRecorder{}
+		consumer, _ := newConsumer(redisClient, broker, recorder)
+
+		runScoreEventConsumer(t, consumer, broker, 0)
+
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+		assert.Equal(t, 2027, consumer.storage.year)
+		assert.Equal(t, []string{"2027:1:scores:1200:199", "2027:1:totals:199"}, recorder.changedKeys())
+	})
+
+	t.Run("fetch_error", func(t *testing.T) {
+		broker := newFakeKafkaBroker(`{"year":2026,"semester":2,"studentId":1200,"disciplineId":199}`)
+		broker.fetchErr = assert.AnError
+
+		redisClient, _ := redismock.NewClientMock()
+		recorder := &keyChangeRecorder{}
+		consumer, out := newConsumer(redisClient, broker, recorder)
+
+		runScoreEventConsumer(t, consumer, broker, 0)
+
+		assert.Equal(t, []string{"2026:2:scores:1200:199", "2026:2:totals:199"}, recorder.changedKeys())
+		assert.Equal(t, "Failed to consume score event: "+assert.AnError.Error()+"\n", out.String())
+	})
+}
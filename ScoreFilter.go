@@ -0,0 +1,186 @@
+package main
+
+import (
+	"encoding/base64"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const filterDateLayout = "2006-01-02"
+
+// ScoreFilter - query filters of discipline scores.
+// Pagination goes backwards by lesson date: the page holds the latest `limit` scores older than cursor,
+// so the first page without cursor is "last N grades". Scores inside the page keep ascending order.
+type ScoreFilter struct {
+	LessonTypeIds   map[int]bool
+	LessonTypeNames map[string]bool
+	From            time.Time
+	To              time.Time
+	AbsentOnly      bool
+	ScoredOnly      bool
+	Limit           int
+	Cursor          *ScoreCursor
+}
+
+type ScoreCursor struct {
+	Date     time.Time
+	LessonId int
+}
+
+// parseScoreFilter reads query parameters of scores list, dates are midnights of the location
+func parseScoreFilter(c *gin.Context, location *time.Location) (filter ScoreFilter, err error) {
+	for _, lessonType := range strings.Split(c.Query("lessonType"), ",") {
+		lessonType = strings.TrimSpace(lessonType)
+		if lessonType == "" {
+			continue
+		}
+
+		if lessonTypeId, atoiErr := strconv.Atoi(lessonType); atoiErr == nil {
+			if filter.LessonTypeIds == nil {
+				filter.LessonTypeIds = make(map[int]bool)
+			}
+			filter.LessonTypeIds[lessonTypeId] = true
+
+		} else {
+			if filter.LessonTypeNames == nil {
+				filter.LessonTypeNames = make(map[string]bool)
+			}
+			filter.LessonTypeNames[strings.ToLower(lessonType)] = true
+		}
+	}
+
+	if c.Query("from") != "" {
+		filter.From, err = time.ParseInLocation(filterDateLayout, c.Query("from"), location)
+		if err != nil {
+			return ScoreFilter{}, MessageError{Format: messageIncorrectParameter, Args: []interface{}{"from", c.Query("from")}}
+		}
+	}
+
+	if c.Query("to") != "" {
+		filter.To, err = time.ParseInLocation(filterDateLayout, c.Query("to"), location)
+		if err != nil {
+			return ScoreFilter{}, MessageError{Format: messageIncorrectParameter, Args: []interface{}{"to", c.Query("to")}}
+		}
+	}
+
+	if c.Query("absentOnly") != "" {
+		filter.AbsentOnly, err = strconv.ParseBool(c.Query("absentOnly"))
+		if err != nil {
+			return ScoreFilter{}, MessageError{Format: messageIncorrectParameter, Args: []interface{}{"absentOnly", c.Query("absentOnly")}}
+		}
+	}
+
+	if c.Query("scoredOnly") != "" {
+		filter.ScoredOnly, err = strconv.ParseBool(c.Query("scoredOnly"))
+		if err != nil {
+			return ScoreFilter{}, MessageError{Format: messageIncorrectParameter, Args: []interface{}{"scoredOnly", c.Query("scoredOnly")}}
+		}
+	}
+
+	if c.Query("limit") != "" {
+		filter.Limit, err = strconv.Atoi(c.Query("limit"))
+		if err != nil || filter.Limit <= 0 {
+			return ScoreFilter{}, MessageError{Format: messageIncorrectParameter, Args: []interface{}{"limit", c.Query("limit")}}
+		}
+	}
+
+	if c.Query("cursor") != "" {
+		filter.Cursor, err = decodeScoreCursor(c.Query("cursor"), location)
+		if err != nil {
+			return ScoreFilter{}, MessageError{Format: messageIncorrectParameter, Args: []interface{}{"cursor", c.Query("cursor")}}
+		}
+	}
+
+	return filter, nil
+}
+
+func (filter ScoreFilter) match(score Score) bool {
+	if (filter.LessonTypeIds != nil || filter.LessonTypeNames != nil) &&
+		!filter.LessonTypeIds[score.Lesson.Type.Id] &&
+		!filter.LessonTypeNames[strings.ToLower(score.Lesson.Type.ShortName)] {
+		return false
+	}
+
+	if !filter.From.IsZero() && score.Lesson.Date.Before(filter.From) {
+		return false
+	}
+
+	// "to" date is inclusive
+	if !filter.To.IsZero() && !score.Lesson.Date.Before(filter.To.AddDate(0, 0, 1)) {
+		return false
+	}
+
+	if filter.AbsentOnly && !score.IsAbsent {
+		return false
+	}
+
+	if filter.ScoredOnly && score.FirstScore == nil && score.SecondScore == nil {
+		return false
+	}
+
+	if filter.Cursor != nil && !filter.Cursor.isOlder(score) {
+		return false
+	}
+
+	return true
+}
+
+// apply expects scores sorted by lesson date and returns matched scores of the page with cursor of the next page
+func (filter ScoreFilter) apply(scores []Score) (filtered []Score, nextCursor string) {
+	filtered = make([]Score, 0, len(scores))
+	for _, score := range scores {
+		if filter.match(score) {
+			filtered = append(filtered, score)
+		}
+	}
+
+	if filter.Limit != 0 && len(filtered) > filter.Limit {
+		filtered = filtered[len(filtered)-filter.Limit:]
+		nextCursor = encodeScoreCursor(filtered[0])
+	}
+
+	return filtered, nextCursor
+}
+
+// isOlder reports whether score goes before the cursor in lesson date order
+func (cursor *ScoreCursor) isOlder(score Score) bool {
+	if score.Lesson.Date.Equal(cursor.Date) {
+		return score.Lesson.Id < cursor.LessonId
+	}
+
+	return score.Lesson.Date.Before(cursor.Date)
+}
+
+func encodeScoreCursor(score Score) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(
+		score.Lesson.Date.Format(filterDateLayout) + ":" + strconv.Itoa(score.Lesson.Id),
+	))
+}
+
+func decodeScoreCursor(encoded string, location *time.Location) (*ScoreCursor, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	dateString, lessonIdString, found := strings.Cut(string(decoded), ":")
+	if !found {
+		return nil, errors.New("wrong cursor format")
+	}
+
+	cursor := &ScoreCursor{}
+	cursor.Date, err = time.ParseInLocation(filterDateLayout, dateString, location)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor.LessonId, err = strconv.Atoi(lessonIdString)
+	if err != nil {
+		return nil, err
+	}
+
+	return cursor, nil
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"github.com/gin-gonic/gin"
+	scoreApi "github.com/kneu-messenger-pigeon/score-api"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func getTestFilterScores() []Score {
+	lecture := scoreApi.LessonType{Id: 1, ShortName: "Лек", LongName: "Лекція"}
+	laboratory := scoreApi.LessonType{Id: 2, ShortName: "Лаб", LongName: "Лабораторна робота"}
+	moduleControl := scoreApi.LessonType{Id: 5, ShortName: "МК", LongName: "Модульний контроль."}
+
+	return []Score{
+		{
+			Score: scoreApi.Score{
+				Lesson:     scoreApi.Lesson{Id: 1, Date: time.Date(2023, time.Month(2), 1, 0, 0, 0, 0, defaultAcademicLocation), Type: lecture},
+				FirstScore: floatPointer(1),
+			},
+		},
+		{
+			Score: scoreApi.Score{
+				Lesson:   scoreApi.Lesson{Id: 2, Date: time.Date(2023, time.Month(2), 5, 0, 0, 0, 0, defaultAcademicLocation), Type: moduleControl},
+				IsAbsent: true,
+			},
+		},
+		{
+			Score: scoreApi.Score{
+				Lesson:     scoreApi.Lesson{Id: 3, Date: time.Date(2023, time.Month(2), 10, 0, 0, 0, 0, defaultAcademicLocation), Type: laboratory},
+				FirstScore: floatPointer(2.5),
+			},
+		},
+		{
+			Score: scoreApi.Score{
+				Lesson:      scoreApi.Lesson{Id: 4, Date: time.Date(2023, time.Month(2), 10, 0, 0, 0, 0, defaultAcademicLocation), Type: moduleControl},
+				FirstScore:  floatPointer(5),
+				SecondScore: floatPointer(3),
+			},
+		},
+		{
+			Score: scoreApi.Score{
+				Lesson: scoreApi.Lesson{Id: 5, Date: time.Date(2023, time.Month(2), 15, 0, 0, 0, 0, defaultAcademicLocation), Type: lecture},
+			},
+		},
+	}
+}
+
+func parseTestScoreFilter(query string) (ScoreFilter, error) {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request, _ = http.NewRequest(http.MethodGet, "/?"+query, nil)
+
+	return parseScoreFilter(c, defaultAcademicLocation)
+}
+
+func TestParseScoreFilter(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		filter, err := parseTestScoreFilter("")
+
+		assert.NoError(t, err)
+		assert.Equal(t, ScoreFilter{}, filter)
+	})
+
+	t.Run("all_params", func(t *testing.T) {
+		cursor := encodeScoreCursor(getTestFilterScores()[3])
+		filter, err := parseTestScoreFilter(
+			"lessonType=5,+Лаб,&from=2023-02-01&to=2023-02-10&absentOnly=true&scoredOnly=1&limit=3&cursor=" + cursor,
+		)
+
+		assert.NoError(t, err)
+		assert.Equal(t, ScoreFilter{
+			LessonTypeIds:   map[int]bool{5: true},
+			LessonTypeNames: map[string]bool{"лаб": true},
+			From:            time.Date(2023, time.Month(2), 1, 0, 0, 0, 0, defaultAcademicLocation),
+			To:              time.Date(2023, time.Month(2), 10, 0, 0, 0, 0, defaultAcademicLocation),
+			AbsentOnly:      true,
+			ScoredOnly:      true,
+			Limit:           3,
+			Cursor: &ScoreCursor{
+				Date:     time.Date(2023, time.Month(2), 10, 0, 0, 0, 0, defaultAcademicLocation),
+				LessonId: 4,
+			},
+		}, filter)
+	})
+
+	t.Run("location", func(t *testing.T) {
+		location, _ := time.LoadLocation("Asia/Tokyo")
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request, _ = http.NewRequest(http.MethodGet, "/?from=2023-02-01", nil)
+
+		filter, err := parseScoreFilter(c, location)
+
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2023, time.Month(2), 1, 0, 0, 0, 0, location), filter.From)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for query, expectedError := range map[string]string{
+			"from=01.02.2023":  "Incorrect from: 01.02.2023",
+			"to=2023-13-01":    "Incorrect to: 2023-13-01",
+			"absentOnly=maybe": "Incorrect absentOnly: maybe",
+			"scoredOnly=yes":   "Incorrect scoredOnly: yes",
+			"limit=0":          "Incorrect limit: 0",
+			"limit=ten":        "Incorrect limit: ten",
+			"cursor=%21%21":    "Incorrect cursor: !!",
+			"cursor=MjAyMw":    "Incorrect cursor: MjAyMw",
+		} {
+			_, err := parseTestScoreFilter(query)
+			assert.EqualError(t, err, expectedError, query)
+		}
+	})
+}
+
+func TestScoreFilterApply(t *testing.T) {
+	scores := getTestFilterScores()
+
+	t.Run("filters", func(t *testing.T) {
+		testCases := map[string]struct {
+			filter   ScoreFilter
+			expected []Score
+		}{
+			"empty": {
+				filter:   ScoreFilter{},
+				expected: scores,
+			},
+			"lesson_type_id": {
+				filter:   ScoreFilter{LessonTypeIds: map[int]bool{1: true}},
+				expected: []Score{scores[0], scores[4]},
+			},
+			"lesson_type_name": {
+				filter:   ScoreFilter{LessonTypeNames: map[string]bool{"мк": true, "лаб": true}},
+				expected: []Score{scores[1], scores[2], scores[3]},
+			},
+			"date_range": {
+				filter: ScoreFilter{
+					From: time.Date(2023, time.Month(2), 5, 0, 0, 0, 0, defaultAcademicLocation),
+					To:   time.Date(2023, time.Month(2), 10, 0, 0, 0, 0, defaultAcademicLocation),
+				},
+				expected: []Score{scores[1], scores[2], scores[3]},
+			},
+			"absent_only": {
+				filter:   ScoreFilter{AbsentOnly: true},
+				expected: []Score{scores[1]},
+			},
+			"scored_only": {
+				filter:   ScoreFilter{ScoredOnly: true},
+				expected: []Score{scores[0], scores[2], scores[3]},
+			},
+			"combined": {
+				filter: ScoreFilter{
+					LessonTypeIds: map[int]bool{1: true, 5: true},
+					ScoredOnly:    true,
+					From:          time.Date(2023, time.Month(2), 2, 0, 0, 0, 0, defaultAcademicLocation),
+				},
+				expected: []Score{scores[3]},
+			},
+			"nothing": {
+				filter:   ScoreFilter{LessonTypeIds: map[int]bool{99: true}},
+				expected: []Score{},
+			},
+		}
+
+		for name, testCase := range testCases {
+			actual, nextCursor := testCase.filter.apply(scores)
+
+			assert.Equal(t, testCase.expected, actual, name)
+			assert.Empty(t, nextCursor, name)
+		}
+	})
+
+	t.Run("pagination", func(t *testing.T) {
+		filter := ScoreFilter{Limit: 2}
+
+		actual, nextCursor := filter.apply(scores)
+		assert.Equal(t, []Score{scores[3], scores[4]}, actual)
+		assert.NotEmpty(t, nextCursor)
+
+		filter.Cursor, _ = decodeScoreCursor(nextCursor, defaultAcademicLocation)
+		actual, nextCursor = filter.apply(scores)
+		assert.Equal(t, []Score{scores[1], scores[2]}, actual)
+		assert.NotEmpty(t, nextCursor)
+
+		filter.Cursor, _ = decodeScoreCursor(nextCursor, defaultAcademicLocation)
+		actual, nextCursor = filter.apply(scores)
+		assert.Equal(t, []Score{scores[0]}, actual)
+		assert.Empty(t, nextCursor)
+	})
+}
+
+func TestScoreCursor(t *testing.T) {
+	score := getTestFilterScores()[2]
+
+	cursor, err := decodeScoreCursor(encodeScoreCursor(score), defaultAcademicLocation)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &ScoreCursor{Date: score.Lesson.Date, LessonId: 3}, cursor)
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"errors"
+	scoreApi "github.com/kneu-messenger-pigeon/score-api"
+	"math"
+)
+
+// ScoreRating extends scoreApi.ScoreRating with details of the rating position
+type ScoreRating struct {
+	scoreApi.ScoreRating
+	// Percentile - percent of students with lower total plus half of students with the same total
+	Percentile float32 `json:"percentile"`
+	// TieSize - amount of students sharing the rating position, including the student
+	TieSize      int `json:"tieSize"`
+	NonZeroCount int `json:"nonZeroCount"`
+}
+
+// ZeroTotalsMode - how students without scores (zero total) are ranked
+type ZeroTotalsMode string
+
+const (
+	// ZeroTotalsLast - zero totals are counted in StudentsCount and take the last position (StudentsCount)
+	ZeroTotalsLast ZeroTotalsMode = "last"
+	// ZeroTotalsShared - zero totals are counted in StudentsCount and share position right after non-zero totals
+	ZeroTotalsShared ZeroTotalsMode = "shared"
+	// ZeroTotalsExcluded - zero totals are not counted in StudentsCount and have no position (Rating is 0)
+	ZeroTotalsExcluded ZeroTotalsMode = "exclude"
+)
+
+func parseZeroTotalsMode(value string) (ZeroTotalsMode, error) {
+	mode := ZeroTotalsMode(value)
+	if mode == ZeroTotalsLast || mode == ZeroTotalsShared || mode == ZeroTotalsExcluded {
+		return mode, nil
+	}
+
+	return "", errors.New("Wrong RATING_ZERO_TOTALS: " + value + ", expected last, shared or exclude")
+}
+
+// RatingCounts - amounts of students in discipline totals, Greater and Tied are relative to the student total
+type RatingCounts struct {
+	Students int
+	NonZero  int
+	Greater  int
+	Tied     int
+}
+
+// makeRating fills rating position by total and counts of discipline totals.
+// Tied is ignored for zero total, all zero totals are tied together. Empty mode is ZeroTotalsLast.
+func (mode ZeroTotalsMode) makeRating(total float32, minTotal float32, maxTotal float32, counts RatingCounts) ScoreRating {
+	scoreRating := ScoreRating{
+		ScoreRating: scoreApi.ScoreRating{
+			Total:         total,
+			StudentsCount: counts.Students,
+			MinTotal:      minTotal,
+			MaxTotal:      maxTotal,
+		},
+		NonZeroCount: counts.NonZero,
+	}
+
+	zeroCount := counts.Students - counts.NonZero
+	if mode == ZeroTotalsExcluded {
+		scoreRating.StudentsCount = counts.NonZero
+		zeroCount = 0
+	}
+
+	lowerCount := 0
+	if total > 0 {
+		scoreRating.Rating = counts.Greater + 1
+		scoreRating.TieSize = counts.Tied
+		lowerCount = counts.NonZero - counts.Greater - counts.Tied + zeroCount
+	} else if mode == ZeroTotalsShared {
+		scoreRating.Rating = counts.NonZero + 1
+		scoreRating.TieSize = zeroCount
+	} else if mode == ZeroTotalsLast || mode == "" {
+		scoreRating.Rating = counts.Students
+		scoreRating.TieSize = zeroCount
+	}
+
+	if scoreRating.StudentsCount > 0 {
+		percentile := (float64(lowerCount) + float64(scoreRating.TieSize)/2) * 100 / float64(scoreRating.StudentsCount)
+		scoreRating.Percentile = float32(math.Round(percentile*10) / 10)
+	}
+
+	return scoreRating
+}
+
+// makeScoreRating calculates rating from all discipline totals with the same semantics as ScoreRatingLoader.load:
+// student without total has zero total, min and max are taken from totals between 0.1 and 100.
+func (mode ZeroTotalsMode) makeScoreRating(totals map[int]float32, studentId int) ScoreRating {
+	total := totals[studentId]
+	counts := RatingCounts{
+		Students: len(totals),
+	}
+
+	var minTotal, maxTotal float32
+	for _, otherTotal := range totals {
+		if otherTotal > 0 {
+			counts.NonZero++
+		}
+
+		if otherTotal > total {
+			counts.Greater++
+		} else if otherTotal == total {
+			counts.Tied++
+		}
+
+		if otherTotal < 0.1 || otherTotal > 100 {
+			continue
+		}
+
+		if minTotal == 0 || otherTotal < minTotal {
+			minTotal = otherTotal
+		}
+
+		if otherTotal > maxTotal {
+			maxTotal = otherTotal
+		}
+	}
+
+	return mode.makeRating(total, minTotal, maxTotal, counts)
+}
@@ -3,38 +3,38 @@ package main
 import (
 	"context"
 	"fmt"
-	scoreApi "github.com/kneu-messenger-pigeon/score-api"
 	"github.com/redis/go-redis/v9"
 	"strconv"
 )
 
 type ScoreRatingLoaderInterface interface {
-	load(year int, semester int, disciplineId int, studentId int) scoreApi.ScoreRating
+	load(year int, semester int, disciplineId int, studentId int) ScoreRating
 }
 
 type ScoreRatingLoader struct {
-	redis *redis.Client
+	redis          *redis.Client
+	zeroTotalsMode ZeroTotalsMode
 }
 
-func (loader *ScoreRatingLoader) load(year int, semester int, disciplineId int, studentId int) (scoreRating scoreApi.ScoreRating) {
+func (loader *ScoreRatingLoader) load(year int, semester int, disciplineId int, studentId int) ScoreRating {
 	ctx := context.Background()
 	disciplineTotalsKey := fmt.Sprintf("%d:%d:totals:%d", year, semester, disciplineId)
 	studentKey := strconv.Itoa(studentId)
 
-	scoreRating.StudentsCount = int(loader.redis.ZCard(ctx, disciplineTotalsKey).Val())
-	scoreRating.Rating = scoreRating.StudentsCount
+	counts := RatingCounts{}
+	counts.Students = int(loader.redis.ZCard(ctx, disciplineTotalsKey).Val())
 	total := loader.redis.ZScore(ctx, disciplineTotalsKey, studentKey).Val()
-	scoreRating.Total = float32(total)
+	counts.NonZero = int(loader.redis.ZCount(ctx, disciplineTotalsKey, "(0", "+inf").Val())
 
 	if total > 0 {
+		totalString := strconv.FormatFloat(total, 'f', -1, 64)
+
 		// rating position is amount of students with Total greater than in current student
-		scoreRating.Rating = int(loader.redis.ZCount(
-			ctx, disciplineTotalsKey,
-			"("+strconv.FormatFloat(total, 'f', -1, 64),
-			"+inf",
-		).Val()) + 1
+		counts.Greater = int(loader.redis.ZCount(ctx, disciplineTotalsKey, "("+totalString, "+inf").Val())
+		counts.Tied = int(loader.redis.ZCount(ctx, disciplineTotalsKey, totalString, totalString).Val())
 	}
 
+	var minTotal, maxTotal float32
 	var score []redis.Z
 	opt := &redis.ZRangeBy{
 		Min:    "0.1",
@@ -46,14 +46,14 @@ func (loader *ScoreRatingLoader) load(year int, semester int, disciplineId int,
 	// MIN: ZRANGE 2022:1:totals:194229 0.1 100 BYSCORE LIMIT 0 1 WITHSCORES
 	score = loader.redis.ZRangeByScoreWithScores(ctx, disciplineTotalsKey, opt).Val()
 	if len(score) != 0 {
-		scoreRating.MinTotal = float32(score[0].Score)
+		minTotal = float32(score[0].Score)
 	}
 
 	// MAX: ZRANGE 2022:1:totals:194229 100 0.1 BYSCORE REV LIMIT 0 1 WITHSCORES
 	score = loader.redis.ZRevRangeByScoreWithScores(ctx, disciplineTotalsKey, opt).Val()
 	if len(score) != 0 {
-		scoreRating.MaxTotal = float32(score[0].Score)
+		maxTotal = float32(score[0].Score)
 	}
 
-	return scoreRating
+	return loader.zeroTotalsMode.makeRating(float32(total), minTotal, maxTotal, counts)
 }
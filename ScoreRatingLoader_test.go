@@ -11,12 +11,17 @@ import (
 func TestScoreRatingLoader(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 
-		expectedScoreRating := scoreApi.ScoreRating{
-			Total:         17.5,
-			StudentsCount: 25,
-			Rating:        8,
-			MinTotal:      10,
-			MaxTotal:      20,
+		expectedScoreRating := ScoreRating{
+			ScoreRating: scoreApi.ScoreRating{
+				Total:         17.5,
+				StudentsCount: 25,
+				Rating:        8,
+				MinTotal:      10,
+				MaxTotal:      20,
+			},
+			Percentile:   62,
+			TieSize:      5,
+			NonZeroCount: 22,
 		}
 
 		redisClient, redisMock := redismock.NewClientMock()
@@ -27,8 +32,10 @@ func TestScoreRatingLoader(t *testing.T) {
 
 		redisMock.ExpectZCard(disciplineTotalsKey).SetVal(25)
 		redisMock.ExpectZScore(disciplineTotalsKey, studentKey).SetVal(17.5)
+		redisMock.ExpectZCount(disciplineTotalsKey, "(0", "+inf").SetVal(22)
 
 		redisMock.ExpectZCount(disciplineTotalsKey, "(17.5", "+inf").SetVal(7)
+		redisMock.ExpectZCount(disciplineTotalsKey, "17.5", "17.5").SetVal(5)
 
 		opt := &redis.ZRangeBy{
 			Min:    "0.1",
@@ -62,12 +69,17 @@ func TestScoreRatingLoader(t *testing.T) {
 	})
 
 	t.Run("zero_total", func(t *testing.T) {
-		expectedScoreRating := scoreApi.ScoreRating{
-			Total:         0,
-			StudentsCount: 25,
-			Rating:        25,
-			MinTotal:      10,
-			MaxTotal:      20,
+		expectedScoreRating := ScoreRating{
+			ScoreRating: scoreApi.ScoreRating{
+				Total:         0,
+				StudentsCount: 25,
+				Rating:        25,
+				MinTotal:      10,
+				MaxTotal:      20,
+			},
+			Percentile:   6,
+			TieSize:      3,
+			NonZeroCount: 22,
 		}
 
 		redisClient, redisMock := redismock.NewClientMock()
@@ -78,6 +90,7 @@ func TestScoreRatingLoader(t *testing.T) {
 
 		redisMock.ExpectZCard(disciplineTotalsKey).SetVal(25)
 		redisMock.ExpectZScore(disciplineTotalsKey, studentKey).RedisNil()
+		redisMock.ExpectZCount(disciplineTotalsKey, "(0", "+inf").SetVal(22)
 
 		opt := &redis.ZRangeBy{
 			Min:    "0.1",
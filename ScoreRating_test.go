@@ -0,0 +1,158 @@
+package main
+
+import (
+	scoreApi "github.com/kneu-messenger-pigeon/score-api"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// TestZeroTotalsModeMakeRating documents rating semantics for discipline of 25 students:
+// 22 of them have non-zero totals, 7 students have total greater than 17.5 and 5 students (including the student) have 17.5
+func TestZeroTotalsModeMakeRating(t *testing.T) {
+	counts := RatingCounts{Students: 25, NonZero: 22, Greater: 7, Tied: 5}
+
+	testCases := []struct {
+		name     string
+		mode     ZeroTotalsMode
+		total    float32
+		counts   RatingCounts
+		expected ScoreRating
+	}{
+		{
+			name:   "last: tied total shares the best position of tie group",
+			mode:   ZeroTotalsLast,
+			total:  17.5,
+			counts: counts,
+			// 10 non-zero and 3 zero totals are lower: (13 + 5 / 2) / 25
+			expected: makeTestScoreRating(17.5, 25, 8, 62, 5, 22),
+		},
+		{
+			name:     "last: zero total takes the last position",
+			mode:     ZeroTotalsLast,
+			total:    0,
+			counts:   counts,
+			expected: makeTestScoreRating(0, 25, 25, 6, 3, 22),
+		},
+		{
+			name:     "last: best total without ties",
+			mode:     ZeroTotalsLast,
+			total:    30,
+			counts:   RatingCounts{Students: 25, NonZero: 22, Greater: 0, Tied: 1},
+			expected: makeTestScoreRating(30, 25, 1, 98, 1, 22),
+		},
+		{
+			name:     "shared: zero totals share position after non-zero totals",
+			mode:     ZeroTotalsShared,
+			total:    0,
+			counts:   counts,
+			expected: makeTestScoreRating(0, 25, 23, 6, 3, 22),
+		},
+		{
+			name:     "shared: non-zero total is ranked the same as in last mode",
+			mode:     ZeroTotalsShared,
+			total:    17.5,
+			counts:   counts,
+			expected: makeTestScoreRating(17.5, 25, 8, 62, 5, 22),
+		},
+		{
+			name:     "exclude: zero total has no position and is not counted",
+			mode:     ZeroTotalsExcluded,
+			total:    0,
+			counts:   counts,
+			expected: makeTestScoreRating(0, 22, 0, 0, 0, 22),
+		},
+		{
+			name:   "exclude: non-zero total is ranked among non-zero totals only",
+			mode:   ZeroTotalsExcluded,
+			total:  17.5,
+			counts: counts,
+			// 10 non-zero totals are lower: (10 + 5 / 2) / 22
+			expected: makeTestScoreRating(17.5, 22, 8, 56.8, 5, 22),
+		},
+		{
+			name:     "last: empty discipline",
+			mode:     ZeroTotalsLast,
+			total:    0,
+			counts:   RatingCounts{},
+			expected: makeTestScoreRating(0, 0, 0, 0, 0, 0),
+		},
+		{
+			name:     "last: all students have zero totals",
+			mode:     ZeroTotalsLast,
+			total:    0,
+			counts:   RatingCounts{Students: 4},
+			expected: makeTestScoreRating(0, 4, 4, 50, 4, 0),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			expected := testCase.expected
+			expected.MinTotal = 10
+			expected.MaxTotal = 20
+
+			assert.Equal(t, expected, testCase.mode.makeRating(testCase.total, 10, 20, testCase.counts))
+		})
+	}
+}
+
+func TestMakeScoreRating(t *testing.T) {
+	totals := map[int]float32{
+		1200: 17.5,
+		1300: 20,
+		1400: 17.5,
+		1500: 10,
+		1600: 0,
+		1700: 120,
+	}
+
+	t.Run("success", func(t *testing.T) {
+		expected := makeTestScoreRating(17.5, 6, 3, 50, 2, 5)
+		expected.MinTotal = 10
+		expected.MaxTotal = 20
+
+		assert.Equal(t, expected, ZeroTotalsLast.makeScoreRating(totals, 1200))
+	})
+
+	t.Run("zero_total", func(t *testing.T) {
+		expected := makeTestScoreRating(0, 6, 6, 8.3, 1, 5)
+		expected.MinTotal = 10
+		expected.MaxTotal = 20
+
+		assert.Equal(t, expected, ZeroTotalsLast.makeScoreRating(totals, 1600))
+	})
+
+	t.Run("not_exists", func(t *testing.T) {
+		assert.Equal(t, 6, ZeroTotalsLast.makeScoreRating(totals, 1800).Rating)
+		assert.Equal(t, ScoreRating{}, ZeroTotalsLast.makeScoreRating(map[int]float32{}, 1200))
+	})
+
+	t.Run("mode", func(t *testing.T) {
+		assert.Equal(t, 5, ZeroTotalsExcluded.makeScoreRating(totals, 1200).StudentsCount)
+		assert.Equal(t, 0, ZeroTotalsExcluded.makeScoreRating(totals, 1600).Rating)
+	})
+}
+
+func TestParseZeroTotalsMode(t *testing.T) {
+	for _, value := range []string{"last", "shared", "exclude"} {
+		mode, err := parseZeroTotalsMode(value)
+		assert.NoError(t, err)
+		assert.Equal(t, ZeroTotalsMode(value), mode)
+	}
+
+	_, err := parseZeroTotalsMode("first")
+	assert.EqualError(t, err, "Wrong RATING_ZERO_TOTALS: first, expected last, shared or exclude")
+}
+
+func makeTestScoreRating(total float32, studentsCount int, rating int, percentile float32, tieSize int, nonZeroCount int) ScoreRating {
+	return ScoreRating{
+		ScoreRating: scoreApi.ScoreRating{
+			Total:         total,
+			StudentsCount: studentsCount,
+			Rating:        rating,
+		},
+		Percentile:   percentile,
+		TieSize:      tieSize,
+		NonZeroCount: nonZeroCount,
+	}
+}
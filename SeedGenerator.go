@@ -0,0 +1,351 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	scoreApi "github.com/kneu-messenger-pigeon/score-api"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// SeedOptions - shape of generated academic year
+type SeedOptions struct {
+	Year        int
+	Students    int
+	Groups      int
+	Disciplines int // per group and semester
+	Lessons     int // per discipline
+	ScoreMean   float64
+	ScoreStdDev float64
+	MaxScore    float64
+	ScoreRate   float64 // probability of score for attended lesson
+	AbsenceRate float64
+	DeletedRate float64 // probability of lesson to be deleted after it was held
+}
+
+const (
+	seedFirstStudentId    = 100001
+	seedFirstGroupId      = 101
+	seedFirstDisciplineId = 1001
+	seedFirstLessonId     = 10001
+	seedRetakeRate        = 0.1
+	seedMinCredits        = 3
+	seedCreditsVariants   = 4
+	seedFaculty           = "Факультет економіки та управління"
+	seedStudyForm         = "денна"
+	seedModuleTestEvery   = 8
+)
+
+const (
+	seedLectureTypeId    = 1
+	seedPracticeTypeId   = 2
+	seedModuleTestTypeId = 15
+)
+
+var seedLessonTypes = []scoreApi.LessonType{
+	{Id: seedLectureTypeId, ShortName: "Лек", LongName: "Лекція"},
+	{Id: seedPracticeTypeId, ShortName: "ПрЗн", LongName: "Практичне зан."},
+	{Id: 3, ShortName: "СемЗн", LongName: "Семінарське зан."},
+	{Id: 4, ShortName: "ЛабЗн", LongName: "Лабораторне зан."},
+	{Id: seedModuleTestTypeId, ShortName: "МК", LongName: "Модульний контроль."},
+}
+
+var seedDisciplineNames = []string{
+	"Політична економія", "Мікроекономіка", "Макроекономіка", "Вища математика", "Статистика",
+	"Економетрика", "Фінанси", "Бухгалтерський облік", "Маркетинг", "Менеджмент",
+	"Інформатика", "Іноземна мова",
+}
+
+func (options SeedOptions) validate() error {
+	if options.Year < FirstAcademicYear {
+		return errors.New("year should be 2022 or later")
+	}
+
+	if options.Students <= 0 || options.Groups <= 0 || options.Disciplines <= 0 || options.Lessons <= 0 {
+		return errors.New("students, groups, disciplines and lessons should be positive")
+	}
+
+	if options.Groups > options.Students {
+		return errors.New("groups should not be more than students")
+	}
+
+	if options.MaxScore <= 0 || options.ScoreStdDev < 0 {
+		return errors.New("max score should be positive and score standard deviation should not be negative")
+	}
+
+	for _, rate := range []float64{options.ScoreRate, options.AbsenceRate, options.DeletedRate} {
+		if rate < 0 || rate > 1 {
+			return errors.New("rates should be between 0 and 1")
+		}
+	}
+
+	return nil
+}
+
+// SeedGenerator generates academic year in the key layout read by Storage.
+// Semesters start on the 1st of September of the year and the 1st of February of the next year,
+// lessons are held weekly and lessons after now are not generated.
+type SeedGenerator struct {
+	options SeedOptions
+	random  *rand.Rand
+	now     time.Time
+	codec   RedisCodec
+
+	nextLessonId int
+}
+
+type seedDiscipline struct {
+	id         int
+	semester   int
+	studentIds []int
+}
+
+func (generator *SeedGenerator) generate() ([]SnapshotEntry, error) {
+	if err := generator.options.validate(); err != nil {
+		return nil, err
+	}
+
+	generator.nextLessonId = seedFirstLessonId
+	lessonTypesJson, _ := json.Marshal(seedLessonTypes)
+
+	entries := []SnapshotEntry{
+		{Key: "currentYear", Type: SnapshotTypeString, String: strconv.Itoa(generator.options.Year)},
+		{Key: "lessonTypes", Type: SnapshotTypeString, String: string(lessonTypesJson)},
+	}
+
+	groups := generator.makeGroups()
+	for index, groupStudentIds := range groups {
+		groupId := strconv.Itoa(seedFirstGroupId + index)
+		members := make([]string, len(groupStudentIds))
+		for memberIndex, studentId := range groupStudentIds {
+			members[memberIndex] = strconv.Itoa(studentId)
+			entries = append(entries, SnapshotEntry{
+				Key:  fmt.Sprintf("%d:student:%d", generator.options.Year, studentId),
+				Type: SnapshotTypeHash,
+				Hash: map[string]string{
+					"name":       fmt.Sprintf("Студент %d", studentId),
+					"group":      fmt.Sprintf("Група %s", groupId),
+					"faculty":    seedFaculty,
+					"study_form": seedStudyForm,
+				},
+			})
+			entries = append(entries, SnapshotEntry{
+				Key:    fmt.Sprintf("%d:student_group:%d", generator.options.Year, studentId),
+				Type:   SnapshotTypeString,
+				String: groupId,
+			})
+		}
+
+		entries = append(entries, SnapshotEntry{
+			Key:  fmt.Sprintf("%d:group:%s", generator.options.Year, groupId),
+			Type: SnapshotTypeSet,
+			Set:  members,
+		})
+	}
+
+	studentDisciplines := make(map[string][]string)
+	for _, discipline := range generator.makeDisciplines(groups) {
+		entries = append(entries, generator.generateDiscipline(discipline)...)
+
+		for _, studentId := range discipline.studentIds {
+			key := fmt.Sprintf("%d:%d:student_disciplines:%d", generator.options.Year, discipline.semester, studentId)
+			studentDisciplines[key] = append(studentDisciplines[key], strconv.Itoa(discipline.id))
+		}
+	}
+
+	for _, key := range sortedStringSliceMapKeys(studentDisciplines) {
+		entries = append(entries, SnapshotEntry{Key: key, Type: SnapshotTypeSet, Set: studentDisciplines[key]})
+	}
+
+	return entries, nil
+}
+
+// makeGroups splits students into groups of nearly the same size
+func (generator *SeedGenerator) makeGroups() [][]int {
+	options := generator.options
+	groups := make([][]int, options.Groups)
+	for index := 0; index < options.Students; index++ {
+		groups[index%options.Groups] = append(groups[index%options.Groups], seedFirstStudentId+index)
+	}
+
+	return groups
+}
+
+// makeDisciplines creates own disciplines for every group in both semesters
+func (generator *SeedGenerator) makeDisciplines(groups [][]int) []seedDiscipline {
+	options := generator.options
+	disciplines := make([]seedDiscipline, 0, options.Groups*options.Disciplines*2)
+	for semester := 1; semester <= 2; semester++ {
+		for _, groupStudentIds := range groups {
+			for index := 0; index < options.Disciplines; index++ {
+				disciplines = append(disciplines, seedDiscipline{
+					id:         seedFirstDisciplineId + len(disciplines),
+					semester:   semester,
+					studentIds: groupStudentIds,
+				})
+			}
+		}
+	}
+
+	return disciplines
+}
+
+func (generator *SeedGenerator) generateDiscipline(discipline seedDiscipline) []SnapshotEntry {
+	year := generator.options.Year
+	keyPrefix := fmt.Sprintf("%d:%d:", year, discipline.semester)
+
+	semesterStart := time.Date(year, time.September, 1, 0, 0, 0, 0, generator.codec.academicLocation())
+	if discipline.semester == 2 {
+		semesterStart = time.Date(year+1, time.February, 1, 0, 0, 0, 0, generator.codec.academicLocation())
+	}
+
+	entries := make([]SnapshotEntry, 0)
+	lessons := make(map[string]string)
+	deletedLessons := make(map[string]string)
+	scores := make(map[int]map[string]string, len(discipline.studentIds))
+	totals := make(map[int]float64, len(discipline.studentIds))
+	updatedAt := semesterStart
+
+	for _, studentId := range discipline.studentIds {
+		scores[studentId] = make(map[string]string)
+		totals[studentId] = 0
+	}
+
+	for index := 0; index < generator.options.Lessons; index++ {
+		lessonDate := semesterStart.AddDate(0, 0, 7*index)
+		if lessonDate.After(generator.now) {
+			break
+		}
+
+		lessonId := generator.nextLessonId
+		generator.nextLessonId++
+		updatedAt = lessonDate.Add(time.Hour * 18)
+
+		lessonValue := generator.codec.encodeLessonValue(lessonDate, seedLessonTypeId(index))
+		if generator.random.Float64() < generator.options.DeletedRate {
+			entries = append(entries, SnapshotEntry{
+				Key:    fmt.Sprintf("%sdeleted-lessons:%d:%d", keyPrefix, discipline.id, lessonId),
+				Type:   SnapshotTypeString,
+				String: lessonValue,
+			})
+			deletedLessons[strconv.Itoa(lessonId)] = lessonValue
+		} else {
+			lessons[strconv.Itoa(lessonId)] = lessonValue
+		}
+
+		for _, studentId := range discipline.studentIds {
+			for half, value := range generator.generateLessonScores() {
+				scores[studentId][generator.codec.encodeScoreField(lessonId, half)] = generator.codec.encodeScoreValue(value)
+				if value != IsAbsentScoreValue {
+					totals[studentId] += float64(value)
+				}
+			}
+		}
+	}
+
+	entries = append(entries,
+		SnapshotEntry{
+			Key:  fmt.Sprintf("%d:discipline:%d", year, discipline.id),
+			Type: SnapshotTypeHash,
+			Hash: map[string]string{
+				"name":    seedDisciplineNames[discipline.id%len(seedDisciplineNames)],
+				"credits": strconv.Itoa(seedMinCredits + discipline.id%seedCreditsVariants),
+			},
+		},
+		SnapshotEntry{
+			Key:    fmt.Sprintf("%d:discipline_semester_updated_at:%d", year, discipline.id),
+			Type:   SnapshotTypeString,
+			String: generator.codec.encodeSemesterUpdatedAt(discipline.semester, updatedAt),
+		},
+	)
+
+	if len(lessons) != 0 {
+		entries = append(entries, SnapshotEntry{
+			Key:  fmt.Sprintf("%slessons:%d", keyPrefix, discipline.id),
+			Type: SnapshotTypeHash,
+			Hash: lessons,
+		})
+	}
+
+	if len(deletedLessons) != 0 {
+		entries = append(entries, SnapshotEntry{
+			Key:  fmt.Sprintf("%sdeleted-lessons-index:%d", keyPrefix, discipline.id),
+			Type: SnapshotTypeHash,
+			Hash: deletedLessons,
+		})
+	}
+
+	totalMembers := make([]SnapshotMember, 0, len(discipline.studentIds))
+	for _, studentId := range discipline.studentIds {
+		if len(scores[studentId]) != 0 {
+			entries = append(entries, SnapshotEntry{
+				Key:  fmt.Sprintf("%sscores:%d:%d", keyPrefix, studentId, discipline.id),
+				Type: SnapshotTypeHash,
+				Hash: scores[studentId],
+			})
+		}
+
+		totalMembers = append(totalMembers, SnapshotMember{
+			Member: strconv.Itoa(studentId),
+			Score:  math.Round(totals[studentId]*100) / 100,
+		})
+	}
+
+	return append(entries, SnapshotEntry{
+		Key:  fmt.Sprintf("%stotals:%d", keyPrefix, discipline.id),
+		Type: SnapshotTypeZset,
+		Zset: totalMembers,
+	})
+}
+
+// generateLessonScores returns score values by half: absence mark, first score with rare retake or nothing
+func (generator *SeedGenerator) generateLessonScores() map[int]float32 {
+	if generator.random.Float64() < generator.options.AbsenceRate {
+		return map[int]float32{1: IsAbsentScoreValue}
+	}
+
+	if generator.random.Float64() >= generator.options.ScoreRate {
+		return nil
+	}
+
+	values := map[int]float32{1: generator.generateScore()}
+	if generator.random.Float64() < seedRetakeRate {
+		values[2] = generator.generateScore()
+	}
+
+	return values
+}
+
+// generateScore returns normally distributed score rounded to a half point and limited by zero and max score
+func (generator *SeedGenerator) generateScore() float32 {
+	options := generator.options
+	score := generator.random.NormFloat64()*options.ScoreStdDev + options.ScoreMean
+
+	return float32(math.Max(0, math.Min(options.MaxScore, math.Round(score*2)/2)))
+}
+
+func seedLessonTypeId(index int) int {
+	if (index+1)%seedModuleTestEvery == 0 {
+		return seedModuleTestTypeId
+	}
+
+	if index%2 == 0 {
+		return seedLectureTypeId
+	}
+
+	return seedPracticeTypeId
+}
+
+func sortedStringSliceMapKeys(values map[string][]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"encoding/json"
+	scoreApi "github.com/kneu-messenger-pigeon/score-api"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+)
+
+func getTestSeedOptions() SeedOptions {
+	return SeedOptions{
+		Year:        2026,
+		Students:    5,
+		Groups:      2,
+		Disciplines: 2,
+		Lessons:     10,
+		ScoreMean:   3.5,
+		ScoreStdDev: 1,
+		MaxScore:    5,
+		ScoreRate:   0.5,
+		AbsenceRate: 0.2,
+		DeletedRate: 0.1,
+	}
+}
+
+func TestSeedGenerator(t *testing.T) {
+	t.Run("key_layout", func(t *testing.T) {
+		codec := RedisCodec{}
+		now := time.Date(2026, time.October, 18, 12, 0, 0, 0, defaultAcademicLocation)
+		generator := SeedGenerator{
+			options: getTestSeedOptions(),
+			random:  rand.New(rand.NewSource(1)),
+			now:     now,
+		}
+
+		entries, err := generator.generate()
+		assert.NoError(t, err)
+
+		entriesByKey := make(map[string]SnapshotEntry, len(entries))
+		for _, entry := range entries {
+			assert.NoError(t, entry.validate())
+			assert.NotContains(t, entriesByKey, entry.Key)
+			entriesByKey[entry.Key] = entry
+		}
+
+		assert.Equal(t, "2026", entriesByKey["currentYear"].String)
+		var lessonTypes []scoreApi.LessonType
+		assert.NoError(t, json.Unmarshal([]byte(entriesByKey["lessonTypes"].String), &lessonTypes))
+		assert.Equal(t, seedLessonTypes, lessonTypes)
+
+		// 5 students in 2 groups with 2 disciplines per semester
+		assert.Equal(t, []string{"1001", "1002"}, entriesByKey["2026:1:student_disciplines:100001"].Set)
+		assert.Equal(t, []string{"1003", "1004"}, entriesByKey["2026:1:student_disciplines:100002"].Set)
+		assert.Equal(t, []string{"1005", "1006"}, entriesByKey["2026:2:student_disciplines:100001"].Set)
+		assert.Equal(t, []string{"100001", "100003", "100005"}, entriesByKey["2026:group:101"].Set)
+		assert.Equal(t, []string{"100002", "100004"}, entriesByKey["2026:group:102"].Set)
+		assert.Equal(t, "102", entriesByKey["2026:student_group:100004"].String)
+		assert.Equal(t, "Група 102", entriesByKey["2026:student:100004"].Hash["group"])
+		assert.Len(t, entriesByKey["2026:1:totals:1001"].Zset, 3)
+		assert.Len(t, entriesByKey["2026:1:totals:1003"].Zset, 2)
+
+		// first semester lessons are held weekly from the 1st of September until now
+		// deleted lessons are indexed by the discipline hash next to their keys
+		lessonsCount := len(entriesByKey["2026:1:lessons:1001"].Hash)
+		deletedLessonsIndex := entriesByKey["2026:1:deleted-lessons-index:1001"].Hash
+		for key, entry := range entriesByKey {
+			if strings.HasPrefix(key, "2026:1:deleted-lessons:1001:") {
+				lessonsCount++
+				assert.Equal(t, entry.String, deletedLessonsIndex[strings.TrimPrefix(key, "2026:1:deleted-lessons:1001:")])
+			}
+		}
+		assert.Equal(t, 7, lessonsCount)
+		assert.Equal(t, lessonsCount-len(entriesByKey["2026:1:lessons:1001"].Hash), len(deletedLessonsIndex))
+
+		for lessonId, lessonValue := range entriesByKey["2026:1:lessons:1001"].Hash {
+			_, err = codec.decodeId(lessonId)
+			assert.NoError(t, err)
+
+			lessonDate, lessonTypeId, err := codec.decodeLessonValue(lessonValue)
+			assert.NoError(t, err)
+			assert.False(t, lessonDate.After(now))
+			assert.Contains(t, []int{seedLectureTypeId, seedPracticeTypeId, seedModuleTestTypeId}, lessonTypeId)
+		}
+
+		semester, updatedAt, err := codec.decodeSemesterUpdatedAt(entriesByKey["2026:discipline_semester_updated_at:1001"].String)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, semester)
+		assert.Equal(t, time.Date(2026, time.October, 13, 18, 0, 0, 0, defaultAcademicLocation), updatedAt.In(defaultAcademicLocation))
+
+		// second semester is not started yet
+		assert.NotContains(t, entriesByKey, "2026:2:lessons:1005")
+		semester, updatedAt, err = codec.decodeSemesterUpdatedAt(entriesByKey["2026:discipline_semester_updated_at:1005"].String)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, semester)
+		assert.Equal(t, time.Date(2027, time.February, 1, 0, 0, 0, 0, defaultAcademicLocation), updatedAt.In(defaultAcademicLocation))
+
+		assert.NotEmpty(t, entriesByKey["2026:discipline:1001"].Hash["name"])
+		assert.Equal(t, "4", entriesByKey["2026:discipline:1001"].Hash["credits"])
+
+		// totals are sums of scores without absence marks
+		for _, member := range entriesByKey["2026:1:totals:1001"].Zset {
+			var sum float64
+			for field, value := range entriesByKey["2026:1:scores:"+member.Member+":1001"].Hash {
+				scoreEntry, err := codec.decodeScoreEntry(field, value)
+				assert.NoError(t, err)
+				assert.LessOrEqual(t, scoreEntry.Value, float32(5))
+				if !scoreEntry.isAbsent() {
+					sum += float64(scoreEntry.Value)
+				}
+			}
+			assert.InDelta(t, sum, member.Score, 0.001)
+		}
+	})
+
+	t.Run("repeatable", func(t *testing.T) {
+		now := time.Date(2027, time.March, 1, 12, 0, 0, 0, defaultAcademicLocation)
+		first := SeedGenerator{options: getTestSeedOptions(), random: rand.New(rand.NewSource(7)), now: now}
+		second := SeedGenerator{options: getTestSeedOptions(), random: rand.New(rand.NewSource(7)), now: now}
+
+		firstEntries, _ := first.generate()
+		secondEntries, _ := second.generate()
+
+		assert.Equal(t, firstEntries, secondEntries)
+	})
+
+	t.Run("wrong_options", func(t *testing.T) {
+		testCases := map[string]func(options *SeedOptions){
+			"year should be 2022 or later": func(options *SeedOptions) {
+				options.Year = 2000
+			},
+			"students, groups, disciplines and lessons should be positive": func(options *SeedOptions) {
+				options.Lessons = 0
+			},
+			"groups should not be more than students": func(options *SeedOptions) {
+				options.Groups = 6
+			},
+			"max score should be positive and score standard deviation should not be negative": func(options *SeedOptions) {
+				options.ScoreStdDev = -1
+			},
+			"rates should be between 0 and 1": func(options *SeedOptions) {
+				options.AbsenceRate = 1.5
+			},
+		}
+
+		for expectedError, modify := range testCases {
+			options := getTestSeedOptions()
+			modify(&options)
+			generator := SeedGenerator{options: options, random: rand.New(rand.NewSource(1))}
+
+			entries, err := generator.generate()
+
+			assert.EqualError(t, err, expectedError)
+			assert.Nil(t, entries)
+		}
+	})
+}
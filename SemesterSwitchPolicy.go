@@ -0,0 +1,335 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"os"
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	SemesterSwitchPolicyInterval  = "interval"
+	SemesterSwitchPolicyThreshold = "threshold"
+	SemesterSwitchPolicyCalendar  = "calendar"
+)
+
+const DisciplineAmountThresholdForSemesterSwitch = 2
+
+const MaxSemesterUpdatedInterval = time.Hour * 24 * 7 * 6 // 6 weeks
+// 6 weeks = 2 weeks fir winter holidays + 3 weeks for exams + 2 weeks for next semester lectures
+
+const academicCalendarRedisKey = "academicCalendar"
+
+// SemesterSwitchPolicyInterface selects actual disciplines when the student has disciplines of both semesters.
+// Discipline of both semesters is always taken from the second semester.
+type SemesterSwitchPolicyInterface interface {
+	selectDisciplines(
+		firstSemesterDisciplines DisciplineSemesters, secondSemesterDisciplines DisciplineSemesters,
+		getLastUpdatedAt func(disciplineId int) (time.Time, error), now time.Time,
+	) ([]DisciplineSemester, error)
+}
+
+// IntervalSemesterSwitchPolicy keeps first semester disciplines which were updated less than maxUpdatedInterval ago
+type IntervalSemesterSwitchPolicy struct {
+	maxUpdatedInterval time.Duration
+}
+
+func (policy IntervalSemesterSwitchPolicy) selectDisciplines(
+	firstSemesterDisciplines DisciplineSemesters, secondSemesterDisciplines DisciplineSemesters,
+	getLastUpdatedAt func(disciplineId int) (time.Time, error), now time.Time,
+) ([]DisciplineSemester, error) {
+	cleanedFirstSemesterDisciplines := make(DisciplineSemesters, 0, len(firstSemesterDisciplines))
+
+	for _, firstSemesterDiscipline := range firstSemesterDisciplines.without(secondSemesterDisciplines) {
+		lastUpdatedAt, err := getLastUpdatedAt(firstSemesterDiscipline.DisciplineId)
+		if err != nil {
+			return nil, err
+		}
+
+		if now.Sub(lastUpdatedAt) < policy.maxUpdatedInterval {
+			cleanedFirstSemesterDisciplines = append(cleanedFirstSemesterDisciplines, firstSemesterDiscipline)
+		}
+	}
+
+	return mergeSemesterDisciplines(cleanedFirstSemesterDisciplines, secondSemesterDisciplines), nil
+}
+
+// ThresholdSemesterSwitchPolicy switches to the second semester when the student has at least threshold
+// disciplines in it, before that disciplines of both semesters are actual
+type ThresholdSemesterSwitchPolicy struct {
+	threshold int
+}
+
+func (policy ThresholdSemesterSwitchPolicy) selectDisciplines(
+	firstSemesterDisciplines DisciplineSemesters, secondSemesterDisciplines DisciplineSemesters,
+	_ func(disciplineId int) (time.Time, error), _ time.Time,
+) ([]DisciplineSemester, error) {
+	if len(secondSemesterDisciplines) >= policy.threshold {
+		return secondSemesterDisciplines, nil
+	}
+
+	return mergeSemesterDisciplines(firstSemesterDisciplines.without(secondSemesterDisciplines), secondSemesterDisciplines), nil
+}
+
+// CalendarSemesterSwitchPolicy selects disciplines by the academic calendar. Calendar stored in Redis
+// `academicCalendar` key as JSON overrides configured one, without any calendar fallback policy is used.
+// Calendar is replaced by the hourly update while requests select disciplines, so it is stored atomically.
+type CalendarSemesterSwitchPolicy struct {
+	redis      *redis.Client
+	location   *time.Location
+	configured *AcademicCalendar
+	calendar   atomic.Pointer[AcademicCalendar]
+	fallback   SemesterSwitchPolicyInterface
+}
+
+func (policy *CalendarSemesterSwitchPolicy) selectDisciplines(
+	firstSemesterDisciplines DisciplineSemesters, secondSemesterDisciplines DisciplineSemesters,
+	getLastUpdatedAt func(disciplineId int) (time.Time, error), now time.Time,
+) ([]DisciplineSemester, error) {
+	calendar := policy.calendar.Load()
+	if calendar == nil {
+		return policy.fallback.selectDisciplines(firstSemesterDisciplines, secondSemesterDisciplines, getLastUpdatedAt, now)
+	}
+
+	// discipline of both semesters is taken from the second semester before its start as well
+	if now.Before(calendar.Semesters[1].Start.Time()) {
+		return mergeSemesterDisciplines(
+			firstSemesterDisciplines.without(secondSemesterDisciplines),
+			secondSemesterDisciplines.within(firstSemesterDisciplines),
+		), nil
+	}
+
+	if now.Before(calendar.firstSemesterActualUntil()) {
+		return mergeSemesterDisciplines(firstSemesterDisciplines.without(secondSemesterDisciplines), secondSemesterDisciplines), nil
+	}
+
+	return secondSemesterDisciplines, nil
+}
+
+func (policy *CalendarSemesterSwitchPolicy) periodicallyUpdateCalendar(ctx context.Context) {
+	for ctx.Err() == nil {
+		time.Sleep(time.Hour)
+		policy.updateCalendar()
+	}
+}
+
+// updateCalendar replaces configured calendar with the one stored in Redis, invalid Redis calendar keeps previous state
+func (policy *CalendarSemesterSwitchPolicy) updateCalendar() {
+	calendarJSON, err := policy.redis.Get(context.Background(), academicCalendarRedisKey).Bytes()
+	if errors.Is(err, redis.Nil) {
+		policy.calendar.Store(policy.configured)
+		return
+	}
+
+	if calendar, err := parseAcademicCalendar(calendarJSON, policy.location); err == nil {
+		policy.calendar.Store(calendar)
+	}
+}
+
+// CalendarDate - date of the academic calendar in `2006-01-02` format,
+// decoded as UTC midnight and moved to midnight of the academic location by parseAcademicCalendar
+type CalendarDate time.Time
+
+func (date *CalendarDate) UnmarshalJSON(data []byte) error {
+	var dateString string
+	if err := json.Unmarshal(data, &dateString); err != nil {
+		return err
+	}
+
+	parsed, err := time.Parse(dateOnlyLayout, dateString)
+	if err != nil {
+		return err
+	}
+
+	*date = CalendarDate(parsed)
+	return nil
+}
+
+// locate moves the date to midnight of the same day in the location, unset date stays zero
+func (date *CalendarDate) locate(location *time.Location) {
+	if !date.Time().IsZero() {
+		year, month, day := date.Time().Date()
+		*date = CalendarDate(time.Date(year, month, day, 0, 0, 0, 0, location))
+	}
+}
+
+func (date CalendarDate) Time() time.Time {
+	return time.Time(date)
+}
+
+// CalendarPeriod - period of the academic calendar, both dates are inclusive
+type CalendarPeriod struct {
+	Start CalendarDate `json:"start"`
+	End   CalendarDate `json:"end"`
+}
+
+func (period *CalendarPeriod) locate(location *time.Location) {
+	period.Start.locate(location)
+	period.End.locate(location)
+}
+
+// endsAt returns the midnight after the last day of the period
+func (period CalendarPeriod) endsAt() time.Time {
+	return period.End.Time().AddDate(0, 0, 1)
+}
+
+type CalendarSemester struct {
+	CalendarPeriod
+	ExamSession CalendarPeriod `json:"examSession"`
+}
+
+// AcademicCalendar - lectures and exam session of both semesters and holidays of the current academic year
+type AcademicCalendar struct {
+	Semesters []CalendarSemester `json:"semesters"`
+	Holidays  []CalendarPeriod   `json:"holidays"`
+}
+
+// firstSemesterActualUntil returns the end of the first semester lectures or exam session, extended by holidays
+// which follow without a gap, so first semester results are shown until the second semester lectures
+func (calendar *AcademicCalendar) firstSemesterActualUntil() time.Time {
+	firstSemester := calendar.Semesters[0]
+	until := firstSemester.endsAt()
+	if !firstSemester.ExamSession.End.Time().IsZero() && firstSemester.ExamSession.endsAt().After(until) {
+		until = firstSemester.ExamSession.endsAt()
+	}
+
+	for _, holiday := range calendar.Holidays {
+		if !holiday.Start.Time().After(until) && holiday.endsAt().After(until) {
+			until = holiday.endsAt()
+		}
+	}
+
+	return until
+}
+
+// locate moves all dates of the calendar to midnights of the location
+func (calendar *AcademicCalendar) locate(location *time.Location) {
+	for index := range calendar.Semesters {
+		calendar.Semesters[index].CalendarPeriod.locate(location)
+		calendar.Semesters[index].ExamSession.locate(location)
+	}
+
+	for index := range calendar.Holidays {
+		calendar.Holidays[index].locate(location)
+	}
+}
+
+func (calendar *AcademicCalendar) validate() error {
+	if len(calendar.Semesters) != 2 {
+		return errors.New("two semesters expected")
+	}
+
+	periods := []CalendarPeriod{calendar.Semesters[0].CalendarPeriod, calendar.Semesters[1].CalendarPeriod}
+	for index, semester := range calendar.Semesters {
+		if !semester.ExamSession.Start.Time().IsZero() || !semester.ExamSession.End.Time().IsZero() {
+			periods = append(periods, semester.ExamSession)
+		}
+		if semester.Start.Time().IsZero() || semester.End.Time().IsZero() {
+			return fmt.Errorf("semester %d: start and end expected", index+1)
+		}
+	}
+	periods = append(periods, calendar.Holidays...)
+
+	for _, period := range periods {
+		if period.End.Time().Before(period.Start.Time()) {
+			return fmt.Errorf(
+				"period %s - %s ends before start",
+				period.Start.Time().Format(dateOnlyLayout), period.End.Time().Format(dateOnlyLayout),
+			)
+		}
+	}
+
+	if !calendar.Semesters[0].Start.Time().Before(calendar.Semesters[1].Start.Time()) {
+		return errors.New("second semester should start after the first one")
+	}
+
+	return nil
+}
+
+// parseAcademicCalendar decodes and validates calendar JSON, dates are midnights of the location
+// and holidays are sorted by start
+func parseAcademicCalendar(calendarJSON []byte, location *time.Location) (*AcademicCalendar, error) {
+	calendar := &AcademicCalendar{}
+	err := json.Unmarshal(calendarJSON, calendar)
+	if err == nil {
+		err = calendar.validate()
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	calendar.locate(location)
+	sort.Slice(calendar.Holidays, func(i, j int) bool {
+		return calendar.Holidays[i].Start.Time().Before(calendar.Holidays[j].Start.Time())
+	})
+
+	return calendar, nil
+}
+
+// loadAcademicCalendar reads calendar JSON file, empty filename means calendar is not configured
+func loadAcademicCalendar(filename string, location *time.Location) (*AcademicCalendar, error) {
+	if filename == "" {
+		return nil, nil
+	}
+
+	content, err := os.ReadFile(filename)
+	var calendar *AcademicCalendar
+	if err == nil {
+		calendar, err = parseAcademicCalendar(content, location)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("Wrong ACADEMIC_CALENDAR_FILE %s: %w", filename, err)
+	}
+
+	return calendar, nil
+}
+
+// mergeSemesterDisciplines returns actual first semester disciplines followed by the second semester ones
+func mergeSemesterDisciplines(
+	firstSemesterDisciplines DisciplineSemesters, secondSemesterDisciplines DisciplineSemesters,
+) []DisciplineSemester {
+	if len(firstSemesterDisciplines) == 0 {
+		return secondSemesterDisciplines
+	}
+
+	disciplines := make([]DisciplineSemester, len(firstSemesterDisciplines)+len(secondSemesterDisciplines))
+	copy(disciplines, firstSemesterDisciplines)
+	copy(disciplines[len(firstSemesterDisciplines):], secondSemesterDisciplines)
+
+	return disciplines
+}
+
+// NewSemesterSwitchPolicy creates policy by name. Calendar policy loads Redis calendar before return,
+// so CLI commands use it as well, and updates it hourly.
+func NewSemesterSwitchPolicy(
+	name string, calendar *AcademicCalendar, redis *redis.Client, location *time.Location, ctx context.Context,
+) SemesterSwitchPolicyInterface {
+	switch name {
+	case SemesterSwitchPolicyThreshold:
+		return ThresholdSemesterSwitchPolicy{threshold: DisciplineAmountThresholdForSemesterSwitch}
+
+	case SemesterSwitchPolicyCalendar:
+		policy := &CalendarSemesterSwitchPolicy{
+			redis:      redis,
+			location:   location,
+			configured: calendar,
+			fallback:   IntervalSemesterSwitchPolicy{maxUpdatedInterval: MaxSemesterUpdatedInterval},
+		}
+		policy.calendar.Store(calendar)
+		if redis != nil {
+			policy.updateCalendar()
+			go policy.periodicallyUpdateCalendar(ctx)
+		}
+		return policy
+
+	default:
+		return IntervalSemesterSwitchPolicy{maxUpdatedInterval: MaxSemesterUpdatedInterval}
+	}
+}
@@ -0,0 +1,425 @@
+package main
+
+import (
+	"context"
+	"github.com/go-redis/redismock/v9"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+	"time"
+)
+
+const testAcademicCalendarJSON = `{
+	"semesters": [
+		{"start": "2025-09-01", "end": "2025-12-19", "examSession": {"start": "2025-12-22", "end": "2026-01-16"}},
+		{"start": "2026-02-02", "end": "2026-05-29", "examSession": {"start": "2026-06-01", "end": "2026-06-26"}}
+	],
+	"holidays": [
+		{"start": "2026-01-26", "end": "2026-02-01"},
+		{"start": "2026-01-17", "end": "2026-01-25"}
+	]
+}`
+
+var testFirstSemesterDisciplines = DisciplineSemesters{
+	{Semester: 1, DisciplineId: 100},
+	{Semester: 1, DisciplineId: 110},
+	{Semester: 1, DisciplineId: 120},
+}
+
+var testSecondSemesterDisciplines = DisciplineSemesters{
+	{Semester: 2, DisciplineId: 120},
+	{Semester: 2, DisciplineId: 130},
+}
+
+func getTestAcademicCalendar(t *testing.T) *AcademicCalendar {
+	calendar, err := parseAcademicCalendar([]byte(testAcademicCalendarJSON), defaultAcademicLocation)
+	assert.NoError(t, err)
+
+	return calendar
+}
+
+func getTestAcademicDate(date string) time.Time {
+	parsed, _ := time.ParseInLocation(dateOnlyLayout, date, defaultAcademicLocation)
+	return parsed
+}
+
+func TestIntervalSemesterSwitchPolicy(t *testing.T) {
+	now := time.Date(2026, time.Month(2), 10, 12, 0, 0, 0, time.UTC)
+	policy := IntervalSemesterSwitchPolicy{maxUpdatedInterval: MaxSemesterUpdatedInterval}
+
+	t.Run("success", func(t *testing.T) {
+		var requestedDisciplines []int
+		getLastUpdatedAt := func(disciplineId int) (time.Time, error) {
+			requestedDisciplines = append(requestedDisciplines, disciplineId)
+			if disciplineId == 100 {
+				return now.Add(-time.Hour), nil
+			}
+			return now.Add(-MaxSemesterUpdatedInterval - time.Hour), nil
+		}
+
+		disciplines, err := policy.selectDisciplines(
+			testFirstSemesterDisciplines, testSecondSemesterDisciplines, getLastUpdatedAt, now,
+		)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []DisciplineSemester{
+			{Semester: 1, DisciplineId: 100},
+			{Semester: 2, DisciplineId: 120},
+			{Semester: 2, DisciplineId: 130},
+		}, disciplines)
+		assert.Equal(t, []int{100, 110}, requestedDisciplines)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		getLastUpdatedAt := func(disciplineId int) (time.Time, error) {
+			return time.Time{}, assert.AnError
+		}
+
+		disciplines, err := policy.selectDisciplines(
+			testFirstSemesterDisciplines, testSecondSemesterDisciplines, getLastUpdatedAt, now,
+		)
+
+		assert.Equal(t, assert.AnError, err)
+		assert.Nil(t, disciplines)
+	})
+}
+
+func TestThresholdSemesterSwitchPolicy(t *testing.T) {
+	getLastUpdatedAt := func(disciplineId int) (time.Time, error) {
+		t.Fatalf("Unexpected last updated at request for discipline %d", disciplineId)
+		return time.Time{}, nil
+	}
+
+	t.Run("below_threshold", func(t *testing.T) {
+		policy := ThresholdSemesterSwitchPolicy{threshold: 3}
+
+		disciplines, err := policy.selectDisciplines(
+			testFirstSemesterDisciplines, testSecondSemesterDisciplines, getLastUpdatedAt, time.Now(),
+		)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []DisciplineSemester{
+			{Semester: 1, DisciplineId: 100},
+			{Semester: 1, DisciplineId: 110},
+			{Semester: 2, DisciplineId: 120},
+			{Semester: 2, DisciplineId: 130},
+		}, disciplines)
+	})
+
+	t.Run("threshold_reached", func(t *testing.T) {
+		policy := ThresholdSemesterSwitchPolicy{threshold: DisciplineAmountThresholdForSemesterSwitch}
+
+		disciplines, err := policy.selectDisciplines(
+			testFirstSemesterDisciplines, testSecondSemesterDisciplines, getLastUpdatedAt, time.Now(),
+		)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []DisciplineSemester(testSecondSemesterDisciplines), disciplines)
+	})
+}
+
+func newTestCalendarSemesterSwitchPolicy(
+	redisClient *redis.Client, configured *AcademicCalendar, calendar *AcademicCalendar, fallback SemesterSwitchPolicyInterface,
+) *CalendarSemesterSwitchPolicy {
+	policy := &CalendarSemesterSwitchPolicy{
+		redis: redisClient, location: defaultAcademicLocation, configured: configured, fallback: fallback,
+	}
+	policy.calendar.Store(calendar)
+
+	return policy
+}
+
+func TestCalendarSemesterSwitchPolicy(t *testing.T) {
+	getLastUpdatedAt := func(disciplineId int) (time.Time, error) {
+		return time.Now(), nil
+	}
+
+	policy := newTestCalendarSemesterSwitchPolicy(
+		nil, nil, getTestAcademicCalendar(t), IntervalSemesterSwitchPolicy{maxUpdatedInterval: MaxSemesterUpdatedInterval},
+	)
+
+	merged := []DisciplineSemester{
+		{Semester: 1, DisciplineId: 100},
+		{Semester: 1, DisciplineId: 110},
+		{Semester: 2, DisciplineId: 120},
+		{Semester: 2, DisciplineId: 130},
+	}
+
+	// discipline 120 of both semesters is taken from the second semester
+	beforeSecondSemester := []DisciplineSemester{
+		{Semester: 1, DisciplineId: 100},
+		{Semester: 1, DisciplineId: 110},
+		{Semester: 2, DisciplineId: 120},
+	}
+
+	testCases := map[string][]DisciplineSemester{
+		"2025-12-01": beforeSecondSemester,
+		"2026-01-10": beforeSecondSemester,
+		"2026-02-01": beforeSecondSemester,
+		"2026-02-02": testSecondSemesterDisciplines,
+		"2026-04-15": testSecondSemesterDisciplines,
+	}
+
+	for date, expected := range testCases {
+		disciplines, err := policy.selectDisciplines(
+			testFirstSemesterDisciplines, testSecondSemesterDisciplines, getLastUpdatedAt, getTestAcademicDate(date),
+		)
+
+		assert.NoError(t, err, date)
+		assert.Equal(t, expected, disciplines, date)
+	}
+
+	t.Run("second_semester_starts_before_first_ends", func(t *testing.T) {
+		calendar := getTestAcademicCalendar(t)
+		calendar.Semesters[1].Start = CalendarDate(getTestAcademicDate("2026-01-12"))
+		policy := newTestCalendarSemesterSwitchPolicy(nil, nil, calendar, nil)
+
+		disciplines, err := policy.selectDisciplines(
+			testFirstSemesterDisciplines, testSecondSemesterDisciplines, getLastUpdatedAt,
+			getTestAcademicDate("2026-01-20"),
+		)
+
+		assert.NoError(t, err)
+		assert.Equal(t, merged, disciplines)
+
+		disciplines, err = policy.selectDisciplines(
+			testFirstSemesterDisciplines, testSecondSemesterDisciplines, getLastUpdatedAt,
+			getTestAcademicDate("2026-02-02"),
+		)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []DisciplineSemester(testSecondSemesterDisciplines), disciplines)
+	})
+
+	t.Run("fallback_without_calendar", func(t *testing.T) {
+		policy := newTestCalendarSemesterSwitchPolicy(nil, nil, nil, ThresholdSemesterSwitchPolicy{threshold: 10})
+
+		disciplines, err := policy.selectDisciplines(
+			testFirstSemesterDisciplines, testSecondSemesterDisciplines, getLastUpdatedAt, time.Now(),
+		)
+
+		assert.NoError(t, err)
+		assert.Equal(t, merged, disciplines)
+	})
+}
+
+func TestCalendarSemesterSwitchPolicyUpdateCalendar(t *testing.T) {
+	configured := getTestAcademicCalendar(t)
+
+	t.Run("redis_calendar", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.ExpectGet(academicCalendarRedisKey).SetVal(`{
+			"semesters": [
+				{"start": "2025-09-01", "end": "2025-12-26"},
+				{"start": "2026-02-09", "end": "2026-06-05"}
+			]
+		}`)
+
+		policy := newTestCalendarSemesterSwitchPolicy(redisClient, configured, configured, nil)
+		policy.updateCalendar()
+
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+		assert.Equal(t, getTestAcademicDate("2026-02-09"), policy.calendar.Load().Semesters[1].Start.Time())
+		assert.Same(t, configured, policy.configured)
+	})
+
+	t.Run("redis_calendar_removed", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.ExpectGet(academicCalendarRedisKey).RedisNil()
+
+		policy := newTestCalendarSemesterSwitchPolicy(redisClient, configured, &AcademicCalendar{}, nil)
+		policy.updateCalendar()
+
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+		assert.Same(t, configured, policy.calendar.Load())
+	})
+
+	t.Run("invalid_redis_calendar", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.ExpectGet(academicCalendarRedisKey).SetVal(`{"semesters": []}`)
+
+		previous := &AcademicCalendar{}
+		policy := newTestCalendarSemesterSwitchPolicy(redisClient, configured, previous, nil)
+		policy.updateCalendar()
+
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+		assert.Same(t, previous, policy.calendar.Load())
+	})
+
+	t.Run("redis_error", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.ExpectGet(academicCalendarRedisKey).SetErr(assert.AnError)
+
+		policy := newTestCalendarSemesterSwitchPolicy(redisClient, nil, configured, nil)
+		policy.updateCalendar()
+
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+		assert.Same(t, configured, policy.calendar.Load())
+	})
+}
+
+func TestAcademicCalendarFirstSemesterActualUntil(t *testing.T) {
+	t.Run("holidays_after_exam_session", func(t *testing.T) {
+		calendar := getTestAcademicCalendar(t)
+
+		assert.Equal(t, getTestAcademicDate("2026-02-02"), calendar.firstSemesterActualUntil())
+	})
+
+	t.Run("holidays_with_gap", func(t *testing.T) {
+		calendar := getTestAcademicCalendar(t)
+		calendar.Holidays = []CalendarPeriod{
+			{Start: CalendarDate(getTestAcademicDate("2026-01-20")), End: CalendarDate(getTestAcademicDate("2026-01-25"))},
+		}
+
+		assert.Equal(t, getTestAcademicDate("2026-01-17"), calendar.firstSemesterActualUntil())
+	})
+
+	t.Run("without_exam_session", func(t *testing.T) {
+		calendar := getTestAcademicCalendar(t)
+		calendar.Semesters[0].ExamSession = CalendarPeriod{}
+		calendar.Holidays = nil
+
+		assert.Equal(t, getTestAcademicDate("2025-12-20"), calendar.firstSemesterActualUntil())
+	})
+}
+
+func TestParseAcademicCalendar(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		calendar, err := parseAcademicCalendar([]byte(testAcademicCalendarJSON), defaultAcademicLocation)
+
+		assert.NoError(t, err)
+		assert.Len(t, calendar.Semesters, 2)
+		assert.Equal(t, getTestAcademicDate("2025-09-01"), calendar.Semesters[0].Start.Time())
+		assert.Equal(t, getTestAcademicDate("2026-06-26"), calendar.Semesters[1].ExamSession.End.Time())
+		assert.Equal(t, getTestAcademicDate("2026-01-17"), calendar.Holidays[0].Start.Time())
+		assert.Equal(t, getTestAcademicDate("2026-01-26"), calendar.Holidays[1].Start.Time())
+	})
+
+	t.Run("location", func(t *testing.T) {
+		location, _ := time.LoadLocation("Asia/Tokyo")
+		calendar, err := parseAcademicCalendar([]byte(testAcademicCalendarJSON), location)
+
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2025, time.September, 1, 0, 0, 0, 0, location), calendar.Semesters[0].Start.Time())
+		assert.Equal(t, time.Date(2026, time.June, 26, 0, 0, 0, 0, location), calendar.Semesters[1].ExamSession.End.Time())
+	})
+
+	testCases := map[string]string{
+		`{`: "unexpected end of JSON input",
+		`{"semesters": [{"start": "2025-09-01", "end": "2025-12-19"}]}`:                                               "two semesters expected",
+		`{"semesters": [{"start": "01.09.2025", "end": "2025-12-19"}, {}]}`:                                           `parsing time "01.09.2025" as "2006-01-02": cannot parse "01.09.2025" as "2006"`,
+		`{"semesters": [{"start": "2025-09-01", "end": "2025-12-19"}, {"start": "2026-02-02"}]}`:                      "semester 2: start and end expected",
+		`{"semesters": [{"start": "2025-09-01", "end": "2025-08-19"}, {"start": "2026-02-02", "end": "2026-05-29"}]}`: "period 2025-09-01 - 2025-08-19 ends before start",
+		`{"semesters": [{"start": "2025-09-01", "end": "2025-12-19"}, {"start": "2026-02-02", "end": "2026-05-29"}],
+			"holidays": [{"start": "2026-01-10", "end": "2026-01-01"}]}`: "period 2026-01-10 - 2026-01-01 ends before start",
+		`{"semesters": [{"start": "2026-02-02", "end": "2026-05-29"}, {"start": "2025-09-01", "end": "2025-12-19"}]}`: "second semester should start after the first one",
+	}
+
+	for calendarJSON, expectedError := range testCases {
+		calendar, err := parseAcademicCalendar([]byte(calendarJSON), defaultAcademicLocation)
+
+		assert.EqualError(t, err, expectedError, calendarJSON)
+		assert.Nil(t, calendar)
+	}
+}
+
+func TestLoadAcademicCalendar(t *testing.T) {
+	t.Run("file", func(t *testing.T) {
+		filename := "TestLoadAcademicCalendar.json"
+		err := os.WriteFile(filename, []byte(testAcademicCalendarJSON), 0644)
+		assert.NoError(t, err)
+		defer os.Remove(filename)
+
+		calendar, err := loadAcademicCalendar(filename, defaultAcademicLocation)
+
+		assert.NoError(t, err)
+		assert.Equal(t, getTestAcademicCalendar(t), calendar)
+	})
+
+	t.Run("not_configured", func(t *testing.T) {
+		calendar, err := loadAcademicCalendar("", defaultAcademicLocation)
+
+		assert.NoError(t, err)
+		assert.Nil(t, calendar)
+	})
+
+	t.Run("missing_file", func(t *testing.T) {
+		calendar, err := loadAcademicCalendar("missing-calendar.json", defaultAcademicLocation)
+
+		assert.EqualError(t, err, "Wrong ACADEMIC_CALENDAR_FILE missing-calendar.json: open missing-calendar.json: no such file or directory")
+		assert.Nil(t, calendar)
+	})
+}
+
+func TestNewSemesterSwitchPolicy(t *testing.T) {
+	assert.Equal(
+		t, IntervalSemesterSwitchPolicy{maxUpdatedInterval: MaxSemesterUpdatedInterval},
+		NewSemesterSwitchPolicy(SemesterSwitchPolicyInterval, nil, nil, defaultAcademicLocation, context.Background()),
+	)
+	assert.Equal(
+		t, ThresholdSemesterSwitchPolicy{threshold: DisciplineAmountThresholdForSemesterSwitch},
+		NewSemesterSwitchPolicy(SemesterSwitchPolicyThreshold, nil, nil, defaultAcademicLocation, context.Background()),
+	)
+
+	t.Run("calendar", func(t *testing.T) {
+		configured := getTestAcademicCalendar(t)
+
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.ExpectGet(academicCalendarRedisKey).RedisNil()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		policy := NewSemesterSwitchPolicy(SemesterSwitchPolicyCalendar, configured, redisClient, defaultAcademicLocation, ctx)
+
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+		assert.IsType(t, &CalendarSemesterSwitchPolicy{}, policy)
+		assert.Same(t, configured, policy.(*CalendarSemesterSwitchPolicy).calendar.Load())
+	})
+
+	t.Run("calendar_without_redis", func(t *testing.T) {
+		policy := NewSemesterSwitchPolicy(SemesterSwitchPolicyCalendar, nil, nil, defaultAcademicLocation, context.Background())
+
+		assert.Equal(t, &CalendarSemesterSwitchPolicy{
+			location: defaultAcademicLocation,
+			fallback: IntervalSemesterSwitchPolicy{maxUpdatedInterval: MaxSemesterUpdatedInterval},
+		}, policy)
+	})
+}
+
+func TestDisciplineSemestersWithout(t *testing.T) {
+	assert.Equal(t, DisciplineSemesters{
+		{Semester: 1, DisciplineId: 100},
+		{Semester: 1, DisciplineId: 110},
+	}, testFirstSemesterDisciplines.without(testSecondSemesterDisciplines))
+	assert.Equal(t, DisciplineSemesters{}, testSecondSemesterDisciplines.without(testSecondSemesterDisciplines))
+}
+
+func TestDisciplineSemestersWithin(t *testing.T) {
+	assert.Equal(t, DisciplineSemesters{
+		{Semester: 2, DisciplineId: 120},
+	}, testSecondSemesterDisciplines.within(testFirstSemesterDisciplines))
+	assert.Equal(t, DisciplineSemesters{}, testSecondSemesterDisciplines.within(DisciplineSemesters{}))
+}
+
+func TestSelectActualDisciplines(t *testing.T) {
+	rules := AcademicRules{semesterSwitch: ThresholdSemesterSwitchPolicy{threshold: 10}}
+
+	getLastUpdatedAt := func(disciplineId int) (time.Time, error) {
+		return time.Now(), nil
+	}
+
+	disciplines, err := rules.selectActualDisciplines(testFirstSemesterDisciplines, DisciplineSemesters{}, getLastUpdatedAt)
+	assert.NoError(t, err)
+	assert.Equal(t, []DisciplineSemester(testFirstSemesterDisciplines), disciplines)
+
+	disciplines, err = rules.selectActualDisciplines(testFirstSemesterDisciplines, testSecondSemesterDisciplines, getLastUpdatedAt)
+	assert.NoError(t, err)
+	assert.Equal(t, []DisciplineSemester{
+		{Semester: 1, DisciplineId: 100},
+		{Semester: 1, DisciplineId: 110},
+		{Semester: 2, DisciplineId: 120},
+		{Semester: 2, DisciplineId: 130},
+	}, disciplines)
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// SnapshotVersion is increased on every incompatible change of the snapshot file format
+const SnapshotVersion = 1
+
+const (
+	SnapshotTypeString = "string"
+	SnapshotTypeSet    = "set"
+	SnapshotTypeHash   = "hash"
+	SnapshotTypeZset   = "zset"
+)
+
+var ErrUnsupportedSnapshot = errors.New("unsupported snapshot")
+
+// SnapshotHeader is the first line of snapshot file, each next line is SnapshotEntry
+type SnapshotHeader struct {
+	Version   int       `json:"version"`
+	Year      int       `json:"year"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// SnapshotEntry - one Redis key, only the field matching Type is filled
+type SnapshotEntry struct {
+	Key    string            `json:"key"`
+	Type   string            `json:"type"`
+	String string            `json:"string,omitempty"`
+	Set    []string          `json:"set,omitempty"`
+	Hash   map[string]string `json:"hash,omitempty"`
+	Zset   []SnapshotMember  `json:"zset,omitempty"`
+}
+
+type SnapshotMember struct {
+	Member string  `json:"member"`
+	Score  float64 `json:"score"`
+}
+
+// SnapshotKeyGroup - keys of one kind read by the service for the year
+type SnapshotKeyGroup struct {
+	Pattern string
+	Type    string
+}
+
+func getSnapshotKeyGroups(year int) []SnapshotKeyGroup {
+	yearPrefix := strconv.Itoa(year) + ":"
+
+	return []SnapshotKeyGroup{
+		{Pattern: yearPrefix + "discipline:*", Type: SnapshotTypeHash},
+		{Pattern: yearPrefix + "discipline_semester_updated_at:*", Type: SnapshotTypeString},
+		{Pattern: yearPrefix + "*:student_disciplines:*", Type: SnapshotTypeSet},
+		{Pattern: yearPrefix + "student:*", Type: SnapshotTypeHash},
+		{Pattern: yearPrefix + "group:*", Type: SnapshotTypeSet},
+		{Pattern: yearPrefix + "student_group:*", Type: SnapshotTypeString},
+		{Pattern: yearPrefix + "*:lessons:*", Type: SnapshotTypeHash},
+		{Pattern: yearPrefix + "*:deleted-lessons:*", Type: SnapshotTypeString},
+		{Pattern: yearPrefix + "*:deleted-lessons-index:*", Type: SnapshotTypeHash},
+		{Pattern: yearPrefix + "*:scores:*", Type: SnapshotTypeHash},
+		{Pattern: yearPrefix + "*:totals:*", Type: SnapshotTypeZset},
+	}
+}
+
+func (header SnapshotHeader) validate() error {
+	if header.Version != SnapshotVersion {
+		return fmt.Errorf("%w: version %d, expected %d", ErrUnsupportedSnapshot, header.Version, SnapshotVersion)
+	}
+
+	if header.Year == 0 {
+		return fmt.Errorf("%w: year is missing in header", ErrUnsupportedSnapshot)
+	}
+
+	return nil
+}
+
+func (entry SnapshotEntry) validate() error {
+	if entry.Key == "" {
+		return fmt.Errorf("%w: entry without key", ErrUnsupportedSnapshot)
+	}
+
+	valuesCount := 0
+	switch entry.Type {
+	case SnapshotTypeString:
+		return nil
+	case SnapshotTypeSet:
+		valuesCount = len(entry.Set)
+	case SnapshotTypeHash:
+		valuesCount = len(entry.Hash)
+	case SnapshotTypeZset:
+		valuesCount = len(entry.Zset)
+	default:
+		return fmt.Errorf("%w: type %q of key %s", ErrUnsupportedSnapshot, entry.Type, entry.Key)
+	}
+
+	if valuesCount == 0 {
+		return fmt.Errorf("%w: empty %s of key %s", ErrUnsupportedSnapshot, entry.Type, entry.Key)
+	}
+
+	return nil
+}
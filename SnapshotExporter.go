@@ -0,0 +1,116 @@
+package main
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/redis/go-redis/v9"
+	"io"
+	"sort"
+	"time"
+)
+
+const snapshotBatchSize = 500
+
+// SnapshotExporter writes every key of the year read by the service into gzip compressed NDJSON
+type SnapshotExporter struct {
+	redis *redis.Client
+	year  int
+}
+
+func (exporter *SnapshotExporter) export(ctx context.Context, writer io.Writer, createdAt time.Time) (int, error) {
+	gzipWriter := gzip.NewWriter(writer)
+	encoder := json.NewEncoder(gzipWriter)
+
+	err := encoder.Encode(SnapshotHeader{
+		Version:   SnapshotVersion,
+		Year:      exporter.year,
+		CreatedAt: createdAt.UTC(),
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, group := range getSnapshotKeyGroups(exporter.year) {
+		keys, err := scanSortedKeys(ctx, exporter.redis, group.Pattern)
+		if err != nil {
+			return count, err
+		}
+
+		for start := 0; start < len(keys); start += snapshotBatchSize {
+			entries, err := exporter.loadEntries(ctx, group.Type, keys[start:min(start+snapshotBatchSize, len(keys))])
+			if err != nil {
+				return count, err
+			}
+
+			for _, entry := range entries {
+				if err = encoder.Encode(entry); err != nil {
+					return count, err
+				}
+				count++
+			}
+		}
+	}
+
+	return count, gzipWriter.Close()
+}
+
+// loadEntries reads values of keys with the same type in one pipeline, keys removed during export are skipped
+func (exporter *SnapshotExporter) loadEntries(ctx context.Context, keyType string, keys []string) ([]SnapshotEntry, error) {
+	commands := make([]redis.Cmder, len(keys))
+	_, _ = exporter.redis.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for index, key := range keys {
+			switch keyType {
+			case SnapshotTypeString:
+				commands[index] = pipe.Get(ctx, key)
+			case SnapshotTypeSet:
+				commands[index] = pipe.SMembers(ctx, key)
+			case SnapshotTypeHash:
+				commands[index] = pipe.HGetAll(ctx, key)
+			case SnapshotTypeZset:
+				commands[index] = pipe.ZRangeWithScores(ctx, key, 0, -1)
+			}
+		}
+		return nil
+	})
+
+	entries := make([]SnapshotEntry, 0, len(keys))
+	for index, key := range keys {
+		if errors.Is(commands[index].Err(), redis.Nil) {
+			continue
+		} else if commands[index].Err() != nil {
+			return nil, commands[index].Err()
+		}
+
+		entry := SnapshotEntry{
+			Key:  key,
+			Type: keyType,
+		}
+
+		switch command := commands[index].(type) {
+		case *redis.StringCmd:
+			entry.String = command.Val()
+		case *redis.StringSliceCmd:
+			entry.Set = command.Val()
+			sort.Strings(entry.Set)
+		case *redis.MapStringStringCmd:
+			entry.Hash = command.Val()
+		case *redis.ZSliceCmd:
+			entry.Zset = make([]SnapshotMember, 0, len(command.Val()))
+			for _, member := range command.Val() {
+				memberString, _ := member.Member.(string)
+				entry.Zset = append(entry.Zset, SnapshotMember{Member: memberString, Score: member.Score})
+			}
+		}
+
+		if entry.Type != SnapshotTypeString && len(entry.Set) == 0 && len(entry.Hash) == 0 && len(entry.Zset) == 0 {
+			continue
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"github.com/go-redis/redismock/v9"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestSnapshotExporter(t *testing.T) {
+	createdAt := time.Date(2026, time.Month(10), 18, 12, 0, 0, 0, time.UTC)
+
+	t.Run("success", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.MatchExpectationsInOrder(true)
+
+		redisMock.ExpectScan(0, "2026:discipline:*", 1000).SetVal([]string{"2026:discipline:199"}, 0)
+		redisMock.ExpectHGetAll("2026:discipline:199").SetVal(map[string]string{"name": "Капітал!"})
+
+		redisMock.ExpectScan(0, "2026:discipline_semester_updated_at:*", 1000).SetVal([]string{
+			"2026:discipline_semester_updated_at:200",
+			"2026:discipline_semester_updated_at:199",
+		}, 0)
+		redisMock.ExpectGet("2026:discipline_semester_updated_at:199").SetVal("11676152800")
+		redisMock.ExpectGet("2026:discipline_semester_updated_at:200").RedisNil()
+
+		redisMock.ExpectScan(0, "2026:*:student_disciplines:*", 1000).SetVal([]string{"2026:1:student_disciplines:1200"}, 0)
+		redisMock.ExpectSMembers("2026:1:student_disciplines:1200").SetVal([]string{"200", "199"})
+
+		redisMock.ExpectScan(0, "2026:student:*", 1000).SetVal([]string{}, 0)
+
+		redisMock.ExpectScan(0, "2026:group:*", 1000).SetVal([]string{"2026:group:17"}, 0)
+		redisMock.ExpectSMembers("2026:group:17").SetVal([]string{"1300", "1200"})
+
+		redisMock.ExpectScan(0, "2026:student_group:*", 1000).SetVal([]string{"2026:student_group:1200"}, 0)
+		redisMock.ExpectGet("2026:student_group:1200").SetVal("17")
+
+		redisMock.ExpectScan(0, "2026:*:lessons:*", 1000).SetVal([]string{"2026:1:lessons:199"}, 0)
+		redisMock.ExpectHGetAll("2026:1:lessons:199").SetVal(map[string]string{"245": "2302121"})
+
+		redisMock.ExpectScan(0, "2026:*:deleted-lessons:*", 1000).SetVal([]string{}, 0)
+		redisMock.ExpectScan(0, "2026:*:deleted-lessons-index:*", 1000).SetVal([]string{}, 0)
+
+		redisMock.ExpectScan(0, "2026:*:scores:*", 1000).SetVal([]string{"2026:1:scores:1200:199"}, 0)
+		redisMock.ExpectHGetAll("2026:1:scores:1200:199").SetVal(map[string]string{})
+
+		redisMock.ExpectScan(0, "2026:*:totals:*", 1000).SetVal([]string{"2026:1:totals:199"}, 0)
+		redisMock.ExpectZRangeWithScores("2026:1:totals:199", 0, -1).SetVal([]redis.Z{
+			{Member: "1200", Score: 7.5},
+		})
+
+		exporter := SnapshotExporter{
+			redis: redisClient,
+			year:  2026,
+		}
+
+		buffer := &bytes.Buffer{}
+		count, err := exporter.export(context.Background(), buffer, createdAt)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 7, count)
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+
+		gzipReader, err := gzip.NewReader(buffer)
+		assert.NoError(t, err)
+		content, _ := io.ReadAll(gzipReader)
+
+		assert.Equal(t, ""+
+			`{"version":1,"year":2026,"createdAt":"2026-10-18T12:00:00Z"}`+"\n"+
+			`{"key":"2026:discipline:199","type":"hash","hash":{"name":"Капітал!"}}`+"\n"+
+			`{"key":"2026:discipline_semester_updated_at:199","type":"string","string":"11676152800"}`+"\n"+
+			`{"key":"2026:1:student_disciplines:1200","type":"set","set":["199","200"]}`+"\n"+
+			`{"key":"2026:group:17","type":"set","set":["1200","1300"]}`+"\n"+
+			`{"key":"2026:student_group:1200","type":"string","string":"17"}`+"\n"+
+			`{"key":"2026:1:lessons:199","type":"hash","hash":{"245":"2302121"}}`+"\n"+
+			`{"key":"2026:1:totals:199","type":"zset","zset":[{"member":"1200","score":7.5}]}`+"\n",
+			string(content),
+		)
+	})
+
+	t.Run("scan_error", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.ExpectScan(0, "2026:discipline:*", 1000).SetErr(assert.AnError)
+
+		exporter := SnapshotExporter{
+			redis: redisClient,
+			year:  2026,
+		}
+
+		_, err := exporter.export(context.Background(), &bytes.Buffer{}, createdAt)
+
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+
+	t.Run("read_error", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.ExpectScan(0, "2026:discipline:*", 1000).SetVal([]string{"2026:discipline:199"}, 0)
+		redisMock.ExpectHGetAll("2026:discipline:199").SetErr(assert.AnError)
+
+		exporter := SnapshotExporter{
+			redis: redisClient,
+			year:  2026,
+		}
+
+		_, err := exporter.export(context.Background(), &bytes.Buffer{}, createdAt)
+
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"io"
+)
+
+// SnapshotImporter restores keys from snapshot file, existing keys with the same name are replaced.
+// Prefix is prepended to every key, so snapshot could be restored next to live data.
+type SnapshotImporter struct {
+	redis  *redis.Client
+	prefix string
+}
+
+func (importer *SnapshotImporter) importSnapshot(ctx context.Context, reader io.Reader) (SnapshotHeader, int, error) {
+	header := SnapshotHeader{}
+
+	gzipReader, err := gzip.NewReader(reader)
+	if err != nil {
+		return header, 0, fmt.Errorf("%w: %w", ErrUnsupportedSnapshot, err)
+	}
+
+	decoder := json.NewDecoder(bufio.NewReader(gzipReader))
+	if err = decoder.Decode(&header); err != nil {
+		return header, 0, fmt.Errorf("%w: %w", ErrUnsupportedSnapshot, err)
+	}
+
+	if err = header.validate(); err != nil {
+		return header, 0, err
+	}
+
+	count := 0
+	entries := make([]SnapshotEntry, 0, snapshotBatchSize)
+	for {
+		entry := SnapshotEntry{}
+		err = decoder.Decode(&entry)
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return header, count, fmt.Errorf("%w: %w", ErrUnsupportedSnapshot, err)
+		} else if err = entry.validate(); err != nil {
+			return header, count, err
+		}
+
+		entries = append(entries, entry)
+		if len(entries) == snapshotBatchSize {
+			if err = importer.writeEntries(ctx, entries); err != nil {
+				return header, count, err
+			}
+			count += len(entries)
+			entries = entries[:0]
+		}
+	}
+
+	if err = importer.writeEntries(ctx, entries); err != nil {
+		return header, count, err
+	}
+
+	return header, count + len(entries), nil
+}
+
+func (importer *SnapshotImporter) writeEntries(ctx context.Context, entries []SnapshotEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	_, err := importer.redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, entry := range entries {
+			key := importer.prefix + entry.Key
+			pipe.Del(ctx, key)
+
+			switch entry.Type {
+			case SnapshotTypeString:
+				pipe.Set(ctx, key, entry.String, 0)
+
+			case SnapshotTypeSet:
+				members := make([]interface{}, len(entry.Set))
+				for index, member := range entry.Set {
+					members[index] = member
+				}
+				pipe.SAdd(ctx, key, members...)
+
+			case SnapshotTypeHash:
+				values := make([]interface{}, 0, len(entry.Hash)*2)
+				for _, field := range sortedMapKeys(entry.Hash) {
+					values = append(values, field, entry.Hash[field])
+				}
+				pipe.HSet(ctx, key, values...)
+
+			case SnapshotTypeZset:
+				members := make([]redis.Z, len(entry.Zset))
+				for index, member := range entry.Zset {
+					members[index] = redis.Z{Member: member.Member, Score: member.Score}
+				}
+				pipe.ZAdd(ctx, key, members...)
+			}
+		}
+		return nil
+	})
+
+	return err
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"github.com/go-redis/redismock/v9"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func gzipSnapshot(lines string) *bytes.Buffer {
+	buffer := &bytes.Buffer{}
+	gzipWriter := gzip.NewWriter(buffer)
+	_, _ = gzipWriter.Write([]byte(lines))
+	_ = gzipWriter.Close()
+
+	return buffer
+}
+
+func TestSnapshotImporter(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		snapshot := gzipSnapshot(`{"version":1,"year":2026,"createdAt":"2026-10-18T12:00:00Z"}` + "\n" +
+			`{"key":"2026:discipline:199","type":"hash","hash":{"name":"Капітал!","code":"K1"}}` + "\n" +
+			`{"key":"2026:discipline_semester_updated_at:199","type":"string","string":"11676152800"}` + "\n" +
+			`{"key":"2026:1:student_disciplines:1200","type":"set","set":["199","200"]}` + "\n" +
+			`{"key":"2026:1:totals:199","type":"zset","zset":[{"member":"1200","score":7.5}]}` + "\n",
+		)
+
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.MatchExpectationsInOrder(true)
+
+		redisMock.ExpectTxPipeline()
+		redisMock.ExpectDel("restored:2026:discipline:199").SetVal(0)
+		redisMock.ExpectHSet("restored:2026:discipline:199", "code", "K1", "name", "Капітал!").SetVal(2)
+		redisMock.ExpectDel("restored:2026:discipline_semester_updated_at:199").SetVal(0)
+		redisMock.ExpectSet("restored:2026:discipline_semester_updated_at:199", "11676152800", 0).SetVal("OK")
+		redisMock.ExpectDel("restored:2026:1:student_disciplines:1200").SetVal(0)
+		redisMock.ExpectSAdd("restored:2026:1:student_disciplines:1200", "199", "200").SetVal(2)
+		redisMock.ExpectDel("restored:2026:1:totals:199").SetVal(0)
+		redisMock.ExpectZAdd("restored:2026:1:totals:199", redis.Z{Member: "1200", Score: 7.5}).SetVal(1)
+		redisMock.ExpectTxPipelineExec()
+
+		importer := SnapshotImporter{
+			redis:  redisClient,
+			prefix: "restored:",
+		}
+
+		header, count, err := importer.importSnapshot(context.Background(), snapshot)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2026, header.Year)
+		assert.Equal(t, 4, count)
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+	})
+
+	t.Run("not_gzip", func(t *testing.T) {
+		redisClient, _ := redismock.NewClientMock()
+		importer := SnapshotImporter{redis: redisClient}
+
+		_, _, err := importer.importSnapshot(context.Background(), bytes.NewBufferString(`{"version":1}`))
+
+		assert.ErrorIs(t, err, ErrUnsupportedSnapshot)
+	})
+
+	t.Run("unsupported_version", func(t *testing.T) {
+		redisClient, _ := redismock.NewClientMock()
+		importer := SnapshotImporter{redis: redisClient}
+
+		_, _, err := importer.importSnapshot(context.Background(), gzipSnapshot(`{"version":2,"year":2026}`+"\n"))
+
+		assert.EqualError(t, err, "unsupported snapshot: version 2, expected 1")
+	})
+
+	t.Run("malformed_entry", func(t *testing.T) {
+		redisClient, _ := redismock.NewClientMock()
+		importer := SnapshotImporter{redis: redisClient}
+
+		_, count, err := importer.importSnapshot(context.Background(), gzipSnapshot(
+			`{"version":1,"year":2026}`+"\n"+`{"key":"2026:1:totals:199","type":"list"}`+"\n",
+		))
+
+		assert.Equal(t, 0, count)
+		assert.ErrorIs(t, err, ErrUnsupportedSnapshot)
+	})
+
+	t.Run("write_error", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.ExpectTxPipeline()
+		redisMock.ExpectDel("2026:discipline_semester_updated_at:199").SetErr(assert.AnError)
+
+		importer := SnapshotImporter{redis: redisClient}
+
+		_, _, err := importer.importSnapshot(context.Background(), gzipSnapshot(
+			`{"version":1,"year":2026}`+"\n"+
+				`{"key":"2026:discipline_semester_updated_at:199","type":"string","string":"11676152800"}`+"\n",
+		))
+
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSnapshotHeaderValidate(t *testing.T) {
+	assert.NoError(t, SnapshotHeader{Version: SnapshotVersion, Year: 2026}.validate())
+
+	err := SnapshotHeader{Version: SnapshotVersion + 1, Year: 2026}.validate()
+	assert.ErrorIs(t, err, ErrUnsupportedSnapshot)
+	assert.EqualError(t, err, "unsupported snapshot: version 2, expected 1")
+
+	assert.ErrorIs(t, SnapshotHeader{Version: SnapshotVersion}.validate(), ErrUnsupportedSnapshot)
+}
+
+func TestSnapshotEntryValidate(t *testing.T) {
+	assert.NoError(t, SnapshotEntry{Key: "2026:discipline_semester_updated_at:199", Type: SnapshotTypeString}.validate())
+	assert.NoError(t, SnapshotEntry{Key: "2026:1:student_disciplines:1200", Type: SnapshotTypeSet, Set: []string{"199"}}.validate())
+	assert.NoError(t, SnapshotEntry{Key: "2026:discipline:199", Type: SnapshotTypeHash, Hash: map[string]string{"name": "Капітал!"}}.validate())
+	assert.NoError(t, SnapshotEntry{Key: "2026:1:totals:199", Type: SnapshotTypeZset, Zset: []SnapshotMember{{"1200", 7.5}}}.validate())
+
+	assert.EqualError(t, SnapshotEntry{Type: SnapshotTypeString}.validate(), "unsupported snapshot: entry without key")
+	assert.EqualError(
+		t, SnapshotEntry{Key: "2026:1:totals:199", Type: "list"}.validate(),
+		`unsupported snapshot: type "list" of key 2026:1:totals:199`,
+	)
+	assert.EqualError(
+		t, SnapshotEntry{Key: "2026:1:totals:199", Type: SnapshotTypeZset}.validate(),
+		"unsupported snapshot: empty zset of key 2026:1:totals:199",
+	)
+}
@@ -14,9 +14,17 @@ import (
 )
 
 type StorageInterface interface {
-	getDisciplineScoreResultsByStudentId(studentId int) (scoreApi.DisciplineScoreResults, error)
-	getDisciplineScoreResultByStudentId(studentId int, disciplineId int) (scoreApi.DisciplineScoreResult, error)
-	getDisciplineScore(studentId int, disciplineId int, lessonId int) (scoreApi.DisciplineScore, error)
+	getDisciplineScoreResultsByStudentId(studentId int) (DisciplineScoreResults, error)
+	getDisciplineScoreResultByStudentId(studentId int, disciplineId int) (DisciplineScoreResult, error)
+	getDisciplineScore(studentId int, disciplineId int, lessonId int) (DisciplineScore, error)
+	getDisciplineDeletedLessons(studentId int, disciplineId int) (DisciplineDeletedLessons, error)
+	getStudentAttendance(studentId int) (StudentAttendance, error)
+	getDisciplineTimeline(studentId int, disciplineId int) (DisciplineTimeline, error)
+	getStudentTranscript(studentId int) (Transcript, error)
+	getStudentLessons(studentId int) ([]DisciplineLessons, error)
+	getStudentProfile(studentId int) (StudentProfile, error)
+	studentExists(studentId int) (bool, error)
+	getAnomalies() AnomalyReport
 }
 
 type Storage struct {
@@ -24,21 +32,82 @@ type Storage struct {
 	year              int
 	lessonTypes       map[int]scoreApi.LessonType
 	scoreRatingLoader ScoreRatingLoaderInterface
+	attendanceLoader  AttendanceLoaderInterface
+	groupRatingLoader *GroupRatingLoader
+	codec             RedisCodec
+	anomalies         *AnomalyCounter
+	deletedLessons    *DeletedLessonsCache
+	rules             AcademicRules
 }
 
 const IsAbsentScoreValue = float32(-999999)
-const DisciplineAmountThresholdForSemesterSwitch = 2
+const FirstAcademicYear = 2022
 
-const MaxSemesterUpdatedInterval = time.Hour * 24 * 7 * 6 // 6 weeks
-// 6 weeks = 2 weeks fir winter holidays + 3 weeks for exams + 2 weeks for next semester lectures
+func (storage *Storage) getDisciplineScoreResultsByStudentId(studentId int) (DisciplineScoreResults, error) {
+	disciplines, err := storage.getActualStudentDisciplines(studentId)
+	if err != nil {
+		return nil, err
+	}
+
+	disciplineScoreResults := make(DisciplineScoreResults, len(disciplines))
+
+	wg := sync.WaitGroup{}
+	wg.Add(len(disciplines))
+
+	for _index := range disciplines {
+		go func(index int) {
+			disciplineScoreResults[index] = storage.makeDisciplineScoreResult(disciplines[index], studentId)
+			wg.Done()
+		}(_index)
+	}
+
+	wg.Wait()
+
+	return disciplineScoreResults, nil
+}
+
+func (storage *Storage) getStudentTranscript(studentId int) (Transcript, error) {
+	disciplines, err := storage.getActualStudentDisciplines(studentId)
+	if err != nil {
+		return Transcript{}, err
+	}
+
+	transcript := Transcript{
+		StudentId:   studentId,
+		Year:        storage.year,
+		Semester:    1,
+		Disciplines: make(DisciplineScoreResults, len(disciplines)),
+	}
+
+	wg := sync.WaitGroup{}
+	wg.Add(len(disciplines))
+
+	for _index := range disciplines {
+		if disciplines[_index].Semester > transcript.Semester {
+			transcript.Semester = disciplines[_index].Semester
+		}
+
+		go func(index int) {
+			transcript.Disciplines[index] = storage.makeDisciplineScoreResult(disciplines[index], studentId)
+			transcript.Disciplines[index].Scores = storage.getScores(
+				disciplines[index].Semester, disciplines[index].DisciplineId, studentId,
+			)
+			wg.Done()
+		}(_index)
+	}
 
-func (storage *Storage) getDisciplineScoreResultsByStudentId(studentId int) (scoreApi.DisciplineScoreResults, error) {
+	wg.Wait()
+
+	return transcript, nil
+}
+
+func (storage *Storage) getStudentLessons(studentId int) ([]DisciplineLessons, error) {
 	disciplines, err := storage.getActualStudentDisciplines(studentId)
 	if err != nil {
 		return nil, err
 	}
 
-	disciplineScoreResults := make([]scoreApi.DisciplineScoreResult, len(disciplines))
+	disciplinesLessons := make([]DisciplineLessons, len(disciplines))
 
 	wg := sync.WaitGroup{}
 	wg.Add(len(disciplines))
@@ -46,12 +115,14 @@ func (storage *Storage) getDisciplineScoreResultsByStudentId(studentId int) (sco
 	for _index := range disciplines {
 		go func(index int) {
 			disciplineId := disciplines[index].DisciplineId
-			disciplineScoreResults[index] = scoreApi.DisciplineScoreResult{
+			disciplinesLessons[index] = DisciplineLessons{
+				Year:     storage.year,
+				Semester: disciplines[index].Semester,
 				Discipline: scoreApi.Discipline{
 					Id:   disciplineId,
 					Name: storage.getDisciplineName(disciplineId),
 				},
-				ScoreRating: storage.scoreRatingLoader.load(storage.year, disciplines[index].Semester, disciplineId, studentId),
+				Lessons: storage.getLessonsWithScores(disciplines[index].Semester, disciplineId, studentId),
 			}
 			wg.Done()
 		}(_index)
@@ -59,42 +130,119 @@ func (storage *Storage) getDisciplineScoreResultsByStudentId(studentId int) (sco
 
 	wg.Wait()
 
-	return disciplineScoreResults, nil
+	return disciplinesLessons, nil
 }
 
-func (storage *Storage) getDisciplineScoreResultByStudentId(studentId int, disciplineId int) (scoreApi.DisciplineScoreResult, error) {
+// getDisciplineWithLessons returns every lesson of discipline without scores, zero value for unknown discipline
+func (storage *Storage) getDisciplineWithLessons(disciplineId int) (DisciplineLessons, error) {
+	semester, err := storage.getSemesterByDisciplineId(disciplineId)
+	if err != nil || semester == 0 {
+		return DisciplineLessons{}, err
+	}
+
+	return DisciplineLessons{
+		Year:     storage.year,
+		Semester: semester,
+		Discipline: scoreApi.Discipline{
+			Id:   disciplineId,
+			Name: storage.getDisciplineName(disciplineId),
+		},
+		Lessons: makeLessonsWithScores(nil, storage.getDisciplineLessons(semester, disciplineId)),
+	}, nil
+}
+
+// getLessonsWithScores returns every lesson of discipline, lessons without student score have empty score values
+func (storage *Storage) getLessonsWithScores(semester int, disciplineId int, studentId int) []Score {
+	studentDisciplineScoresKey := fmt.Sprintf("%d:%d:scores:%d:%d", storage.year, semester, studentId, disciplineId)
+
+	lessons := storage.getDisciplineLessons(semester, disciplineId)
+	if len(lessons) == 0 {
+		return make([]Score, 0)
+	}
+
+	rawScores := storage.redis.HGetAll(context.Background(), studentDisciplineScoresKey).Val()
+
+	return makeLessonsWithScores(storage.decodeScoreEntries(studentDisciplineScoresKey, rawScores), lessons)
+}
+
+// makeLessonsWithScores returns every lesson with student scores, scores of deleted lessons are skipped
+func makeLessonsWithScores(scoreEntries []ScoreEntry, lessons map[int]scoreApi.Lesson) []Score {
+	scoredLessonIds := make(map[int]bool, len(scoreEntries))
+	scores := make([]Score, 0, len(lessons))
+	for _, score := range makeScores(scoreEntries, lessons, nil) {
+		if _, exists := lessons[score.Lesson.Id]; exists {
+			scoredLessonIds[score.Lesson.Id] = true
+			scores = append(scores, score)
+		}
+	}
+
+	for lessonId, lesson := range lessons {
+		if !scoredLessonIds[lessonId] {
+			scores = append(scores, Score{
+				Score: scoreApi.Score{
+					Lesson: lesson,
+				},
+			})
+		}
+	}
+
+	sortScores(scores)
+
+	return scores
+}
+
+// makeDisciplineScoreResult loads everything except scores
+func (storage *Storage) makeDisciplineScoreResult(discipline DisciplineSemester, studentId int) DisciplineScoreResult {
+	return DisciplineScoreResult{
+		Discipline: scoreApi.Discipline{
+			Id:   discipline.DisciplineId,
+			Name: storage.getDisciplineName(discipline.DisciplineId),
+		},
+		ScoreRating: storage.scoreRatingLoader.load(storage.year, discipline.Semester, discipline.DisciplineId, studentId),
+		Attendance:  storage.attendanceLoader.load(storage.year, discipline.Semester, discipline.DisciplineId, studentId).total(),
+	}
+}
+
+func (storage *Storage) getDisciplineScoreResultByStudentId(studentId int, disciplineId int) (DisciplineScoreResult, error) {
 	semester, err := storage.getSemesterByDisciplineId(disciplineId)
 
 	if err != nil {
-		return scoreApi.DisciplineScoreResult{}, err
+		return DisciplineScoreResult{}, err
 	}
 
 	if semester == 0 {
-		return scoreApi.DisciplineScoreResult{}, nil
+		return DisciplineScoreResult{}, nil
 	}
 
-	return scoreApi.DisciplineScoreResult{
+	groupRating, err := storage.groupRatingLoader.load(semester, disciplineId, studentId)
+	if err != nil {
+		return DisciplineScoreResult{}, err
+	}
+
+	return DisciplineScoreResult{
 		Discipline: scoreApi.Discipline{
 			Id:   disciplineId,
 			Name: storage.getDisciplineName(disciplineId),
 		},
 		ScoreRating: storage.scoreRatingLoader.load(storage.year, semester, disciplineId, studentId),
+		GroupRating: groupRating,
+		Attendance:  storage.attendanceLoader.load(storage.year, semester, disciplineId, studentId).total(),
 		Scores:      storage.getScores(semester, disciplineId, studentId),
 	}, nil
 }
 
-func (storage *Storage) getDisciplineScore(studentId int, disciplineId int, lessonId int) (scoreApi.DisciplineScore, error) {
+func (storage *Storage) getDisciplineScore(studentId int, disciplineId int, lessonId int) (DisciplineScore, error) {
 	semester, err := storage.getSemesterByDisciplineId(disciplineId)
 
 	if err != nil {
-		return scoreApi.DisciplineScore{}, err
+		return DisciplineScore{}, err
 	}
 
 	if semester == 0 {
-		return scoreApi.DisciplineScore{}, nil
+		return DisciplineScore{}, nil
 	}
 
-	return scoreApi.DisciplineScore{
+	return DisciplineScore{
 		Discipline: scoreApi.Discipline{
 			Id:   disciplineId,
 			Name: storage.getDisciplineName(disciplineId),
@@ -103,55 +251,280 @@ func (storage *Storage) getDisciplineScore(studentId int, disciplineId int, less
 	}, nil
 }
 
-// getActualStudentDisciplines
-// 1. Get student disciplines for the first semester
-// 2. Get student disciplines for the second semester
-// 3. If the second semester disciplines is empty, return the first semester disciplines
-// 4. Check disciplines from the first semester - if they are not in the second semester, check the last update time
-// 5. If the last update time is less than 6 weeks, add the discipline to the result
-// 6. Result will contain disciplines from the seconds semester + from first semesters that are not in the second semester and have been updated less than 6 weeks ago
-func (storage *Storage) getActualStudentDisciplines(studentId int) ([]DisciplineSemester, error) {
-	firstSemesterDisciplines, err := storage.getStudentDisciplinesIdsForSemester(studentId, 1)
+func (storage *Storage) getDisciplineTimeline(studentId int, disciplineId int) (DisciplineTimeline, error) {
+	semester, err := storage.getSemesterByDisciplineId(disciplineId)
+
 	if err != nil {
-		return nil, err
+		return DisciplineTimeline{}, err
 	}
 
-	secondSemesterDisciplines, err := storage.getStudentDisciplinesIdsForSemester(studentId, 2)
+	if semester == 0 {
+		return DisciplineTimeline{}, nil
+	}
+
+	scores := storage.getScores(semester, disciplineId, studentId)
+
+	return DisciplineTimeline{
+		Discipline: scoreApi.Discipline{
+			Id:   disciplineId,
+			Name: storage.getDisciplineName(disciplineId),
+		},
+		LessonTypes: makeTimelineLessonTypes(scores),
+		Points:      makeTimelinePoints(scores, storage.getOtherStudentsScores(semester, disciplineId, studentId, scores)),
+	}, nil
+}
+
+// getOtherStudentsScores loads scores of all students from the discipline totals except the given one.
+// Nothing is loaded when the student has no scores, as the timeline will be empty anyway.
+func (storage *Storage) getOtherStudentsScores(semester int, disciplineId int, studentId int, scores []Score) [][]Score {
+	if len(scores) == 0 {
+		return nil
+	}
+
+	ctx := context.Background()
+	disciplineTotalsKey := fmt.Sprintf("%d:%d:totals:%d", storage.year, semester, disciplineId)
+	studentKey := strconv.Itoa(studentId)
+
+	otherStudentKeys := make([]string, 0)
+	for _, member := range storage.redis.ZRange(ctx, disciplineTotalsKey, 0, -1).Val() {
+		if member != studentKey {
+			otherStudentKeys = append(otherStudentKeys, member)
+		}
+	}
+
+	if len(otherStudentKeys) == 0 {
+		return nil
+	}
+
+	lessons := storage.getDisciplineLessons(semester, disciplineId)
+
+	pipeline := storage.redis.Pipeline()
+	scoresKeys := make([]string, len(otherStudentKeys))
+	rawScoresCommands := make([]*redis.MapStringStringCmd, len(otherStudentKeys))
+	for index, otherStudentKey := range otherStudentKeys {
+		scoresKeys[index] = fmt.Sprintf("%d:%d:scores:%s:%d", storage.year, semester, otherStudentKey, disciplineId)
+		rawScoresCommands[index] = pipeline.HGetAll(ctx, scoresKeys[index])
+	}
+	_, _ = pipeline.Exec(ctx)
+
+	otherStudentsScores := make([][]Score, len(rawScoresCommands))
+	for index, rawScoresCommand := range rawScoresCommands {
+		otherStudentsScores[index] = makeScores(
+			storage.decodeScoreEntries(scoresKeys[index], rawScoresCommand.Val()), lessons, nil,
+		)
+	}
+
+	return otherStudentsScores
+}
+
+func (storage *Storage) getStudentAttendance(studentId int) (StudentAttendance, error) {
+	disciplines, err := storage.getActualStudentDisciplines(studentId)
 	if err != nil {
-		return nil, err
+		return StudentAttendance{}, err
 	}
 
-	if len(secondSemesterDisciplines) == 0 {
-		return firstSemesterDisciplines, nil
+	disciplinesAttendance := make([]AttendanceByLessonType, len(disciplines))
+
+	wg := sync.WaitGroup{}
+	wg.Add(len(disciplines))
+
+	for _index := range disciplines {
+		go func(index int) {
+			disciplinesAttendance[index] = storage.attendanceLoader.load(
+				storage.year, disciplines[index].Semester, disciplines[index].DisciplineId, studentId,
+			)
+			wg.Done()
+		}(_index)
 	}
 
-	cleanedFirstSemesterDisciplines := make([]DisciplineSemester, 0, len(firstSemesterDisciplines))
+	wg.Wait()
+
+	return makeStudentAttendance(disciplinesAttendance, storage.lessonTypes), nil
+}
 
-	var lastUpdatedAt time.Time
-	for _, firstSemesterDiscipline := range firstSemesterDisciplines {
-		if secondSemesterDisciplines.Has(firstSemesterDiscipline.DisciplineId) {
-			continue
+func (storage *Storage) getDisciplineDeletedLessons(studentId int, disciplineId int) (DisciplineDeletedLessons, error) {
+	semester, err := storage.getSemesterByDisciplineId(disciplineId)
+
+	if err != nil {
+		return DisciplineDeletedLessons{}, err
+	}
+
+	if semester == 0 {
+		return DisciplineDeletedLessons{}, nil
+	}
+
+	deletedLessons, err := storage.loadDeletedLessons(semester, disciplineId)
+	if err != nil {
+		return DisciplineDeletedLessons{}, err
+	}
+
+	studentDisciplineScoresKey := fmt.Sprintf("%d:%d:scores:%d:%d", storage.year, semester, studentId, disciplineId)
+	rawScores := storage.redis.HGetAll(context.Background(), studentDisciplineScoresKey).Val()
+	scoreEntries := storage.decodeScoreEntries(studentDisciplineScoresKey, rawScores)
+
+	lessons := make([]Score, 0, len(deletedLessons))
+	scoredLessonIds := make(map[int]bool)
+	for _, score := range makeScores(scoreEntries, storage.getDisciplineLessons(semester, disciplineId), deletedLessons) {
+		if score.Deleted {
+			scoredLessonIds[score.Lesson.Id] = true
+			lessons = append(lessons, score)
 		}
+	}
+
+	for lessonId, lesson := range deletedLessons {
+		if !scoredLessonIds[lessonId] {
+			lessons = append(lessons, Score{
+				Score: scoreApi.Score{
+					Lesson: lesson,
+				},
+				Deleted: true,
+			})
+		}
+	}
+
+	sortScores(lessons)
 
-		_, lastUpdatedAt, err = storage.getDisciplineSemesterAndUpdatedAt(firstSemesterDiscipline.DisciplineId)
+	return DisciplineDeletedLessons{
+		Discipline: scoreApi.Discipline{
+			Id:   disciplineId,
+			Name: storage.getDisciplineName(disciplineId),
+		},
+		Lessons: lessons,
+	}, nil
+}
+
+// loadDeletedLessons loads all deleted lessons of discipline from the `deleted-lessons-index` hash
+// written next to `deleted-lessons:{disciplineId}:{lessonId}` keys, the result is cached for DeletedLessonsCacheTTL
+func (storage *Storage) loadDeletedLessons(semester int, disciplineId int) (map[int]scoreApi.Lesson, error) {
+	if deletedLessons, hit := storage.deletedLessons.get(storage.year, semester, disciplineId, time.Now()); hit {
+		return deletedLessons, nil
+	}
+
+	deletedLessonsIndexKey := fmt.Sprintf("%d:%d:deleted-lessons-index:%d", storage.year, semester, disciplineId)
+	values, err := storage.redis.HGetAll(context.Background(), deletedLessonsIndexKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	deletedLessons := make(map[int]scoreApi.Lesson, len(values))
+	for lessonIdString, lessonValue := range values {
+		lesson, err := storage.decodeLesson(lessonIdString, lessonValue)
 		if err != nil {
-			return nil, err
+			storage.anomalies.report(deletedLessonsIndexKey, lessonIdString, err)
+		} else {
+			deletedLessons[lesson.Id] = lesson
 		}
+	}
+
+	storage.deletedLessons.set(storage.year, semester, disciplineId, deletedLessons, time.Now())
 
-		if time.Since(lastUpdatedAt) < MaxSemesterUpdatedInterval {
-			cleanedFirstSemesterDisciplines = append(cleanedFirstSemesterDisciplines, firstSemesterDiscipline)
+	return deletedLessons, nil
+}
+
+// getStudentProfile returns empty profile for unknown student: without profile hash and disciplines in any year
+func (storage *Storage) getStudentProfile(studentId int) (StudentProfile, error) {
+	fields, err := storage.redis.HGetAll(
+		context.Background(), fmt.Sprintf("%d:student:%d", storage.year, studentId),
+	).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return StudentProfile{}, err
+	}
+
+	semesters, err := storage.getStudentSemesters(studentId)
+	if err != nil {
+		return StudentProfile{}, err
+	}
+
+	disciplines, err := storage.getActualStudentDisciplines(studentId)
+	if err != nil {
+		return StudentProfile{}, err
+	}
+
+	return makeStudentProfile(studentId, fields, semesters, len(disciplines)), nil
+}
+
+// studentExists checks the same keys as getStudentProfile: profile hash and student disciplines in any year
+func (storage *Storage) studentExists(studentId int) (bool, error) {
+	keys := []string{fmt.Sprintf("%d:student:%d", storage.year, studentId)}
+	for year := FirstAcademicYear; year <= storage.year; year++ {
+		for semester := 1; semester <= 2; semester++ {
+			keys = append(keys, fmt.Sprintf("%d:%d:student_disciplines:%d", year, semester, studentId))
+		}
+	}
+
+	existsCount, err := storage.redis.Exists(context.Background(), keys...).Result()
+
+	return existsCount != 0, err
+}
+
+// getStudentSemesters checks student disciplines keys of every semester since FirstAcademicYear until current year
+func (storage *Storage) getStudentSemesters(studentId int) ([]YearSemester, error) {
+	ctx := context.Background()
+	semesters := make([]YearSemester, 0)
+	if storage.year < FirstAcademicYear {
+		return semesters, nil
+	}
+
+	pipeline := storage.redis.Pipeline()
+	existsCommands := make([]*redis.IntCmd, 0, (storage.year-FirstAcademicYear+1)*2)
+	for year := FirstAcademicYear; year <= storage.year; year++ {
+		for semester := 1; semester <= 2; semester++ {
+			existsCommands = append(existsCommands, pipeline.Exists(
+				ctx, fmt.Sprintf("%d:%d:student_disciplines:%d", year, semester, studentId),
+			))
 		}
 	}
 
-	if len(cleanedFirstSemesterDisciplines) == 0 {
-		return secondSemesterDisciplines, nil
+	if _, err := pipeline.Exec(ctx); err != nil {
+		return nil, err
 	}
 
-	disciplines := make([]DisciplineSemester, len(cleanedFirstSemesterDisciplines)+len(secondSemesterDisciplines))
-	copy(disciplines, cleanedFirstSemesterDisciplines)
-	copy(disciplines[len(cleanedFirstSemesterDisciplines):], secondSemesterDisciplines)
+	for index, existsCommand := range existsCommands {
+		if existsCommand.Val() != 0 {
+			semesters = append(semesters, YearSemester{
+				Year:     FirstAcademicYear + index/2,
+				Semester: index%2 + 1,
+			})
+		}
+	}
 
-	return disciplines, nil
+	return semesters, nil
+}
+
+// getActualStudentDisciplines loads student disciplines of both semesters, see selectActualDisciplines
+func (storage *Storage) getActualStudentDisciplines(studentId int) ([]DisciplineSemester, error) {
+	firstSemesterDisciplines, err := storage.getStudentDisciplinesIdsForSemester(studentId, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	secondSemesterDisciplines, err := storage.getStudentDisciplinesIdsForSemester(studentId, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	return storage.rules.selectActualDisciplines(
+		firstSemesterDisciplines, secondSemesterDisciplines,
+		func(disciplineId int) (time.Time, error) {
+			_, lastUpdatedAt, err := storage.getDisciplineSemesterAndUpdatedAt(disciplineId)
+			return lastUpdatedAt, err
+		},
+	)
+}
+
+// selectActualDisciplines returns the first semester disciplines when the second semester is empty,
+// otherwise selection depends on the semester switch policy
+func (rules AcademicRules) selectActualDisciplines(
+	firstSemesterDisciplines DisciplineSemesters, secondSemesterDisciplines DisciplineSemesters,
+	getLastUpdatedAt func(disciplineId int) (time.Time, error),
+) ([]DisciplineSemester, error) {
+	if len(secondSemesterDisciplines) == 0 {
+		return firstSemesterDisciplines, nil
+	}
+
+	return rules.semesterSwitchPolicy().selectDisciplines(
+		firstSemesterDisciplines, secondSemesterDisciplines, getLastUpdatedAt, time.Now(),
+	)
 }
 
 func (storage *Storage) getStudentDisciplinesIdsForSemester(studentId int, semester int) (DisciplineSemesters, error) {
@@ -161,10 +534,18 @@ func (storage *Storage) getStudentDisciplinesIdsForSemester(studentId int, semes
 		return nil, err
 	}
 
-	disciplineSemesters := make(DisciplineSemesters, len(stringIds))
-	for index, stringId := range stringIds {
-		disciplineSemesters[index].Semester = semester
-		disciplineSemesters[index].DisciplineId, _ = strconv.Atoi(stringId)
+	disciplineSemesters := make(DisciplineSemesters, 0, len(stringIds))
+	for _, stringId := range stringIds {
+		disciplineId, err := storage.codec.decodeId(stringId)
+		if err != nil {
+			storage.anomalies.report(studentDisciplinesKey, "", err)
+			continue
+		}
+
+		disciplineSemesters = append(disciplineSemesters, DisciplineSemester{
+			Semester:     semester,
+			DisciplineId: disciplineId,
+		})
 	}
 
 	return disciplineSemesters, nil
@@ -182,75 +563,179 @@ func (storage *Storage) getDisciplineSemesterAndUpdatedAt(disciplineId int) (sem
 		return 0, time.Time{}, err
 	}
 
-	if len(disciplineLastUpdateAtValue) < 2 {
+	if disciplineLastUpdateAtValue == "" {
 		return 0, time.Time{}, nil
 	}
 
-	semester, _ = strconv.Atoi(disciplineLastUpdateAtValue[0:1])
-	unixTimestamp, _ := strconv.ParseInt(disciplineLastUpdateAtValue[1:], 10, 0)
-
-	updatedAt = time.Unix(unixTimestamp, 0)
+	semester, updatedAt, err = storage.codec.decodeSemesterUpdatedAt(disciplineLastUpdateAtValue)
+	if err != nil {
+		storage.anomalies.report(disciplineLastUpdateAtKey, "", err)
+		return 0, time.Time{}, nil
+	}
 
 	return semester, updatedAt, nil
 }
 
-func (storage *Storage) getScores(semester int, disciplineId int, studentId int) []scoreApi.Score {
+func (storage *Storage) getScores(semester int, disciplineId int, studentId int) []Score {
 	studentDisciplineScoresKey := fmt.Sprintf("%d:%d:scores:%d:%d", storage.year, semester, studentId, disciplineId)
-	disciplineKey := fmt.Sprintf("%d:%d:lessons:%d", storage.year, semester, disciplineId)
 
 	rawScores := storage.redis.HGetAll(context.Background(), studentDisciplineScoresKey).Val()
 	if len(rawScores) == 0 {
-		return make([]scoreApi.Score, 0)
+		return make([]Score, 0)
 	}
 
-	var lessonId int
+	scoreEntries := storage.decodeScoreEntries(studentDisciplineScoresKey, rawScores)
+	lessons := storage.getDisciplineLessons(semester, disciplineId)
+
+	return makeScores(scoreEntries, lessons, storage.getDeletedLessons(semester, disciplineId, scoreEntries, lessons))
+}
+
+// getDisciplineLessons decodes discipline lessons hash, malformed lessons are skipped and reported as anomalies
+func (storage *Storage) getDisciplineLessons(semester int, disciplineId int) map[int]scoreApi.Lesson {
+	disciplineKey := fmt.Sprintf("%d:%d:lessons:%d", storage.year, semester, disciplineId)
 
-	lessons := make(map[int]string)
+	lessons := make(map[int]scoreApi.Lesson)
 	for lessonIdString, lessonValue := range storage.redis.HGetAll(context.Background(), disciplineKey).Val() {
-		lessonId, _ = strconv.Atoi(lessonIdString)
-		lessons[lessonId] = lessonValue
+		lesson, err := storage.decodeLesson(lessonIdString, lessonValue)
+		if err != nil {
+			storage.anomalies.report(disciplineKey, lessonIdString, err)
+		} else {
+			lessons[lesson.Id] = lesson
+		}
+	}
+
+	return lessons
+}
+
+func (storage *Storage) decodeLesson(lessonIdString string, lessonValue string) (scoreApi.Lesson, error) {
+	lessonId, err := storage.codec.decodeId(lessonIdString)
+	if err != nil {
+		return scoreApi.Lesson{}, err
+	}
+
+	lessonDate, lessonTypeId, err := storage.codec.decodeLessonValue(lessonValue)
+	if err != nil {
+		return scoreApi.Lesson{}, err
 	}
 
-	var lessonTypeId int
-	var lessonDate time.Time
-	var lessonHalf int
-	var scoreValue *float32
-	var exists bool
+	return scoreApi.Lesson{
+		Id:   lessonId,
+		Date: lessonDate,
+		Type: storage.lessonTypes[lessonTypeId],
+	}, nil
+}
+
+// decodeScoreEntries decodes student discipline scores hash, malformed entries are skipped and reported as anomalies
+func (storage *Storage) decodeScoreEntries(studentDisciplineScoresKey string, rawScores map[string]string) []ScoreEntry {
+	scoreEntries := make([]ScoreEntry, 0, len(rawScores))
+	for field, value := range rawScores {
+		scoreEntry, err := storage.codec.decodeScoreEntry(field, value)
+		if err != nil {
+			storage.anomalies.report(studentDisciplineScoresKey, field, err)
+		} else {
+			scoreEntries = append(scoreEntries, scoreEntry)
+		}
+	}
+
+	return scoreEntries
+}
+
+// getDeletedLessons loads deleted lessons which are still referenced by scores
+func (storage *Storage) getDeletedLessons(semester int, disciplineId int, scoreEntries []ScoreEntry, lessons map[int]scoreApi.Lesson) map[int]scoreApi.Lesson {
+	ctx := context.Background()
+
+	deletedLessonKeys := make(map[int]string)
+	deletedLessonCommands := make(map[int]*redis.StringCmd)
+	pipeline := storage.redis.Pipeline()
+	for _, scoreEntry := range scoreEntries {
+		if _, exists := lessons[scoreEntry.LessonId]; exists {
+			continue
+		}
+
+		if _, exists := deletedLessonCommands[scoreEntry.LessonId]; !exists {
+			deletedLessonKeys[scoreEntry.LessonId] = fmt.Sprintf(
+				"%d:%d:deleted-lessons:%d:%d",
+				storage.year, semester, disciplineId, scoreEntry.LessonId,
+			)
+			deletedLessonCommands[scoreEntry.LessonId] = pipeline.Get(ctx, deletedLessonKeys[scoreEntry.LessonId])
+		}
+	}
+
+	if len(deletedLessonCommands) == 0 {
+		return nil
+	}
+
+	_, _ = pipeline.Exec(ctx)
+
+	deletedLessons := make(map[int]scoreApi.Lesson, len(deletedLessonCommands))
+	for lessonId, deletedLessonCommand := range deletedLessonCommands {
+		if deletedLessonCommand.Val() == "" {
+			continue
+		}
+
+		lessonDate, lessonTypeId, err := storage.codec.decodeLessonValue(deletedLessonCommand.Val())
+		if err != nil {
+			storage.anomalies.report(deletedLessonKeys[lessonId], "", err)
+			continue
+		}
+
+		deletedLessons[lessonId] = scoreApi.Lesson{
+			Id:   lessonId,
+			Date: lessonDate,
+			Type: storage.lessonTypes[lessonTypeId],
+		}
+	}
 
-	scoresMap := make(map[int]*scoreApi.Score, len(rawScores))
+	return deletedLessons
+}
 
-	for lessonIdCompacted, scoreString := range rawScores {
-		lessonId, lessonHalf = parseLessonIdAndHalf(lessonIdCompacted)
+// makeScores converts decoded student discipline scores into scores sorted by lesson date.
+// Scores of lessons which are absent in lessons are marked as deleted and take lesson metadata from deletedLessons.
+func makeScores(scoreEntries []ScoreEntry, lessons map[int]scoreApi.Lesson, deletedLessons map[int]scoreApi.Lesson) []Score {
+	scoresMap := make(map[int]*Score, len(scoreEntries))
+
+	for _, scoreEntry := range scoreEntries {
+		score, exists := scoresMap[scoreEntry.LessonId]
+		if !exists {
+			lesson, isActual := lessons[scoreEntry.LessonId]
+			if !isActual {
+				lesson = deletedLessons[scoreEntry.LessonId]
+				lesson.Id = scoreEntry.LessonId
+			}
 
-		if _, exists = scoresMap[lessonId]; !exists {
-			lessonDate, lessonTypeId = parseLessonValueString(lessons[lessonId])
-			scoresMap[lessonId] = &scoreApi.Score{
-				Lesson: scoreApi.Lesson{
-					Id:   lessonId,
-					Date: lessonDate,
-					Type: storage.lessonTypes[lessonTypeId],
+			score = &Score{
+				Score: scoreApi.Score{
+					Lesson: lesson,
 				},
+				Deleted: !isActual,
 			}
+			scoresMap[scoreEntry.LessonId] = score
 		}
 
-		scoreValue = parseFloat(scoreString)
-		if IsAbsentScoreValue == *scoreValue {
-			scoresMap[lessonId].IsAbsent = true
-		} else if lessonHalf == 1 {
-			scoresMap[lessonId].FirstScore = scoreValue
-		} else if lessonHalf == 2 {
-			scoresMap[lessonId].SecondScore = scoreValue
+		scoreValue := scoreEntry.Value
+		if scoreEntry.isAbsent() {
+			score.IsAbsent = true
+		} else if scoreEntry.Half == 1 {
+			score.FirstScore = &scoreValue
+		} else if scoreEntry.Half == 2 {
+			score.SecondScore = &scoreValue
 		}
 	}
 
-	scores := make([]scoreApi.Score, len(scoresMap))
+	scores := make([]Score, len(scoresMap))
 	i := 0
 	for _, score := range scoresMap {
 		scores[i] = *score
 		i++
 	}
 
-	// Sort by date
+	sortScores(scores)
+
+	return scores
+}
+
+// sortScores sorts by lesson date, then by lesson id
+func sortScores(scores []Score) {
 	sort.SliceStable(scores, func(i, j int) bool {
 		if scores[i].Lesson.Date.Equal(scores[j].Lesson.Date) {
 			return scores[i].Lesson.Id < scores[j].Lesson.Id
@@ -258,83 +743,137 @@ func (storage *Storage) getScores(semester int, disciplineId int, studentId int)
 			return scores[i].Lesson.Date.Before(scores[j].Lesson.Date)
 		}
 	})
-
-	return scores
 }
 
-func (storage *Storage) getScore(semester int, disciplineId int, studentId int, lessonId int) scoreApi.Score {
+func (storage *Storage) getScore(semester int, disciplineId int, studentId int, lessonId int) Score {
 	ctx := context.Background()
 	studentDisciplineScoresKey := fmt.Sprintf("%d:%d:scores:%d:%d", storage.year, semester, studentId, disciplineId)
 	disciplineLessonsKey := fmt.Sprintf("%d:%d:lessons:%d", storage.year, semester, disciplineId)
 
 	lessonValue := storage.redis.HGet(ctx, disciplineLessonsKey, strconv.Itoa(lessonId)).Val()
+	deleted := lessonValue == ""
 
-	if lessonValue == "" {
-		deletedLessonKey := fmt.Sprintf(
-			"%d:%d:deleted-lessons:%d:%d",
-			storage.year, semester, disciplineId, lessonId,
-		)
+	deletedLessonKey := fmt.Sprintf(
+		"%d:%d:deleted-lessons:%d:%d",
+		storage.year, semester, disciplineId, lessonId,
+	)
 
+	if deleted {
 		lessonValue = storage.redis.Get(ctx, deletedLessonKey).Val()
 	}
 
 	if lessonValue == "" {
-		return scoreApi.Score{}
+		return Score{}
 	}
 
-	lessonDate, lessonTypeId := parseLessonValueString(lessonValue)
+	lessonDate, lessonTypeId, err := storage.codec.decodeLessonValue(lessonValue)
+	if err != nil {
+		if deleted {
+			storage.anomalies.report(deletedLessonKey, "", err)
+		} else {
+			storage.anomalies.report(disciplineLessonsKey, strconv.Itoa(lessonId), err)
+		}
+		return Score{}
+	}
 
-	score := scoreApi.Score{
-		Lesson: scoreApi.Lesson{
-			Id:   lessonId,
-			Date: lessonDate,
-			Type: storage.lessonTypes[lessonTypeId],
+	score := Score{
+		Score: scoreApi.Score{
+			Lesson: scoreApi.Lesson{
+				Id:   lessonId,
+				Date: lessonDate,
+				Type: storage.lessonTypes[lessonTypeId],
+			},
 		},
+		Deleted: deleted,
 	}
 
-	lessonIdPrefix := strconv.Itoa(lessonId) + ":"
-	rawScores := storage.redis.HMGet(ctx, studentDisciplineScoresKey, lessonIdPrefix+"1", lessonIdPrefix+"2").Val()
+	scoreFields := []string{storage.codec.encodeScoreField(lessonId, 1), storage.codec.encodeScoreField(lessonId, 2)}
+	rawScores := storage.redis.HMGet(ctx, studentDisciplineScoresKey, scoreFields...).Val()
 
-	var scoreValue *float32
-	for lessonHalf, scoreString := range rawScores {
-		if scoreString != nil {
-			scoreValue = parseFloat(scoreString)
-			if IsAbsentScoreValue == *scoreValue {
-				score.IsAbsent = true
-			} else if lessonHalf == 0 {
-				score.FirstScore = scoreValue
-			} else if lessonHalf == 1 {
-				score.SecondScore = scoreValue
-			}
+	for index, rawScore := range rawScores {
+		scoreString, isString := rawScore.(string)
+		if !isString {
+			continue
+		}
+
+		scoreValue, err := storage.codec.decodeScoreValue(scoreString)
+		if err != nil {
+			storage.anomalies.report(studentDisciplineScoresKey, scoreFields[index], err)
+		} else if IsAbsentScoreValue == scoreValue {
+			score.IsAbsent = true
+		} else if index == 0 {
+			score.FirstScore = &scoreValue
+		} else if index == 1 {
+			score.SecondScore = &scoreValue
 		}
 	}
 
 	return score
 }
 
-func parseFloat(input interface{}) *float32 {
-	f64, _ := strconv.ParseFloat(input.(string), 10)
-	f32 := float32(f64)
-	return &f32
-}
+func (storage *Storage) getDisciplineRating(disciplineId int) (DisciplineRating, error) {
+	semester, err := storage.getSemesterByDisciplineId(disciplineId)
 
-func parseLessonIdAndHalf(lessonIdCompacted string) (lessonId int, lessonHalf int) {
-	lessonId, _ = strconv.Atoi(lessonIdCompacted[:len(lessonIdCompacted)-2])
-	lessonHalf, _ = strconv.Atoi(lessonIdCompacted[len(lessonIdCompacted)-1:])
+	if err != nil || semester == 0 {
+		return DisciplineRating{}, err
+	}
 
-	return
+	disciplineTotalsKey := fmt.Sprintf("%d:%d:totals:%d", storage.year, semester, disciplineId)
+	members, err := storage.redis.ZRevRangeWithScores(context.Background(), disciplineTotalsKey, 0, -1).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return DisciplineRating{}, err
+	}
+
+	studentIds := make([]int, 0, len(members))
+	totals := make([]float32, 0, len(members))
+	for _, member := range members {
+		studentIdString, _ := member.Member.(string)
+		studentId, err := storage.codec.decodeId(studentIdString)
+		if err != nil {
+			storage.anomalies.report(disciplineTotalsKey, studentIdString, err)
+			continue
+		}
+
+		studentIds = append(studentIds, studentId)
+		totals = append(totals, float32(member.Score))
+	}
+
+	return DisciplineRating{
+		Discipline: scoreApi.Discipline{
+			Id:   disciplineId,
+			Name: storage.getDisciplineName(disciplineId),
+		},
+		Year:     storage.year,
+		Semester: semester,
+		Students: storage.rules.zeroTotalsMode.makeStudentTotals(studentIds, totals),
+	}, nil
 }
 
-func parseLessonValueString(lessonString string) (date time.Time, typeId int) {
-	if len(lessonString) >= 7 {
-		typeId, _ = strconv.Atoi(lessonString[6:])
-		year, _ := strconv.Atoi(lessonString[0:2])
-		month, _ := strconv.Atoi(lessonString[2:4])
-		day, _ := strconv.Atoi(lessonString[4:6])
+func (storage *Storage) getGroupStudentIds(groupId int) ([]int, error) {
+	groupKey := fmt.Sprintf("%d:group:%d", storage.year, groupId)
+	members, err := storage.redis.SMembers(context.Background(), groupKey).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
 
-		date = time.Date(2000+year, time.Month(month), day, 0, 0, 0, 0, time.Local)
+	studentIds := make([]int, 0, len(members))
+	for _, member := range members {
+		studentId, err := storage.codec.decodeId(member)
+		if err != nil {
+			storage.anomalies.report(groupKey, member, err)
+			continue
+		}
+
+		studentIds = append(studentIds, studentId)
 	}
-	return
+
+	sort.Ints(studentIds)
+
+	return studentIds, nil
+}
+
+func (storage *Storage) getAnomalies() AnomalyReport {
+	return storage.anomalies.getReport()
 }
 
 func (storage *Storage) getDisciplineName(disciplineId int) string {
@@ -344,21 +883,24 @@ func (storage *Storage) getDisciplineName(disciplineId int) string {
 	).Val()
 }
 
-func (storage *Storage) periodicallyUpdateGeneralData(ctx context.Context) {
-	var year int
-	var lessonTypesJSON []byte
-	var lessonTypes []scoreApi.LessonType
+// decodeDisciplineCredits returns DefaultDisciplineCredits when credits are not set or malformed
+func (storage *Storage) decodeDisciplineCredits(disciplineKey string, creditsString string) float32 {
+	if creditsString == "" {
+		return DefaultDisciplineCredits
+	}
 
-	for ctx.Err() == nil {
-		year, _ = storage.redis.Get(context.Background(), "currentYear").Int()
-		if year >= 2022 {
-			storage.year = year
-		}
+	credits, err := storage.codec.decodeCredits(creditsString)
+	if err != nil {
+		storage.anomalies.report(disciplineKey, "credits", err)
+		return DefaultDisciplineCredits
+	}
 
-		lessonTypesJSON, _ = storage.redis.Get(context.Background(), "lessonTypes").Bytes()
-		if len(lessonTypesJSON) > 1 && json.Unmarshal(lessonTypesJSON, &lessonTypes) == nil {
-			storage.lessonTypes = makeLessonTypesMap(&lessonTypes)
-		}
+	return credits
+}
+
+func (storage *Storage) periodicallyUpdateGeneralData(ctx context.Context) {
+	for ctx.Err() == nil {
+		storage.updateGeneralData()
 
 		if storage.year == 0 || len(storage.lessonTypes) == 0 {
 			time.Sleep(time.Minute)
@@ -368,6 +910,21 @@ func (storage *Storage) periodicallyUpdateGeneralData(ctx context.Context) {
 	}
 }
 
+// updateGeneralData loads current year and lesson types, values missing in Redis keep previous state
+func (storage *Storage) updateGeneralData() {
+	var lessonTypes []scoreApi.LessonType
+
+	year, _ := storage.redis.Get(context.Background(), "currentYear").Int()
+	if year >= FirstAcademicYear {
+		storage.year = year
+	}
+
+	lessonTypesJSON, _ := storage.redis.Get(context.Background(), "lessonTypes").Bytes()
+	if len(lessonTypesJSON) > 1 && json.Unmarshal(lessonTypesJSON, &lessonTypes) == nil {
+		storage.lessonTypes = makeLessonTypesMap(&lessonTypes)
+	}
+}
+
 func makeLessonTypesMap(lessonTypesSlice *[]scoreApi.LessonType) map[int]scoreApi.LessonType {
 	lessonTypesMap := map[int]scoreApi.LessonType{}
 	for _, lessonType := range *lessonTypesSlice {
@@ -377,15 +934,36 @@ func makeLessonTypesMap(lessonTypesSlice *[]scoreApi.LessonType) map[int]scoreAp
 	return lessonTypesMap
 }
 
-func NewStorage(redis *redis.Client, ctx context.Context) *Storage {
+func NewStorage(redis *redis.Client, rules AcademicRules, ctx context.Context) *Storage {
+	storage := newStorage(redis, rules)
+
+	go storage.periodicallyUpdateGeneralData(ctx)
+
+	return storage
+}
+
+// newStorage creates storage without background general data updates, used directly by CLI commands
+func newStorage(redis *redis.Client, rules AcademicRules) *Storage {
+	anomalies := &AnomalyCounter{}
+	codec := RedisCodec{location: rules.location}
+
 	storage := &Storage{
 		redis: redis,
 		scoreRatingLoader: &ScoreRatingLoader{
-			redis: redis,
+			redis:          redis,
+			zeroTotalsMode: rules.zeroTotalsMode,
+		},
+		attendanceLoader: &AttendanceLoader{
+			redis:     redis,
+			codec:     codec,
+			anomalies: anomalies,
 		},
+		codec:          codec,
+		anomalies:      anomalies,
+		deletedLessons: NewDeletedLessonsCache(DeletedLessonsCacheTTL),
+		rules:          rules,
 	}
-
-	go storage.periodicallyUpdateGeneralData(ctx)
+	storage.groupRatingLoader = &GroupRatingLoader{storage: storage}
 
 	return storage
 }
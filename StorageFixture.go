@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"gopkg.in/yaml.v3"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// StorageFixture describes the whole storage content for MemoryStorage, it is loaded from JSON or YAML file
+type StorageFixture struct {
+	Year        int                 `json:"year" yaml:"year"`
+	LessonTypes []FixtureLessonType `json:"lessonTypes" yaml:"lessonTypes"`
+	Disciplines []FixtureDiscipline `json:"disciplines" yaml:"disciplines"`
+	Students    []FixtureStudent    `json:"students" yaml:"students"`
+}
+
+type FixtureLessonType struct {
+	Id        int    `json:"id" yaml:"id"`
+	ShortName string `json:"shortName" yaml:"shortName"`
+	LongName  string `json:"longName" yaml:"longName"`
+}
+
+type FixtureDiscipline struct {
+	Id        int             `json:"id" yaml:"id"`
+	Name      string          `json:"name" yaml:"name"`
+	Semester  int             `json:"semester" yaml:"semester"`
+	Credits   float32         `json:"credits" yaml:"credits"`
+	UpdatedAt time.Time       `json:"updatedAt" yaml:"updatedAt"`
+	Lessons   []FixtureLesson `json:"lessons" yaml:"lessons"`
+	Scores    []FixtureScore  `json:"scores" yaml:"scores"`
+}
+
+// FixtureLesson - Date is in YYYY-MM-DD format and means the day in academic timezone
+type FixtureLesson struct {
+	Id      int    `json:"id" yaml:"id"`
+	Date    string `json:"date" yaml:"date"`
+	Type    int    `json:"type" yaml:"type"`
+	Deleted bool   `json:"deleted" yaml:"deleted"`
+}
+
+type FixtureScore struct {
+	Student int      `json:"student" yaml:"student"`
+	Lesson  int      `json:"lesson" yaml:"lesson"`
+	First   *float32 `json:"first" yaml:"first"`
+	Second  *float32 `json:"second" yaml:"second"`
+	Absent  bool     `json:"absent" yaml:"absent"`
+}
+
+// FixtureStudent - Group is academic group id, zero means the student is not a member of any group.
+// GroupName, Faculty and StudyForm are profile fields shown as is.
+type FixtureStudent struct {
+	Id          int    `json:"id" yaml:"id"`
+	Name        string `json:"name" yaml:"name"`
+	Group       int    `json:"group" yaml:"group"`
+	GroupName   string `json:"groupName" yaml:"groupName"`
+	Faculty     string `json:"faculty" yaml:"faculty"`
+	StudyForm   string `json:"studyForm" yaml:"studyForm"`
+	Disciplines []int  `json:"disciplines" yaml:"disciplines"`
+}
+
+// loadStorageFixture reads fixture file, format is chosen by .json, .yaml or .yml extension.
+// Unknown fields are rejected to catch typos in hand-written fixtures.
+func loadStorageFixture(filename string) (StorageFixture, error) {
+	fixture := StorageFixture{}
+
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return fixture, err
+	}
+
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(content))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&fixture)
+
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(content))
+		decoder.KnownFields(true)
+		err = decoder.Decode(&fixture)
+
+	default:
+		err = errors.New("unsupported fixture format, expected .json, .yaml or .yml file")
+	}
+
+	if err != nil {
+		return StorageFixture{}, errors.New("Failed to load fixture " + filename + ": " + err.Error())
+	}
+
+	return fixture, nil
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadStorageFixture(t *testing.T) {
+	t.Run("yaml", func(t *testing.T) {
+		fixture, err := loadStorageFixture("fixture.example.yaml")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2026, fixture.Year)
+		assert.Len(t, fixture.LessonTypes, 3)
+		assert.Len(t, fixture.Disciplines, 2)
+		assert.Len(t, fixture.Students, 3)
+
+		assert.Equal(t, FixtureLesson{Id: 247, Date: "2026-09-22", Type: 1, Deleted: true}, fixture.Disciplines[0].Lessons[2])
+		assert.Equal(t, FixtureScore{Student: 1200, Lesson: 245, First: floatPointer(4.5), Second: floatPointer(2)}, fixture.Disciplines[0].Scores[0])
+		assert.Equal(t, time.Date(2026, time.Month(10), 1, 12, 0, 0, 0, time.UTC), fixture.Disciplines[0].UpdatedAt)
+	})
+
+	t.Run("json", func(t *testing.T) {
+		filename := filepath.Join(t.TempDir(), "fixture.json")
+		_ = os.WriteFile(filename, []byte(`{
+			"year": 2026,
+			"lessonTypes": [{"id": 1, "shortName": "Лек", "longName": "Лекція"}],
+			"disciplines": [{
+				"id": 199, "name": "Капітал!", "semester": 2, "updatedAt": "2026-10-01T12:00:00Z",
+				"lessons": [{"id": 245, "date": "2026-09-08", "type": 1}],
+				"scores": [{"student": 1200, "lesson": 245, "absent": true}]
+			}],
+			"students": [{"id": 1200, "disciplines": [199]}]
+		}`), 0o600)
+
+		fixture, err := loadStorageFixture(filename)
+
+		assert.NoError(t, err)
+		assert.Equal(t, StorageFixture{
+			Year:        2026,
+			LessonTypes: []FixtureLessonType{{Id: 1, ShortName: "Лек", LongName: "Лекція"}},
+			Disciplines: []FixtureDiscipline{
+				{
+					Id:        199,
+					Name:      "Капітал!",
+					Semester:  2,
+					UpdatedAt: time.Date(2026, time.Month(10), 1, 12, 0, 0, 0, time.UTC),
+					Lessons:   []FixtureLesson{{Id: 245, Date: "2026-09-08", Type: 1}},
+					Scores:    []FixtureScore{{Student: 1200, Lesson: 245, Absent: true}},
+				},
+			},
+			Students: []FixtureStudent{{Id: 1200, Disciplines: []int{199}}},
+		}, fixture)
+	})
+
+	t.Run("unknown_field", func(t *testing.T) {
+		filename := filepath.Join(t.TempDir(), "fixture.yml")
+		_ = os.WriteFile(filename, []byte("year: 2026\nlesson_types: []\n"), 0o600)
+
+		_, err := loadStorageFixture(filename)
+
+		assert.ErrorContains(t, err, "Failed to load fixture "+filename)
+		assert.ErrorContains(t, err, "field lesson_types not found")
+	})
+
+	t.Run("unsupported_format", func(t *testing.T) {
+		filename := filepath.Join(t.TempDir(), "fixture.toml")
+		_ = os.WriteFile(filename, []byte("year = 2026\n"), 0o600)
+
+		_, err := loadStorageFixture(filename)
+
+		assert.ErrorContains(t, err, "unsupported fixture format")
+	})
+
+	t.Run("not_exists", func(t *testing.T) {
+		_, err := loadStorageFixture(filepath.Join(t.TempDir(), "fixture.yaml"))
+
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"github.com/go-redis/redismock/v9"
 	scoreApi "github.com/kneu-messenger-pigeon/score-api"
+	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
 	"strconv"
 	"testing"
@@ -29,12 +30,15 @@ func TestNewStorage(t *testing.T) {
 		redisMock.ExpectGet("currentYear").SetVal(strconv.Itoa(expectedYear))
 		redisMock.ExpectGet("lessonTypes").SetVal(`[{"id":1,"shortName":"Тст","longName":"Тест"}]`)
 
-		storage := NewStorage(redisClient, ctx)
+		storage := NewStorage(redisClient, getTestAcademicRules(), ctx)
 		time.Sleep(time.Millisecond)
 		cancel()
 
 		assert.Equal(t, expectedYear, storage.year)
 		assert.Equal(t, expectedLessonTypes, storage.lessonTypes)
+		assert.Equal(t, getTestAcademicRules(), storage.rules)
+		assert.Equal(t, RedisCodec{location: defaultAcademicLocation}, storage.codec)
+		assert.Equal(t, ZeroTotalsLast, storage.scoreRatingLoader.(*ScoreRatingLoader).zeroTotalsMode)
 
 		assert.NoError(t, redisMock.ExpectationsWereMet())
 	})
@@ -48,7 +52,7 @@ func TestNewStorage(t *testing.T) {
 		redisMock.ExpectGet("currentYear").RedisNil()
 		redisMock.ExpectGet("lessonTypes").RedisNil()
 
-		storage := NewStorage(redisClient, ctx)
+		storage := NewStorage(redisClient, getTestAcademicRules(), ctx)
 		time.Sleep(time.Millisecond)
 		cancel()
 
@@ -67,32 +71,34 @@ func TestStorageGetDisciplineScoreResultsByStudentId(t *testing.T) {
 
 		lessonTypes := GetTestLessonTypes()
 
-		expectedResults := scoreApi.DisciplineScoreResults{
-			scoreApi.DisciplineScoreResult{
+		expectedResults := DisciplineScoreResults{
+			DisciplineScoreResult{
 				Discipline: scoreApi.Discipline{
 					Id:   100,
 					Name: "Капітал!",
 				},
-				ScoreRating: scoreApi.ScoreRating{
+				ScoreRating: ScoreRating{ScoreRating: scoreApi.ScoreRating{
 					Total:         17,
 					StudentsCount: 25,
 					Rating:        8,
 					MinTotal:      10,
 					MaxTotal:      20,
-				},
+				}},
+				Attendance: makeTestAttendance(12, 2),
 			},
-			scoreApi.DisciplineScoreResult{
+			DisciplineScoreResult{
 				Discipline: scoreApi.Discipline{
 					Id:   200,
 					Name: "Гроші та лихварство",
 				},
-				ScoreRating: scoreApi.ScoreRating{
+				ScoreRating: ScoreRating{ScoreRating: scoreApi.ScoreRating{
 					Total:         12,
 					StudentsCount: 25,
 					Rating:        12,
 					MinTotal:      7,
 					MaxTotal:      17,
-				},
+				}},
+				Attendance: makeTestAttendance(10, 0),
 			},
 		}
 
@@ -129,11 +135,16 @@ func TestStorageGetDisciplineScoreResultsByStudentId(t *testing.T) {
 		scoreRatingLoader.On("load", 2026, 1, 100, 1100).Return(expectedResults[0].ScoreRating)
 		scoreRatingLoader.On("load", 2026, 2, 200, 1100).Return(expectedResults[1].ScoreRating)
 
+		attendanceLoader := NewMockAttendanceLoaderInterface(t)
+		attendanceLoader.On("load", 2026, 1, 100, 1100).Return(AttendanceByLessonType{1: expectedResults[0].Attendance})
+		attendanceLoader.On("load", 2026, 2, 200, 1100).Return(AttendanceByLessonType{1: expectedResults[1].Attendance})
+
 		storage := Storage{
 			redis:             redisClient,
 			year:              2026,
 			lessonTypes:       lessonTypes,
 			scoreRatingLoader: scoreRatingLoader,
+			attendanceLoader:  attendanceLoader,
 		}
 
 		actualResults, err := storage.getDisciplineScoreResultsByStudentId(1100)
@@ -148,46 +159,49 @@ func TestStorageGetDisciplineScoreResultsByStudentId(t *testing.T) {
 
 		lessonTypes := GetTestLessonTypes()
 
-		expectedResults := scoreApi.DisciplineScoreResults{
-			scoreApi.DisciplineScoreResult{
+		expectedResults := DisciplineScoreResults{
+			DisciplineScoreResult{
 				Discipline: scoreApi.Discipline{
 					Id:   200,
 					Name: "Капітал!",
 				},
-				ScoreRating: scoreApi.ScoreRating{
+				ScoreRating: ScoreRating{ScoreRating: scoreApi.ScoreRating{
 					Total:         17,
 					StudentsCount: 25,
 					Rating:        8,
 					MinTotal:      10,
 					MaxTotal:      20,
-				},
+				}},
+				Attendance: makeTestAttendance(8, 1),
 			},
-			scoreApi.DisciplineScoreResult{
+			DisciplineScoreResult{
 				Discipline: scoreApi.Discipline{
 					Id:   204,
 					Name: "Гроші та лихварство",
 				},
-				ScoreRating: scoreApi.ScoreRating{
+				ScoreRating: ScoreRating{ScoreRating: scoreApi.ScoreRating{
 					Total:         12,
 					StudentsCount: 25,
 					Rating:        12,
 					MinTotal:      7,
 					MaxTotal:      17,
-				},
+				}},
+				Attendance: makeTestAttendance(8, 3),
 			},
 
-			scoreApi.DisciplineScoreResult{
+			DisciplineScoreResult{
 				Discipline: scoreApi.Discipline{
 					Id:   210,
 					Name: "Іноваційно-інвестиційний менеджмент",
 				},
-				ScoreRating: scoreApi.ScoreRating{
+				ScoreRating: ScoreRating{ScoreRating: scoreApi.ScoreRating{
 					Total:         12,
 					StudentsCount: 25,
 					Rating:        12,
 					MinTotal:      7,
 					MaxTotal:      17,
-				},
+				}},
+				Attendance: makeTestAttendance(6, 0),
 			},
 		}
 
@@ -214,11 +228,17 @@ func TestStorageGetDisciplineScoreResultsByStudentId(t *testing.T) {
 		scoreRatingLoader.On("load", 2026, 2, 204, 1100).Return(expectedResults[1].ScoreRating)
 		scoreRatingLoader.On("load", 2026, 2, 210, 1100).Return(expectedResults[2].ScoreRating)
 
+		attendanceLoader := NewMockAttendanceLoaderInterface(t)
+		attendanceLoader.On("load", 2026, 2, 200, 1100).Return(AttendanceByLessonType{1: expectedResults[0].Attendance})
+		attendanceLoader.On("load", 2026, 2, 204, 1100).Return(AttendanceByLessonType{1: expectedResults[1].Attendance})
+		attendanceLoader.On("load", 2026, 2, 210, 1100).Return(AttendanceByLessonType{1: expectedResults[2].Attendance})
+
 		storage := Storage{
 			redis:             redisClient,
 			year:              2026,
 			lessonTypes:       lessonTypes,
 			scoreRatingLoader: scoreRatingLoader,
+			attendanceLoader:  attendanceLoader,
 		}
 
 		actualResults, err := storage.getDisciplineScoreResultsByStudentId(1100)
@@ -249,7 +269,7 @@ func TestStorageGetDisciplineScoreResultsByStudentId(t *testing.T) {
 
 		actualResults, err := storage.getDisciplineScoreResultsByStudentId(1100)
 
-		assert.Equal(t, scoreApi.DisciplineScoreResults{}, actualResults)
+		assert.Equal(t, DisciplineScoreResults{}, actualResults)
 		assert.NoError(t, err)
 		assert.NoError(t, redisMock.ExpectationsWereMet())
 	})
@@ -361,45 +381,52 @@ func TestGetDisciplineScoreResultByStudentId(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		lessonTypes := GetTestLessonTypes()
 
-		expectedResult := scoreApi.DisciplineScoreResult{
+		expectedResult := DisciplineScoreResult{
 			Discipline: scoreApi.Discipline{
 				Id:   199,
 				Name: "Капітал!",
 			},
-			ScoreRating: scoreApi.ScoreRating{
+			ScoreRating: ScoreRating{ScoreRating: scoreApi.ScoreRating{
 				Total:         17,
 				StudentsCount: 25,
 				Rating:        8,
 				MinTotal:      10,
 				MaxTotal:      20,
-			},
-			Scores: []scoreApi.Score{
+			}},
+			Attendance: makeTestAttendance(14, 1),
+			Scores: []Score{
 				{
-					Lesson: scoreApi.Lesson{
-						Id:   245,
-						Date: time.Date(2023, time.Month(2), 12, 0, 0, 0, 0, time.Local),
-						Type: lessonTypes[1],
+					Score: scoreApi.Score{
+						Lesson: scoreApi.Lesson{
+							Id:   245,
+							Date: time.Date(2023, time.Month(2), 12, 0, 0, 0, 0, defaultAcademicLocation),
+							Type: lessonTypes[1],
+						},
+						FirstScore:  floatPointer(4.5),
+						SecondScore: floatPointer(2),
+						IsAbsent:    false,
 					},
-					FirstScore:  floatPointer(4.5),
-					SecondScore: floatPointer(2),
-					IsAbsent:    false,
 				},
 				{
-					Lesson: scoreApi.Lesson{
-						Id:   247,
-						Date: time.Date(2023, time.Month(2), 12, 0, 0, 0, 0, time.Local),
-						Type: lessonTypes[1],
+					Score: scoreApi.Score{
+						Lesson: scoreApi.Lesson{
+							Id:   247,
+							Date: time.Date(2023, time.Month(2), 12, 0, 0, 0, 0, defaultAcademicLocation),
+							Type: lessonTypes[1],
+						},
+						FirstScore: floatPointer(1),
+						IsAbsent:   false,
 					},
-					FirstScore: floatPointer(1),
-					IsAbsent:   false,
 				},
 				{
-					Lesson: scoreApi.Lesson{
-						Id:   255,
-						Date: time.Date(2023, time.Month(2), 14, 0, 0, 0, 0, time.Local),
-						Type: lessonTypes[15],
+					Score: scoreApi.Score{
+						Lesson: scoreApi.Lesson{
+							Id:   255,
+							Date: time.Date(2023, time.Month(2), 14, 0, 0, 0, 0, defaultAcademicLocation),
+							Type: lessonTypes[15],
+						},
+						IsAbsent: true,
 					},
-					IsAbsent: true,
 				},
 			},
 		}
@@ -411,6 +438,7 @@ func TestGetDisciplineScoreResultByStudentId(t *testing.T) {
 		discipline1SemesterUpdatedAtValue := "1" + strconv.FormatInt(time.Now().Unix(), 10)
 		redisMock.ExpectGet(discipline1SemesterUpdatedAtKey).SetVal(discipline1SemesterUpdatedAtValue)
 
+		redisMock.ExpectGet("2026:student_group:1200").RedisNil()
 		redisMock.ExpectHGet("2026:discipline:199", "name").SetVal(expectedResult.Discipline.Name)
 
 		studentDisciplineScoresKey := "2026:1:scores:1200:199"
@@ -432,12 +460,17 @@ func TestGetDisciplineScoreResultByStudentId(t *testing.T) {
 		scoreRatingLoader := NewMockScoreRatingLoaderInterface(t)
 		scoreRatingLoader.On("load", 2026, 1, expectedResult.Discipline.Id, 1200).Return(expectedResult.ScoreRating)
 
+		attendanceLoader := NewMockAttendanceLoaderInterface(t)
+		attendanceLoader.On("load", 2026, 1, expectedResult.Discipline.Id, 1200).Return(AttendanceByLessonType{15: expectedResult.Attendance})
+
 		storage := Storage{
 			redis:             redisClient,
 			year:              2026,
 			lessonTypes:       lessonTypes,
 			scoreRatingLoader: scoreRatingLoader,
+			attendanceLoader:  attendanceLoader,
 		}
+		storage.groupRatingLoader = &GroupRatingLoader{storage: &storage}
 
 		actualResult, err := storage.getDisciplineScoreResultByStudentId(1200, expectedResult.Discipline.Id)
 
@@ -446,22 +479,194 @@ func TestGetDisciplineScoreResultByStudentId(t *testing.T) {
 		assert.NoError(t, redisMock.ExpectationsWereMet())
 	})
 
+	t.Run("malformed_entries", func(t *testing.T) {
+		lessonTypes := GetTestLessonTypes()
+
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.MatchExpectationsInOrder(true)
+
+		redisMock.ExpectGet("2026:discipline_semester_updated_at:199").SetVal("1" + strconv.FormatInt(time.Now().Unix(), 10))
+		redisMock.ExpectGet("2026:student_group:1200").RedisNil()
+		redisMock.ExpectHGet("2026:discipline:199", "name").SetVal("Капітал!")
+
+		redisMock.ExpectHGetAll("2026:1:scores:1200:199").SetVal(map[string]string{
+			"245:1": "4.5",
+			"2451":  "3",
+			"247:1": "abc",
+			"255:3": "1",
+		})
+
+		redisMock.ExpectHGetAll("2026:1:lessons:199").SetVal(map[string]string{
+			"245": "2302121",
+			"250": "23021",
+			"abc": "2302121",
+		})
+
+		scoreRatingLoader := NewMockScoreRatingLoaderInterface(t)
+		scoreRatingLoader.On("load", 2026, 1, 199, 1200).Return(ScoreRating{})
+
+		attendanceLoader := NewMockAttendanceLoaderInterface(t)
+		attendanceLoader.On("load", 2026, 1, 199, 1200).Return(AttendanceByLessonType{})
+
+		storage := Storage{
+			redis:             redisClient,
+			year:              2026,
+			lessonTypes:       lessonTypes,
+			scoreRatingLoader: scoreRatingLoader,
+			attendanceLoader:  attendanceLoader,
+			anomalies:         &AnomalyCounter{},
+		}
+		storage.groupRatingLoader = &GroupRatingLoader{storage: &storage}
+
+		actualResult, err := storage.getDisciplineScoreResultByStudentId(1200, 199)
+
+		assert.NoError(t, err)
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+		assert.Equal(t, []Score{
+			{
+				Score: scoreApi.Score{
+					Lesson: scoreApi.Lesson{
+						Id:   245,
+						Date: time.Date(2023, time.Month(2), 12, 0, 0, 0, 0, defaultAcademicLocation),
+						Type: lessonTypes[1],
+					},
+					FirstScore: floatPointer(4.5),
+				},
+			},
+		}, actualResult.Scores)
+
+		report := storage.getAnomalies()
+		assert.Equal(t, 5, report.Total)
+
+		kindCounts := make(map[string]int)
+		for _, kindReport := range report.Kinds {
+			kindCounts[kindReport.Kind] = kindReport.Count
+		}
+		assert.Equal(t, map[string]int{
+			ErrMalformedScoreField.Error():  2,
+			ErrMalformedScoreValue.Error():  1,
+			ErrMalformedLessonValue.Error(): 1,
+			ErrMalformedId.Error():          1,
+		}, kindCounts)
+	})
+
+	t.Run("malformed_semester_updated_at", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.ExpectGet("2026:discipline_semester_updated_at:199").SetVal("3abc")
+
+		storage := Storage{
+			redis:     redisClient,
+			year:      2026,
+			anomalies: &AnomalyCounter{},
+		}
+		storage.groupRatingLoader = &GroupRatingLoader{storage: &storage}
+
+		actualResult, err := storage.getDisciplineScoreResultByStudentId(1200, 199)
+
+		assert.NoError(t, err)
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+		assert.Equal(t, DisciplineScoreResult{}, actualResult)
+		assert.Equal(t, AnomalyReport{
+			Total: 1,
+			Kinds: []AnomalyKindReport{
+				{
+					Kind:  ErrMalformedSemesterUpdatedAt.Error(),
+					Count: 1,
+					Samples: []AnomalySample{
+						{
+							Key:   "2026:discipline_semester_updated_at:199",
+							Value: "3abc",
+							Error: `malformed discipline semester updated at "3abc": semester 1 or 2 expected`,
+						},
+					},
+				},
+			},
+		}, storage.getAnomalies())
+	})
+
+	t.Run("deleted_lessons_scores", func(t *testing.T) {
+		lessonTypes := GetTestLessonTypes()
+
+		expectedScores := []Score{
+			{
+				Score: scoreApi.Score{
+					Lesson: scoreApi.Lesson{
+						Id:   240,
+						Date: time.Date(2023, time.Month(2), 10, 0, 0, 0, 0, defaultAcademicLocation),
+						Type: lessonTypes[15],
+					},
+					FirstScore: floatPointer(3),
+				},
+				Deleted: true,
+			},
+			{
+				Score: scoreApi.Score{
+					Lesson: scoreApi.Lesson{
+						Id:   245,
+						Date: time.Date(2023, time.Month(2), 12, 0, 0, 0, 0, defaultAcademicLocation),
+						Type: lessonTypes[1],
+					},
+					FirstScore: floatPointer(4.5),
+				},
+			},
+		}
+
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.MatchExpectationsInOrder(true)
+
+		discipline1SemesterUpdatedAtValue := "1" + strconv.FormatInt(time.Now().Unix(), 10)
+		redisMock.ExpectGet("2026:discipline_semester_updated_at:199").SetVal(discipline1SemesterUpdatedAtValue)
+		redisMock.ExpectGet("2026:student_group:1200").RedisNil()
+		redisMock.ExpectHGet("2026:discipline:199", "name").SetVal("Капітал!")
+
+		redisMock.ExpectHGetAll("2026:1:scores:1200:199").SetVal(map[string]string{
+			"245:1": "4.5",
+			"240:1": "3",
+		})
+		redisMock.ExpectHGetAll("2026:1:lessons:199").SetVal(map[string]string{
+			"245": "2302121",
+		})
+		redisMock.ExpectGet("2026:1:deleted-lessons:199:240").SetVal("23021015")
+
+		scoreRatingLoader := NewMockScoreRatingLoaderInterface(t)
+		scoreRatingLoader.On("load", 2026, 1, 199, 1200).Return(ScoreRating{})
+
+		attendanceLoader := NewMockAttendanceLoaderInterface(t)
+		attendanceLoader.On("load", 2026, 1, 199, 1200).Return(AttendanceByLessonType{})
+
+		storage := Storage{
+			redis:             redisClient,
+			year:              2026,
+			lessonTypes:       lessonTypes,
+			scoreRatingLoader: scoreRatingLoader,
+			attendanceLoader:  attendanceLoader,
+		}
+		storage.groupRatingLoader = &GroupRatingLoader{storage: &storage}
+
+		actualResult, err := storage.getDisciplineScoreResultByStudentId(1200, 199)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedScores, actualResult.Scores)
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+	})
+
 	t.Run("noScores", func(t *testing.T) {
 		lessonTypes := GetTestLessonTypes()
 
-		expectedResult := scoreApi.DisciplineScoreResult{
+		expectedResult := DisciplineScoreResult{
 			Discipline: scoreApi.Discipline{
 				Id:   199,
 				Name: "Капітал!",
 			},
-			ScoreRating: scoreApi.ScoreRating{
+			ScoreRating: ScoreRating{ScoreRating: scoreApi.ScoreRating{
 				Total:         17,
 				StudentsCount: 25,
 				Rating:        8,
 				MinTotal:      10,
 				MaxTotal:      20,
-			},
-			Scores: []scoreApi.Score{},
+			}},
+			Attendance: makeTestAttendance(0, 0),
+			Scores:     []Score{},
 		}
 
 		redisClient, redisMock := redismock.NewClientMock()
@@ -471,6 +676,7 @@ func TestGetDisciplineScoreResultByStudentId(t *testing.T) {
 		discipline1SemesterUpdatedAtValue := "1" + strconv.FormatInt(time.Now().Unix(), 10)
 		redisMock.ExpectGet(discipline1SemesterUpdatedAtKey).SetVal(discipline1SemesterUpdatedAtValue)
 
+		redisMock.ExpectGet("2026:student_group:1200").RedisNil()
 		redisMock.ExpectHGet("2026:discipline:199", "name").SetVal(expectedResult.Discipline.Name)
 
 		studentDisciplineScoresKey := "2026:1:scores:1200:199"
@@ -479,12 +685,17 @@ func TestGetDisciplineScoreResultByStudentId(t *testing.T) {
 		scoreRatingLoader := NewMockScoreRatingLoaderInterface(t)
 		scoreRatingLoader.On("load", 2026, 1, expectedResult.Discipline.Id, 1200).Return(expectedResult.ScoreRating)
 
+		attendanceLoader := NewMockAttendanceLoaderInterface(t)
+		attendanceLoader.On("load", 2026, 1, expectedResult.Discipline.Id, 1200).Return(AttendanceByLessonType{15: expectedResult.Attendance})
+
 		storage := Storage{
 			redis:             redisClient,
 			year:              2026,
 			lessonTypes:       lessonTypes,
 			scoreRatingLoader: scoreRatingLoader,
+			attendanceLoader:  attendanceLoader,
 		}
+		storage.groupRatingLoader = &GroupRatingLoader{storage: &storage}
 
 		actualResult, err := storage.getDisciplineScoreResultByStudentId(1200, expectedResult.Discipline.Id)
 
@@ -510,11 +721,12 @@ func TestGetDisciplineScoreResultByStudentId(t *testing.T) {
 			lessonTypes:       lessonTypes,
 			scoreRatingLoader: NewMockScoreRatingLoaderInterface(t),
 		}
+		storage.groupRatingLoader = &GroupRatingLoader{storage: &storage}
 
 		actualResult, err := storage.getDisciplineScoreResultByStudentId(1200, disciplineId)
 
 		assert.NoError(t, err)
-		assert.Equal(t, scoreApi.DisciplineScoreResult{}, actualResult)
+		assert.Equal(t, DisciplineScoreResult{}, actualResult)
 		assert.NoError(t, redisMock.ExpectationsWereMet())
 	})
 
@@ -537,12 +749,13 @@ func TestGetDisciplineScoreResultByStudentId(t *testing.T) {
 			lessonTypes:       lessonTypes,
 			scoreRatingLoader: NewMockScoreRatingLoaderInterface(t),
 		}
+		storage.groupRatingLoader = &GroupRatingLoader{storage: &storage}
 
 		actualResult, actualErr := storage.getDisciplineScoreResultByStudentId(1200, disciplineId)
 
 		assert.Error(t, actualErr)
 		assert.Equal(t, expectedError, actualErr)
-		assert.Equal(t, scoreApi.DisciplineScoreResult{}, actualResult)
+		assert.Equal(t, DisciplineScoreResult{}, actualResult)
 
 		assert.NoError(t, redisMock.ExpectationsWereMet())
 	})
@@ -553,20 +766,22 @@ func TestGetDisciplineScore(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		lessonTypes := GetTestLessonTypes()
 
-		expectedResult := scoreApi.DisciplineScore{
+		expectedResult := DisciplineScore{
 			Discipline: scoreApi.Discipline{
 				Id:   199,
 				Name: "Капітал!",
 			},
-			Score: scoreApi.Score{
-				Lesson: scoreApi.Lesson{
-					Id:   245,
-					Date: time.Date(2023, time.Month(2), 12, 0, 0, 0, 0, time.Local),
-					Type: lessonTypes[1],
+			Score: Score{
+				Score: scoreApi.Score{
+					Lesson: scoreApi.Lesson{
+						Id:   245,
+						Date: time.Date(2023, time.Month(2), 12, 0, 0, 0, 0, defaultAcademicLocation),
+						Type: lessonTypes[1],
+					},
+					FirstScore:  floatPointer(4.5),
+					SecondScore: floatPointer(2),
+					IsAbsent:    false,
 				},
-				FirstScore:  floatPointer(4.5),
-				SecondScore: floatPointer(2),
-				IsAbsent:    false,
 			},
 		}
 
@@ -607,20 +822,22 @@ func TestGetDisciplineScore(t *testing.T) {
 	t.Run("absent", func(t *testing.T) {
 		lessonTypes := GetTestLessonTypes()
 
-		expectedResult := scoreApi.DisciplineScore{
+		expectedResult := DisciplineScore{
 			Discipline: scoreApi.Discipline{
 				Id:   199,
 				Name: "Капітал!",
 			},
-			Score: scoreApi.Score{
-				Lesson: scoreApi.Lesson{
-					Id:   245,
-					Date: time.Date(2023, time.Month(2), 12, 0, 0, 0, 0, time.Local),
-					Type: lessonTypes[1],
+			Score: Score{
+				Score: scoreApi.Score{
+					Lesson: scoreApi.Lesson{
+						Id:   245,
+						Date: time.Date(2023, time.Month(2), 12, 0, 0, 0, 0, defaultAcademicLocation),
+						Type: lessonTypes[1],
+					},
+					FirstScore:  nil,
+					SecondScore: nil,
+					IsAbsent:    true,
 				},
-				FirstScore:  nil,
-				SecondScore: nil,
-				IsAbsent:    true,
 			},
 		}
 
@@ -661,12 +878,12 @@ func TestGetDisciplineScore(t *testing.T) {
 	t.Run("deleted_lesson", func(t *testing.T) {
 		lessonTypes := GetTestLessonTypes()
 
-		expectedResult := scoreApi.DisciplineScore{
+		expectedResult := DisciplineScore{
 			Discipline: scoreApi.Discipline{
 				Id:   199,
 				Name: "Капітал!",
 			},
-			Score: scoreApi.Score{},
+			Score: Score{},
 		}
 
 		redisClient, redisMock := redismock.NewClientMock()
@@ -702,17 +919,20 @@ func TestGetDisciplineScore(t *testing.T) {
 	t.Run("deleted_score", func(t *testing.T) {
 		lessonTypes := GetTestLessonTypes()
 
-		expectedResult := scoreApi.DisciplineScore{
+		expectedResult := DisciplineScore{
 			Discipline: scoreApi.Discipline{
 				Id:   199,
 				Name: "Капітал!",
 			},
-			Score: scoreApi.Score{
-				Lesson: scoreApi.Lesson{
-					Id:   245,
-					Date: time.Date(2023, time.Month(2), 12, 0, 0, 0, 0, time.Local),
-					Type: lessonTypes[1],
+			Score: Score{
+				Score: scoreApi.Score{
+					Lesson: scoreApi.Lesson{
+						Id:   245,
+						Date: time.Date(2023, time.Month(2), 12, 0, 0, 0, 0, defaultAcademicLocation),
+						Type: lessonTypes[1],
+					},
 				},
+				Deleted: true,
 			},
 		}
 
@@ -753,12 +973,12 @@ func TestGetDisciplineScore(t *testing.T) {
 	t.Run("discipline_never_updated", func(t *testing.T) {
 		lessonTypes := GetTestLessonTypes()
 
-		expectedResult := scoreApi.DisciplineScore{
+		expectedResult := DisciplineScore{
 			Discipline: scoreApi.Discipline{
 				Id:   199,
 				Name: "Капітал!",
 			},
-			Score: scoreApi.Score{},
+			Score: Score{},
 		}
 
 		redisClient, redisMock := redismock.NewClientMock()
@@ -778,7 +998,7 @@ func TestGetDisciplineScore(t *testing.T) {
 		)
 
 		assert.NoError(t, err)
-		assert.Equal(t, scoreApi.DisciplineScore{}, actualResult)
+		assert.Equal(t, DisciplineScore{}, actualResult)
 		assert.NoError(t, redisMock.ExpectationsWereMet())
 	})
 
@@ -806,27 +1026,844 @@ func TestGetDisciplineScore(t *testing.T) {
 
 		assert.Error(t, actualErr)
 		assert.Equal(t, expectedError, actualErr)
-		assert.Equal(t, scoreApi.DisciplineScore{}, actualResult)
+		assert.Equal(t, DisciplineScore{}, actualResult)
 
 		assert.NoError(t, redisMock.ExpectationsWereMet())
 	})
 }
 
-func GetTestLessonTypes() map[int]scoreApi.LessonType {
-	return map[int]scoreApi.LessonType{
-		1: {
-			Id:        1,
-			ShortName: "ПрЗн",
-			LongName:  "Практичне зан.",
-		},
-		15: {
-			Id:        15,
-			ShortName: "МК",
-			LongName:  "Модульний контроль.",
-		},
-	}
+func TestStorageGetDisciplineDeletedLessons(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		lessonTypes := GetTestLessonTypes()
+
+		expectedResult := DisciplineDeletedLessons{
+			Discipline: scoreApi.Discipline{
+				Id:   199,
+				Name: "Капітал!",
+			},
+			Lessons: []Score{
+				{
+					// score of lesson which is missing in both lessons and deleted lessons
+					Score: scoreApi.Score{
+						Lesson: scoreApi.Lesson{
+							Id:   230,
+							Type: lessonTypes[0],
+						},
+						IsAbsent: true,
+					},
+					Deleted: true,
+				},
+				{
+					Score: scoreApi.Score{
+						Lesson: scoreApi.Lesson{
+							Id:   240,
+							Date: time.Date(2023, time.Month(2), 10, 0, 0, 0, 0, defaultAcademicLocation),
+							Type: lessonTypes[15],
+						},
+						FirstScore: floatPointer(3),
+					},
+					Deleted: true,
+				},
+				{
+					Score: scoreApi.Score{
+						Lesson: scoreApi.Lesson{
+							Id:   241,
+							Date: time.Date(2023, time.Month(2), 11, 0, 0, 0, 0, defaultAcademicLocation),
+							Type: lessonTypes[1],
+						},
+					},
+					Deleted: true,
+				},
+			},
+		}
+
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.MatchExpectationsInOrder(true)
+
+		discipline1SemesterUpdatedAtValue := "1" + strconv.FormatInt(time.Now().Unix(), 10)
+		redisMock.ExpectGet("2026:discipline_semester_updated_at:199").SetVal(discipline1SemesterUpdatedAtValue)
+
+		redisMock.ExpectHGetAll("2026:1:deleted-lessons-index:199").SetVal(map[string]string{
+			"240": "23021015",
+			"241": "2302111",
+		})
+
+		redisMock.ExpectHGetAll("2026:1:scores:1200:199").SetVal(map[string]string{
+			"245:1": "4.5",
+			"240:1": "3",
+			"230:1": strconv.FormatFloat(float64(IsAbsentScoreValue), 'f', -1, 64),
+		})
+		redisMock.ExpectHGetAll("2026:1:lessons:199").SetVal(map[string]string{
+			"245": "2302121",
+		})
+		redisMock.ExpectHGet("2026:discipline:199", "name").SetVal(expectedResult.Discipline.Name)
+
+		storage := Storage{
+			redis:          redisClient,
+			year:           2026,
+			lessonTypes:    lessonTypes,
+			deletedLessons: NewDeletedLessonsCache(DeletedLessonsCacheTTL),
+		}
+
+		actualResult, err := storage.getDisciplineDeletedLessons(1200, 199)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedResult, actualResult)
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+
+		// deleted lessons are taken from the cache without loading the index
+		redisMock.ExpectGet("2026:discipline_semester_updated_at:199").SetVal(discipline1SemesterUpdatedAtValue)
+		redisMock.ExpectHGetAll("2026:1:scores:1200:199").SetVal(map[string]string{
+			"245:1": "4.5",
+			"240:1": "3",
+			"230:1": strconv.FormatFloat(float64(IsAbsentScoreValue), 'f', -1, 64),
+		})
+		redisMock.ExpectHGetAll("2026:1:lessons:199").SetVal(map[string]string{
+			"245": "2302121",
+		})
+		redisMock.ExpectHGet("2026:discipline:199", "name").SetVal(expectedResult.Discipline.Name)
+
+		actualResult, err = storage.getDisciplineDeletedLessons(1200, 199)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedResult, actualResult)
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+	})
+
+	t.Run("discipline_never_updated", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.ExpectGet("2026:discipline_semester_updated_at:850").RedisNil()
+
+		storage := Storage{
+			redis:       redisClient,
+			year:        2026,
+			lessonTypes: GetTestLessonTypes(),
+		}
+
+		actualResult, err := storage.getDisciplineDeletedLessons(1200, 850)
+
+		assert.NoError(t, err)
+		assert.Equal(t, DisciplineDeletedLessons{}, actualResult)
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+	})
+
+	t.Run("redis_error", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.MatchExpectationsInOrder(true)
+
+		discipline1SemesterUpdatedAtValue := "1" + strconv.FormatInt(time.Now().Unix(), 10)
+		redisMock.ExpectGet("2026:discipline_semester_updated_at:199").SetVal(discipline1SemesterUpdatedAtValue)
+		redisMock.ExpectHGetAll("2026:1:deleted-lessons-index:199").SetErr(assert.AnError)
+
+		storage := Storage{
+			redis:       redisClient,
+			year:        2026,
+			lessonTypes: GetTestLessonTypes(),
+		}
+
+		actualResult, err := storage.getDisciplineDeletedLessons(1200, 199)
+
+		assert.Equal(t, assert.AnError, err)
+		assert.Equal(t, DisciplineDeletedLessons{}, actualResult)
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+	})
 }
 
-func floatPointer(value float32) *float32 {
-	return &value
+func TestStorageGetDisciplineTimeline(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		lessonTypes := GetTestLessonTypes()
+
+		firstDate := time.Date(2023, time.Month(2), 12, 0, 0, 0, 0, defaultAcademicLocation)
+		secondDate := time.Date(2023, time.Month(2), 14, 0, 0, 0, 0, defaultAcademicLocation)
+
+		expectedResult := DisciplineTimeline{
+			Discipline: scoreApi.Discipline{
+				Id:   199,
+				Name: "Капітал!",
+			},
+			LessonTypes: []scoreApi.LessonType{lessonTypes[1], lessonTypes[15]},
+			Points: []TimelinePoint{
+				{
+					Date:             firstDate,
+					Total:            6.5,
+					LessonTypeTotals: map[int]float32{1: 6.5, 15: 0},
+					AverageTotal:     2.5,
+				},
+				{
+					Date:             secondDate,
+					Total:            16.5,
+					LessonTypeTotals: map[int]float32{1: 6.5, 15: 10},
+					AverageTotal:     5,
+				},
+			},
+		}
+
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.MatchExpectationsInOrder(true)
+
+		discipline1SemesterUpdatedAtValue := "1" + strconv.FormatInt(time.Now().Unix(), 10)
+		redisMock.ExpectGet("2026:discipline_semester_updated_at:199").SetVal(discipline1SemesterUpdatedAtValue)
+
+		lessons := map[string]string{
+			"245": "2302121",
+			"255": "23021415",
+		}
+
+		redisMock.ExpectHGetAll("2026:1:scores:1200:199").SetVal(map[string]string{
+			"245:1": "4.5",
+			"245:2": "2",
+			"255:1": "10",
+		})
+		redisMock.ExpectHGetAll("2026:1:lessons:199").SetVal(lessons)
+		redisMock.ExpectHGet("2026:discipline:199", "name").SetVal(expectedResult.Discipline.Name)
+
+		redisMock.ExpectZRange("2026:1:totals:199", 0, -1).SetVal([]string{"1200", "1300", "1400"})
+		redisMock.ExpectHGetAll("2026:1:lessons:199").SetVal(lessons)
+		redisMock.ExpectHGetAll("2026:1:scores:1300:199").SetVal(map[string]string{
+			"245:1": "5",
+			"255:1": "5",
+		})
+		redisMock.ExpectHGetAll("2026:1:scores:1400:199").SetVal(map[string]string{
+			"255:2": strconv.FormatFloat(float64(IsAbsentScoreValue), 'f', -1, 64),
+		})
+
+		storage := Storage{
+			redis:       redisClient,
+			year:        2026,
+			lessonTypes: lessonTypes,
+		}
+
+		actualResult, err := storage.getDisciplineTimeline(1200, 199)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedResult, actualResult)
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+	})
+
+	t.Run("discipline_never_updated", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.MatchExpectationsInOrder(true)
+
+		redisMock.ExpectGet("2026:discipline_semester_updated_at:850").RedisNil()
+
+		storage := Storage{
+			redis:       redisClient,
+			year:        2026,
+			lessonTypes: GetTestLessonTypes(),
+		}
+
+		actualResult, err := storage.getDisciplineTimeline(1200, 850)
+
+		assert.NoError(t, err)
+		assert.Equal(t, DisciplineTimeline{}, actualResult)
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+	})
+
+	t.Run("redis_error", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.MatchExpectationsInOrder(true)
+
+		redisMock.ExpectGet("2026:discipline_semester_updated_at:850").SetErr(assert.AnError)
+
+		storage := Storage{
+			redis:       redisClient,
+			year:        2026,
+			lessonTypes: GetTestLessonTypes(),
+		}
+
+		actualResult, err := storage.getDisciplineTimeline(1200, 850)
+
+		assert.Equal(t, assert.AnError, err)
+		assert.Equal(t, DisciplineTimeline{}, actualResult)
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+	})
+}
+
+func TestStorageGetStudentTranscript(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		lessonTypes := GetTestLessonTypes()
+
+		expectedTranscript := Transcript{
+			StudentId: 1100,
+			Year:      2026,
+			Semester:  2,
+			Disciplines: DisciplineScoreResults{
+				{
+					Discipline: scoreApi.Discipline{
+						Id:   200,
+						Name: "Капітал!",
+					},
+					ScoreRating: ScoreRating{ScoreRating: scoreApi.ScoreRating{
+						Total:         4.5,
+						StudentsCount: 25,
+						Rating:        8,
+						MinTotal:      1,
+						MaxTotal:      20,
+					}},
+					Attendance: makeTestAttendance(2, 0),
+					Scores: []Score{
+						{
+							Score: scoreApi.Score{
+								Lesson: scoreApi.Lesson{
+									Id:   245,
+									Date: time.Date(2023, time.Month(2), 12, 0, 0, 0, 0, defaultAcademicLocation),
+									Type: lessonTypes[1],
+								},
+								FirstScore: floatPointer(4.5),
+							},
+						},
+					},
+				},
+			},
+		}
+
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.MatchExpectationsInOrder(false)
+
+		redisMock.ExpectSMembers("2026:1:student_disciplines:1100").RedisNil()
+		redisMock.ExpectSMembers("2026:2:student_disciplines:1100").SetVal([]string{"200"})
+		redisMock.ExpectHGet("2026:discipline:200", "name").SetVal("Капітал!")
+		redisMock.ExpectHGetAll("2026:2:scores:1100:200").SetVal(map[string]string{
+			"245:1": "4.5",
+		})
+		redisMock.ExpectHGetAll("2026:2:lessons:200").SetVal(map[string]string{
+			"245": "2302121",
+			"246": "2302131",
+		})
+
+		scoreRatingLoader := NewMockScoreRatingLoaderInterface(t)
+		scoreRatingLoader.On("load", 2026, 2, 200, 1100).Return(expectedTranscript.Disciplines[0].ScoreRating)
+
+		attendanceLoader := NewMockAttendanceLoaderInterface(t)
+		attendanceLoader.On("load", 2026, 2, 200, 1100).Return(AttendanceByLessonType{
+			1: expectedTranscript.Disciplines[0].Attendance,
+		})
+
+		storage := Storage{
+			redis:             redisClient,
+			year:              2026,
+			lessonTypes:       lessonTypes,
+			scoreRatingLoader: scoreRatingLoader,
+			attendanceLoader:  attendanceLoader,
+		}
+
+		actualTranscript, err := storage.getStudentTranscript(1100)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedTranscript, actualTranscript)
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+	})
+
+	t.Run("redis_error", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.ExpectSMembers("2026:1:student_disciplines:1100").SetErr(assert.AnError)
+
+		storage := Storage{
+			redis:       redisClient,
+			year:        2026,
+			lessonTypes: GetTestLessonTypes(),
+		}
+
+		actualTranscript, err := storage.getStudentTranscript(1100)
+
+		assert.Equal(t, assert.AnError, err)
+		assert.Equal(t, Transcript{}, actualTranscript)
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+	})
+}
+
+func TestStorageGetStudentLessons(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		lessonTypes := GetTestLessonTypes()
+
+		expectedLessons := []DisciplineLessons{
+			{
+				Year:     2026,
+				Semester: 2,
+				Discipline: scoreApi.Discipline{
+					Id:   200,
+					Name: "Капітал!",
+				},
+				Lessons: []Score{
+					{
+						Score: scoreApi.Score{
+							Lesson: scoreApi.Lesson{
+								Id:   245,
+								Date: time.Date(2023, time.Month(2), 12, 0, 0, 0, 0, defaultAcademicLocation),
+								Type: lessonTypes[1],
+							},
+							FirstScore: floatPointer(4.5),
+						},
+					},
+					{
+						Score: scoreApi.Score{
+							Lesson: scoreApi.Lesson{
+								Id:   246,
+								Date: time.Date(2023, time.Month(2), 13, 0, 0, 0, 0, defaultAcademicLocation),
+								Type: lessonTypes[15],
+							},
+						},
+					},
+				},
+			},
+		}
+
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.MatchExpectationsInOrder(false)
+
+		redisMock.ExpectSMembers("2026:1:student_disciplines:1100").RedisNil()
+		redisMock.ExpectSMembers("2026:2:student_disciplines:1100").SetVal([]string{"200"})
+		redisMock.ExpectHGet("2026:discipline:200", "name").SetVal("Капітал!")
+		redisMock.ExpectHGetAll("2026:2:lessons:200").SetVal(map[string]string{
+			"245": "2302121",
+			"246": "23021315",
+		})
+		redisMock.ExpectHGetAll("2026:2:scores:1100:200").SetVal(map[string]string{
+			"245:1": "4.5",
+			"199:1": "1", // score of deleted lesson
+		})
+
+		storage := Storage{
+			redis:       redisClient,
+			year:        2026,
+			lessonTypes: lessonTypes,
+		}
+
+		actualLessons, err := storage.getStudentLessons(1100)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedLessons, actualLessons)
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+	})
+
+	t.Run("no_lessons", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.MatchExpectationsInOrder(false)
+
+		redisMock.ExpectSMembers("2026:1:student_disciplines:1100").RedisNil()
+		redisMock.ExpectSMembers("2026:2:student_disciplines:1100").SetVal([]string{"200"})
+		redisMock.ExpectHGet("2026:discipline:200", "name").SetVal("Капітал!")
+		redisMock.ExpectHGetAll("2026:2:lessons:200").RedisNil()
+
+		storage := Storage{
+			redis:       redisClient,
+			year:        2026,
+			lessonTypes: GetTestLessonTypes(),
+		}
+
+		actualLessons, err := storage.getStudentLessons(1100)
+
+		assert.NoError(t, err)
+		assert.Len(t, actualLessons, 1)
+		assert.Empty(t, actualLessons[0].Lessons)
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+	})
+
+	t.Run("redis_error", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.ExpectSMembers("2026:1:student_disciplines:1100").SetErr(assert.AnError)
+
+		storage := Storage{
+			redis:       redisClient,
+			year:        2026,
+			lessonTypes: GetTestLessonTypes(),
+		}
+
+		actualLessons, err := storage.getStudentLessons(1100)
+
+		assert.Equal(t, assert.AnError, err)
+		assert.Nil(t, actualLessons)
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+	})
+}
+
+func TestStorageGetStudentAttendance(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		lessonTypes := GetTestLessonTypes()
+
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.MatchExpectationsInOrder(true)
+
+		redisMock.ExpectSMembers("2026:1:student_disciplines:1100").SetVal([]string{"100", "110"})
+		redisMock.ExpectSMembers("2026:2:student_disciplines:1100").RedisNil()
+
+		attendanceLoader := NewMockAttendanceLoaderInterface(t)
+		attendanceLoader.On("load", 2026, 1, 100, 1100).Return(AttendanceByLessonType{
+			1:  makeTestAttendance(6, 2),
+			15: makeTestAttendance(1, 0),
+		})
+		attendanceLoader.On("load", 2026, 1, 110, 1100).Return(AttendanceByLessonType{
+			1: makeTestAttendance(3, 0),
+		})
+
+		storage := Storage{
+			redis:            redisClient,
+			year:             2026,
+			lessonTypes:      lessonTypes,
+			attendanceLoader: attendanceLoader,
+		}
+
+		expectedLessonTypeAttendance := makeTestAttendance(6, 2)
+		expectedLessonTypeAttendance.merge(makeTestAttendance(3, 0))
+
+		actualResult, err := storage.getStudentAttendance(1100)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 10, actualResult.TotalLessons)
+		assert.Equal(t, 2, actualResult.Absent)
+		assert.Equal(t, 8, actualResult.Attended)
+		assert.Equal(t, float32(80), actualResult.Percentage)
+		assert.Len(t, actualResult.AbsentDates, 2)
+		assert.Equal(t, []LessonTypeAttendance{
+			{
+				LessonType: lessonTypes[1],
+				Attendance: expectedLessonTypeAttendance,
+			},
+			{
+				LessonType: lessonTypes[15],
+				Attendance: makeTestAttendance(1, 0),
+			},
+		}, actualResult.LessonTypes)
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+	})
+
+	t.Run("redis_error", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.MatchExpectationsInOrder(true)
+
+		redisMock.ExpectSMembers("2026:1:student_disciplines:1100").SetErr(assert.AnError)
+
+		storage := Storage{
+			redis:            redisClient,
+			year:             2026,
+			lessonTypes:      GetTestLessonTypes(),
+			attendanceLoader: NewMockAttendanceLoaderInterface(t),
+		}
+
+		actualResult, err := storage.getStudentAttendance(1100)
+
+		assert.Equal(t, assert.AnError, err)
+		assert.Equal(t, StudentAttendance{}, actualResult)
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+	})
+}
+
+func GetTestLessonTypes() map[int]scoreApi.LessonType {
+	return map[int]scoreApi.LessonType{
+		1: {
+			Id:        1,
+			ShortName: "ПрЗн",
+			LongName:  "Практичне зан.",
+		},
+		15: {
+			Id:        15,
+			ShortName: "МК",
+			LongName:  "Модульний контроль.",
+		},
+	}
+}
+
+func makeTestAttendance(totalLessons int, absent int) Attendance {
+	attendance := NewAttendance()
+	for i := 0; i < totalLessons; i++ {
+		lessonAttendance := Attendance{TotalLessons: 1}
+		if i < absent {
+			lessonAttendance.Absent = 1
+			lessonAttendance.AbsentDates = LessonDates{
+				{Time: time.Date(2023, time.Month(2), 12+i, 0, 0, 0, 0, defaultAcademicLocation)},
+			}
+		}
+		attendance.merge(lessonAttendance)
+	}
+
+	return attendance
+}
+
+func floatPointer(value float32) *float32 {
+	return &value
+}
+
+func TestStorageGetDisciplineRating(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.MatchExpectationsInOrder(true)
+
+		redisMock.ExpectGet("2026:discipline_semester_updated_at:199").SetVal("21676152800")
+		redisMock.ExpectZRevRangeWithScores("2026:2:totals:199", 0, -1).SetVal([]redis.Z{
+			{Member: "1200", Score: 7.5},
+			{Member: "bad", Score: 7},
+			{Member: "1300", Score: 5},
+			{Member: "1400", Score: 5},
+			{Member: "1500", Score: 0},
+		})
+		redisMock.ExpectHGet("2026:discipline:199", "name").SetVal("Капітал!")
+
+		storage := Storage{
+			redis:     redisClient,
+			year:      2026,
+			anomalies: &AnomalyCounter{},
+		}
+
+		actualRating, err := storage.getDisciplineRating(199)
+
+		assert.NoError(t, err)
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+		assert.Equal(t, DisciplineRating{
+			Discipline: scoreApi.Discipline{
+				Id:   199,
+				Name: "Капітал!",
+			},
+			Year:     2026,
+			Semester: 2,
+			Students: []StudentTotal{
+				{StudentId: 1200, Total: 7.5, Rating: 1},
+				{StudentId: 1300, Total: 5, Rating: 2},
+				{StudentId: 1400, Total: 5, Rating: 2},
+				{StudentId: 1500, Total: 0, Rating: 4},
+			},
+		}, actualRating)
+		assert.Equal(t, 1, storage.getAnomalies().Total)
+	})
+
+	t.Run("not_exists", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.ExpectGet("2026:discipline_semester_updated_at:199").RedisNil()
+
+		storage := Storage{
+			redis: redisClient,
+			year:  2026,
+		}
+
+		actualRating, err := storage.getDisciplineRating(199)
+
+		assert.NoError(t, err)
+		assert.Empty(t, actualRating)
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+	})
+
+	t.Run("error", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.ExpectGet("2026:discipline_semester_updated_at:199").SetVal("21676152800")
+		redisMock.ExpectZRevRangeWithScores("2026:2:totals:199", 0, -1).SetErr(assert.AnError)
+
+		storage := Storage{
+			redis: redisClient,
+			year:  2026,
+		}
+
+		_, err := storage.getDisciplineRating(199)
+
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+}
+
+func TestStorageGetDisciplineWithLessons(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		lessonTypes := GetTestLessonTypes()
+
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.MatchExpectationsInOrder(true)
+
+		redisMock.ExpectGet("2026:discipline_semester_updated_at:199").SetVal("21676152800")
+		redisMock.ExpectHGet("2026:discipline:199", "name").SetVal("Капітал!")
+		redisMock.ExpectHGetAll("2026:2:lessons:199").SetVal(map[string]string{
+			"247": "2302131",
+			"245": "2302121",
+		})
+
+		storage := Storage{
+			redis:       redisClient,
+			year:        2026,
+			lessonTypes: lessonTypes,
+			anomalies:   &AnomalyCounter{},
+		}
+
+		actualLessons, err := storage.getDisciplineWithLessons(199)
+
+		assert.NoError(t, err)
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+		assert.Equal(t, DisciplineLessons{
+			Year:     2026,
+			Semester: 2,
+			Discipline: scoreApi.Discipline{
+				Id:   199,
+				Name: "Капітал!",
+			},
+			Lessons: []Score{
+				{
+					Score: scoreApi.Score{
+						Lesson: scoreApi.Lesson{
+							Id:   245,
+							Date: time.Date(2023, time.Month(2), 12, 0, 0, 0, 0, defaultAcademicLocation),
+							Type: lessonTypes[1],
+						},
+					},
+				},
+				{
+					Score: scoreApi.Score{
+						Lesson: scoreApi.Lesson{
+							Id:   247,
+							Date: time.Date(2023, time.Month(2), 13, 0, 0, 0, 0, defaultAcademicLocation),
+							Type: lessonTypes[1],
+						},
+					},
+				},
+			},
+		}, actualLessons)
+	})
+
+	t.Run("not_exists", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.ExpectGet("2026:discipline_semester_updated_at:199").RedisNil()
+
+		storage := Storage{
+			redis: redisClient,
+			year:  2026,
+		}
+
+		actualLessons, err := storage.getDisciplineWithLessons(199)
+
+		assert.NoError(t, err)
+		assert.Empty(t, actualLessons)
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+	})
+
+	t.Run("error", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.ExpectGet("2026:discipline_semester_updated_at:199").SetErr(assert.AnError)
+
+		storage := Storage{
+			redis: redisClient,
+			year:  2026,
+		}
+
+		_, err := storage.getDisciplineWithLessons(199)
+
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+}
+
+func TestStorageStudentExists(t *testing.T) {
+	keys := []string{
+		"2023:student:1200",
+		"2022:1:student_disciplines:1200",
+		"2022:2:student_disciplines:1200",
+		"2023:1:student_disciplines:1200",
+		"2023:2:student_disciplines:1200",
+	}
+
+	t.Run("exists", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.ExpectExists(keys...).SetVal(1)
+
+		storage := Storage{redis: redisClient, year: 2023}
+		exists, err := storage.studentExists(1200)
+
+		assert.NoError(t, err)
+		assert.True(t, exists)
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+	})
+
+	t.Run("not_exists", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.ExpectExists(keys...).SetVal(0)
+
+		storage := Storage{redis: redisClient, year: 2023}
+		exists, err := storage.studentExists(1200)
+
+		assert.NoError(t, err)
+		assert.False(t, exists)
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+	})
+
+	t.Run("redis_error", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.ExpectExists(keys...).SetErr(assert.AnError)
+
+		storage := Storage{redis: redisClient, year: 2023}
+		exists, err := storage.studentExists(1200)
+
+		assert.Equal(t, assert.AnError, err)
+		assert.False(t, exists)
+	})
+}
+
+func TestStorageGetStudentProfile(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.MatchExpectationsInOrder(true)
+
+		redisMock.ExpectHGetAll("2023:student:1200").SetVal(map[string]string{
+			"name":       "Іван Франко",
+			"group":      "ЕК-201",
+			"faculty":    "Факультет економіки та управління",
+			"study_form": "денна",
+			"unknown":    "value",
+		})
+		redisMock.ExpectExists("2022:1:student_disciplines:1200").SetVal(0)
+		redisMock.ExpectExists("2022:2:student_disciplines:1200").SetVal(1)
+		redisMock.ExpectExists("2023:1:student_disciplines:1200").SetVal(1)
+		redisMock.ExpectExists("2023:2:student_disciplines:1200").SetVal(0)
+		redisMock.ExpectSMembers("2023:1:student_disciplines:1200").SetVal([]string{"199", "200"})
+		redisMock.ExpectSMembers("2023:2:student_disciplines:1200").SetVal([]string{})
+
+		storage := Storage{
+			redis: redisClient,
+			year:  2023,
+		}
+
+		profile, err := storage.getStudentProfile(1200)
+
+		assert.NoError(t, err)
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+		assert.Equal(t, StudentProfile{
+			Id:        1200,
+			Name:      "Іван Франко",
+			Group:     "ЕК-201",
+			Faculty:   "Факультет економіки та управління",
+			StudyForm: "денна",
+			Semesters: []YearSemester{
+				{Year: 2022, Semester: 2},
+				{Year: 2023, Semester: 1},
+			},
+			DisciplinesCount: 2,
+		}, profile)
+	})
+
+	t.Run("not_exists", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.MatchExpectationsInOrder(true)
+
+		redisMock.ExpectHGetAll("2022:student:1200").SetVal(map[string]string{})
+		redisMock.ExpectExists("2022:1:student_disciplines:1200").SetVal(0)
+		redisMock.ExpectExists("2022:2:student_disciplines:1200").SetVal(0)
+		redisMock.ExpectSMembers("2022:1:student_disciplines:1200").SetVal([]string{})
+		redisMock.ExpectSMembers("2022:2:student_disciplines:1200").SetVal([]string{})
+
+		storage := Storage{
+			redis: redisClient,
+			year:  2022,
+		}
+
+		profile, err := storage.getStudentProfile(1200)
+
+		assert.NoError(t, err)
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+		assert.Empty(t, profile)
+	})
+
+	t.Run("redis_error", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.ExpectHGetAll("2022:student:1200").SetVal(map[string]string{})
+		redisMock.ExpectExists("2022:1:student_disciplines:1200").SetErr(assert.AnError)
+
+		storage := Storage{
+			redis: redisClient,
+			year:  2022,
+		}
+
+		_, err := storage.getStudentProfile(1200)
+
+		assert.ErrorIs(t, err, assert.AnError)
+	})
 }
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"strconv"
+	"time"
+)
+
+// StudentChangeIndex records lessons of detected score changes to the per-student index of `/changes` endpoint.
+// Changes older than retention are removed on every write.
+type StudentChangeIndex struct {
+	retention time.Duration
+}
+
+func (index *StudentChangeIndex) notify(pipe redis.Pipeliner, event ScoreChangeEvent) error {
+	members := make([]redis.Z, 0, len(event.Changes))
+	indexedLessons := make(map[int]bool, len(event.Changes))
+	for _, change := range event.Changes {
+		// total change without lesson changes means rating recalculation, not a new score of the student
+		if change.LessonId == 0 || indexedLessons[change.LessonId] {
+			continue
+		}
+		indexedLessons[change.LessonId] = true
+
+		members = append(members, redis.Z{
+			Score:  float64(event.DetectedAt.UnixMilli()),
+			Member: encodeStudentChangeMember(event.Semester, event.DisciplineId, change.LessonId),
+		})
+	}
+
+	if len(members) == 0 {
+		return nil
+	}
+
+	ctx := context.Background()
+	key := fmt.Sprintf(studentChangesKey, event.Year, event.StudentId)
+	expiredBefore := event.DetectedAt.Add(-index.retention).UnixMilli()
+
+	pipe.ZAdd(ctx, key, members...)
+	pipe.ZRemRangeByScore(ctx, key, "-inf", "("+strconv.FormatInt(expiredBefore, 10))
+	pipe.Expire(ctx, key, index.retention)
+
+	return nil
+}
+
+func NewStudentChangeIndex(retention time.Duration) *StudentChangeIndex {
+	return &StudentChangeIndex{
+		retention: retention,
+	}
+}
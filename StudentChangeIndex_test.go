@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"github.com/go-redis/redismock/v9"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestStudentChangeIndexNotify(t *testing.T) {
+	detectedAt := time.Date(2026, time.Month(3), 15, 12, 0, 0, 0, time.UTC)
+
+	t.Run("success", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.MatchExpectationsInOrder(true)
+		redisMock.ExpectTxPipeline()
+		redisMock.ExpectZAdd(
+			"2026:student_changes:1200",
+			redis.Z{Score: float64(detectedAt.UnixMilli()), Member: "2:199:245"},
+			redis.Z{Score: float64(detectedAt.UnixMilli()), Member: "2:199:246"},
+		).SetVal(2)
+		redisMock.ExpectZRemRangeByScore(
+			"2026:student_changes:1200", "-inf",
+			"("+strconv.FormatInt(detectedAt.Add(-time.Hour*24*7).UnixMilli(), 10),
+		).SetVal(0)
+		redisMock.ExpectExpire("2026:student_changes:1200", time.Hour*24*7).SetVal(true)
+		redisMock.ExpectTxPipelineExec()
+
+		pipe := redisClient.TxPipeline()
+		index := NewStudentChangeIndex(time.Hour * 24 * 7)
+		err := index.notify(pipe, ScoreChangeEvent{
+			Year:         2026,
+			Semester:     2,
+			StudentId:    1200,
+			DisciplineId: 199,
+			Changes: []ScoreChange{
+				{Type: ScoreChangeNewScore, LessonId: 245, Half: 1, Value: floatPointer(4)},
+				{Type: ScoreChangeNewScore, LessonId: 245, Half: 2, Value: floatPointer(2)},
+				{Type: ScoreChangeNewAbsence, LessonId: 246, Half: 1},
+			},
+			DetectedAt: detectedAt,
+		})
+
+		assert.NoError(t, err)
+		_, err = pipe.Exec(context.Background())
+		assert.NoError(t, err)
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+	})
+
+	t.Run("total_change", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+
+		pipe := redisClient.TxPipeline()
+		index := NewStudentChangeIndex(time.Hour * 24 * 7)
+		err := index.notify(pipe, ScoreChangeEvent{
+			Year:         2026,
+			Semester:     2,
+			StudentId:    1200,
+			DisciplineId: 199,
+			Changes:      []ScoreChange{{Type: ScoreChangeTotal, Value: floatPointer(7.5)}},
+			DetectedAt:   detectedAt,
+		})
+
+		assert.NoError(t, err)
+		_, err = pipe.Exec(context.Background())
+		assert.NoError(t, err)
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+	})
+}
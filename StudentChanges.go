@@ -0,0 +1,79 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	scoreApi "github.com/kneu-messenger-pigeon/score-api"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// studentChangesKey - zset of `{semester}:{disciplineId}:{lessonId}` members scored by unix milliseconds
+// of the last detected change of the lesson scores
+const studentChangesKey = "%d:student_changes:%d"
+
+const DefaultStudentChangesRetentionDays = 14
+
+// StudentChangesCursorLag - changes are scored by clocks of different instances and written after detection,
+// so cursor never passes now minus this lag and late written changes are returned by the next request
+const StudentChangesCursorLag = time.Minute
+
+var ErrWrongStudentChangeMember = errors.New("wrong student change member")
+
+// StudentChanges - disciplines with lessons which scores changed after Since.
+// Cursor should be passed as `since` of the next request, both are unix milliseconds.
+// Changes of the last StudentChangesCursorLag are returned again by the next request.
+type StudentChanges struct {
+	Since       int64               `json:"since"`
+	Cursor      int64               `json:"cursor"`
+	Disciplines []DisciplineChanges `json:"disciplines"`
+}
+
+type DisciplineChanges struct {
+	Discipline  scoreApi.Discipline `json:"discipline"`
+	ScoreRating ScoreRating         `json:"scoreRating"`
+	Scores      []Score             `json:"scores"`
+}
+
+func encodeStudentChangeMember(semester int, disciplineId int, lessonId int) string {
+	return fmt.Sprintf("%d:%d:%d", semester, disciplineId, lessonId)
+}
+
+func parseStudentChangeMember(member string) (discipline DisciplineSemester, lessonId int, err error) {
+	parts := strings.Split(member, ":")
+	if len(parts) != 3 {
+		return DisciplineSemester{}, 0, ErrWrongStudentChangeMember
+	}
+
+	discipline.Semester, _ = strconv.Atoi(parts[0])
+	discipline.DisciplineId, _ = strconv.Atoi(parts[1])
+	lessonId, _ = strconv.Atoi(parts[2])
+	if (discipline.Semester != 1 && discipline.Semester != 2) || discipline.DisciplineId <= 0 || lessonId <= 0 {
+		return DisciplineSemester{}, 0, ErrWrongStudentChangeMember
+	}
+
+	return discipline, lessonId, nil
+}
+
+// parseChangesSince reads required `since` query parameter as unix milliseconds or RFC 3339 time
+func parseChangesSince(c *gin.Context) (time.Time, error) {
+	sinceString := c.Query("since")
+	if milliseconds, err := strconv.ParseInt(sinceString, 10, 64); err == nil && milliseconds >= 0 {
+		return time.UnixMilli(milliseconds), nil
+	}
+
+	since, err := time.Parse(time.RFC3339, sinceString)
+	if err != nil {
+		return time.Time{}, MessageError{Format: messageIncorrectParameter, Args: []interface{}{"since", sinceString}}
+	}
+
+	return since, nil
+}
+
+// isChangesSinceExpired reports that changes before since could be already removed from the index by retention,
+// so the client should reload all scores instead of incremental sync
+func isChangesSinceExpired(since time.Time, now time.Time, retention time.Duration) bool {
+	return since.Before(now.Add(-retention))
+}
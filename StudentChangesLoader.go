@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	scoreApi "github.com/kneu-messenger-pigeon/score-api"
+	"github.com/redis/go-redis/v9"
+	"strconv"
+	"time"
+)
+
+type StudentChangesLoaderInterface interface {
+	getStudentChanges(studentId int, since time.Time) (StudentChanges, error)
+}
+
+// StudentChangesLoader reads the change index written by StudentChangeIndex
+type StudentChangesLoader struct {
+	storage *Storage
+}
+
+// getStudentChanges returns lessons from the change index of the current year changed after since,
+// disciplines are ordered by the first change
+func (loader *StudentChangesLoader) getStudentChanges(studentId int, since time.Time) (StudentChanges, error) {
+	changesKey := fmt.Sprintf(studentChangesKey, loader.storage.year, studentId)
+	entries, err := loader.storage.redis.ZRangeByScoreWithScores(context.Background(), changesKey, &redis.ZRangeBy{
+		Min: "(" + strconv.FormatInt(since.UnixMilli(), 10),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		return StudentChanges{}, err
+	}
+
+	changes := StudentChanges{
+		Since:       since.UnixMilli(),
+		Cursor:      since.UnixMilli(),
+		Disciplines: make([]DisciplineChanges, 0),
+	}
+	disciplineIndexes := make(map[DisciplineSemester]int)
+
+	for _, entry := range entries {
+		member, _ := entry.Member.(string)
+		discipline, lessonId, err := parseStudentChangeMember(member)
+		if err != nil {
+			loader.storage.anomalies.report(changesKey, member, err)
+			continue
+		}
+		changes.Cursor = max(changes.Cursor, int64(entry.Score))
+
+		score := loader.storage.getScore(discipline.Semester, discipline.DisciplineId, studentId, lessonId)
+		if score.Lesson.Id == 0 {
+			continue
+		}
+
+		index, exists := disciplineIndexes[discipline]
+		if !exists {
+			index = len(changes.Disciplines)
+			disciplineIndexes[discipline] = index
+			changes.Disciplines = append(changes.Disciplines, DisciplineChanges{
+				Discipline: scoreApi.Discipline{
+					Id:   discipline.DisciplineId,
+					Name: loader.storage.getDisciplineName(discipline.DisciplineId),
+				},
+				ScoreRating: loader.storage.scoreRatingLoader.load(
+					loader.storage.year, discipline.Semester, discipline.DisciplineId, studentId,
+				),
+			})
+		}
+		changes.Disciplines[index].Scores = append(changes.Disciplines[index].Scores, score)
+	}
+
+	for index := range changes.Disciplines {
+		sortScores(changes.Disciplines[index].Scores)
+	}
+
+	// without changes cursor moves to the lag limit as well, so a quiet client does not fall behind the retention
+	cursorLimit := time.Now().Add(-StudentChangesCursorLag).UnixMilli()
+	if len(entries) == 0 {
+		changes.Cursor = cursorLimit
+	}
+	changes.Cursor = max(changes.Since, min(changes.Cursor, cursorLimit))
+
+	return changes, nil
+}
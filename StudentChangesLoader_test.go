@@ -0,0 +1,147 @@
+package main
+
+import (
+	"github.com/go-redis/redismock/v9"
+	scoreApi "github.com/kneu-messenger-pigeon/score-api"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestStudentChangesLoader(t *testing.T) {
+	since := time.UnixMilli(1773576000000)
+
+	t.Run("success", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.MatchExpectationsInOrder(true)
+
+		redisMock.ExpectZRangeByScoreWithScores("2026:student_changes:1200", &redis.ZRangeBy{
+			Min: "(1773576000000",
+			Max: "+inf",
+		}).SetVal([]redis.Z{
+			{Member: "2:199:246", Score: 1773576001000},
+			{Member: "wrong", Score: 1773576002000},
+			{Member: "1:200:300", Score: 1773576003000},
+			{Member: "2:199:245", Score: 1773576004000},
+		})
+
+		redisMock.ExpectHGet("2026:2:lessons:199", "246").SetVal("2302131")
+		redisMock.ExpectHMGet("2026:2:scores:1200:199", "246:1", "246:2").SetVal([]interface{}{"-999999", nil})
+		redisMock.ExpectHGet("2026:discipline:199", "name").SetVal("Капітал!")
+
+		redisMock.ExpectHGet("2026:1:lessons:200", "300").SetVal("")
+		redisMock.ExpectGet("2026:1:deleted-lessons:200:300").SetVal("")
+
+		redisMock.ExpectHGet("2026:2:lessons:199", "245").SetVal("2302121")
+		redisMock.ExpectHMGet("2026:2:scores:1200:199", "245:1", "245:2").SetVal([]interface{}{"4.5", nil})
+
+		scoreRatingLoader := NewMockScoreRatingLoaderInterface(t)
+		scoreRatingLoader.On("load", 2026, 2, 199, 1200).Return(ScoreRating{
+			ScoreRating: scoreApi.ScoreRating{Total: 4.5, StudentsCount: 25, Rating: 10},
+		}).Once()
+
+		storage := Storage{
+			redis:             redisClient,
+			year:              2026,
+			lessonTypes:       GetTestLessonTypes(),
+			scoreRatingLoader: scoreRatingLoader,
+			anomalies:         &AnomalyCounter{},
+		}
+		loader := StudentChangesLoader{storage: &storage}
+
+		changes, err := loader.getStudentChanges(1200, since)
+
+		assert.NoError(t, err)
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+		assert.Equal(t, StudentChanges{
+			Since:  1773576000000,
+			Cursor: 1773576004000,
+			Disciplines: []DisciplineChanges{
+				{
+					Discipline: scoreApi.Discipline{Id: 199, Name: "Капітал!"},
+					ScoreRating: ScoreRating{
+						ScoreRating: scoreApi.ScoreRating{Total: 4.5, StudentsCount: 25, Rating: 10},
+					},
+					Scores: []Score{
+						{Score: scoreApi.Score{
+							Lesson: scoreApi.Lesson{
+								Id:   245,
+								Date: time.Date(2023, time.Month(2), 12, 0, 0, 0, 0, defaultAcademicLocation),
+								Type: GetTestLessonTypes()[1],
+							},
+							FirstScore: floatPointer(4.5),
+						}},
+						{Score: scoreApi.Score{
+							Lesson: scoreApi.Lesson{
+								Id:   246,
+								Date: time.Date(2023, time.Month(2), 13, 0, 0, 0, 0, defaultAcademicLocation),
+								Type: GetTestLessonTypes()[1],
+							},
+							IsAbsent: true,
+						}},
+					},
+				},
+			},
+		}, changes)
+		assert.Equal(t, 1, storage.anomalies.getReport().Total)
+	})
+
+	t.Run("no_changes", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.ExpectZRangeByScoreWithScores("2026:student_changes:1200", &redis.ZRangeBy{
+			Min: "(1773576000000",
+			Max: "+inf",
+		}).SetVal([]redis.Z{})
+
+		storage := Storage{redis: redisClient, year: 2026}
+		loader := StudentChangesLoader{storage: &storage}
+
+		changes, err := loader.getStudentChanges(1200, since)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1773576000000), changes.Since)
+		assert.InDelta(t, time.Now().Add(-StudentChangesCursorLag).UnixMilli(), changes.Cursor, 1000)
+		assert.Equal(t, []DisciplineChanges{}, changes.Disciplines)
+	})
+
+	t.Run("cursor_lag", func(t *testing.T) {
+		recentSince := time.Now().Add(-StudentChangesCursorLag * 10)
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.MatchExpectationsInOrder(true)
+		redisMock.ExpectZRangeByScoreWithScores("2026:student_changes:1200", &redis.ZRangeBy{
+			Min: "(" + strconv.FormatInt(recentSince.UnixMilli(), 10),
+			Max: "+inf",
+		}).SetVal([]redis.Z{
+			{Member: "1:200:300", Score: float64(time.Now().Add(-time.Second).UnixMilli())},
+		})
+		redisMock.ExpectHGet("2026:1:lessons:200", "300").SetVal("")
+		redisMock.ExpectGet("2026:1:deleted-lessons:200:300").SetVal("")
+
+		storage := Storage{redis: redisClient, year: 2026}
+		loader := StudentChangesLoader{storage: &storage}
+
+		changes, err := loader.getStudentChanges(1200, recentSince)
+
+		assert.NoError(t, err)
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+		assert.InDelta(t, time.Now().Add(-StudentChangesCursorLag).UnixMilli(), changes.Cursor, 1000)
+		assert.Greater(t, changes.Cursor, changes.Since)
+	})
+
+	t.Run("redis_error", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.ExpectZRangeByScoreWithScores("2026:student_changes:1200", &redis.ZRangeBy{
+			Min: "(1773576000000",
+			Max: "+inf",
+		}).SetErr(assert.AnError)
+
+		storage := Storage{redis: redisClient, year: 2026}
+		loader := StudentChangesLoader{storage: &storage}
+
+		_, err := loader.getStudentChanges(1200, since)
+
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParseStudentChangeMember(t *testing.T) {
+	assert.Equal(t, "2:199:245", encodeStudentChangeMember(2, 199, 245))
+
+	discipline, lessonId, err := parseStudentChangeMember("2:199:245")
+	assert.NoError(t, err)
+	assert.Equal(t, DisciplineSemester{DisciplineId: 199, Semester: 2}, discipline)
+	assert.Equal(t, 245, lessonId)
+
+	for _, member := range []string{"", "2:199", "3:199:245", "1:0:245", "1:199:x", "1:199:245:1"} {
+		_, _, err = parseStudentChangeMember(member)
+		assert.ErrorIs(t, err, ErrWrongStudentChangeMember, member)
+	}
+}
+
+func TestParseChangesSince(t *testing.T) {
+	parseTestChangesSince := func(query string) (time.Time, error) {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request, _ = http.NewRequest(http.MethodGet, "/?"+query, nil)
+
+		return parseChangesSince(c)
+	}
+
+	since, err := parseTestChangesSince("since=1773576000000")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1773576000000), since.UnixMilli())
+
+	since, err = parseTestChangesSince("since=2026-03-15T12:00:00%2B02:00")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2026, time.Month(3), 15, 10, 0, 0, 0, time.UTC).UnixMilli(), since.UnixMilli())
+
+	_, err = parseTestChangesSince("")
+	assert.Equal(t, MessageError{Format: messageIncorrectParameter, Args: []interface{}{"since", ""}}, err)
+
+	_, err = parseTestChangesSince("since=yesterday")
+	assert.Equal(t, MessageError{Format: messageIncorrectParameter, Args: []interface{}{"since", "yesterday"}}, err)
+
+	_, err = parseTestChangesSince("since=-5")
+	assert.Error(t, err)
+}
+
+func TestIsChangesSinceExpired(t *testing.T) {
+	now := time.Date(2026, time.Month(3), 15, 12, 0, 0, 0, time.UTC)
+
+	retention := time.Hour * 24 * DefaultStudentChangesRetentionDays
+
+	assert.False(t, isChangesSinceExpired(now.Add(-time.Hour), now, retention))
+	assert.False(t, isChangesSinceExpired(now.Add(-retention), now, retention))
+	assert.True(t, isChangesSinceExpired(now.Add(-retention-time.Millisecond), now, retention))
+	assert.True(t, isChangesSinceExpired(now.Add(-2*time.Hour), now, time.Hour))
+}
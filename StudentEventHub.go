@@ -0,0 +1,361 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	scoreApi "github.com/kneu-messenger-pigeon/score-api"
+	"io"
+	"sync"
+	"time"
+)
+
+const (
+	StudentEventScore  = "score"
+	StudentEventRating = "rating"
+)
+
+const DefaultMaxStudentEventStreams = 1000
+const studentEventsBufferSize = 256
+const studentEventStreamCapacity = 32
+const studentEventsHeartbeat = time.Second * 15
+
+// studentEventsRetention - time the changes of the student are still tracked after the last stream is closed,
+// so reconnected client could resume from Last-Event-ID
+const studentEventsRetention = time.Minute * 2
+
+var ErrTooManyStreams = errors.New("too many event streams")
+
+// StudentEvent - Data is DisciplineScore for score event and StudentRatingEvent for rating event
+type StudentEvent struct {
+	Id        uint64
+	StudentId int
+	Type      string
+	Data      interface{}
+}
+
+type StudentRatingEvent struct {
+	Discipline  scoreApi.Discipline `json:"discipline"`
+	ScoreRating ScoreRating         `json:"scoreRating"`
+}
+
+// StudentEventStream receives events of the student, events channel is closed when the stream can not keep up
+type StudentEventStream struct {
+	studentId int
+	events    chan StudentEvent
+}
+
+// studentWatch - baseline of the student scores hashes and ratings keyed by totals key
+type studentWatch struct {
+	streams    map[*StudentEventStream]bool
+	scores     map[string]map[string]ScoreEntry
+	ratings    map[string]ScoreRating
+	releasedAt time.Time
+}
+
+// StudentEventHub detects score and rating changes of students with open event streams and keeps the last events
+// in the ring buffer. Event ids start from the hub start time in microseconds, so they grow across restarts.
+type StudentEventHub struct {
+	out          io.Writer
+	storage      *Storage
+	maxStreams   int
+	heartbeat    time.Duration
+	mutex        sync.Mutex
+	streamsCount int
+	lastEventId  uint64
+	buffer       []StudentEvent
+	watches      map[int]*studentWatch
+}
+
+// subscribe opens the stream and returns buffered events of the student after lastEventId.
+// Baseline of the new watch is loaded without the hub mutex, the stream slot is reserved before it.
+func (hub *StudentEventHub) subscribe(studentId int, lastEventId uint64) (*StudentEventStream, []StudentEvent, error) {
+	hub.mutex.Lock()
+	if hub.streamsCount >= hub.maxStreams {
+		hub.mutex.Unlock()
+		return nil, nil, ErrTooManyStreams
+	}
+
+	hub.streamsCount++
+	hub.removeExpiredWatches(time.Now())
+	watch := hub.watches[studentId]
+	hub.mutex.Unlock()
+
+	var err error
+	if watch == nil {
+		watch, err = hub.loadWatch(studentId)
+	}
+
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+
+	if err != nil {
+		hub.streamsCount--
+		return nil, nil, err
+	}
+
+	// watch could be loaded by the concurrent subscribe or removed as expired meanwhile
+	if existingWatch := hub.watches[studentId]; existingWatch != nil {
+		watch = existingWatch
+	} else {
+		hub.watches[studentId] = watch
+	}
+
+	stream := &StudentEventStream{
+		studentId: studentId,
+		events:    make(chan StudentEvent, studentEventStreamCapacity),
+	}
+	watch.streams[stream] = true
+
+	replay := make([]StudentEvent, 0)
+	for _, event := range hub.buffer {
+		if lastEventId != 0 && event.Id > lastEventId && event.StudentId == studentId {
+			replay = append(replay, event)
+		}
+	}
+
+	return stream, replay, nil
+}
+
+func (hub *StudentEventHub) unsubscribe(stream *StudentEventStream) {
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+
+	hub.removeStream(stream)
+}
+
+// removeStream should be called under the hub mutex, it is safe to remove the same stream twice
+func (hub *StudentEventHub) removeStream(stream *StudentEventStream) {
+	watch := hub.watches[stream.studentId]
+	if watch == nil || !watch.streams[stream] {
+		return
+	}
+
+	delete(watch.streams, stream)
+	hub.streamsCount--
+	if len(watch.streams) == 0 {
+		watch.releasedAt = time.Now()
+	}
+}
+
+func (hub *StudentEventHub) removeExpiredWatches(now time.Time) {
+	for studentId, watch := range hub.watches {
+		if len(watch.streams) == 0 && now.Sub(watch.releasedAt) > studentEventsRetention {
+			delete(hub.watches, studentId)
+		}
+	}
+}
+
+// loadWatch reads current scores and ratings of the actual student disciplines as the baseline
+func (hub *StudentEventHub) loadWatch(studentId int) (*studentWatch, error) {
+	disciplines, err := hub.storage.getActualStudentDisciplines(studentId)
+	if err != nil {
+		return nil, err
+	}
+
+	watch := &studentWatch{
+		streams: make(map[*StudentEventStream]bool),
+		scores:  make(map[string]map[string]ScoreEntry, len(disciplines)),
+		ratings: make(map[string]ScoreRating, len(disciplines)),
+	}
+
+	ctx := context.Background()
+	year := hub.storage.year
+	for _, discipline := range disciplines {
+		scoresKey := fmt.Sprintf("%d:%d:scores:%d:%d", year, discipline.Semester, studentId, discipline.DisciplineId)
+		rawScores, err := hub.storage.redis.HGetAll(ctx, scoresKey).Result()
+		if err != nil {
+			return nil, err
+		}
+		watch.scores[scoresKey] = hub.decodeScoreEntries(scoresKey, rawScores)
+
+		totalsKey := fmt.Sprintf("%d:%d:totals:%d", year, discipline.Semester, discipline.DisciplineId)
+		watch.ratings[totalsKey] = hub.storage.scoreRatingLoader.load(
+			year, discipline.Semester, discipline.DisciplineId, studentId,
+		)
+	}
+
+	return watch, nil
+}
+
+// handleKeyChange is called by the single key changes listener, so baselines of the key are not updated
+// concurrently. Redis is read without the hub mutex, it is held only to swap baselines and publish events.
+// Returns the logged error, so the listener can deliver the key again.
+func (hub *StudentEventHub) handleKeyChange(key string) error {
+	changeKey, err := parseScoreChangeKey(key)
+	if err != nil || changeKey.Year != hub.storage.year {
+		return nil
+	}
+
+	hub.mutex.Lock()
+	hub.removeExpiredWatches(time.Now())
+	hub.mutex.Unlock()
+
+	if changeKey.StudentId != 0 {
+		err = hub.detectScoresChanges(key, changeKey)
+	} else {
+		hub.detectRatingsChanges(key, changeKey)
+	}
+
+	if err != nil {
+		_, _ = fmt.Fprintf(hub.out, "Failed to detect student events of %s: %s\n", key, err)
+	}
+
+	return err
+}
+
+func (hub *StudentEventHub) detectScoresChanges(key string, changeKey scoreChangeKey) error {
+	if hub.getWatch(changeKey.StudentId) == nil {
+		return nil
+	}
+
+	rawScores, err := hub.storage.redis.HGetAll(context.Background(), key).Result()
+	if err != nil {
+		return err
+	}
+	current := hub.decodeScoreEntries(key, rawScores)
+
+	hub.mutex.Lock()
+	watch := hub.watches[changeKey.StudentId]
+	var changes []ScoreChange
+	if watch != nil {
+		changes = diffScoreEntries(watch.scores[key], current)
+		watch.scores[key] = current
+	}
+	hub.mutex.Unlock()
+
+	events := make([]StudentEvent, 0, len(changes))
+	publishedLessonIds := make(map[int]bool)
+	for _, change := range changes {
+		if publishedLessonIds[change.LessonId] {
+			continue
+		}
+		publishedLessonIds[change.LessonId] = true
+
+		disciplineScore, err := hub.storage.getDisciplineScore(changeKey.StudentId, changeKey.DisciplineId, change.LessonId)
+		if err != nil {
+			return err
+		}
+
+		if disciplineScore.Score.Lesson.Id != 0 {
+			events = append(events, StudentEvent{
+				StudentId: changeKey.StudentId,
+				Type:      StudentEventScore,
+				Data:      disciplineScore,
+			})
+		}
+	}
+
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+
+	for _, event := range events {
+		hub.publish(event)
+	}
+
+	return nil
+}
+
+func (hub *StudentEventHub) detectRatingsChanges(key string, changeKey scoreChangeKey) {
+	hub.mutex.Lock()
+	previousRatings := make(map[int]ScoreRating)
+	for studentId, watch := range hub.watches {
+		if previousRating, tracked := watch.ratings[key]; tracked {
+			previousRatings[studentId] = previousRating
+		}
+	}
+	hub.mutex.Unlock()
+
+	var discipline scoreApi.Discipline
+	ratings := make(map[int]ScoreRating)
+	for studentId, previousRating := range previousRatings {
+		rating := hub.storage.scoreRatingLoader.load(
+			changeKey.Year, changeKey.Semester, changeKey.DisciplineId, studentId,
+		)
+		if rating == previousRating {
+			continue
+		}
+		ratings[studentId] = rating
+
+		if discipline.Id == 0 {
+			discipline = scoreApi.Discipline{
+				Id:   changeKey.DisciplineId,
+				Name: hub.storage.getDisciplineName(changeKey.DisciplineId),
+			}
+		}
+	}
+
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+
+	for studentId, rating := range ratings {
+		watch := hub.watches[studentId]
+		if watch == nil {
+			continue
+		}
+		watch.ratings[key] = rating
+
+		hub.publish(StudentEvent{
+			StudentId: studentId,
+			Type:      StudentEventRating,
+			Data: StudentRatingEvent{
+				Discipline:  discipline,
+				ScoreRating: rating,
+			},
+		})
+	}
+}
+
+func (hub *StudentEventHub) getWatch(studentId int) *studentWatch {
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+
+	return hub.watches[studentId]
+}
+
+// publish should be called under the hub mutex. Stream which buffer is full is closed,
+// the client reconnects and receives missed events from the ring buffer.
+func (hub *StudentEventHub) publish(event StudentEvent) {
+	hub.lastEventId++
+	event.Id = hub.lastEventId
+
+	if len(hub.buffer) >= studentEventsBufferSize {
+		hub.buffer = append(hub.buffer[:0], hub.buffer[1:]...)
+	}
+	hub.buffer = append(hub.buffer, event)
+
+	watch := hub.watches[event.StudentId]
+	if watch == nil {
+		return
+	}
+
+	for stream := range watch.streams {
+		select {
+		case stream.events <- event:
+		default:
+			hub.removeStream(stream)
+			close(stream.events)
+		}
+	}
+}
+
+func (hub *StudentEventHub) decodeScoreEntries(key string, rawScores map[string]string) map[string]ScoreEntry {
+	scoreEntries := make(map[string]ScoreEntry, len(rawScores))
+	for _, scoreEntry := range hub.storage.decodeScoreEntries(key, rawScores) {
+		scoreEntries[hub.storage.codec.encodeScoreField(scoreEntry.LessonId, scoreEntry.Half)] = scoreEntry
+	}
+
+	return scoreEntries
+}
+
+func newStudentEventHub(out io.Writer, storage *Storage, maxStreams int) *StudentEventHub {
+	return &StudentEventHub{
+		out:         out,
+		storage:     storage,
+		maxStreams:  maxStreams,
+		heartbeat:   studentEventsHeartbeat,
+		lastEventId: uint64(time.Now().UnixMicro()),
+		buffer:      make([]StudentEvent, 0, studentEventsBufferSize),
+		watches:     make(map[int]*studentWatch),
+	}
+}
@@ -0,0 +1,256 @@
+package main
+
+import (
+	"bytes"
+	"github.com/go-redis/redismock/v9"
+	scoreApi "github.com/kneu-messenger-pigeon/score-api"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func makeTestEventRating(total float32, rating int) ScoreRating {
+	return ScoreRating{ScoreRating: scoreApi.ScoreRating{Total: total, StudentsCount: 25, Rating: rating}}
+}
+
+// expectTestStudentWatch expects loading of the student 1200 baseline with discipline 199 of the second semester
+func expectTestStudentWatch(redisMock redismock.ClientMock, scoreRatingLoader *MockScoreRatingLoaderInterface) {
+	redisMock.ExpectSMembers("2026:1:student_disciplines:1200").SetVal([]string{})
+	redisMock.ExpectSMembers("2026:2:student_disciplines:1200").SetVal([]string{"199"})
+	redisMock.ExpectHGetAll("2026:2:scores:1200:199").SetVal(map[string]string{"245:1": "4.5"})
+	scoreRatingLoader.On("load", 2026, 2, 199, 1200).Return(makeTestEventRating(4.5, 10)).Once()
+}
+
+func newTestStudentEventHub(redisClient *redis.Client, scoreRatingLoader ScoreRatingLoaderInterface, maxStreams int) *StudentEventHub {
+	hub := newStudentEventHub(&bytes.Buffer{}, &Storage{
+		redis:             redisClient,
+		year:              2026,
+		lessonTypes:       GetTestLessonTypes(),
+		scoreRatingLoader: scoreRatingLoader,
+		anomalies:         &AnomalyCounter{},
+	}, maxStreams)
+	hub.lastEventId = 100
+
+	return hub
+}
+
+func TestStudentEventHub(t *testing.T) {
+	t.Run("redis_error", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.MatchExpectationsInOrder(true)
+		scoreRatingLoader := NewMockScoreRatingLoaderInterface(t)
+		expectTestStudentWatch(redisMock, scoreRatingLoader)
+
+		out := &bytes.Buffer{}
+		hub := newTestStudentEventHub(redisClient, scoreRatingLoader, 10)
+		hub.out = out
+		stream, _, err := hub.subscribe(1200, 0)
+		assert.NoError(t, err)
+
+		redisMock.ExpectHGetAll("2026:2:scores:1200:199").SetErr(assert.AnError)
+
+		err = hub.handleKeyChange("2026:2:scores:1200:199")
+
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+		assert.Empty(t, stream.events)
+		assert.Equal(t, "Failed to detect student events of 2026:2:scores:1200:199: "+assert.AnError.Error()+"\n", out.String())
+	})
+
+	t.Run("score_event", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.MatchExpectationsInOrder(true)
+		scoreRatingLoader := NewMockScoreRatingLoaderInterface(t)
+		expectTestStudentWatch(redisMock, scoreRatingLoader)
+
+		hub := newTestStudentEventHub(redisClient, scoreRatingLoader, 10)
+		stream, replay, err := hub.subscribe(1200, 0)
+
+		assert.NoError(t, err)
+		assert.Empty(t, replay)
+		assert.Equal(t, 1, hub.streamsCount)
+
+		redisMock.ExpectHGetAll("2026:2:scores:1200:199").SetVal(map[string]string{"245:1": "4.5", "245:2": "2", "246:1": "-999999"})
+		redisMock.ExpectGet("2026:discipline_semester_updated_at:199").SetVal("21676152800")
+		redisMock.ExpectHGet("2026:discipline:199", "name").SetVal("Капітал!")
+		redisMock.ExpectHGet("2026:2:lessons:199", "245").SetVal("2302121")
+		redisMock.ExpectHMGet("2026:2:scores:1200:199", "245:1", "245:2").SetVal([]interface{}{"4.5", "2"})
+		redisMock.ExpectGet("2026:discipline_semester_updated_at:199").SetVal("21676152800")
+		redisMock.ExpectHGet("2026:discipline:199", "name").SetVal("Капітал!")
+		redisMock.ExpectHGet("2026:2:lessons:199", "246").SetVal("2302131")
+		redisMock.ExpectHMGet("2026:2:scores:1200:199", "246:1", "246:2").SetVal([]interface{}{"-999999", nil})
+
+		assert.NoError(t, hub.handleKeyChange("2026:2:scores:1200:199"))
+
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+		assert.Len(t, stream.events, 2)
+
+		event := <-stream.events
+		assert.Equal(t, uint64(101), event.Id)
+		assert.Equal(t, 1200, event.StudentId)
+		assert.Equal(t, StudentEventScore, event.Type)
+		assert.Equal(t, DisciplineScore{
+			Discipline: scoreApi.Discipline{Id: 199, Name: "Капітал!"},
+			Score: Score{Score: scoreApi.Score{
+				Lesson: scoreApi.Lesson{
+					Id:   245,
+					Date: time.Date(2023, time.Month(2), 12, 0, 0, 0, 0, defaultAcademicLocation),
+					Type: GetTestLessonTypes()[1],
+				},
+				FirstScore:  floatPointer(4.5),
+				SecondScore: floatPointer(2),
+			}},
+		}, event.Data)
+
+		event = <-stream.events
+		assert.Equal(t, uint64(102), event.Id)
+		assert.True(t, event.Data.(DisciplineScore).Score.IsAbsent)
+
+		hub.unsubscribe(stream)
+		hub.unsubscribe(stream)
+		assert.Equal(t, 0, hub.streamsCount)
+
+		_, replay, err = hub.subscribe(1200, 101)
+		assert.NoError(t, err)
+		assert.Len(t, replay, 1)
+		assert.Equal(t, uint64(102), replay[0].Id)
+	})
+
+	t.Run("rating_event", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.MatchExpectationsInOrder(true)
+		scoreRatingLoader := NewMockScoreRatingLoaderInterface(t)
+		expectTestStudentWatch(redisMock, scoreRatingLoader)
+
+		hub := newTestStudentEventHub(redisClient, scoreRatingLoader, 10)
+		stream, _, err := hub.subscribe(1200, 0)
+		assert.NoError(t, err)
+
+		scoreRatingLoader.On("load", 2026, 2, 199, 1200).Return(makeTestEventRating(4.5, 10)).Once()
+		hub.handleKeyChange("2026:2:totals:199")
+		assert.Empty(t, stream.events)
+
+		scoreRatingLoader.On("load", 2026, 2, 199, 1200).Return(makeTestEventRating(4.5, 12)).Once()
+		redisMock.ExpectHGet("2026:discipline:199", "name").SetVal("Капітал!")
+		hub.handleKeyChange("2026:2:totals:199")
+
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+		event := <-stream.events
+		assert.Equal(t, StudentEventRating, event.Type)
+		assert.Equal(t, StudentRatingEvent{
+			Discipline:  scoreApi.Discipline{Id: 199, Name: "Капітал!"},
+			ScoreRating: makeTestEventRating(4.5, 12),
+		}, event.Data)
+
+		hub.handleKeyChange("2026:2:totals:200")
+		hub.handleKeyChange("2025:2:totals:199")
+		hub.handleKeyChange("2026:2:scores:1300:199")
+		assert.Empty(t, stream.events)
+	})
+
+	t.Run("redis_without_hub_mutex", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		scoreRatingLoader := NewMockScoreRatingLoaderInterface(t)
+		hub := newTestStudentEventHub(redisClient, scoreRatingLoader, 10)
+
+		assertHubUnlocked := func(mock.Arguments) {
+			assert.True(t, hub.mutex.TryLock(), "hub mutex is held during Redis request")
+			hub.mutex.Unlock()
+		}
+
+		redisMock.ExpectSMembers("2026:1:student_disciplines:1200").SetVal([]string{})
+		redisMock.ExpectSMembers("2026:2:student_disciplines:1200").SetVal([]string{"199"})
+		redisMock.ExpectHGetAll("2026:2:scores:1200:199").SetVal(map[string]string{"245:1": "4.5"})
+		scoreRatingLoader.On("load", 2026, 2, 199, 1200).Return(makeTestEventRating(4.5, 10)).Run(assertHubUnlocked).Once()
+
+		stream, _, err := hub.subscribe(1200, 0)
+		assert.NoError(t, err)
+
+		scoreRatingLoader.On("load", 2026, 2, 199, 1200).Return(makeTestEventRating(4.5, 12)).Run(assertHubUnlocked).Once()
+		redisMock.ExpectHGet("2026:discipline:199", "name").SetVal("Капітал!")
+		hub.handleKeyChange("2026:2:totals:199")
+
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+		assert.Len(t, stream.events, 1)
+		assert.Equal(t, makeTestEventRating(4.5, 12), hub.watches[1200].ratings["2026:2:totals:199"])
+	})
+
+	t.Run("too_many_streams", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		scoreRatingLoader := NewMockScoreRatingLoaderInterface(t)
+		expectTestStudentWatch(redisMock, scoreRatingLoader)
+
+		hub := newTestStudentEventHub(redisClient, scoreRatingLoader, 2)
+		_, _, err := hub.subscribe(1200, 0)
+		assert.NoError(t, err)
+		_, _, err = hub.subscribe(1200, 0)
+		assert.NoError(t, err)
+
+		_, _, err = hub.subscribe(1200, 0)
+		assert.ErrorIs(t, err, ErrTooManyStreams)
+		assert.Equal(t, 2, hub.streamsCount)
+	})
+
+	t.Run("slow_stream", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		scoreRatingLoader := NewMockScoreRatingLoaderInterface(t)
+		expectTestStudentWatch(redisMock, scoreRatingLoader)
+
+		hub := newTestStudentEventHub(redisClient, scoreRatingLoader, 10)
+		stream, _, err := hub.subscribe(1200, 0)
+		assert.NoError(t, err)
+
+		for index := 0; index <= studentEventStreamCapacity; index++ {
+			hub.publish(StudentEvent{StudentId: 1200, Type: StudentEventRating})
+		}
+
+		assert.Equal(t, 0, hub.streamsCount)
+		assert.Len(t, stream.events, studentEventStreamCapacity)
+		for range stream.events {
+		}
+		hub.unsubscribe(stream)
+		assert.Equal(t, 0, hub.streamsCount)
+	})
+
+	t.Run("ring_buffer", func(t *testing.T) {
+		hub := newTestStudentEventHub(nil, nil, 10)
+
+		for index := 0; index < studentEventsBufferSize+5; index++ {
+			hub.publish(StudentEvent{StudentId: 1200})
+		}
+
+		assert.Len(t, hub.buffer, studentEventsBufferSize)
+		assert.Equal(t, uint64(106), hub.buffer[0].Id)
+		assert.Equal(t, uint64(100+studentEventsBufferSize+5), hub.buffer[studentEventsBufferSize-1].Id)
+	})
+
+	t.Run("expired_watch", func(t *testing.T) {
+		hub := newTestStudentEventHub(nil, nil, 10)
+		hub.watches[1200] = &studentWatch{
+			streams:    map[*StudentEventStream]bool{},
+			releasedAt: time.Now().Add(-studentEventsRetention - time.Second),
+		}
+		hub.watches[1300] = &studentWatch{
+			streams:    map[*StudentEventStream]bool{},
+			releasedAt: time.Now(),
+		}
+
+		hub.removeExpiredWatches(time.Now())
+
+		assert.NotContains(t, hub.watches, 1200)
+		assert.Contains(t, hub.watches, 1300)
+	})
+
+	t.Run("redis_error", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.ExpectSMembers("2026:1:student_disciplines:1200").SetErr(assert.AnError)
+
+		hub := newTestStudentEventHub(redisClient, NewMockScoreRatingLoaderInterface(t), 10)
+		_, _, err := hub.subscribe(1200, 0)
+
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Equal(t, 0, hub.streamsCount)
+	})
+}
@@ -0,0 +1,35 @@
+package main
+
+// StudentProfile - profile fields stored in `{year}:student:{id}` hash and overview of the student data.
+// Id is zero when the student is unknown: there are neither profile nor disciplines.
+type StudentProfile struct {
+	Id               int            `json:"id"`
+	Name             string         `json:"name"`
+	Group            string         `json:"group"`
+	Faculty          string         `json:"faculty"`
+	StudyForm        string         `json:"studyForm"`
+	Semesters        []YearSemester `json:"semesters"`
+	DisciplinesCount int            `json:"disciplinesCount"`
+}
+
+type YearSemester struct {
+	Year     int `json:"year"`
+	Semester int `json:"semester"`
+}
+
+// makeStudentProfile fills profile fields from Redis hash, unknown fields are ignored
+func makeStudentProfile(studentId int, fields map[string]string, semesters []YearSemester, disciplinesCount int) StudentProfile {
+	if len(fields) == 0 && len(semesters) == 0 {
+		return StudentProfile{}
+	}
+
+	return StudentProfile{
+		Id:               studentId,
+		Name:             fields["name"],
+		Group:            fields["group"],
+		Faculty:          fields["faculty"],
+		StudyForm:        fields["study_form"],
+		Semesters:        semesters,
+		DisciplinesCount: disciplinesCount,
+	}
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestMakeStudentProfile(t *testing.T) {
+	assert.Equal(t, StudentProfile{}, makeStudentProfile(1200, map[string]string{}, []YearSemester{}, 0))
+
+	// student with profile but without disciplines yet exists
+	assert.Equal(t, StudentProfile{
+		Id:        1200,
+		Name:      "Іван Франко",
+		Semesters: []YearSemester{},
+	}, makeStudentProfile(1200, map[string]string{"name": "Іван Франко"}, []YearSemester{}, 0))
+
+	assert.Equal(t, StudentProfile{
+		Id:               1200,
+		Semesters:        []YearSemester{{Year: 2026, Semester: 1}},
+		DisciplinesCount: 3,
+	}, makeStudentProfile(1200, nil, []YearSemester{{Year: 2026, Semester: 1}}, 3))
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	scoreApi "github.com/kneu-messenger-pigeon/score-api"
+	"math"
+)
+
+// DefaultDisciplineCredits - weight of discipline without `credits` field in `{year}:discipline:{id}` hash
+const DefaultDisciplineCredits = float32(1)
+
+const DefaultPassingTotal = float32(60)
+
+// StudentSummary - totals of all student disciplines of both semesters in the year.
+// Best and Worst are nil when the student has no disciplines with scores.
+type StudentSummary struct {
+	StudentId           int                 `json:"studentId"`
+	Year                int                 `json:"year"`
+	WeightedAverage     float32             `json:"weightedAverage"`
+	Credits             float32             `json:"credits"`
+	PassingTotal        float32             `json:"passingTotal"`
+	AboveThresholdCount int                 `json:"aboveThresholdCount"`
+	BelowThresholdCount int                 `json:"belowThresholdCount"`
+	Best                *DisciplineSummary  `json:"best"`
+	Worst               *DisciplineSummary  `json:"worst"`
+	Disciplines         []DisciplineSummary `json:"disciplines"`
+}
+
+type DisciplineSummary struct {
+	Discipline  scoreApi.Discipline `json:"discipline"`
+	Semester    int                 `json:"semester"`
+	Credits     float32             `json:"credits"`
+	ScoreRating ScoreRating         `json:"scoreRating"`
+	HasScores   bool                `json:"hasScores"`
+}
+
+// makeStudentSummary calculates credits weighted average of totals rounded to 0.01.
+// Total equal to passingTotal is above threshold. The first discipline wins between the same best or worst totals.
+// Disciplines without scores, e.g. not started yet, are not counted by thresholds and can not be best or worst.
+func makeStudentSummary(studentId int, year int, disciplines []DisciplineSummary, passingTotal float32) StudentSummary {
+	summary := StudentSummary{
+		StudentId:    studentId,
+		Year:         year,
+		PassingTotal: passingTotal,
+		Disciplines:  disciplines,
+	}
+
+	var weightedTotalsSum float64
+	for index := range disciplines {
+		discipline := &disciplines[index]
+		total := discipline.ScoreRating.Total
+
+		weightedTotalsSum += float64(total) * float64(discipline.Credits)
+		summary.Credits += discipline.Credits
+
+		if !discipline.HasScores {
+			continue
+		}
+
+		if total >= passingTotal {
+			summary.AboveThresholdCount++
+		} else {
+			summary.BelowThresholdCount++
+		}
+
+		if summary.Best == nil || total > summary.Best.ScoreRating.Total {
+			summary.Best = discipline
+		}
+
+		if summary.Worst == nil || total < summary.Worst.ScoreRating.Total {
+			summary.Worst = discipline
+		}
+	}
+
+	if summary.Credits > 0 {
+		summary.WeightedAverage = float32(math.Round(weightedTotalsSum/float64(summary.Credits)*100) / 100)
+	}
+
+	return summary
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	scoreApi "github.com/kneu-messenger-pigeon/score-api"
+	"github.com/redis/go-redis/v9"
+	"sync"
+)
+
+type StudentSummaryLoaderInterface interface {
+	getStudentSummary(studentId int) (StudentSummary, error)
+}
+
+type StudentSummaryLoader struct {
+	storage *Storage
+}
+
+// getStudentSummary combines all student disciplines of both semesters, not only actual ones
+func (loader *StudentSummaryLoader) getStudentSummary(studentId int) (StudentSummary, error) {
+	firstSemesterDisciplines, err := loader.storage.getStudentDisciplinesIdsForSemester(studentId, 1)
+	if err != nil {
+		return StudentSummary{}, err
+	}
+
+	secondSemesterDisciplines, err := loader.storage.getStudentDisciplinesIdsForSemester(studentId, 2)
+	if err != nil {
+		return StudentSummary{}, err
+	}
+
+	ctx := context.Background()
+	disciplines := append(firstSemesterDisciplines, secondSemesterDisciplines...)
+
+	// name, credits and scores existence of every discipline are loaded by one round trip
+	pipeline := loader.storage.redis.Pipeline()
+	disciplineKeys := make([]string, len(disciplines))
+	disciplineCommands := make([]*redis.SliceCmd, len(disciplines))
+	scoresExistCommands := make([]*redis.IntCmd, len(disciplines))
+	for index, discipline := range disciplines {
+		disciplineKeys[index] = fmt.Sprintf("%d:discipline:%d", loader.storage.year, discipline.DisciplineId)
+		scoresKey := fmt.Sprintf("%d:%d:scores:%d:%d", loader.storage.year, discipline.Semester, studentId, discipline.DisciplineId)
+
+		disciplineCommands[index] = pipeline.HMGet(ctx, disciplineKeys[index], "name", "credits")
+		scoresExistCommands[index] = pipeline.Exists(ctx, scoresKey)
+	}
+
+	if len(disciplines) != 0 {
+		if _, err = pipeline.Exec(ctx); err != nil {
+			return StudentSummary{}, err
+		}
+	}
+
+	disciplineSummaries := make([]DisciplineSummary, len(disciplines))
+	for index, discipline := range disciplines {
+		// missing hash fields are nil values, they become empty strings
+		name, _ := disciplineCommands[index].Val()[0].(string)
+		credits, _ := disciplineCommands[index].Val()[1].(string)
+		disciplineSummaries[index] = DisciplineSummary{
+			Discipline: scoreApi.Discipline{
+				Id:   discipline.DisciplineId,
+				Name: name,
+			},
+			Semester:  discipline.Semester,
+			Credits:   loader.storage.decodeDisciplineCredits(disciplineKeys[index], credits),
+			HasScores: scoresExistCommands[index].Val() != 0,
+		}
+	}
+
+	wg := sync.WaitGroup{}
+	wg.Add(len(disciplines))
+
+	for _index := range disciplines {
+		go func(index int) {
+			disciplineSummaries[index].ScoreRating = loader.storage.scoreRatingLoader.load(
+				loader.storage.year, disciplines[index].Semester, disciplines[index].DisciplineId, studentId,
+			)
+			wg.Done()
+		}(_index)
+	}
+
+	wg.Wait()
+
+	return makeStudentSummary(studentId, loader.storage.year, disciplineSummaries, loader.storage.rules.passingTotal), nil
+}
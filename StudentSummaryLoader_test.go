@@ -0,0 +1,130 @@
+package main
+
+import (
+	"github.com/go-redis/redismock/v9"
+	scoreApi "github.com/kneu-messenger-pigeon/score-api"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestStudentSummaryLoader(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.MatchExpectationsInOrder(true)
+
+		redisMock.ExpectSMembers("2026:1:student_disciplines:1200").SetVal([]string{"199"})
+		redisMock.ExpectSMembers("2026:2:student_disciplines:1200").SetVal([]string{"200", "201", "202"})
+		redisMock.ExpectHMGet("2026:discipline:199", "name", "credits").SetVal([]interface{}{"Капітал!", "4.5"})
+		redisMock.ExpectExists("2026:1:scores:1200:199").SetVal(1)
+		redisMock.ExpectHMGet("2026:discipline:200", "name", "credits").SetVal([]interface{}{"Політична економія", nil})
+		redisMock.ExpectExists("2026:2:scores:1200:200").SetVal(1)
+		redisMock.ExpectHMGet("2026:discipline:201", "name", "credits").SetVal([]interface{}{"Гроші та лихварство", "many"})
+		redisMock.ExpectExists("2026:2:scores:1200:201").SetVal(1)
+		redisMock.ExpectHMGet("2026:discipline:202", "name", "credits").SetVal([]interface{}{"Додаткова вартість", "1"})
+		redisMock.ExpectExists("2026:2:scores:1200:202").SetVal(0)
+
+		makeTestScoreRating := func(total float32) ScoreRating {
+			return ScoreRating{ScoreRating: scoreApi.ScoreRating{Total: total, StudentsCount: 25}}
+		}
+
+		scoreRatingLoader := NewMockScoreRatingLoaderInterface(t)
+		scoreRatingLoader.On("load", 2026, 1, 199, 1200).Return(makeTestScoreRating(80))
+		scoreRatingLoader.On("load", 2026, 2, 200, 1200).Return(makeTestScoreRating(50))
+		scoreRatingLoader.On("load", 2026, 2, 201, 1200).Return(makeTestScoreRating(65))
+		scoreRatingLoader.On("load", 2026, 2, 202, 1200).Return(makeTestScoreRating(0))
+
+		storage := Storage{
+			redis:             redisClient,
+			year:              2026,
+			scoreRatingLoader: scoreRatingLoader,
+			anomalies:         &AnomalyCounter{},
+			rules:             getTestAcademicRules(),
+		}
+		loader := StudentSummaryLoader{storage: &storage}
+
+		summary, err := loader.getStudentSummary(1200)
+
+		assert.NoError(t, err)
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+
+		expectedDisciplines := []DisciplineSummary{
+			{
+				Discipline:  scoreApi.Discipline{Id: 199, Name: "Капітал!"},
+				Semester:    1,
+				Credits:     4.5,
+				ScoreRating: makeTestScoreRating(80),
+				HasScores:   true,
+			},
+			{
+				Discipline:  scoreApi.Discipline{Id: 200, Name: "Політична економія"},
+				Semester:    2,
+				Credits:     DefaultDisciplineCredits,
+				ScoreRating: makeTestScoreRating(50),
+				HasScores:   true,
+			},
+			{
+				Discipline:  scoreApi.Discipline{Id: 201, Name: "Гроші та лихварство"},
+				Semester:    2,
+				Credits:     DefaultDisciplineCredits,
+				ScoreRating: makeTestScoreRating(65),
+				HasScores:   true,
+			},
+			{
+				Discipline:  scoreApi.Discipline{Id: 202, Name: "Додаткова вартість"},
+				Semester:    2,
+				Credits:     1,
+				ScoreRating: makeTestScoreRating(0),
+			},
+		}
+
+		assert.Equal(t, StudentSummary{
+			StudentId: 1200,
+			Year:      2026,
+			// (80*4.5 + 50 + 65 + 0) / 7.5
+			WeightedAverage:     63.33,
+			Credits:             7.5,
+			PassingTotal:        DefaultPassingTotal,
+			AboveThresholdCount: 2,
+			BelowThresholdCount: 1,
+			Best:                &summary.Disciplines[0],
+			Worst:               &summary.Disciplines[1],
+			Disciplines:         expectedDisciplines,
+		}, summary)
+		assert.Equal(t, 1, storage.getAnomalies().Total)
+	})
+
+	t.Run("redis_error", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.ExpectSMembers("2026:1:student_disciplines:1200").SetVal([]string{"199"})
+		redisMock.ExpectSMembers("2026:2:student_disciplines:1200").SetErr(assert.AnError)
+
+		storage := Storage{
+			redis: redisClient,
+			year:  2026,
+			rules: getTestAcademicRules(),
+		}
+		loader := StudentSummaryLoader{storage: &storage}
+
+		_, err := loader.getStudentSummary(1200)
+
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+
+	t.Run("pipeline_error", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.ExpectSMembers("2026:1:student_disciplines:1200").SetVal([]string{"199"})
+		redisMock.ExpectSMembers("2026:2:student_disciplines:1200").SetVal([]string{})
+		redisMock.ExpectHMGet("2026:discipline:199", "name", "credits").SetErr(assert.AnError)
+
+		storage := Storage{
+			redis: redisClient,
+			year:  2026,
+			rules: getTestAcademicRules(),
+		}
+		loader := StudentSummaryLoader{storage: &storage}
+
+		_, err := loader.getStudentSummary(1200)
+
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	scoreApi "github.com/kneu-messenger-pigeon/score-api"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestMakeStudentSummary(t *testing.T) {
+	makeTestDisciplineSummary := func(disciplineId int, credits float32, total float32) DisciplineSummary {
+		return DisciplineSummary{
+			Discipline:  scoreApi.Discipline{Id: disciplineId},
+			Semester:    1,
+			Credits:     credits,
+			ScoreRating: ScoreRating{ScoreRating: scoreApi.ScoreRating{Total: total}},
+			HasScores:   true,
+		}
+	}
+
+	t.Run("success", func(t *testing.T) {
+		disciplines := []DisciplineSummary{
+			makeTestDisciplineSummary(199, 4, 90),
+			makeTestDisciplineSummary(200, 2, 60),
+			makeTestDisciplineSummary(201, 3, 45.5),
+			makeTestDisciplineSummary(202, 1, 90),
+		}
+
+		summary := makeStudentSummary(1200, 2026, disciplines, DefaultPassingTotal)
+
+		assert.Equal(t, 1200, summary.StudentId)
+		assert.Equal(t, 2026, summary.Year)
+		assert.Equal(t, float32(10), summary.Credits)
+		// (90*4 + 60*2 + 45.5*3 + 90*1) / 10 = 70.65
+		assert.Equal(t, float32(70.65), summary.WeightedAverage)
+		assert.Equal(t, DefaultPassingTotal, summary.PassingTotal)
+		assert.Equal(t, 3, summary.AboveThresholdCount)
+		assert.Equal(t, 1, summary.BelowThresholdCount)
+		assert.Equal(t, &disciplines[0], summary.Best)
+		assert.Equal(t, &disciplines[2], summary.Worst)
+		assert.Equal(t, disciplines, summary.Disciplines)
+	})
+
+	t.Run("disciplines_without_scores", func(t *testing.T) {
+		notStarted := makeTestDisciplineSummary(203, 2, 0)
+		notStarted.HasScores = false
+		disciplines := []DisciplineSummary{
+			makeTestDisciplineSummary(199, 4, 90),
+			makeTestDisciplineSummary(200, 2, 45.5),
+			notStarted,
+		}
+
+		summary := makeStudentSummary(1200, 2026, disciplines, DefaultPassingTotal)
+
+		assert.Equal(t, float32(8), summary.Credits)
+		// (90*4 + 45.5*2 + 0*2) / 8 = 56.375
+		assert.Equal(t, float32(56.38), summary.WeightedAverage)
+		assert.Equal(t, 1, summary.AboveThresholdCount)
+		assert.Equal(t, 1, summary.BelowThresholdCount)
+		assert.Equal(t, &disciplines[0], summary.Best)
+		assert.Equal(t, &disciplines[1], summary.Worst)
+
+		summary = makeStudentSummary(1200, 2026, []DisciplineSummary{notStarted}, DefaultPassingTotal)
+
+		assert.Equal(t, 0, summary.AboveThresholdCount)
+		assert.Equal(t, 0, summary.BelowThresholdCount)
+		assert.Nil(t, summary.Best)
+		assert.Nil(t, summary.Worst)
+	})
+
+	t.Run("configured_passing_total", func(t *testing.T) {
+		summary := makeStudentSummary(1200, 2026, []DisciplineSummary{makeTestDisciplineSummary(199, 4, 90)}, 91)
+
+		assert.Equal(t, float32(91), summary.PassingTotal)
+		assert.Equal(t, 0, summary.AboveThresholdCount)
+		assert.Equal(t, 1, summary.BelowThresholdCount)
+	})
+
+	t.Run("no_disciplines", func(t *testing.T) {
+		summary := makeStudentSummary(1200, 2026, []DisciplineSummary{}, DefaultPassingTotal)
+
+		assert.Equal(t, StudentSummary{
+			StudentId:    1200,
+			Year:         2026,
+			PassingTotal: DefaultPassingTotal,
+			Disciplines:  []DisciplineSummary{},
+		}, summary)
+	})
+}
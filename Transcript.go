@@ -0,0 +1,15 @@
+package main
+
+import "fmt"
+
+// Transcript - actual disciplines of the student with scores, used for file exports
+type Transcript struct {
+	StudentId   int
+	Year        int
+	Semester    int
+	Disciplines DisciplineScoreResults
+}
+
+func (transcript Transcript) filename(extension string) string {
+	return fmt.Sprintf("scores-%d-%d-%d.%s", transcript.StudentId, transcript.Year, transcript.Semester, extension)
+}
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"encoding/csv"
+	"github.com/xuri/excelize/v2"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const ukrainianDateLayout = "02.01.2006"
+const xlsxSheetNameMaxLength = 31
+const xlsxSummarySheetName = "Підсумки"
+
+var transcriptSummaryHeader = []string{
+	"Дисципліна", "Загальний бал", "Рейтинг", "Кількість студентів",
+}
+
+var transcriptScoresHeader = []string{
+	"Дата", "Тип заняття", "Перша оцінка", "Друга оцінка", "Відсутність",
+}
+
+// writeTranscriptCsv writes one row per score with discipline totals repeated on each row.
+// Semicolon separator and UTF-8 BOM let spreadsheet applications with Ukrainian locale open the file as is.
+func writeTranscriptCsv(writer io.Writer, transcript Transcript) error {
+	_, err := writer.Write([]byte("\xEF\xBB\xBF"))
+	if err != nil {
+		return err
+	}
+
+	csvWriter := csv.NewWriter(writer)
+	csvWriter.Comma = ';'
+
+	_ = csvWriter.Write(append(append([]string{}, transcriptSummaryHeader...), transcriptScoresHeader...))
+
+	for _, discipline := range transcript.Disciplines {
+		disciplineRow := []string{
+			discipline.Discipline.Name,
+			formatUkrainianNumber(discipline.ScoreRating.Total),
+			strconv.Itoa(discipline.ScoreRating.Rating),
+			strconv.Itoa(discipline.ScoreRating.StudentsCount),
+		}
+
+		if len(discipline.Scores) == 0 {
+			_ = csvWriter.Write(append(disciplineRow, "", "", "", "", ""))
+		}
+
+		for _, score := range discipline.Scores {
+			_ = csvWriter.Write(append(
+				append([]string{}, disciplineRow...),
+				formatUkrainianDate(score.Lesson.Date),
+				score.Lesson.Type.LongName,
+				formatUkrainianScore(score.FirstScore),
+				formatUkrainianScore(score.SecondScore),
+				formatUkrainianBool(score.IsAbsent),
+			))
+		}
+	}
+
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+// writeTranscriptXlsx writes a summary sheet followed by one sheet with lesson scores per discipline.
+func writeTranscriptXlsx(writer io.Writer, transcript Transcript) error {
+	file := excelize.NewFile()
+	defer file.Close()
+
+	err := file.SetSheetName(file.GetSheetName(0), xlsxSummarySheetName)
+	if err != nil {
+		return err
+	}
+
+	numberStyle, err := file.NewStyle(&excelize.Style{CustomNumFmt: stringPointer("0.##")})
+	if err != nil {
+		return err
+	}
+
+	dateStyle, err := file.NewStyle(&excelize.Style{CustomNumFmt: stringPointer("dd.mm.yyyy")})
+	if err != nil {
+		return err
+	}
+
+	err = file.SetSheetRow(xlsxSummarySheetName, "A1", &transcriptSummaryHeader)
+	if err != nil {
+		return err
+	}
+
+	// excelize compares sheet names case-insensitively, so used names are kept in lower case
+	usedSheetNames := map[string]bool{strings.ToLower(xlsxSummarySheetName): true}
+
+	for index, discipline := range transcript.Disciplines {
+		row := strconv.Itoa(index + 2)
+		err = file.SetSheetRow(xlsxSummarySheetName, "A"+row, &[]interface{}{
+			discipline.Discipline.Name,
+			discipline.ScoreRating.Total,
+			discipline.ScoreRating.Rating,
+			discipline.ScoreRating.StudentsCount,
+		})
+		if err == nil {
+			err = file.SetCellStyle(xlsxSummarySheetName, "B"+row, "B"+row, numberStyle)
+		}
+		if err != nil {
+			return err
+		}
+
+		sheetName := makeXlsxSheetName(discipline.Discipline.Name, usedSheetNames)
+		usedSheetNames[strings.ToLower(sheetName)] = true
+
+		err = writeTranscriptXlsxDisciplineSheet(file, sheetName, discipline.Scores, numberStyle, dateStyle)
+		if err != nil {
+			return err
+		}
+	}
+
+	return file.Write(writer)
+}
+
+func writeTranscriptXlsxDisciplineSheet(
+	file *excelize.File, sheetName string, scores []Score, numberStyle int, dateStyle int,
+) error {
+	_, err := file.NewSheet(sheetName)
+	if err == nil {
+		err = file.SetSheetRow(sheetName, "A1", &transcriptScoresHeader)
+	}
+
+	for scoreIndex := 0; err == nil && scoreIndex < len(scores); scoreIndex++ {
+		score := scores[scoreIndex]
+		row := strconv.Itoa(scoreIndex + 2)
+		err = file.SetSheetRow(sheetName, "A"+row, &[]interface{}{
+			xlsxDateValue(score.Lesson.Date),
+			score.Lesson.Type.LongName,
+			xlsxScoreValue(score.FirstScore),
+			xlsxScoreValue(score.SecondScore),
+			formatUkrainianBool(score.IsAbsent),
+		})
+		if err == nil {
+			err = file.SetCellStyle(sheetName, "A"+row, "A"+row, dateStyle)
+		}
+		if err == nil {
+			err = file.SetCellStyle(sheetName, "C"+row, "D"+row, numberStyle)
+		}
+	}
+
+	return err
+}
+
+// makeXlsxSheetName drops characters forbidden in sheet names, cuts the name to the allowed length
+// and adds a numeric suffix when the name is already used by another discipline.
+// usedSheetNames keys are lower case, as sheet names are case-insensitive.
+func makeXlsxSheetName(name string, usedSheetNames map[string]bool) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return -1
+		}
+		return r
+	}, strings.Trim(name, "' "))
+
+	if name == "" {
+		name = "Дисципліна"
+	}
+
+	sheetName := truncateRunes(name, xlsxSheetNameMaxLength)
+	for index := 2; usedSheetNames[strings.ToLower(sheetName)]; index++ {
+		suffix := " (" + strconv.Itoa(index) + ")"
+		sheetName = truncateRunes(name, xlsxSheetNameMaxLength-utf8.RuneCountInString(suffix)) + suffix
+	}
+
+	return sheetName
+}
+
+func truncateRunes(value string, length int) string {
+	runes := []rune(value)
+	if len(runes) > length {
+		return string(runes[:length])
+	}
+
+	return value
+}
+
+func xlsxDateValue(date time.Time) interface{} {
+	if date.IsZero() {
+		return ""
+	}
+
+	return date
+}
+
+func xlsxScoreValue(score *float32) interface{} {
+	if score == nil {
+		return ""
+	}
+
+	return *score
+}
+
+func formatUkrainianNumber(value float32) string {
+	return strings.Replace(strconv.FormatFloat(float64(value), 'f', -1, 32), ".", ",", 1)
+}
+
+func formatUkrainianScore(score *float32) string {
+	if score == nil {
+		return ""
+	}
+
+	return formatUkrainianNumber(*score)
+}
+
+func formatUkrainianDate(date time.Time) string {
+	if date.IsZero() {
+		return ""
+	}
+
+	return date.Format(ukrainianDateLayout)
+}
+
+func formatUkrainianBool(value bool) string {
+	if value {
+		return "так"
+	}
+
+	return ""
+}
+
+func stringPointer(value string) *string {
+	return &value
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bytes"
+	scoreApi "github.com/kneu-messenger-pigeon/score-api"
+	"github.com/stretchr/testify/assert"
+	"github.com/xuri/excelize/v2"
+	"strings"
+	"testing"
+	"time"
+)
+
+func getTestTranscript() Transcript {
+	lessonTypes := GetTestLessonTypes()
+
+	return Transcript{
+		StudentId: 1200,
+		Year:      2026,
+		Semester:  2,
+		Disciplines: DisciplineScoreResults{
+			{
+				Discipline: scoreApi.Discipline{
+					Id:   199,
+					Name: "Капітал!",
+				},
+				ScoreRating: ScoreRating{ScoreRating: scoreApi.ScoreRating{
+					Total:         17.5,
+					StudentsCount: 25,
+					Rating:        8,
+				}},
+				Scores: []Score{
+					{
+						Score: scoreApi.Score{
+							Lesson: scoreApi.Lesson{
+								Id:   245,
+								Date: time.Date(2023, time.Month(2), 12, 0, 0, 0, 0, defaultAcademicLocation),
+								Type: lessonTypes[1],
+							},
+							FirstScore:  floatPointer(4.5),
+							SecondScore: floatPointer(2),
+						},
+					},
+					{
+						Score: scoreApi.Score{
+							Lesson: scoreApi.Lesson{
+								Id:   255,
+								Date: time.Date(2023, time.Month(2), 14, 0, 0, 0, 0, defaultAcademicLocation),
+								Type: lessonTypes[15],
+							},
+							IsAbsent: true,
+						},
+					},
+				},
+			},
+			{
+				Discipline: scoreApi.Discipline{
+					Id:   200,
+					Name: "Гроші: кредит / банки",
+				},
+				ScoreRating: ScoreRating{ScoreRating: scoreApi.ScoreRating{
+					StudentsCount: 25,
+					Rating:        25,
+				}},
+			},
+		},
+	}
+}
+
+func TestTranscriptFilename(t *testing.T) {
+	assert.Equal(t, "scores-1200-2026-2.csv", getTestTranscript().filename("csv"))
+}
+
+func TestWriteTranscriptCsv(t *testing.T) {
+	expectedCsv := "\xEF\xBB\xBF" +
+		"Дисципліна;Загальний бал;Рейтинг;Кількість студентів;Дата;Тип заняття;Перша оцінка;Друга оцінка;Відсутність\n" +
+		"Капітал!;17,5;8;25;12.02.2023;Практичне зан.;4,5;2;\n" +
+		"Капітал!;17,5;8;25;14.02.2023;Модульний контроль.;;;так\n" +
+		"Гроші: кредит / банки;0;25;25;;;;;\n"
+
+	buffer := &bytes.Buffer{}
+	err := writeTranscriptCsv(buffer, getTestTranscript())
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedCsv, buffer.String())
+}
+
+func TestWriteTranscriptXlsx(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	err := writeTranscriptXlsx(buffer, getTestTranscript())
+	assert.NoError(t, err)
+
+	file, err := excelize.OpenReader(buffer)
+	assert.NoError(t, err)
+	defer file.Close()
+
+	assert.Equal(t, []string{"Підсумки", "Капітал!", "Гроші кредит  банки"}, file.GetSheetList())
+
+	rows, err := file.GetRows("Підсумки")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"Дисципліна", "Загальний бал", "Рейтинг", "Кількість студентів"},
+		{"Капітал!", "17.5", "8", "25"},
+		{"Гроші: кредит / банки", "0", "25", "25"},
+	}, rows)
+
+	rows, err = file.GetRows("Капітал!")
+	assert.NoError(t, err)
+	assert.Len(t, rows, 3)
+	assert.Equal(t, []string{"12.02.2023", "Практичне зан.", "4.5", "2"}, rows[1])
+	assert.Equal(t, []string{"14.02.2023", "Модульний контроль.", "", "", "так"}, rows[2])
+
+	t.Run("case_insensitive_sheet_names", func(t *testing.T) {
+		transcript := getTestTranscript()
+		transcript.Disciplines[1].Discipline.Name = "КАПІТАЛ!"
+		transcript.Disciplines = append(transcript.Disciplines, DisciplineScoreResult{
+			Discipline: scoreApi.Discipline{Id: 201, Name: "підсумки"},
+		})
+
+		buffer := &bytes.Buffer{}
+		err := writeTranscriptXlsx(buffer, transcript)
+		assert.NoError(t, err)
+
+		file, err := excelize.OpenReader(buffer)
+		assert.NoError(t, err)
+		defer file.Close()
+
+		assert.Equal(t, []string{"Підсумки", "Капітал!", "КАПІТАЛ! (2)", "підсумки (2)"}, file.GetSheetList())
+
+		rows, err := file.GetRows("Капітал!")
+		assert.NoError(t, err)
+		assert.Len(t, rows, 3)
+	})
+}
+
+func TestMakeXlsxSheetName(t *testing.T) {
+	usedSheetNames := map[string]bool{
+		"підсумки": true,
+	}
+
+	longName := strings.Repeat("Економіка ", 5)
+
+	first := makeXlsxSheetName(longName, usedSheetNames)
+	usedSheetNames[strings.ToLower(first)] = true
+	second := makeXlsxSheetName(longName, usedSheetNames)
+
+	assert.Equal(t, "Економіка Економіка Економіка Е", first)
+	assert.Equal(t, "Економіка Економіка Економі (2)", second)
+	assert.Equal(t, "Підсумки (2)", makeXlsxSheetName("Підсумки", usedSheetNames))
+	assert.Equal(t, "ПІДСУМКИ (2)", makeXlsxSheetName("ПІДСУМКИ", usedSheetNames))
+	assert.Equal(t, "економіка економіка економі (2)", makeXlsxSheetName(strings.ToLower(longName), usedSheetNames))
+	assert.Equal(t, "Дисципліна", makeXlsxSheetName("[*]", usedSheetNames))
+}
@@ -0,0 +1,285 @@
+package main
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	scoreApi "github.com/kneu-messenger-pigeon/score-api"
+	"github.com/redis/go-redis/v9"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	messageIncorrectStudentId    = "Incorrect student_id: %s"
+	messageIncorrectDisciplineId = "Incorrect discipline_Id: %s"
+	messageIncorrectLessonId     = "Incorrect lesson_id: %s"
+	messageIncorrectGroupId      = "Incorrect group_id: %s"
+	messageIncorrectParameter    = "Incorrect %s: %s"
+	messageDisciplineNotExists   = "Discipline not exists: %s"
+	messageLessonNotExists       = "Lesson not exists: %s"
+	messageGroupNotExists        = "Group not exists: %s"
+	messageStudentNotExists      = "Student not exists: %s"
+	messageTooManyStreams        = "Too many event streams, retry later"
+	messageChangesExpired        = "Changes since %s are not available anymore, reload all scores"
+)
+
+const translationsRedisKey = "translations"
+
+//go:embed translations.json
+var embeddedTranslationsJSON []byte
+
+type LessonTypeTranslation struct {
+	ShortName string `json:"shortName"`
+	LongName  string `json:"longName"`
+}
+
+// Translation - lesson types keyed by stored (Ukrainian) short name and messages keyed by English format string
+type Translation struct {
+	LessonTypes map[string]LessonTypeTranslation `json:"lessonTypes"`
+	Messages    map[string]string                `json:"messages"`
+}
+
+// TranslationCatalogue - translations keyed by language code
+type TranslationCatalogue map[string]Translation
+
+// MessageError keeps message format and arguments, so the message could be translated for the response.
+type MessageError struct {
+	Format string
+	Args   []interface{}
+}
+
+func (err MessageError) Error() string {
+	return fmt.Sprintf(err.Format, err.Args...)
+}
+
+// Translator localizes lesson types and error messages for the language of the request.
+// Without requested language responses keep English messages and lesson type names stored in Redis.
+// Catalogue is replaced by the hourly update while requests are translated, so it is stored atomically.
+type Translator struct {
+	redis     *redis.Client
+	embedded  TranslationCatalogue
+	catalogue atomic.Pointer[TranslationCatalogue]
+}
+
+// loaded returns the current catalogue, methods translating several values use one loaded catalogue
+func (translator *Translator) loaded() TranslationCatalogue {
+	return *translator.catalogue.Load()
+}
+
+func (translator *Translator) language(c *gin.Context) string {
+	return translator.loaded().language(c)
+}
+
+func (catalogue TranslationCatalogue) language(c *gin.Context) string {
+	if language := catalogue.supportedLanguage(c.Query("lang")); language != "" {
+		return language
+	}
+
+	for _, language := range parseAcceptLanguage(c.GetHeader("Accept-Language")) {
+		if catalogue.isSupported(language) {
+			return language
+		}
+	}
+
+	return ""
+}
+
+// supportedLanguage normalizes language tag, unsupported language is empty like without requested language
+func (catalogue TranslationCatalogue) supportedLanguage(tag string) string {
+	if language := normalizeLanguage(tag); catalogue.isSupported(language) {
+		return language
+	}
+
+	return ""
+}
+
+func (catalogue TranslationCatalogue) isSupported(language string) bool {
+	_, exists := catalogue[language]
+	return language != "" && exists
+}
+
+func (translator *Translator) message(c *gin.Context, format string, args ...interface{}) string {
+	catalogue := translator.loaded()
+	if translated := catalogue[catalogue.language(c)].Messages[format]; translated != "" {
+		format = translated
+	}
+
+	return fmt.Sprintf(format, args...)
+}
+
+func (translator *Translator) errorMessage(c *gin.Context, err error) string {
+	var messageError MessageError
+	if errors.As(err, &messageError) {
+		return translator.message(c, messageError.Format, messageError.Args...)
+	}
+
+	return err.Error()
+}
+
+func (translator *Translator) lessonType(language string, lessonType scoreApi.LessonType) scoreApi.LessonType {
+	return translator.loaded().lessonType(language, lessonType)
+}
+
+func (catalogue TranslationCatalogue) lessonType(language string, lessonType scoreApi.LessonType) scoreApi.LessonType {
+	translation, exists := catalogue[language].LessonTypes[lessonType.ShortName]
+	if exists && translation.ShortName != "" {
+		lessonType.ShortName = translation.ShortName
+	}
+	if exists && translation.LongName != "" {
+		lessonType.LongName = translation.LongName
+	}
+
+	return lessonType
+}
+
+func (translator *Translator) localizeScores(c *gin.Context, scores []Score) {
+	catalogue := translator.loaded()
+	catalogue.localizeScores(catalogue.language(c), scores)
+}
+
+func (catalogue TranslationCatalogue) localizeScores(language string, scores []Score) {
+	for index := range scores {
+		scores[index].Lesson.Type = catalogue.lessonType(language, scores[index].Lesson.Type)
+	}
+}
+
+func (translator *Translator) localizeLessonTypes(c *gin.Context, lessonTypes []scoreApi.LessonType) {
+	catalogue := translator.loaded()
+	language := catalogue.language(c)
+	for index := range lessonTypes {
+		lessonTypes[index] = catalogue.lessonType(language, lessonTypes[index])
+	}
+}
+
+func (translator *Translator) localizeAttendance(c *gin.Context, studentAttendance StudentAttendance) {
+	catalogue := translator.loaded()
+	language := catalogue.language(c)
+	for index := range studentAttendance.LessonTypes {
+		studentAttendance.LessonTypes[index].LessonType = catalogue.lessonType(
+			language, studentAttendance.LessonTypes[index].LessonType,
+		)
+	}
+}
+
+func (translator *Translator) periodicallyUpdateCatalogue(ctx context.Context) {
+	for ctx.Err() == nil {
+		translator.updateCatalogue()
+		time.Sleep(time.Hour)
+	}
+}
+
+// updateCatalogue merges translations stored in Redis over embedded ones
+func (translator *Translator) updateCatalogue() {
+	var redisCatalogue TranslationCatalogue
+
+	translationsJSON, _ := translator.redis.Get(context.Background(), translationsRedisKey).Bytes()
+	if len(translationsJSON) > 1 && json.Unmarshal(translationsJSON, &redisCatalogue) == nil {
+		catalogue := mergeTranslationCatalogues(translator.embedded, redisCatalogue)
+		translator.catalogue.Store(&catalogue)
+	}
+}
+
+func mergeTranslationCatalogues(base TranslationCatalogue, override TranslationCatalogue) TranslationCatalogue {
+	merged := make(TranslationCatalogue, len(base)+len(override))
+
+	for _, catalogue := range []TranslationCatalogue{base, override} {
+		for language, translation := range catalogue {
+			language = normalizeLanguage(language)
+			mergedTranslation, exists := merged[language]
+			if !exists {
+				mergedTranslation = Translation{
+					LessonTypes: make(map[string]LessonTypeTranslation),
+					Messages:    make(map[string]string),
+				}
+			}
+
+			for shortName, lessonTypeTranslation := range translation.LessonTypes {
+				mergedLessonType := mergedTranslation.LessonTypes[shortName]
+				if lessonTypeTranslation.ShortName != "" {
+					mergedLessonType.ShortName = lessonTypeTranslation.ShortName
+				}
+				if lessonTypeTranslation.LongName != "" {
+					mergedLessonType.LongName = lessonTypeTranslation.LongName
+				}
+				mergedTranslation.LessonTypes[shortName] = mergedLessonType
+			}
+			for format, message := range translation.Messages {
+				mergedTranslation.Messages[format] = message
+			}
+
+			merged[language] = mergedTranslation
+		}
+	}
+
+	return merged
+}
+
+// parseAcceptLanguage returns primary language subtags of the header ordered by quality
+func parseAcceptLanguage(header string) []string {
+	type weightedLanguage struct {
+		language string
+		quality  float64
+	}
+
+	weightedLanguages := make([]weightedLanguage, 0)
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		language := normalizeLanguage(tag)
+		if language == "" || language == "*" {
+			continue
+		}
+
+		quality := 1.0
+		if qualityString, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			quality, _ = strconv.ParseFloat(qualityString, 64)
+		}
+
+		if quality > 0 {
+			weightedLanguages = append(weightedLanguages, weightedLanguage{language: language, quality: quality})
+		}
+	}
+
+	sort.SliceStable(weightedLanguages, func(i, j int) bool {
+		return weightedLanguages[i].quality > weightedLanguages[j].quality
+	})
+
+	languages := make([]string, len(weightedLanguages))
+	for index, item := range weightedLanguages {
+		languages[index] = item.language
+	}
+
+	return languages
+}
+
+// normalizeLanguage turns `en-US` into `en`
+func normalizeLanguage(tag string) string {
+	language, _, _ := strings.Cut(strings.TrimSpace(tag), "-")
+	return strings.ToLower(language)
+}
+
+func NewTranslator(redis *redis.Client, ctx context.Context) (*Translator, error) {
+	var embedded TranslationCatalogue
+	if err := json.Unmarshal(embeddedTranslationsJSON, &embedded); err != nil {
+		return nil, fmt.Errorf("failed to parse embedded translations: %w", err)
+	}
+
+	translator := &Translator{
+		redis:    redis,
+		embedded: embedded,
+	}
+	catalogue := mergeTranslationCatalogues(embedded, nil)
+	translator.catalogue.Store(&catalogue)
+
+	if redis != nil {
+		go translator.periodicallyUpdateCatalogue(ctx)
+	}
+
+	return translator, nil
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redismock/v9"
+	scoreApi "github.com/kneu-messenger-pigeon/score-api"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func makeTestTranslatorContext(query string, acceptLanguage string) *gin.Context {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request, _ = http.NewRequest(http.MethodGet, "/?"+query, nil)
+	if acceptLanguage != "" {
+		c.Request.Header.Set("Accept-Language", acceptLanguage)
+	}
+
+	return c
+}
+
+func TestTranslatorLanguage(t *testing.T) {
+	translator, _ := NewTranslator(nil, context.Background())
+
+	testCases := []struct {
+		query          string
+		acceptLanguage string
+		expected       string
+	}{
+		{query: "", acceptLanguage: "", expected: ""},
+		{query: "lang=en", acceptLanguage: "uk", expected: "en"},
+		{query: "lang=UK", acceptLanguage: "", expected: "uk"},
+		{query: "lang=de", acceptLanguage: "en-US,en;q=0.9", expected: "en"},
+		{query: "", acceptLanguage: "de-DE, uk;q=0.5, en;q=0.8", expected: "en"},
+		{query: "", acceptLanguage: "en;q=0, uk-UA;q=0.3", expected: "uk"},
+		{query: "", acceptLanguage: "de, *", expected: ""},
+	}
+
+	for _, testCase := range testCases {
+		assert.Equal(
+			t, testCase.expected,
+			translator.language(makeTestTranslatorContext(testCase.query, testCase.acceptLanguage)),
+			testCase.query+" | "+testCase.acceptLanguage,
+		)
+	}
+
+	assert.Equal(t, "en", translator.loaded().supportedLanguage("EN"))
+	assert.Equal(t, "", translator.loaded().supportedLanguage("de"))
+}
+
+func TestTranslatorMessage(t *testing.T) {
+	translator, _ := NewTranslator(nil, context.Background())
+
+	t.Run("default", func(t *testing.T) {
+		c := makeTestTranslatorContext("", "")
+		assert.Equal(t, "Incorrect student_id: -1", translator.message(c, messageIncorrectStudentId, "-1"))
+	})
+
+	t.Run("translated", func(t *testing.T) {
+		c := makeTestTranslatorContext("lang=uk", "")
+		assert.Equal(t, "Некоректний student_id: -1", translator.message(c, messageIncorrectStudentId, "-1"))
+		assert.Equal(t, "Unknown: 1", translator.message(c, "Unknown: %d", 1))
+	})
+
+	t.Run("error", func(t *testing.T) {
+		c := makeTestTranslatorContext("lang=uk", "")
+		messageError := MessageError{Format: messageIncorrectParameter, Args: []interface{}{"limit", "0"}}
+
+		assert.Equal(t, "Incorrect limit: 0", messageError.Error())
+		assert.Equal(t, "Некоректний параметр limit: 0", translator.errorMessage(c, messageError))
+		assert.Equal(t, "expected error", translator.errorMessage(c, errors.New("expected error")))
+	})
+}
+
+func TestTranslatorLessonType(t *testing.T) {
+	translator, _ := NewTranslator(nil, context.Background())
+	lessonTypes := GetTestLessonTypes()
+
+	assert.Equal(t, scoreApi.LessonType{Id: 15, ShortName: "MT", LongName: "Module test"}, translator.lessonType("en", lessonTypes[15]))
+	assert.Equal(t, lessonTypes[15], translator.lessonType("uk", lessonTypes[15]))
+	assert.Equal(t, lessonTypes[15], translator.lessonType("", lessonTypes[15]))
+
+	unknownLessonType := scoreApi.LessonType{Id: 99, ShortName: "Інше", LongName: "Інше заняття"}
+	assert.Equal(t, unknownLessonType, translator.lessonType("en", unknownLessonType))
+}
+
+func TestTranslatorLocalize(t *testing.T) {
+	translator, _ := NewTranslator(nil, context.Background())
+	c := makeTestTranslatorContext("lang=en", "")
+
+	scores := []Score{
+		{Score: scoreApi.Score{Lesson: scoreApi.Lesson{Id: 1, Type: GetTestLessonTypes()[1]}}},
+	}
+	translator.localizeScores(c, scores)
+	assert.Equal(t, "Practical class", scores[0].Lesson.Type.LongName)
+
+	lessonTypes := []scoreApi.LessonType{GetTestLessonTypes()[15]}
+	translator.localizeLessonTypes(c, lessonTypes)
+	assert.Equal(t, "MT", lessonTypes[0].ShortName)
+
+	studentAttendance := StudentAttendance{
+		LessonTypes: []LessonTypeAttendance{{LessonType: GetTestLessonTypes()[1]}},
+	}
+	translator.localizeAttendance(c, studentAttendance)
+	assert.Equal(t, "Pr", studentAttendance.LessonTypes[0].LessonType.ShortName)
+}
+
+func TestTranslatorUpdateCatalogue(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.ExpectGet(translationsRedisKey).SetVal(`{
+			"en": {"lessonTypes": {"МК": {"longName": "Module control"}}},
+			"uk": {"messages": {"Lesson not exists: %s": "Немає заняття: %s"}},
+			"PL": {"lessonTypes": {"МК": {"shortName": "KM", "longName": "Kolokwium modułowe"}}}
+		}`)
+
+		translator, _ := NewTranslator(nil, context.Background())
+		translator.redis = redisClient
+		translator.updateCatalogue()
+
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+
+		mkLessonType := GetTestLessonTypes()[15]
+		assert.Equal(t, scoreApi.LessonType{Id: 15, ShortName: "MT", LongName: "Module control"}, translator.lessonType("en", mkLessonType))
+		assert.Equal(t, scoreApi.LessonType{Id: 15, ShortName: "KM", LongName: "Kolokwium modułowe"}, translator.lessonType("pl", mkLessonType))
+
+		c := makeTestTranslatorContext("lang=uk", "")
+		assert.Equal(t, "Немає заняття: 5", translator.message(c, messageLessonNotExists, "5"))
+		assert.Equal(t, "Дисципліна не існує: 5", translator.message(c, messageDisciplineNotExists, "5"))
+
+		assert.Equal(t, "pl", translator.language(makeTestTranslatorContext("", "pl-PL")))
+	})
+
+	t.Run("wrong_json", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.ExpectGet(translationsRedisKey).SetVal(`{"en": [`)
+
+		translator, _ := NewTranslator(nil, context.Background())
+		translator.redis = redisClient
+		translator.updateCatalogue()
+
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+		assert.Equal(t, translator.embedded["en"].LessonTypes, translator.loaded()["en"].LessonTypes)
+	})
+	t.Run("concurrent_requests", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		for index := 0; index < 10; index++ {
+			redisMock.ExpectGet(translationsRedisKey).SetVal(`{"uk": {"messages": {"Lesson not exists: %s": "Немає заняття: %s"}}}`)
+		}
+
+		translator, _ := NewTranslator(nil, context.Background())
+		translator.redis = redisClient
+
+		done := make(chan bool)
+		go func() {
+			for index := 0; index < 10; index++ {
+				translator.updateCatalogue()
+			}
+			close(done)
+		}()
+
+		c := makeTestTranslatorContext("lang=uk", "")
+		for index := 0; index < 10; index++ {
+			assert.Contains(t, []string{"Немає заняття: 5", "Заняття не існує: 5"}, translator.message(c, messageLessonNotExists, "5"))
+		}
+		<-done
+
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+		assert.Equal(t, "Немає заняття: 5", translator.message(c, messageLessonNotExists, "5"))
+	})
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// webhookQueueKey - zset of encoded WebhookDelivery scored by unix milliseconds of the next attempt
+const webhookQueueKey = "webhook_queue"
+
+// webhookDeadLettersKey - list of the last deliveries which failed all attempts
+const webhookDeadLettersKey = "webhook_dead_letters"
+const webhookDeadLettersLimit = 1000
+
+const DefaultWebhookMaxAttempts = 8
+const webhookBatchSize = 50
+const webhookRequestTimeout = time.Second * 10
+
+// webhookDeliveryLease - delay of the next attempt of delivery in progress, so it is retried if the process dies.
+// It is longer than sending of the whole batch with request timeouts, so a slow batch is not claimed twice.
+const webhookDeliveryLease = webhookBatchSize*webhookRequestTimeout + time.Minute
+const webhookBaseBackoff = time.Second * 5
+const webhookMaxBackoff = time.Hour
+
+const (
+	webhookIdHeader        = "X-Webhook-Id"
+	webhookTimestampHeader = "X-Webhook-Timestamp"
+	webhookSignatureHeader = "X-Webhook-Signature"
+)
+
+// claimWebhookDeliveriesScript atomically takes due deliveries and delays their next attempt until the lease end,
+// so concurrent instances never send the same delivery at once.
+// KEYS: webhook queue. ARGV: now and lease end in unix milliseconds, batch size.
+var claimWebhookDeliveriesScript = redis.NewScript(`
+local members = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, ARGV[3])
+for _, member in ipairs(members) do
+	redis.call('ZADD', KEYS[1], 'XX', ARGV[2], member)
+end
+
+return members
+`)
+
+// WebhookDelivery - payload of the event for one webhook url, stored in the persistent retry queue
+type WebhookDelivery struct {
+	Id        string `json:"id"`
+	Url       string `json:"url"`
+	Body      string `json:"body"`
+	Attempt   int    `json:"attempt"`
+	LastError string `json:"lastError,omitempty"`
+}
+
+// WebhookNotifier queues score change events for every configured url and delivers them with retries.
+// Delivery is at least once: receivers should deduplicate events by X-Webhook-Id.
+type WebhookNotifier struct {
+	out         io.Writer
+	redis       *redis.Client
+	httpClient  *http.Client
+	urls        []string
+	secret      string
+	maxAttempts int
+}
+
+func (notifier *WebhookNotifier) notify(pipe redis.Pipeliner, event ScoreChangeEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	members := make([]redis.Z, len(notifier.urls))
+	for index, url := range notifier.urls {
+		delivery, _ := json.Marshal(WebhookDelivery{
+			Id:   event.Id,
+			Url:  url,
+			Body: string(body),
+		})
+		members[index] = redis.Z{
+			Score:  float64(time.Now().UnixMilli()),
+			Member: string(delivery),
+		}
+	}
+
+	pipe.ZAdd(context.Background(), webhookQueueKey, members...)
+
+	return nil
+}
+
+func (notifier *WebhookNotifier) periodicallyDeliver(ctx context.Context) {
+	for ctx.Err() == nil {
+		count, err := notifier.deliverDue(time.Now())
+		if err != nil {
+			_, _ = fmt.Fprintf(notifier.out, "Failed to deliver webhooks: %s\n", err)
+		}
+
+		if count < webhookBatchSize {
+			time.Sleep(time.Second)
+		}
+	}
+}
+
+// deliverDue sends deliveries with next attempt before now and returns amount of processed deliveries
+func (notifier *WebhookNotifier) deliverDue(now time.Time) (int, error) {
+	ctx := context.Background()
+	members, err := claimWebhookDeliveriesScript.Run(
+		ctx, notifier.redis, []string{webhookQueueKey},
+		now.UnixMilli(), now.Add(webhookDeliveryLease).UnixMilli(), webhookBatchSize,
+	).StringSlice()
+	if err != nil || len(members) == 0 {
+		return 0, err
+	}
+
+	for _, member := range members {
+		var delivery WebhookDelivery
+		if err = json.Unmarshal([]byte(member), &delivery); err != nil {
+			_, _ = fmt.Fprintf(notifier.out, "Drop malformed webhook delivery %q: %s\n", member, err)
+			err = notifier.redis.ZRem(ctx, webhookQueueKey, member).Err()
+		} else {
+			err = notifier.complete(member, delivery, notifier.send(delivery, now), now)
+		}
+
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return len(members), nil
+}
+
+// complete removes delivery from the queue, failed delivery is scheduled again with backoff or moved to dead letters
+func (notifier *WebhookNotifier) complete(member string, delivery WebhookDelivery, sendErr error, now time.Time) error {
+	ctx := context.Background()
+	_, err := notifier.redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZRem(ctx, webhookQueueKey, member)
+		if sendErr == nil {
+			return nil
+		}
+
+		delivery.Attempt++
+		delivery.LastError = sendErr.Error()
+		encoded, _ := json.Marshal(delivery)
+
+		if delivery.Attempt >= notifier.maxAttempts {
+			pipe.LPush(ctx, webhookDeadLettersKey, string(encoded))
+			pipe.LTrim(ctx, webhookDeadLettersKey, 0, webhookDeadLettersLimit-1)
+		} else {
+			pipe.ZAdd(ctx, webhookQueueKey, redis.Z{
+				Score:  float64(now.Add(webhookBackoff(delivery.Attempt)).UnixMilli()),
+				Member: string(encoded),
+			})
+		}
+
+		return nil
+	})
+
+	return err
+}
+
+func (notifier *WebhookNotifier) send(delivery WebhookDelivery, now time.Time) error {
+	request, err := http.NewRequest(http.MethodPost, delivery.Url, strings.NewReader(delivery.Body))
+	if err != nil {
+		return err
+	}
+
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(webhookIdHeader, delivery.Id)
+	request.Header.Set(webhookTimestampHeader, timestamp)
+	request.Header.Set(webhookSignatureHeader, "sha256="+signWebhookBody(notifier.secret, timestamp, delivery.Body))
+
+	response, err := notifier.httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	_, _ = io.Copy(io.Discard, response.Body)
+	_ = response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("unexpected response status %d", response.StatusCode)
+	}
+
+	return nil
+}
+
+// signWebhookBody returns hex HMAC-SHA256 of `{timestamp}.{body}`
+func signWebhookBody(secret string, timestamp string, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "." + body))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookBackoff doubles delay after every failed attempt starting from webhookBaseBackoff up to webhookMaxBackoff
+func webhookBackoff(attempt int) time.Duration {
+	backoff := webhookBaseBackoff
+	for index := 1; index < attempt && backoff < webhookMaxBackoff; index++ {
+		backoff *= 2
+	}
+
+	return min(backoff, webhookMaxBackoff)
+}
+
+func NewWebhookNotifier(
+	out io.Writer, redis *redis.Client, urls []string, secret string, maxAttempts int, ctx context.Context,
+) *WebhookNotifier {
+	notifier := &WebhookNotifier{
+		out:         out,
+		redis:       redis,
+		httpClient:  &http.Client{Timeout: webhookRequestTimeout},
+		urls:        urls,
+		secret:      secret,
+		maxAttempts: maxAttempts,
+	}
+
+	go notifier.periodicallyDeliver(ctx)
+
+	return notifier
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/go-redis/redismock/v9"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSignWebhookBody(t *testing.T) {
+	assert.Equal(
+		t, "5ad265e6615b64b835cae994e1526056136c85c5a0d090d4f35b730288b456de",
+		signWebhookBody("secret", "1700000000", `{"id":"abc"}`),
+	)
+}
+
+func TestWebhookBackoff(t *testing.T) {
+	assert.Equal(t, time.Second*5, webhookBackoff(1))
+	assert.Equal(t, time.Second*10, webhookBackoff(2))
+	assert.Equal(t, time.Second*40, webhookBackoff(4))
+	assert.Equal(t, time.Hour, webhookBackoff(20))
+}
+
+func TestWebhookNotifierNotify(t *testing.T) {
+	event := ScoreChangeEvent{
+		Id:           "abc",
+		Year:         2026,
+		Semester:     2,
+		StudentId:    1200,
+		DisciplineId: 199,
+		Changes:      []ScoreChange{{Type: ScoreChangeNewAbsence, LessonId: 245, Half: 1}},
+		DetectedAt:   time.Date(2026, time.Month(3), 15, 12, 0, 0, 0, time.UTC),
+	}
+	expectedBody := `{"id":"abc","year":2026,"semester":2,"studentId":1200,"disciplineId":199,` +
+		`"changes":[{"type":"new_absence","lessonId":245,"half":1}],"detectedAt":"2026-03-15T12:00:00Z"}`
+
+	var actualArgs []interface{}
+	redisClient, redisMock := redismock.NewClientMock()
+	redisMock.CustomMatch(func(expected, actual []interface{}) error {
+		actualArgs = actual
+		return nil
+	}).ExpectZAdd(webhookQueueKey, redis.Z{}, redis.Z{}).SetVal(2)
+
+	notifier := WebhookNotifier{
+		urls: []string{"https://first.example/hook", "https://second.example/hook"},
+	}
+
+	pipe := redisClient.Pipeline()
+	err := notifier.notify(pipe, event)
+
+	assert.NoError(t, err)
+	_, err = pipe.Exec(context.Background())
+	assert.NoError(t, err)
+	assert.NoError(t, redisMock.ExpectationsWereMet())
+	assert.Len(t, actualArgs, 6)
+	assert.Equal(t, "zadd", actualArgs[0])
+	assert.Equal(t, webhookQueueKey, actualArgs[1])
+	assert.InDelta(t, time.Now().UnixMilli(), actualArgs[2], float64(time.Minute.Milliseconds()))
+
+	var delivery WebhookDelivery
+	assert.NoError(t, json.Unmarshal([]byte(actualArgs[3].(string)), &delivery))
+	assert.Equal(t, WebhookDelivery{Id: "abc", Url: "https://first.example/hook", Body: expectedBody}, delivery)
+	assert.NoError(t, json.Unmarshal([]byte(actualArgs[5].(string)), &delivery))
+	assert.Equal(t, "https://second.example/hook", delivery.Url)
+}
+
+func TestWebhookNotifierDeliverDue(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	expectClaim := func(redisMock redismock.ClientMock) *redismock.ExpectedCmd {
+		return redisMock.ExpectEvalSha(
+			claimWebhookDeliveriesScript.Hash(), []string{webhookQueueKey},
+			int64(1700000000000), now.Add(webhookDeliveryLease).UnixMilli(), webhookBatchSize,
+		)
+	}
+
+	makeMember := func(delivery WebhookDelivery) string {
+		encoded, _ := json.Marshal(delivery)
+		return string(encoded)
+	}
+
+	t.Run("success", func(t *testing.T) {
+		requests := make([]*http.Request, 0)
+		bodies := make([]string, 0)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			requests = append(requests, r)
+			bodies = append(bodies, string(body))
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		member := makeMember(WebhookDelivery{Id: "abc", Url: server.URL, Body: `{"id":"abc"}`})
+
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.MatchExpectationsInOrder(true)
+		expectClaim(redisMock).SetVal([]interface{}{member})
+		redisMock.ExpectTxPipeline()
+		redisMock.ExpectZRem(webhookQueueKey, member).SetVal(1)
+		redisMock.ExpectTxPipelineExec()
+
+		notifier := WebhookNotifier{
+			out:         &bytes.Buffer{},
+			redis:       redisClient,
+			httpClient:  server.Client(),
+			secret:      "secret",
+			maxAttempts: 3,
+		}
+
+		count, err := notifier.deliverDue(now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count)
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+		assert.Len(t, requests, 1)
+		assert.Equal(t, http.MethodPost, requests[0].Method)
+		assert.Equal(t, `{"id":"abc"}`, bodies[0])
+		assert.Equal(t, "application/json", requests[0].Header.Get("Content-Type"))
+		assert.Equal(t, "abc", requests[0].Header.Get(webhookIdHeader))
+		assert.Equal(t, "1700000000", requests[0].Header.Get(webhookTimestampHeader))
+		assert.Equal(
+			t, "sha256=5ad265e6615b64b835cae994e1526056136c85c5a0d090d4f35b730288b456de",
+			requests[0].Header.Get(webhookSignatureHeader),
+		)
+	})
+
+	t.Run("retry", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer server.Close()
+
+		member := makeMember(WebhookDelivery{Id: "abc", Url: server.URL, Body: `{}`, Attempt: 1})
+		retryMember := makeMember(WebhookDelivery{
+			Id: "abc", Url: server.URL, Body: `{}`, Attempt: 2, LastError: "unexpected response status 502",
+		})
+
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.MatchExpectationsInOrder(true)
+		expectClaim(redisMock).SetVal([]interface{}{member})
+		redisMock.ExpectTxPipeline()
+		redisMock.ExpectZRem(webhookQueueKey, member).SetVal(1)
+		redisMock.ExpectZAdd(webhookQueueKey, redis.Z{
+			Score:  float64(now.Add(time.Second * 10).UnixMilli()),
+			Member: retryMember,
+		}).SetVal(1)
+		redisMock.ExpectTxPipelineExec()
+
+		notifier := WebhookNotifier{
+			out:         &bytes.Buffer{},
+			redis:       redisClient,
+			httpClient:  server.Client(),
+			maxAttempts: 3,
+		}
+
+		count, err := notifier.deliverDue(now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count)
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+	})
+
+	t.Run("dead_letter", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		member := makeMember(WebhookDelivery{Id: "abc", Url: server.URL, Body: `{}`, Attempt: 2})
+		deadMember := makeMember(WebhookDelivery{
+			Id: "abc", Url: server.URL, Body: `{}`, Attempt: 3, LastError: "unexpected response status 500",
+		})
+
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.MatchExpectationsInOrder(true)
+		expectClaim(redisMock).SetVal([]interface{}{member})
+		redisMock.ExpectTxPipeline()
+		redisMock.ExpectZRem(webhookQueueKey, member).SetVal(1)
+		redisMock.ExpectLPush(webhookDeadLettersKey, deadMember).SetVal(1)
+		redisMock.ExpectLTrim(webhookDeadLettersKey, 0, webhookDeadLettersLimit-1).SetVal("OK")
+		redisMock.ExpectTxPipelineExec()
+
+		notifier := WebhookNotifier{
+			out:         &bytes.Buffer{},
+			redis:       redisClient,
+			httpClient:  server.Client(),
+			maxAttempts: 3,
+		}
+
+		_, err := notifier.deliverDue(now)
+
+		assert.NoError(t, err)
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		redisMock.MatchExpectationsInOrder(true)
+		expectClaim(redisMock).SetVal([]interface{}{"{"})
+		redisMock.ExpectZRem(webhookQueueKey, "{").SetVal(1)
+
+		out := &bytes.Buffer{}
+		notifier := WebhookNotifier{
+			out:   out,
+			redis: redisClient,
+		}
+
+		count, err := notifier.deliverDue(now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count)
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+		assert.Contains(t, out.String(), `Drop malformed webhook delivery "{"`)
+	})
+
+	t.Run("lease_covers_batch", func(t *testing.T) {
+		assert.Greater(t, webhookDeliveryLease, webhookBatchSize*webhookRequestTimeout)
+	})
+
+	t.Run("empty_queue", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		expectClaim(redisMock).SetVal([]interface{}{})
+
+		notifier := WebhookNotifier{redis: redisClient}
+
+		count, err := notifier.deliverDue(now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, count)
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+	})
+
+	t.Run("redis_error", func(t *testing.T) {
+		redisClient, redisMock := redismock.NewClientMock()
+		expectClaim(redisMock).SetErr(assert.AnError)
+
+		notifier := WebhookNotifier{redis: redisClient}
+
+		_, err := notifier.deliverDue(now)
+
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/redis/go-redis/v9"
@@ -13,35 +14,131 @@ import (
 const ExitCodeMainError = 1
 
 func runApp(out io.Writer, listenAndServe func(string, http.Handler) error) error {
-	envFilename := ""
-	if _, err := os.Stat(".env"); err == nil {
-		envFilename = ".env"
+	config, err := loadConfig(getEnvFilename())
+	if err != nil {
+		return err
 	}
 
-	var opt *redis.Options
-	config, err := loadConfig(envFilename)
-	if err == nil {
-		opt, err = redis.ParseURL(config.redisDsn)
+	gradeScales, err := loadGradeScales(config.gradeScales)
+	if err != nil {
+		return err
 	}
 
+	academicCalendar, err := loadAcademicCalendar(config.academicCalendar, config.timezone)
 	if err != nil {
 		return err
 	}
 
+	dependencies := RouterDependencies{
+		out:              out,
+		adminToken:       config.adminToken,
+		location:         config.timezone,
+		dateOnlyJson:     config.dateOnlyJson,
+		changesRetention: config.changesRetention,
+	}
+	if config.storageBackend == StorageBackendMemory {
+		semesterSwitchPolicy := NewSemesterSwitchPolicy(
+			config.semesterSwitch, academicCalendar, nil, config.timezone, context.Background(),
+		)
+
+		var storage *MemoryStorage
+		storage, err = loadMemoryStorage(config.storageFixture, newAcademicRules(config, semesterSwitchPolicy))
+		if err != nil {
+			return err
+		}
+
+		dependencies.storage = storage
+		dependencies.groupRatings = storage
+		dependencies.summaries = storage
+		dependencies.risks = storage
+		dependencies.changes = storage
+		dependencies.translator, err = NewTranslator(nil, context.Background())
+		if err != nil {
+			return err
+		}
+		dependencies.gradeScaler = NewGradeScaler(nil, gradeScales, context.Background())
+	} else {
+		var storage *Storage
+		storage, dependencies.translator, dependencies.gradeScaler, dependencies.eventHub, err = newRedisBackend(
+			out, config, gradeScales, academicCalendar,
+		)
+		if err != nil {
+			return err
+		}
+
+		dependencies.storage = storage
+		dependencies.groupRatings = storage.groupRatingLoader
+		dependencies.summaries = &StudentSummaryLoader{storage: storage}
+		dependencies.risks = &DisciplineRiskLoader{storage: storage}
+		dependencies.changes = &StudentChangesLoader{storage: storage}
+	}
+
+	gin.SetMode(gin.ReleaseMode)
+	return listenAndServe(config.listenAddress, setupRouter(dependencies))
+}
+
+func newRedisBackend(
+	out io.Writer, config Config, gradeScales GradeScales, academicCalendar *AcademicCalendar,
+) (*Storage, *Translator, *GradeScaler, *StudentEventHub, error) {
+	opt, err := redis.ParseURL(config.redisDsn)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+
 	redisClient := redis.NewClient(opt)
+	translator, err := NewTranslator(redisClient, context.Background())
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
 
 	_, err = redisClient.Ping(context.Background()).Result()
 	if err != nil {
 		fmt.Fprintf(out, "Failed to connect to redisClient: %s\n", err.Error())
 	}
 
-	storage := NewStorage(redisClient, context.Background())
-
-	gin.SetMode(gin.ReleaseMode)
-	return listenAndServe(
-		config.listenAddress,
-		setupRouter(out, storage),
+	semesterSwitchPolicy := NewSemesterSwitchPolicy(
+		config.semesterSwitch, academicCalendar, redisClient, config.timezone, context.Background(),
 	)
+
+	storage := NewStorage(redisClient, newAcademicRules(config, semesterSwitchPolicy), context.Background())
+	eventHub := newStudentEventHub(out, storage, config.maxEventStreams)
+
+	notifiers := ScoreChangeNotifiers{NewStudentChangeIndex(config.changesRetention)}
+	if len(config.webhooks.urls) != 0 {
+		notifiers = append(notifiers, NewWebhookNotifier(
+			out, redisClient, config.webhooks.urls, config.webhooks.secret,
+			config.webhooks.maxAttempts, context.Background(),
+		))
+	}
+	detector := NewScoreChangeDetector(out, redisClient, notifiers)
+
+	handleKeyChange := func(key string) error {
+		return errors.Join(eventHub.handleKeyChange(key), detector.handleKeyChange(key))
+	}
+
+	// score events from Kafka replace keyspace notifications as the source of changed keys
+	if config.scoreEvents == ScoreEventsSourceKafka {
+		NewScoreEventConsumer(
+			out, newKafkaReader(config.kafkaHost, config.kafkaTopic, config.kafkaGroupId),
+			storage, handleKeyChange, context.Background(),
+		)
+	} else {
+		go listenScoreKeyChanges(context.Background(), redisClient, handleKeyChange)
+	}
+
+	return storage,
+		translator,
+		NewGradeScaler(redisClient, gradeScales, context.Background()),
+		eventHub,
+		nil
+}
+
+func getEnvFilename() string {
+	if _, err := os.Stat(".env"); err == nil {
+		return ".env"
+	}
+
+	return ""
 }
 
 func handleExitError(errStream io.Writer, err error) int {
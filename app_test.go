@@ -5,6 +5,7 @@ import (
 	"errors"
 	"github.com/stretchr/testify/assert"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 )
@@ -28,6 +29,65 @@ func TestRunApp(t *testing.T) {
 		assert.Equal(t, expectedConfig.listenAddress, actualListen)
 	})
 
+	t.Run("Run with memory storage", func(t *testing.T) {
+		_ = os.Setenv("REDIS_DSN", "")
+		_ = os.Setenv("LISTEN", expectedConfig.listenAddress)
+		_ = os.Setenv("STORAGE_BACKEND", StorageBackendMemory)
+		_ = os.Setenv("STORAGE_FIXTURE", "fixture.example.yaml")
+		defer os.Unsetenv("STORAGE_BACKEND")
+		defer os.Unsetenv("STORAGE_FIXTURE")
+
+		var out bytes.Buffer
+		var actualHandler http.Handler
+		listenAndServe := func(_ string, handler http.Handler) error {
+			actualHandler = handler
+			return nil
+		}
+
+		err := runApp(&out, listenAndServe)
+		assert.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/v1/students/1200/disciplines/199", nil)
+		actualHandler.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"name":"Капітал!"`)
+		assert.Contains(t, w.Body.String(), `"grades":{"ects":"F","national":"2"}`)
+	})
+
+	t.Run("Run with wrong grade scales file", func(t *testing.T) {
+		_ = os.Setenv("LISTEN", expectedConfig.listenAddress)
+		_ = os.Setenv("STORAGE_BACKEND", StorageBackendMemory)
+		_ = os.Setenv("STORAGE_FIXTURE", "fixture.example.yaml")
+		_ = os.Setenv("GRADE_SCALES_FILE", "not-exists.json")
+		defer os.Unsetenv("STORAGE_BACKEND")
+		defer os.Unsetenv("STORAGE_FIXTURE")
+		defer os.Unsetenv("GRADE_SCALES_FILE")
+
+		var out bytes.Buffer
+		err := runApp(&out, func(string, http.Handler) error {
+			return nil
+		})
+
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("Run with wrong fixture", func(t *testing.T) {
+		_ = os.Setenv("LISTEN", expectedConfig.listenAddress)
+		_ = os.Setenv("STORAGE_BACKEND", StorageBackendMemory)
+		_ = os.Setenv("STORAGE_FIXTURE", "not-exists.yaml")
+		defer os.Unsetenv("STORAGE_BACKEND")
+		defer os.Unsetenv("STORAGE_FIXTURE")
+
+		var out bytes.Buffer
+		err := runApp(&out, func(string, http.Handler) error {
+			return nil
+		})
+
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+
 	t.Run("Run with wrong env file", func(t *testing.T) {
 		previousWd, err := os.Getwd()
 		assert.NoErrorf(t, err, "Failed to get working dir: %s", err)
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+const (
+	ExitCodeCheckWarnings = 2
+	ExitCodeCheckErrors   = 3
+)
+
+// runCheckCommand scans keys of one year and prints found inconsistencies.
+// Exit code is 0 for clean keyspace, ExitCodeCheckWarnings or ExitCodeCheckErrors by the highest issue severity
+// and ExitCodeMainError when the check could not be done.
+func runCheckCommand(args []string, out io.Writer, errStream io.Writer) int {
+	flagSet := newCommandFlagSet("check", errStream)
+	year := flagSet.Int("year", 0, "year to check, default is currentYear from redis")
+	format := flagSet.String("format", "text", "output format: text or json")
+
+	if err := flagSet.Parse(args); err != nil {
+		return ExitCodeMainError
+	}
+
+	if *format != "text" && *format != "json" {
+		return handleExitError(errStream, errors.New("Unknown format "+*format+", expected text or json"))
+	}
+
+	redisClient, checkYear, _, err := connectCommandRedis(*year)
+	if err != nil {
+		return handleExitError(errStream, err)
+	}
+
+	checker := KeyspaceChecker{
+		redis: redisClient,
+		year:  checkYear,
+	}
+
+	report, err := checker.check(context.Background())
+	if err != nil {
+		return handleExitError(errStream, err)
+	}
+
+	if *format == "json" {
+		err = writeCheckReportJson(out, report)
+	} else {
+		err = writeCheckReportText(out, report)
+	}
+
+	if err != nil {
+		return handleExitError(errStream, err)
+	}
+
+	return getCheckExitCode(report)
+}
+
+func getCheckExitCode(report CheckReport) int {
+	if report.Errors != 0 {
+		return ExitCodeCheckErrors
+	}
+
+	if report.Warnings != 0 {
+		return ExitCodeCheckWarnings
+	}
+
+	return 0
+}
+
+func writeCheckReportJson(out io.Writer, report CheckReport) error {
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(report)
+}
+
+func writeCheckReportText(out io.Writer, report CheckReport) error {
+	_, err := fmt.Fprintf(
+		out, "Keyspace check of year %d: %d errors, %d warnings\n",
+		report.Year, report.Errors, report.Warnings,
+	)
+	if err != nil || len(report.Issues) == 0 {
+		return err
+	}
+
+	writer := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(writer, "SEVERITY\tCHECK\tKEY\tMESSAGE")
+	for _, issue := range report.Issues {
+		_, _ = fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n", issue.Severity, issue.Check, issue.Key, issue.Message)
+	}
+
+	return writer.Flush()
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+func getTestCheckReport() CheckReport {
+	return CheckReport{
+		Year:     2026,
+		Errors:   1,
+		Warnings: 1,
+		Issues: []CheckIssue{
+			{
+				Check:    CheckTotalMismatch,
+				Severity: CheckSeverityError,
+				Key:      "2026:1:totals:199",
+				Message:  "student 1300 total 5 does not match sum of scores 3",
+			},
+			{
+				Check:    CheckDisciplineWithoutName,
+				Severity: CheckSeverityWarning,
+				Key:      "2026:discipline:200",
+				Message:  "discipline 200 has no name",
+			},
+		},
+	}
+}
+
+func TestGetCheckExitCode(t *testing.T) {
+	assert.Equal(t, 0, getCheckExitCode(CheckReport{}))
+	assert.Equal(t, ExitCodeCheckWarnings, getCheckExitCode(CheckReport{Warnings: 2}))
+	assert.Equal(t, ExitCodeCheckErrors, getCheckExitCode(CheckReport{Errors: 1, Warnings: 2}))
+}
+
+func TestWriteCheckReport(t *testing.T) {
+	t.Run("text", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		err := writeCheckReportText(out, getTestCheckReport())
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Keyspace check of year 2026: 1 errors, 1 warnings\n"+
+			"SEVERITY  CHECK                    KEY                  MESSAGE\n"+
+			"error     total_mismatch           2026:1:totals:199    student 1300 total 5 does not match sum of scores 3\n"+
+			"warning   discipline_without_name  2026:discipline:200  discipline 200 has no name\n",
+			out.String(),
+		)
+	})
+
+	t.Run("text_clean", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		err := writeCheckReportText(out, CheckReport{Year: 2026})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Keyspace check of year 2026: 0 errors, 0 warnings\n", out.String())
+	})
+
+	t.Run("json", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		err := writeCheckReportJson(out, getTestCheckReport())
+		assert.NoError(t, err)
+
+		actualReport := CheckReport{}
+		assert.NoError(t, json.Unmarshal(out.Bytes(), &actualReport))
+		assert.Equal(t, getTestCheckReport(), actualReport)
+	})
+}
+
+func TestRunCheckCommand(t *testing.T) {
+	t.Run("wrong_flag", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		errStream := &bytes.Buffer{}
+
+		assert.Equal(t, ExitCodeMainError, runCheckCommand([]string{"--unknown"}, out, errStream))
+		assert.Contains(t, errStream.String(), "flag provided but not defined")
+	})
+
+	t.Run("wrong_format", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		errStream := &bytes.Buffer{}
+
+		assert.Equal(t, ExitCodeMainError, runCheckCommand([]string{"--format", "xml"}, out, errStream))
+		assert.Contains(t, errStream.String(), "Unknown format xml")
+	})
+
+	t.Run("empty_config", func(t *testing.T) {
+		_ = os.Setenv("REDIS_DSN", "")
+		out := &bytes.Buffer{}
+		errStream := &bytes.Buffer{}
+
+		assert.Equal(t, ExitCodeMainError, runCheckCommand([]string{"--year", "2026"}, out, errStream))
+		assert.Contains(t, errStream.String(), "empty REDIS_DSN")
+	})
+
+	t.Run("redis_unavailable", func(t *testing.T) {
+		_ = os.Setenv("REDIS_DSN", "redis://127.0.0.1:1/0")
+		defer os.Unsetenv("REDIS_DSN")
+		out := &bytes.Buffer{}
+		errStream := &bytes.Buffer{}
+
+		assert.Equal(t, ExitCodeMainError, runCheckCommand([]string{"--year", "2026"}, out, errStream))
+		assert.Contains(t, errStream.String(), "Failed to connect to redis")
+	})
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Command runs CLI subcommand with its own arguments and returns the process exit code
+type Command func(args []string, out io.Writer, errStream io.Writer) int
+
+var commands = map[string]Command{
+	"check":      runCheckCommand,
+	"student":    runStudentCommand,
+	"discipline": runDisciplineCommand,
+	"lesson":     runLessonCommand,
+	"rating":     runRatingCommand,
+	"seed":       runSeedCommand,
+	"snapshot":   runSnapshotCommand,
+}
+
+// runCommand starts the API server without arguments or dispatches to the subcommand named by the first argument
+func runCommand(args []string, out io.Writer, errStream io.Writer, listenAndServe func(string, http.Handler) error) int {
+	if len(args) == 0 || args[0] == "serve" {
+		return handleExitError(errStream, runApp(out, listenAndServe))
+	}
+
+	command, exists := commands[args[0]]
+	if !exists {
+		return handleExitError(errStream, errors.New(
+			"Unknown command "+args[0]+", available commands: serve, "+strings.Join(getCommandNames(), ", "),
+		))
+	}
+
+	return command(args[1:], out, errStream)
+}
+
+func getCommandNames() []string {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// newCommandFlagSet creates flag set which writes usage and parse errors to errStream instead of exiting
+func newCommandFlagSet(name string, errStream io.Writer) *flag.FlagSet {
+	flagSet := flag.NewFlagSet(name, flag.ContinueOnError)
+	flagSet.SetOutput(errStream)
+
+	return flagSet
+}
+
+// connectCommandRedis loads config from env (and .env file) and connects to Redis database of score storage.
+// Year is taken from Redis `currentYear` when it is not passed explicitly.
+func connectCommandRedis(year int) (*redis.Client, int, Config, error) {
+	redisClient, config, err := newCommandRedisClient(-1)
+	if err != nil {
+		return nil, 0, Config{}, err
+	}
+
+	if year == 0 {
+		year, _ = redisClient.Get(context.Background(), "currentYear").Int()
+		if year == 0 {
+			return nil, 0, Config{}, errors.New("year is not passed and currentYear is not set in redis")
+		}
+	}
+
+	return redisClient, year, config, nil
+}
+
+// newCommandRedisClient loads config and connects to Redis, not negative db replaces database number of REDIS_DSN
+func newCommandRedisClient(db int) (*redis.Client, Config, error) {
+	config, err := loadCommandConfig(getEnvFilename())
+	if err != nil {
+		return nil, Config{}, err
+	}
+
+	opt, err := redis.ParseURL(config.redisDsn)
+	if err != nil {
+		return nil, Config{}, err
+	}
+
+	if db >= 0 {
+		opt.DB = db
+	}
+
+	redisClient := redis.NewClient(opt)
+	if err = redisClient.Ping(context.Background()).Err(); err != nil {
+		return nil, Config{}, fmt.Errorf("Failed to connect to redis: %w", err)
+	}
+
+	return redisClient, config, nil
+}
+
+// newCommandAcademicRules makes rules of storage used by commands, calendar policy loads Redis calendar on creation
+func newCommandAcademicRules(config Config, redisClient *redis.Client) (AcademicRules, error) {
+	academicCalendar, err := loadAcademicCalendar(config.academicCalendar, config.timezone)
+	if err != nil {
+		return AcademicRules{}, err
+	}
+
+	return newAcademicRules(config, NewSemesterSwitchPolicy(
+		config.semesterSwitch, academicCalendar, redisClient, config.timezone, context.Background(),
+	)), nil
+}
+
+// scanSortedKeys returns all keys matched by pattern in lexicographical order
+func scanSortedKeys(ctx context.Context, redisClient *redis.Client, pattern string) ([]string, error) {
+	keys := make([]string, 0)
+	iterator := redisClient.Scan(ctx, 0, pattern, 1000).Iterator()
+	for iterator.Next(ctx) {
+		keys = append(keys, iterator.Val())
+	}
+
+	sort.Strings(keys)
+
+	return keys, iterator.Err()
+}
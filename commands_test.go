@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"os"
+	"testing"
+)
+
+func TestRunCommand(t *testing.T) {
+	t.Run("serve", func(t *testing.T) {
+		_ = os.Setenv("REDIS_DSN", expectedConfig.redisDsn)
+		_ = os.Setenv("LISTEN", expectedConfig.listenAddress)
+
+		for _, args := range [][]string{{}, {"serve"}} {
+			out := &bytes.Buffer{}
+			errStream := &bytes.Buffer{}
+
+			actualListen := ""
+			listenAndServe := func(listen string, _ http.Handler) error {
+				actualListen = listen
+				return nil
+			}
+
+			assert.Equal(t, 0, runCommand(args, out, errStream, listenAndServe))
+			assert.Equal(t, expectedConfig.listenAddress, actualListen)
+		}
+	})
+
+	t.Run("command", func(t *testing.T) {
+		previousCommands := commands
+		defer func() {
+			commands = previousCommands
+		}()
+
+		var actualArgs []string
+		commands = map[string]Command{
+			"test": func(args []string, out io.Writer, errStream io.Writer) int {
+				actualArgs = args
+				return 7
+			},
+		}
+
+		exitCode := runCommand([]string{"test", "--year", "2026"}, &bytes.Buffer{}, &bytes.Buffer{}, nil)
+
+		assert.Equal(t, 7, exitCode)
+		assert.Equal(t, []string{"--year", "2026"}, actualArgs)
+	})
+
+	t.Run("unknown_command", func(t *testing.T) {
+		errStream := &bytes.Buffer{}
+
+		assert.Equal(t, ExitCodeMainError, runCommand([]string{"unknown"}, &bytes.Buffer{}, errStream, nil))
+		assert.Contains(t, errStream.String(), "Unknown command unknown, available commands: serve, check, discipline, lesson, rating, seed, snapshot, student")
+	})
+}
@@ -4,15 +4,93 @@ import (
 	"errors"
 	"fmt"
 	"github.com/joho/godotenv"
+	"net/url"
 	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	StorageBackendRedis  = "redis"
+	StorageBackendMemory = "memory"
+)
+
+const (
+	ScoreEventsSourceKeyspace = "keyspace"
+	ScoreEventsSourceKafka    = "kafka"
 )
 
 type Config struct {
-	redisDsn      string
-	listenAddress string
+	scoreEvents      string
+	kafkaHost        string
+	kafkaTopic       string
+	kafkaGroupId     string
+	redisDsn         string
+	listenAddress    string
+	adminToken       string
+	timezone         *time.Location
+	dateOnlyJson     bool
+	storageBackend   string
+	storageFixture   string
+	zeroTotalsMode   ZeroTotalsMode
+	passingTotal     float32
+	admissionTotal   float32
+	absenceRatio     float32
+	gradeScales      string
+	semesterSwitch   string
+	academicCalendar string
+	webhooks         WebhookConfig
+	maxEventStreams  int
+	changesRetention time.Duration
+}
+
+type WebhookConfig struct {
+	urls        []string
+	secret      string
+	maxAttempts int
 }
 
 func loadConfig(envFilename string) (Config, error) {
+	config, err := loadEnvConfig(envFilename)
+	if err != nil {
+		return Config{}, err
+	}
+
+	if config.storageBackend == StorageBackendRedis && config.redisDsn == "" {
+		return Config{}, errors.New("empty REDIS_DSN")
+	}
+
+	if config.storageBackend == StorageBackendMemory && config.storageFixture == "" {
+		return Config{}, errors.New("empty STORAGE_FIXTURE")
+	}
+
+	if config.listenAddress == "" {
+		return Config{}, errors.New("empty LISTEN")
+	}
+
+	if config.scoreEvents == ScoreEventsSourceKafka && config.kafkaHost == "" {
+		return Config{}, errors.New("empty KAFKA_HOST")
+	}
+
+	if len(config.webhooks.urls) != 0 && config.webhooks.secret == "" {
+		return Config{}, errors.New("empty WEBHOOK_SECRET")
+	}
+
+	return config, nil
+}
+
+// loadCommandConfig loads config for CLI commands, which do not listen for requests and always work with Redis
+func loadCommandConfig(envFilename string) (Config, error) {
+	config, err := loadEnvConfig(envFilename)
+	if err == nil && config.redisDsn == "" {
+		return Config{}, errors.New("empty REDIS_DSN")
+	}
+
+	return config, err
+}
+
+func loadEnvConfig(envFilename string) (Config, error) {
 	if envFilename != "" {
 		err := godotenv.Load(envFilename)
 		if err != nil {
@@ -20,16 +98,140 @@ func loadConfig(envFilename string) (Config, error) {
 		}
 	}
 	config := Config{
-		redisDsn:      os.Getenv("REDIS_DSN"),
-		listenAddress: os.Getenv("LISTEN"),
+		scoreEvents:      os.Getenv("SCORE_EVENTS_SOURCE"),
+		kafkaHost:        os.Getenv("KAFKA_HOST"),
+		kafkaTopic:       os.Getenv("KAFKA_TOPIC"),
+		kafkaGroupId:     os.Getenv("KAFKA_GROUP_ID"),
+		redisDsn:         os.Getenv("REDIS_DSN"),
+		listenAddress:    os.Getenv("LISTEN"),
+		adminToken:       os.Getenv("ADMIN_TOKEN"),
+		storageBackend:   os.Getenv("STORAGE_BACKEND"),
+		storageFixture:   os.Getenv("STORAGE_FIXTURE"),
+		gradeScales:      os.Getenv("GRADE_SCALES_FILE"),
+		semesterSwitch:   os.Getenv("SEMESTER_SWITCH_POLICY"),
+		academicCalendar: os.Getenv("ACADEMIC_CALENDAR_FILE"),
 	}
 
-	if config.redisDsn == "" {
-		return Config{}, errors.New("empty REDIS_DSN")
+	if config.scoreEvents == "" {
+		config.scoreEvents = ScoreEventsSourceKeyspace
+	} else if config.scoreEvents != ScoreEventsSourceKeyspace && config.scoreEvents != ScoreEventsSourceKafka {
+		return Config{}, errors.New("Wrong SCORE_EVENTS_SOURCE: " + config.scoreEvents)
 	}
 
-	if config.listenAddress == "" {
-		return Config{}, errors.New("empty LISTEN")
+	if config.kafkaTopic == "" {
+		config.kafkaTopic = DefaultScoreEventsTopic
+	}
+
+	if config.kafkaGroupId == "" {
+		hostname, _ := os.Hostname()
+		config.kafkaGroupId = ScoreEventsConsumerGroupPrefix + hostname
+	}
+
+	if config.storageBackend == "" {
+		config.storageBackend = StorageBackendRedis
+	} else if config.storageBackend != StorageBackendRedis && config.storageBackend != StorageBackendMemory {
+		return Config{}, errors.New("Wrong STORAGE_BACKEND: " + config.storageBackend)
+	}
+
+	if config.semesterSwitch == "" {
+		config.semesterSwitch = SemesterSwitchPolicyInterval
+	} else if config.semesterSwitch != SemesterSwitchPolicyInterval &&
+		config.semesterSwitch != SemesterSwitchPolicyThreshold && config.semesterSwitch != SemesterSwitchPolicyCalendar {
+		return Config{}, errors.New("Wrong SEMESTER_SWITCH_POLICY: " + config.semesterSwitch)
+	}
+
+	config.timezone = defaultAcademicLocation
+	if os.Getenv("TIMEZONE") != "" {
+		var err error
+		config.timezone, err = time.LoadLocation(os.Getenv("TIMEZONE"))
+		if err != nil {
+			return Config{}, errors.New(fmt.Sprintf("Wrong TIMEZONE %s: %s", os.Getenv("TIMEZONE"), err))
+		}
+	}
+
+	config.zeroTotalsMode = ZeroTotalsLast
+	if os.Getenv("RATING_ZERO_TOTALS") != "" {
+		var err error
+		config.zeroTotalsMode, err = parseZeroTotalsMode(os.Getenv("RATING_ZERO_TOTALS"))
+		if err != nil {
+			return Config{}, err
+		}
+	}
+
+	config.passingTotal = DefaultPassingTotal
+	if os.Getenv("PASSING_TOTAL") != "" {
+		passingTotal, err := strconv.ParseFloat(os.Getenv("PASSING_TOTAL"), 32)
+		if err != nil || passingTotal < 0 {
+			return Config{}, errors.New("Wrong PASSING_TOTAL: " + os.Getenv("PASSING_TOTAL"))
+		}
+		config.passingTotal = float32(passingTotal)
+	}
+
+	config.admissionTotal = DefaultAdmissionTotal
+	if os.Getenv("ADMISSION_TOTAL") != "" {
+		admissionTotal, err := strconv.ParseFloat(os.Getenv("ADMISSION_TOTAL"), 32)
+		if err != nil || admissionTotal < 0 {
+			return Config{}, errors.New("Wrong ADMISSION_TOTAL: " + os.Getenv("ADMISSION_TOTAL"))
+		}
+		config.admissionTotal = float32(admissionTotal)
+	}
+
+	config.absenceRatio = DefaultAbsenceRiskRatio
+	if os.Getenv("ABSENCE_RISK_RATIO") != "" {
+		absenceRatio, err := strconv.ParseFloat(os.Getenv("ABSENCE_RISK_RATIO"), 32)
+		if err != nil || absenceRatio <= 0 || absenceRatio > 1 {
+			return Config{}, errors.New("Wrong ABSENCE_RISK_RATIO: " + os.Getenv("ABSENCE_RISK_RATIO"))
+		}
+		config.absenceRatio = float32(absenceRatio)
+	}
+
+	config.webhooks.secret = os.Getenv("WEBHOOK_SECRET")
+	for _, webhookUrl := range strings.Split(os.Getenv("WEBHOOK_URLS"), ",") {
+		webhookUrl = strings.TrimSpace(webhookUrl)
+		if webhookUrl == "" {
+			continue
+		}
+
+		parsedUrl, err := url.Parse(webhookUrl)
+		if err != nil || (parsedUrl.Scheme != "http" && parsedUrl.Scheme != "https") || parsedUrl.Host == "" {
+			return Config{}, errors.New("Wrong WEBHOOK_URLS: " + webhookUrl)
+		}
+		config.webhooks.urls = append(config.webhooks.urls, webhookUrl)
+	}
+
+	config.webhooks.maxAttempts = DefaultWebhookMaxAttempts
+	if os.Getenv("WEBHOOK_MAX_ATTEMPTS") != "" {
+		maxAttempts, err := strconv.Atoi(os.Getenv("WEBHOOK_MAX_ATTEMPTS"))
+		if err != nil || maxAttempts <= 0 {
+			return Config{}, errors.New("Wrong WEBHOOK_MAX_ATTEMPTS: " + os.Getenv("WEBHOOK_MAX_ATTEMPTS"))
+		}
+		config.webhooks.maxAttempts = maxAttempts
+	}
+
+	config.maxEventStreams = DefaultMaxStudentEventStreams
+	if os.Getenv("SSE_MAX_STREAMS") != "" {
+		maxEventStreams, err := strconv.Atoi(os.Getenv("SSE_MAX_STREAMS"))
+		if err != nil || maxEventStreams <= 0 {
+			return Config{}, errors.New("Wrong SSE_MAX_STREAMS: " + os.Getenv("SSE_MAX_STREAMS"))
+		}
+		config.maxEventStreams = maxEventStreams
+	}
+
+	config.changesRetention = time.Hour * 24 * DefaultStudentChangesRetentionDays
+	if os.Getenv("CHANGES_RETENTION_DAYS") != "" {
+		retentionDays, err := strconv.Atoi(os.Getenv("CHANGES_RETENTION_DAYS"))
+		if err != nil || retentionDays <= 0 {
+			return Config{}, errors.New("Wrong CHANGES_RETENTION_DAYS: " + os.Getenv("CHANGES_RETENTION_DAYS"))
+		}
+		config.changesRetention = time.Hour * 24 * time.Duration(retentionDays)
+	}
+
+	if os.Getenv("DATE_ONLY_JSON") != "" {
+		var err error
+		config.dateOnlyJson, err = strconv.ParseBool(os.Getenv("DATE_ONLY_JSON"))
+		if err != nil {
+			return Config{}, errors.New("Wrong DATE_ONLY_JSON: " + os.Getenv("DATE_ONLY_JSON"))
+		}
 	}
 
 	return config, nil
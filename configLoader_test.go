@@ -5,11 +5,30 @@ import (
 	"github.com/stretchr/testify/assert"
 	"os"
 	"testing"
+	"time"
 )
 
 var expectedConfig = Config{
-	redisDsn:      "REDIS:6379",
-	listenAddress: ":8080",
+	scoreEvents:      ScoreEventsSourceKeyspace,
+	kafkaTopic:       DefaultScoreEventsTopic,
+	kafkaGroupId:     ScoreEventsConsumerGroupPrefix + getTestHostname(),
+	redisDsn:         "REDIS:6379",
+	listenAddress:    ":8080",
+	timezone:         defaultAcademicLocation,
+	storageBackend:   StorageBackendRedis,
+	zeroTotalsMode:   ZeroTotalsLast,
+	passingTotal:     DefaultPassingTotal,
+	admissionTotal:   DefaultAdmissionTotal,
+	absenceRatio:     DefaultAbsenceRiskRatio,
+	semesterSwitch:   SemesterSwitchPolicyInterval,
+	webhooks:         WebhookConfig{maxAttempts: DefaultWebhookMaxAttempts},
+	maxEventStreams:  DefaultMaxStudentEventStreams,
+	changesRetention: time.Hour * 24 * DefaultStudentChangesRetentionDays,
+}
+
+func getTestHostname() string {
+	hostname, _ := os.Hostname()
+	return hostname
 }
 
 func TestLoadConfigFromEnvVars(t *testing.T) {
@@ -67,6 +86,277 @@ func TestLoadConfigFromEnvVars(t *testing.T) {
 
 	})
 
+	t.Run("TimezoneAndDateOnlyJson", func(t *testing.T) {
+		_ = os.Setenv("REDIS_DSN", expectedConfig.redisDsn)
+		_ = os.Setenv("LISTEN", expectedConfig.listenAddress)
+		_ = os.Setenv("TIMEZONE", "America/New_York")
+		_ = os.Setenv("DATE_ONLY_JSON", "true")
+		defer os.Unsetenv("TIMEZONE")
+		defer os.Unsetenv("DATE_ONLY_JSON")
+
+		config, err := loadConfig("")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "America/New_York", config.timezone.String())
+		assert.True(t, config.dateOnlyJson)
+	})
+
+	t.Run("WrongTimezone", func(t *testing.T) {
+		_ = os.Setenv("REDIS_DSN", expectedConfig.redisDsn)
+		_ = os.Setenv("LISTEN", expectedConfig.listenAddress)
+		_ = os.Setenv("TIMEZONE", "Europe/Atlantis")
+		defer os.Unsetenv("TIMEZONE")
+
+		config, err := loadConfig("")
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "Wrong TIMEZONE Europe/Atlantis")
+		assert.Empty(t, config.redisDsn)
+	})
+
+	t.Run("WrongDateOnlyJson", func(t *testing.T) {
+		_ = os.Setenv("REDIS_DSN", expectedConfig.redisDsn)
+		_ = os.Setenv("LISTEN", expectedConfig.listenAddress)
+		_ = os.Setenv("DATE_ONLY_JSON", "sometimes")
+		defer os.Unsetenv("DATE_ONLY_JSON")
+
+		config, err := loadConfig("")
+
+		assert.EqualError(t, err, "Wrong DATE_ONLY_JSON: sometimes")
+		assert.Empty(t, config.redisDsn)
+	})
+
+	t.Run("MemoryStorageBackend", func(t *testing.T) {
+		_ = os.Setenv("REDIS_DSN", "")
+		_ = os.Setenv("LISTEN", expectedConfig.listenAddress)
+		_ = os.Setenv("STORAGE_BACKEND", StorageBackendMemory)
+		defer os.Unsetenv("STORAGE_BACKEND")
+
+		_, err := loadConfig("")
+		assert.EqualError(t, err, "empty STORAGE_FIXTURE")
+
+		_ = os.Setenv("STORAGE_FIXTURE", "fixture.example.yaml")
+		defer os.Unsetenv("STORAGE_FIXTURE")
+
+		config, err := loadConfig("")
+
+		assert.NoError(t, err)
+		assert.Equal(t, StorageBackendMemory, config.storageBackend)
+		assert.Equal(t, "fixture.example.yaml", config.storageFixture)
+		assert.Empty(t, config.redisDsn)
+
+		_, err = loadCommandConfig("")
+		assert.EqualError(t, err, "empty REDIS_DSN")
+	})
+
+	t.Run("WrongStorageBackend", func(t *testing.T) {
+		_ = os.Setenv("REDIS_DSN", expectedConfig.redisDsn)
+		_ = os.Setenv("LISTEN", expectedConfig.listenAddress)
+		_ = os.Setenv("STORAGE_BACKEND", "mysql")
+		defer os.Unsetenv("STORAGE_BACKEND")
+
+		config, err := loadConfig("")
+
+		assert.EqualError(t, err, "Wrong STORAGE_BACKEND: mysql")
+		assert.Empty(t, config.redisDsn)
+	})
+
+	t.Run("AdminToken", func(t *testing.T) {
+		_ = os.Setenv("REDIS_DSN", expectedConfig.redisDsn)
+		_ = os.Setenv("LISTEN", expectedConfig.listenAddress)
+		_ = os.Setenv("ADMIN_TOKEN", "admin-secret")
+		defer os.Unsetenv("ADMIN_TOKEN")
+
+		config, err := loadConfig("")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "admin-secret", config.adminToken)
+	})
+
+	t.Run("RatingZeroTotals", func(t *testing.T) {
+		_ = os.Setenv("REDIS_DSN", expectedConfig.redisDsn)
+		_ = os.Setenv("LISTEN", expectedConfig.listenAddress)
+		_ = os.Setenv("RATING_ZERO_TOTALS", "exclude")
+		defer os.Unsetenv("RATING_ZERO_TOTALS")
+
+		config, err := loadConfig("")
+
+		assert.NoError(t, err)
+		assert.Equal(t, ZeroTotalsExcluded, config.zeroTotalsMode)
+
+		_ = os.Setenv("RATING_ZERO_TOTALS", "first")
+
+		_, err = loadConfig("")
+		assert.EqualError(t, err, "Wrong RATING_ZERO_TOTALS: first, expected last, shared or exclude")
+	})
+
+	t.Run("PassingTotal", func(t *testing.T) {
+		_ = os.Setenv("REDIS_DSN", expectedConfig.redisDsn)
+		_ = os.Setenv("LISTEN", expectedConfig.listenAddress)
+		_ = os.Setenv("PASSING_TOTAL", "50.5")
+		defer os.Unsetenv("PASSING_TOTAL")
+
+		config, err := loadConfig("")
+
+		assert.NoError(t, err)
+		assert.Equal(t, float32(50.5), config.passingTotal)
+
+		_ = os.Setenv("PASSING_TOTAL", "sixty")
+
+		_, err = loadConfig("")
+		assert.EqualError(t, err, "Wrong PASSING_TOTAL: sixty")
+	})
+
+	t.Run("RiskThresholds", func(t *testing.T) {
+		_ = os.Setenv("REDIS_DSN", expectedConfig.redisDsn)
+		_ = os.Setenv("LISTEN", expectedConfig.listenAddress)
+		_ = os.Setenv("ADMISSION_TOTAL", "40")
+		_ = os.Setenv("ABSENCE_RISK_RATIO", "0.25")
+		defer os.Unsetenv("ADMISSION_TOTAL")
+		defer os.Unsetenv("ABSENCE_RISK_RATIO")
+
+		config, err := loadConfig("")
+
+		assert.NoError(t, err)
+		assert.Equal(t, float32(40), config.admissionTotal)
+		assert.Equal(t, float32(0.25), config.absenceRatio)
+
+		_ = os.Setenv("ADMISSION_TOTAL", "-1")
+
+		_, err = loadConfig("")
+		assert.EqualError(t, err, "Wrong ADMISSION_TOTAL: -1")
+
+		_ = os.Setenv("ADMISSION_TOTAL", "40")
+		_ = os.Setenv("ABSENCE_RISK_RATIO", "1.5")
+
+		_, err = loadConfig("")
+		assert.EqualError(t, err, "Wrong ABSENCE_RISK_RATIO: 1.5")
+	})
+
+	t.Run("Webhooks", func(t *testing.T) {
+		_ = os.Setenv("REDIS_DSN", expectedConfig.redisDsn)
+		_ = os.Setenv("LISTEN", expectedConfig.listenAddress)
+		_ = os.Setenv("WEBHOOK_URLS", "https://bot.example/hook, http://localhost:8090/scores,")
+		_ = os.Setenv("WEBHOOK_SECRET", "secret")
+		_ = os.Setenv("WEBHOOK_MAX_ATTEMPTS", "5")
+		defer os.Unsetenv("WEBHOOK_URLS")
+		defer os.Unsetenv("WEBHOOK_SECRET")
+		defer os.Unsetenv("WEBHOOK_MAX_ATTEMPTS")
+
+		config, err := loadConfig("")
+
+		assert.NoError(t, err)
+		assert.Equal(t, WebhookConfig{
+			urls:        []string{"https://bot.example/hook", "http://localhost:8090/scores"},
+			secret:      "secret",
+			maxAttempts: 5,
+		}, config.webhooks)
+
+		_ = os.Setenv("WEBHOOK_MAX_ATTEMPTS", "0")
+		_, err = loadConfig("")
+		assert.EqualError(t, err, "Wrong WEBHOOK_MAX_ATTEMPTS: 0")
+
+		_ = os.Setenv("WEBHOOK_MAX_ATTEMPTS", "5")
+		_ = os.Setenv("WEBHOOK_SECRET", "")
+		_, err = loadConfig("")
+		assert.EqualError(t, err, "empty WEBHOOK_SECRET")
+
+		_ = os.Setenv("WEBHOOK_URLS", "ftp://bot.example/hook")
+		_, err = loadConfig("")
+		assert.EqualError(t, err, "Wrong WEBHOOK_URLS: ftp://bot.example/hook")
+	})
+
+	t.Run("MaxEventStreams", func(t *testing.T) {
+		_ = os.Setenv("REDIS_DSN", expectedConfig.redisDsn)
+		_ = os.Setenv("LISTEN", expectedConfig.listenAddress)
+		_ = os.Setenv("SSE_MAX_STREAMS", "20")
+		defer os.Unsetenv("SSE_MAX_STREAMS")
+
+		config, err := loadConfig("")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 20, config.maxEventStreams)
+
+		_ = os.Setenv("SSE_MAX_STREAMS", "-1")
+
+		_, err = loadConfig("")
+		assert.EqualError(t, err, "Wrong SSE_MAX_STREAMS: -1")
+	})
+
+	t.Run("SemesterSwitchPolicy", func(t *testing.T) {
+		_ = os.Setenv("REDIS_DSN", expectedConfig.redisDsn)
+		_ = os.Setenv("LISTEN", expectedConfig.listenAddress)
+		_ = os.Setenv("SEMESTER_SWITCH_POLICY", "calendar")
+		_ = os.Setenv("ACADEMIC_CALENDAR_FILE", "calendar.json")
+		defer os.Unsetenv("SEMESTER_SWITCH_POLICY")
+		defer os.Unsetenv("ACADEMIC_CALENDAR_FILE")
+
+		config, err := loadConfig("")
+
+		assert.NoError(t, err)
+		assert.Equal(t, SemesterSwitchPolicyCalendar, config.semesterSwitch)
+		assert.Equal(t, "calendar.json", config.academicCalendar)
+
+		_ = os.Setenv("SEMESTER_SWITCH_POLICY", "weekly")
+
+		_, err = loadConfig("")
+		assert.EqualError(t, err, "Wrong SEMESTER_SWITCH_POLICY: weekly")
+	})
+
+	t.Run("ChangesRetention", func(t *testing.T) {
+		_ = os.Setenv("REDIS_DSN", expectedConfig.redisDsn)
+		_ = os.Setenv("LISTEN", expectedConfig.listenAddress)
+		_ = os.Setenv("CHANGES_RETENTION_DAYS", "30")
+		defer os.Unsetenv("CHANGES_RETENTION_DAYS")
+
+		config, err := loadConfig("")
+
+		assert.NoError(t, err)
+		assert.Equal(t, time.Hour*24*30, config.changesRetention)
+
+		_ = os.Setenv("CHANGES_RETENTION_DAYS", "0")
+
+		_, err = loadConfig("")
+		assert.EqualError(t, err, "Wrong CHANGES_RETENTION_DAYS: 0")
+	})
+
+	t.Run("Kafka", func(t *testing.T) {
+		_ = os.Setenv("REDIS_DSN", expectedConfig.redisDsn)
+		_ = os.Setenv("LISTEN", expectedConfig.listenAddress)
+		_ = os.Setenv("KAFKA_HOST", "kafka:9092")
+		_ = os.Setenv("KAFKA_TOPIC", "scores")
+		defer os.Unsetenv("KAFKA_HOST")
+		defer os.Unsetenv("KAFKA_TOPIC")
+
+		config, err := loadConfig("")
+
+		assert.NoError(t, err)
+		assert.Equal(t, ScoreEventsSourceKeyspace, config.scoreEvents)
+
+		_ = os.Setenv("SCORE_EVENTS_SOURCE", ScoreEventsSourceKafka)
+		_ = os.Setenv("KAFKA_GROUP_ID", "score-storage-api-1")
+		defer os.Unsetenv("SCORE_EVENTS_SOURCE")
+		defer os.Unsetenv("KAFKA_GROUP_ID")
+
+		config, err = loadConfig("")
+
+		assert.NoError(t, err)
+		assert.Equal(t, ScoreEventsSourceKafka, config.scoreEvents)
+		assert.Equal(t, "kafka:9092", config.kafkaHost)
+		assert.Equal(t, "scores", config.kafkaTopic)
+		assert.Equal(t, "score-storage-api-1", config.kafkaGroupId)
+
+		_ = os.Setenv("KAFKA_HOST", "")
+
+		_, err = loadConfig("")
+		assert.EqualError(t, err, "empty KAFKA_HOST")
+
+		_ = os.Setenv("SCORE_EVENTS_SOURCE", "pubsub")
+
+		_, err = loadConfig("")
+		assert.EqualError(t, err, "Wrong SCORE_EVENTS_SOURCE: pubsub")
+	})
+
 	t.Run("NotExistConfigFile", func(t *testing.T) {
 		_ = os.Setenv("REDIS_DSN", "")
 		_ = os.Setenv("LISTEN", ":8080")
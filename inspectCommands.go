@@ -0,0 +1,310 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// InspectRequest - positive ids passed as positional arguments and options of the inspect command,
+// studentId is set by optional `--student` flag of discipline and lesson commands, totals are graded by every scale
+type InspectRequest struct {
+	ids       []int
+	studentId int
+	language  string
+	scales    GradeScales
+}
+
+// inspectFunc loads data of the inspect command with the same storage functions as API handlers
+// and presents it like API responses. Nil result with nil error means that requested data does not exist.
+type inspectFunc func(storage *Storage, presenter ResultPresenter, request InspectRequest) (interface{}, error)
+
+func runStudentCommand(args []string, out io.Writer, errStream io.Writer) int {
+	return runInspectCommand("student", []string{"student_id"}, false, args, out, errStream,
+		func(storage *Storage, presenter ResultPresenter, request InspectRequest) (interface{}, error) {
+			disciplineScoreResults, err := storage.getDisciplineScoreResultsByStudentId(request.ids[0])
+			if err != nil {
+				return nil, err
+			}
+
+			presenter.presentDisciplineScoreResults(request.language, disciplineScoreResults)
+			request.scales.gradeResults(disciplineScoreResults)
+			return disciplineScoreResults, nil
+		},
+	)
+}
+
+// runDisciplineCommand prints lessons of discipline, or the discipline of the student like API does with `--student`
+func runDisciplineCommand(args []string, out io.Writer, errStream io.Writer) int {
+	return runInspectCommand("discipline", []string{"discipline_id"}, true, args, out, errStream,
+		func(storage *Storage, presenter ResultPresenter, request InspectRequest) (interface{}, error) {
+			if request.studentId == 0 {
+				disciplineLessons, err := storage.getDisciplineWithLessons(request.ids[0])
+				if err != nil || disciplineLessons.Discipline.Id == 0 {
+					return nil, err
+				}
+
+				presenter.presentDisciplineLessons(request.language, &disciplineLessons)
+				return disciplineLessons, nil
+			}
+
+			disciplineScoreResult, err := storage.getDisciplineScoreResultByStudentId(request.studentId, request.ids[0])
+			if err != nil || disciplineScoreResult.Discipline.Id == 0 {
+				return nil, err
+			}
+
+			presenter.presentDisciplineScoreResult(request.language, &disciplineScoreResult)
+			disciplineScoreResult.Grades = request.scales.grades(disciplineScoreResult.ScoreRating.Total)
+			return disciplineScoreResult, nil
+		},
+	)
+}
+
+// runLessonCommand prints lesson of discipline, with scores of the student like API does with `--student`
+func runLessonCommand(args []string, out io.Writer, errStream io.Writer) int {
+	return runInspectCommand("lesson", []string{"discipline_id", "lesson_id"}, true, args, out, errStream,
+		func(storage *Storage, presenter ResultPresenter, request InspectRequest) (interface{}, error) {
+			disciplineScore, err := storage.getDisciplineScore(request.studentId, request.ids[0], request.ids[1])
+			if err != nil || disciplineScore.Discipline.Id == 0 || disciplineScore.Score.Lesson.Id == 0 {
+				return nil, err
+			}
+
+			presenter.presentDisciplineScore(request.language, &disciplineScore)
+			return disciplineScore, nil
+		},
+	)
+}
+
+func runRatingCommand(args []string, out io.Writer, errStream io.Writer) int {
+	return runInspectCommand("rating", []string{"discipline_id"}, false, args, out, errStream,
+		func(storage *Storage, _ ResultPresenter, request InspectRequest) (interface{}, error) {
+			disciplineRating, err := storage.getDisciplineRating(request.ids[0])
+			if err != nil || disciplineRating.Discipline.Id == 0 {
+				return nil, err
+			}
+
+			return disciplineRating, nil
+		},
+	)
+}
+
+// runInspectCommand parses common inspect flags and ids, loads data with Storage and prints it
+// as the same JSON as API responds or as human-readable table.
+func runInspectCommand(
+	name string, argNames []string, withStudent bool, args []string, out io.Writer, errStream io.Writer,
+	inspect inspectFunc,
+) int {
+	flagSet := newCommandFlagSet(name, errStream)
+	year := flagSet.Int("year", 0, "academic year, default is currentYear from redis")
+	format := flagSet.String("format", "json", "output format: json or table")
+	language := flagSet.String("lang", "", "language of lesson types like `lang` query parameter of API")
+	studentId := new(int)
+	if withStudent {
+		flagSet.IntVar(studentId, "student", 0, "student_id to show scores of the student")
+	}
+
+	positionalArgs, err := parseCommandArgs(flagSet, args)
+	if err != nil {
+		return ExitCodeMainError
+	}
+
+	if *format != "json" && *format != "table" {
+		return handleExitError(errStream, errors.New("Unknown format "+*format+", expected json or table"))
+	}
+
+	if *studentId < 0 {
+		return handleExitError(errStream, fmt.Errorf(messageIncorrectParameter, "student", strconv.Itoa(*studentId)))
+	}
+
+	ids, err := parseInspectIds(argNames, positionalArgs)
+	if err != nil {
+		return handleExitError(errStream, err)
+	}
+
+	redisClient, inspectYear, config, err := connectCommandRedis(*year)
+	if err != nil {
+		return handleExitError(errStream, err)
+	}
+
+	rules, err := newCommandAcademicRules(config, redisClient)
+	if err != nil {
+		return handleExitError(errStream, err)
+	}
+
+	translator, err := NewTranslator(nil, context.Background())
+	if err != nil {
+		return handleExitError(errStream, err)
+	}
+	translator.redis = redisClient
+	translator.updateCatalogue()
+
+	gradeScales, err := loadGradeScales(config.gradeScales)
+	if err != nil {
+		return handleExitError(errStream, err)
+	}
+	gradeScaler := NewGradeScaler(nil, gradeScales, context.Background())
+	gradeScaler.redis = redisClient
+	gradeScaler.updateScales()
+
+	storage := newStorage(redisClient, rules)
+	storage.updateGeneralData()
+	storage.year = inspectYear
+
+	request := InspectRequest{
+		ids:       ids,
+		studentId: *studentId,
+		language:  translator.loaded().supportedLanguage(*language),
+		scales:    *gradeScaler.scales.Load(),
+	}
+	presenter := ResultPresenter{translator: translator, dateFormat: LessonDateFormat{dateOnly: config.dateOnlyJson}}
+
+	result, err := inspect(storage, presenter, request)
+	if err == nil && result == nil {
+		err = errors.New("Not found: " + formatInspectRequest(argNames, request))
+	}
+
+	if err == nil && *format == "table" {
+		err = writeInspectTable(out, result)
+	} else if err == nil {
+		err = json.NewEncoder(out).Encode(result)
+	}
+
+	return handleExitError(errStream, err)
+}
+
+// parseCommandArgs parses flags placed anywhere between positional arguments
+func parseCommandArgs(flagSet *flag.FlagSet, args []string) ([]string, error) {
+	positionalArgs := make([]string, 0, len(args))
+	for {
+		if err := flagSet.Parse(args); err != nil {
+			return nil, err
+		}
+
+		args = flagSet.Args()
+		if len(args) == 0 {
+			return positionalArgs, nil
+		}
+
+		positionalArgs = append(positionalArgs, args[0])
+		args = args[1:]
+	}
+}
+
+func parseInspectIds(argNames []string, args []string) ([]int, error) {
+	if len(args) != len(argNames) {
+		return nil, errors.New("Expected arguments: <" + strings.Join(argNames, "> <") + ">")
+	}
+
+	ids := make([]int, len(args))
+	for index, arg := range args {
+		ids[index], _ = strconv.Atoi(arg)
+		if ids[index] <= 0 {
+			return nil, fmt.Errorf(messageIncorrectParameter, argNames[index], arg)
+		}
+	}
+
+	return ids, nil
+}
+
+func formatInspectRequest(argNames []string, request InspectRequest) string {
+	parts := make([]string, 0, len(request.ids)+1)
+	for index, id := range request.ids {
+		parts = append(parts, argNames[index]+"="+strconv.Itoa(id))
+	}
+	if request.studentId != 0 {
+		parts = append(parts, "student="+strconv.Itoa(request.studentId))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+func writeInspectTable(out io.Writer, result interface{}) error {
+	writer := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+
+	switch value := result.(type) {
+	case DisciplineScoreResults:
+		_, _ = fmt.Fprintln(writer, "DISCIPLINE\tNAME\tTOTAL\tRATING\tSTUDENTS\tATTENDANCE")
+		for _, disciplineScoreResult := range value {
+			_, _ = fmt.Fprintf(
+				writer, "%d\t%s\t%s\t%d\t%d\t%s\n",
+				disciplineScoreResult.Discipline.Id,
+				disciplineScoreResult.Discipline.Name,
+				formatInspectScore(&disciplineScoreResult.ScoreRating.Total),
+				disciplineScoreResult.ScoreRating.Rating,
+				disciplineScoreResult.ScoreRating.StudentsCount,
+				formatInspectAttendance(disciplineScoreResult.Attendance),
+			)
+		}
+
+	case DisciplineScoreResult:
+		_, _ = fmt.Fprintf(
+			writer, "Discipline %d %s: total %s, rating %d of %d, attendance %s\n",
+			value.Discipline.Id, value.Discipline.Name, formatInspectScore(&value.ScoreRating.Total),
+			value.ScoreRating.Rating, value.ScoreRating.StudentsCount, formatInspectAttendance(value.Attendance),
+		)
+		writeInspectScores(writer, value.Scores)
+
+	case DisciplineScore:
+		_, _ = fmt.Fprintf(writer, "Discipline %d %s\n", value.Discipline.Id, value.Discipline.Name)
+		writeInspectScores(writer, []Score{value.Score})
+
+	case DisciplineLessons:
+		_, _ = fmt.Fprintf(
+			writer, "Discipline %d %s: year %d, semester %d\n",
+			value.Discipline.Id, value.Discipline.Name, value.Year, value.Semester,
+		)
+		writeInspectScores(writer, value.Lessons)
+
+	case DisciplineRating:
+		_, _ = fmt.Fprintf(
+			writer, "Discipline %d %s: year %d, semester %d\n",
+			value.Discipline.Id, value.Discipline.Name, value.Year, value.Semester,
+		)
+		_, _ = fmt.Fprintln(writer, "RATING\tSTUDENT\tTOTAL")
+		for _, studentTotal := range value.Students {
+			_, _ = fmt.Fprintf(
+				writer, "%d\t%d\t%s\n",
+				studentTotal.Rating, studentTotal.StudentId, formatInspectScore(&studentTotal.Total),
+			)
+		}
+
+	default:
+		return fmt.Errorf("Unsupported table output of %T", result)
+	}
+
+	return writer.Flush()
+}
+
+func writeInspectScores(writer io.Writer, scores []Score) {
+	_, _ = fmt.Fprintln(writer, "LESSON\tDATE\tTYPE\tFIRST\tSECOND\tABSENT\tDELETED")
+	for _, score := range scores {
+		_, _ = fmt.Fprintf(
+			writer, "%d\t%s\t%s\t%s\t%s\t%t\t%t\n",
+			score.Lesson.Id,
+			score.Lesson.Date.Format(dateOnlyLayout),
+			score.Lesson.Type.ShortName,
+			formatInspectScore(score.FirstScore),
+			formatInspectScore(score.SecondScore),
+			score.IsAbsent,
+			score.Deleted,
+		)
+	}
+}
+
+func formatInspectScore(score *float32) string {
+	if score == nil {
+		return "-"
+	}
+
+	return strconv.FormatFloat(float64(*score), 'f', -1, 32)
+}
+
+func formatInspectAttendance(attendance Attendance) string {
+	return fmt.Sprintf("%d/%d", attendance.Attended, attendance.TotalLessons)
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"bytes"
+	scoreApi "github.com/kneu-messenger-pigeon/score-api"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestParseCommandArgs(t *testing.T) {
+	t.Run("interspersed", func(t *testing.T) {
+		flagSet := newCommandFlagSet("test", &bytes.Buffer{})
+		format := flagSet.String("format", "json", "")
+		year := flagSet.Int("year", 0, "")
+
+		args, err := parseCommandArgs(flagSet, []string{"23", "--format", "table", "199", "--year=2026"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"23", "199"}, args)
+		assert.Equal(t, "table", *format)
+		assert.Equal(t, 2026, *year)
+	})
+
+	t.Run("wrong_flag", func(t *testing.T) {
+		errStream := &bytes.Buffer{}
+		flagSet := newCommandFlagSet("test", errStream)
+
+		_, err := parseCommandArgs(flagSet, []string{"23", "--unknown"})
+
+		assert.Error(t, err)
+		assert.Contains(t, errStream.String(), "flag provided but not defined: -unknown")
+	})
+}
+
+func TestParseInspectIds(t *testing.T) {
+	argNames := []string{"student_id", "discipline_id"}
+
+	ids, err := parseInspectIds(argNames, []string{"23", "199"})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{23, 199}, ids)
+
+	_, err = parseInspectIds(argNames, []string{"23"})
+	assert.EqualError(t, err, "Expected arguments: <student_id> <discipline_id>")
+
+	_, err = parseInspectIds(argNames, []string{"23", "abc"})
+	assert.EqualError(t, err, "Incorrect discipline_id: abc")
+
+	assert.Equal(
+		t, "discipline_id=199 lesson_id=245 student=23",
+		formatInspectRequest([]string{"discipline_id", "lesson_id"}, InspectRequest{ids: []int{199, 245}, studentId: 23}),
+	)
+	assert.Equal(t, "discipline_id=199", formatInspectRequest([]string{"discipline_id"}, InspectRequest{ids: []int{199}}))
+}
+
+func TestWriteInspectTable(t *testing.T) {
+	lessonTypes := GetTestLessonTypes()
+	score := Score{
+		Score: scoreApi.Score{
+			Lesson: scoreApi.Lesson{
+				Id:   245,
+				Date: time.Date(2023, time.Month(2), 12, 0, 0, 0, 0, defaultAcademicLocation),
+				Type: lessonTypes[1],
+			},
+			FirstScore: floatPointer(4.5),
+		},
+	}
+	discipline := scoreApi.Discipline{Id: 199, Name: "Капітал!"}
+	scoreRating := ScoreRating{ScoreRating: scoreApi.ScoreRating{Total: 17, StudentsCount: 25, Rating: 8}}
+	attendance := Attendance{TotalLessons: 3, Attended: 2, Absent: 1}
+
+	t.Run("student", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		err := writeInspectTable(out, DisciplineScoreResults{
+			{Discipline: discipline, ScoreRating: scoreRating, Attendance: attendance},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, ""+
+			"DISCIPLINE  NAME      TOTAL  RATING  STUDENTS  ATTENDANCE\n"+
+			"199         Капітал!  17     8       25        2/3\n",
+			out.String(),
+		)
+	})
+
+	t.Run("discipline", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		err := writeInspectTable(out, DisciplineScoreResult{
+			Discipline: discipline, ScoreRating: scoreRating, Attendance: attendance, Scores: []Score{score},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, ""+
+			"Discipline 199 Капітал!: total 17, rating 8 of 25, attendance 2/3\n"+
+			"LESSON  DATE        TYPE  FIRST  SECOND  ABSENT  DELETED\n"+
+			"245     2023-02-12  "+lessonTypes[1].ShortName+"  4.5    -       false   false\n",
+			out.String(),
+		)
+	})
+
+	t.Run("lesson", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		err := writeInspectTable(out, DisciplineScore{Discipline: discipline, Score: score})
+
+		assert.NoError(t, err)
+		assert.Contains(t, out.String(), "Discipline 199 Капітал!\nLESSON")
+		assert.Contains(t, out.String(), "4.5")
+	})
+
+	t.Run("discipline_lessons", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		err := writeInspectTable(out, DisciplineLessons{
+			Discipline: discipline, Year: 2026, Semester: 1, Lessons: []Score{{Score: scoreApi.Score{Lesson: score.Lesson}}},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, ""+
+			"Discipline 199 Капітал!: year 2026, semester 1\n"+
+			"LESSON  DATE        TYPE  FIRST  SECOND  ABSENT  DELETED\n"+
+			"245     2023-02-12  "+lessonTypes[1].ShortName+"  -      -       false   false\n",
+			out.String(),
+		)
+	})
+
+	t.Run("rating", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		err := writeInspectTable(out, DisciplineRating{
+			Discipline: discipline,
+			Year:       2026,
+			Semester:   1,
+			Students: []StudentTotal{
+				{StudentId: 1200, Total: 7.5, Rating: 1},
+				{StudentId: 1300, Total: 0, Rating: 2},
+			},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, ""+
+			"Discipline 199 Капітал!: year 2026, semester 1\n"+
+			"RATING  STUDENT  TOTAL\n"+
+			"1       1200     7.5\n"+
+			"2       1300     0\n",
+			out.String(),
+		)
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		assert.EqualError(t, writeInspectTable(&bytes.Buffer{}, "text"), "Unsupported table output of string")
+	})
+}
+
+func TestRunInspectCommands(t *testing.T) {
+	t.Run("wrong_format", func(t *testing.T) {
+		errStream := &bytes.Buffer{}
+
+		assert.Equal(t, ExitCodeMainError, runStudentCommand([]string{"23", "--format", "xml"}, &bytes.Buffer{}, errStream))
+		assert.Contains(t, errStream.String(), "Unknown format xml")
+	})
+
+	t.Run("wrong_arguments", func(t *testing.T) {
+		errStream := &bytes.Buffer{}
+
+		assert.Equal(t, ExitCodeMainError, runLessonCommand([]string{"199"}, &bytes.Buffer{}, errStream))
+		assert.Contains(t, errStream.String(), "Expected arguments: <discipline_id> <lesson_id>")
+	})
+
+	t.Run("wrong_student", func(t *testing.T) {
+		errStream := &bytes.Buffer{}
+
+		assert.Equal(
+			t, ExitCodeMainError, runDisciplineCommand([]string{"199", "--student", "-23"}, &bytes.Buffer{}, errStream),
+		)
+		assert.Contains(t, errStream.String(), "Incorrect student: -23")
+	})
+
+	t.Run("student_flag_of_student_command", func(t *testing.T) {
+		errStream := &bytes.Buffer{}
+
+		assert.Equal(
+			t, ExitCodeMainError, runStudentCommand([]string{"23", "--student", "24"}, &bytes.Buffer{}, errStream),
+		)
+		assert.Contains(t, errStream.String(), "flag provided but not defined: -student")
+	})
+
+	t.Run("wrong_id", func(t *testing.T) {
+		errStream := &bytes.Buffer{}
+
+		assert.Equal(t, ExitCodeMainError, runRatingCommand([]string{"abc"}, &bytes.Buffer{}, errStream))
+		assert.Contains(t, errStream.String(), "Incorrect discipline_id: abc")
+	})
+
+	t.Run("empty_config", func(t *testing.T) {
+		_ = os.Setenv("REDIS_DSN", "")
+		errStream := &bytes.Buffer{}
+
+		assert.Equal(t, ExitCodeMainError, runDisciplineCommand([]string{"199", "--student", "23"}, &bytes.Buffer{}, errStream))
+		assert.Contains(t, errStream.String(), "empty REDIS_DSN")
+	})
+}
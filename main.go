@@ -8,5 +8,5 @@ import (
 )
 
 func main() {
-	os.Exit(handleExitError(os.Stderr, runApp(os.Stdout, http.ListenAndServe)))
+	os.Exit(runCommand(os.Args[1:], os.Stdout, os.Stderr, http.ListenAndServe))
 }
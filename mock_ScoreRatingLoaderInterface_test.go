@@ -3,7 +3,6 @@
 package main
 
 import (
-	scoreApi "github.com/kneu-messenger-pigeon/score-api"
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -13,14 +12,14 @@ type MockScoreRatingLoaderInterface struct {
 }
 
 // load provides a mock function with given fields: year, semester, disciplineId, studentId
-func (_m *MockScoreRatingLoaderInterface) load(year int, semester int, disciplineId int, studentId int) scoreApi.ScoreRating {
+func (_m *MockScoreRatingLoaderInterface) load(year int, semester int, disciplineId int, studentId int) ScoreRating {
 	ret := _m.Called(year, semester, disciplineId, studentId)
 
-	var r0 scoreApi.ScoreRating
-	if rf, ok := ret.Get(0).(func(int, int, int, int) scoreApi.ScoreRating); ok {
+	var r0 ScoreRating
+	if rf, ok := ret.Get(0).(func(int, int, int, int) ScoreRating); ok {
 		r0 = rf(year, semester, disciplineId, studentId)
 	} else {
-		r0 = ret.Get(0).(scoreApi.ScoreRating)
+		r0 = ret.Get(0).(ScoreRating)
 	}
 
 	return r0
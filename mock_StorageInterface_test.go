@@ -3,7 +3,6 @@
 package main
 
 import (
-	scoreApi "github.com/kneu-messenger-pigeon/score-api"
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -12,19 +11,57 @@ type MockStorageInterface struct {
 	mock.Mock
 }
 
+// getAnomalies provides a mock function with given fields:
+func (_m *MockStorageInterface) getAnomalies() AnomalyReport {
+	ret := _m.Called()
+
+	var r0 AnomalyReport
+	if rf, ok := ret.Get(0).(func() AnomalyReport); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(AnomalyReport)
+	}
+
+	return r0
+}
+
+// getDisciplineDeletedLessons provides a mock function with given fields: studentId, disciplineId
+func (_m *MockStorageInterface) getDisciplineDeletedLessons(studentId int, disciplineId int) (DisciplineDeletedLessons, error) {
+	ret := _m.Called(studentId, disciplineId)
+
+	var r0 DisciplineDeletedLessons
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, int) (DisciplineDeletedLessons, error)); ok {
+		return rf(studentId, disciplineId)
+	}
+	if rf, ok := ret.Get(0).(func(int, int) DisciplineDeletedLessons); ok {
+		r0 = rf(studentId, disciplineId)
+	} else {
+		r0 = ret.Get(0).(DisciplineDeletedLessons)
+	}
+
+	if rf, ok := ret.Get(1).(func(int, int) error); ok {
+		r1 = rf(studentId, disciplineId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // getDisciplineScore provides a mock function with given fields: studentId, disciplineId, lessonId
-func (_m *MockStorageInterface) getDisciplineScore(studentId int, disciplineId int, lessonId int) (scoreApi.DisciplineScore, error) {
+func (_m *MockStorageInterface) getDisciplineScore(studentId int, disciplineId int, lessonId int) (DisciplineScore, error) {
 	ret := _m.Called(studentId, disciplineId, lessonId)
 
-	var r0 scoreApi.DisciplineScore
+	var r0 DisciplineScore
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int, int, int) (scoreApi.DisciplineScore, error)); ok {
+	if rf, ok := ret.Get(0).(func(int, int, int) (DisciplineScore, error)); ok {
 		return rf(studentId, disciplineId, lessonId)
 	}
-	if rf, ok := ret.Get(0).(func(int, int, int) scoreApi.DisciplineScore); ok {
+	if rf, ok := ret.Get(0).(func(int, int, int) DisciplineScore); ok {
 		r0 = rf(studentId, disciplineId, lessonId)
 	} else {
-		r0 = ret.Get(0).(scoreApi.DisciplineScore)
+		r0 = ret.Get(0).(DisciplineScore)
 	}
 
 	if rf, ok := ret.Get(1).(func(int, int, int) error); ok {
@@ -36,19 +73,43 @@ func (_m *MockStorageInterface) getDisciplineScore(studentId int, disciplineId i
 	return r0, r1
 }
 
+// getDisciplineTimeline provides a mock function with given fields: studentId, disciplineId
+func (_m *MockStorageInterface) getDisciplineTimeline(studentId int, disciplineId int) (DisciplineTimeline, error) {
+	ret := _m.Called(studentId, disciplineId)
+
+	var r0 DisciplineTimeline
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, int) (DisciplineTimeline, error)); ok {
+		return rf(studentId, disciplineId)
+	}
+	if rf, ok := ret.Get(0).(func(int, int) DisciplineTimeline); ok {
+		r0 = rf(studentId, disciplineId)
+	} else {
+		r0 = ret.Get(0).(DisciplineTimeline)
+	}
+
+	if rf, ok := ret.Get(1).(func(int, int) error); ok {
+		r1 = rf(studentId, disciplineId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // getDisciplineScoreResultByStudentId provides a mock function with given fields: studentId, disciplineId
-func (_m *MockStorageInterface) getDisciplineScoreResultByStudentId(studentId int, disciplineId int) (scoreApi.DisciplineScoreResult, error) {
+func (_m *MockStorageInterface) getDisciplineScoreResultByStudentId(studentId int, disciplineId int) (DisciplineScoreResult, error) {
 	ret := _m.Called(studentId, disciplineId)
 
-	var r0 scoreApi.DisciplineScoreResult
+	var r0 DisciplineScoreResult
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int, int) (scoreApi.DisciplineScoreResult, error)); ok {
+	if rf, ok := ret.Get(0).(func(int, int) (DisciplineScoreResult, error)); ok {
 		return rf(studentId, disciplineId)
 	}
-	if rf, ok := ret.Get(0).(func(int, int) scoreApi.DisciplineScoreResult); ok {
+	if rf, ok := ret.Get(0).(func(int, int) DisciplineScoreResult); ok {
 		r0 = rf(studentId, disciplineId)
 	} else {
-		r0 = ret.Get(0).(scoreApi.DisciplineScoreResult)
+		r0 = ret.Get(0).(DisciplineScoreResult)
 	}
 
 	if rf, ok := ret.Get(1).(func(int, int) error); ok {
@@ -61,19 +122,69 @@ func (_m *MockStorageInterface) getDisciplineScoreResultByStudentId(studentId in
 }
 
 // getDisciplineScoreResultsByStudentId provides a mock function with given fields: studentId
-func (_m *MockStorageInterface) getDisciplineScoreResultsByStudentId(studentId int) (scoreApi.DisciplineScoreResults, error) {
+func (_m *MockStorageInterface) getDisciplineScoreResultsByStudentId(studentId int) (DisciplineScoreResults, error) {
+	ret := _m.Called(studentId)
+
+	var r0 DisciplineScoreResults
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int) (DisciplineScoreResults, error)); ok {
+		return rf(studentId)
+	}
+	if rf, ok := ret.Get(0).(func(int) DisciplineScoreResults); ok {
+		r0 = rf(studentId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(DisciplineScoreResults)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(studentId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// getStudentAttendance provides a mock function with given fields: studentId
+func (_m *MockStorageInterface) getStudentAttendance(studentId int) (StudentAttendance, error) {
+	ret := _m.Called(studentId)
+
+	var r0 StudentAttendance
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int) (StudentAttendance, error)); ok {
+		return rf(studentId)
+	}
+	if rf, ok := ret.Get(0).(func(int) StudentAttendance); ok {
+		r0 = rf(studentId)
+	} else {
+		r0 = ret.Get(0).(StudentAttendance)
+	}
+
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(studentId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// getStudentLessons provides a mock function with given fields: studentId
+func (_m *MockStorageInterface) getStudentLessons(studentId int) ([]DisciplineLessons, error) {
 	ret := _m.Called(studentId)
 
-	var r0 scoreApi.DisciplineScoreResults
+	var r0 []DisciplineLessons
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int) (scoreApi.DisciplineScoreResults, error)); ok {
+	if rf, ok := ret.Get(0).(func(int) ([]DisciplineLessons, error)); ok {
 		return rf(studentId)
 	}
-	if rf, ok := ret.Get(0).(func(int) scoreApi.DisciplineScoreResults); ok {
+	if rf, ok := ret.Get(0).(func(int) []DisciplineLessons); ok {
 		r0 = rf(studentId)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(scoreApi.DisciplineScoreResults)
+			r0 = ret.Get(0).([]DisciplineLessons)
 		}
 	}
 
@@ -86,6 +197,78 @@ func (_m *MockStorageInterface) getDisciplineScoreResultsByStudentId(studentId i
 	return r0, r1
 }
 
+// getStudentProfile provides a mock function with given fields: studentId
+func (_m *MockStorageInterface) getStudentProfile(studentId int) (StudentProfile, error) {
+	ret := _m.Called(studentId)
+
+	var r0 StudentProfile
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int) (StudentProfile, error)); ok {
+		return rf(studentId)
+	}
+	if rf, ok := ret.Get(0).(func(int) StudentProfile); ok {
+		r0 = rf(studentId)
+	} else {
+		r0 = ret.Get(0).(StudentProfile)
+	}
+
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(studentId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// studentExists provides a mock function with given fields: studentId
+func (_m *MockStorageInterface) studentExists(studentId int) (bool, error) {
+	ret := _m.Called(studentId)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int) (bool, error)); ok {
+		return rf(studentId)
+	}
+	if rf, ok := ret.Get(0).(func(int) bool); ok {
+		r0 = rf(studentId)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(studentId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// getStudentTranscript provides a mock function with given fields: studentId
+func (_m *MockStorageInterface) getStudentTranscript(studentId int) (Transcript, error) {
+	ret := _m.Called(studentId)
+
+	var r0 Transcript
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int) (Transcript, error)); ok {
+		return rf(studentId)
+	}
+	if rf, ok := ret.Get(0).(func(int) Transcript); ok {
+		r0 = rf(studentId)
+	} else {
+		r0 = ret.Get(0).(Transcript)
+	}
+
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(studentId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 type mockConstructorTestingTNewMockStorageInterface interface {
 	mock.TestingT
 	Cleanup(func())
@@ -1,21 +1,75 @@
 package main
 
 import (
+	"crypto/subtle"
 	"github.com/gin-gonic/gin"
+	scoreApi "github.com/kneu-messenger-pigeon/score-api"
 	"io"
 	"net/http"
+	"time"
 )
 
-func setupRouter(out io.Writer, storage StorageInterface) *gin.Engine {
+// RouterDependencies - components of API handlers, routes of unset optional components are not registered
+type RouterDependencies struct {
+	out              io.Writer
+	storage          StorageInterface
+	groupRatings     GroupRatingLoaderInterface
+	summaries        StudentSummaryLoaderInterface
+	risks            DisciplineRiskLoaderInterface
+	changes          StudentChangesLoaderInterface
+	translator       *Translator
+	gradeScaler      *GradeScaler
+	eventHub         *StudentEventHub
+	adminToken       string
+	location         *time.Location
+	dateOnlyJson     bool
+	changesRetention time.Duration
+}
+
+func setupRouter(dependencies RouterDependencies) *gin.Engine {
+	eventHub := dependencies.eventHub
 	apiController := &ApiController{
-		out:     out,
-		storage: storage,
+		out:              dependencies.out,
+		storage:          dependencies.storage,
+		groupRatings:     dependencies.groupRatings,
+		summaries:        dependencies.summaries,
+		risks:            dependencies.risks,
+		changes:          dependencies.changes,
+		translator:       dependencies.translator,
+		gradeScaler:      dependencies.gradeScaler,
+		eventHub:         eventHub,
+		location:         dependencies.location,
+		dateFormat:       LessonDateFormat{dateOnly: dependencies.dateOnlyJson},
+		changesRetention: dependencies.changesRetention,
 	}
 
 	r := gin.New()
-	r.GET("/v1/students/:student_id/disciplines", apiController.getStudentDisciplines)
-	r.GET("/v1/students/:student_id/disciplines/:discipline_id", apiController.getStudentDiscipline)
-	r.GET("/v1/students/:student_id/disciplines/:discipline_id/scores/:lesson_id", apiController.getStudentDisciplineScore)
+	r.GET("/v1/students/:student_id", apiController.getStudentProfile)
+
+	student := r.Group("/v1/students/:student_id", apiController.requireKnownStudent)
+	student.GET("/disciplines", apiController.getStudentDisciplines)
+	student.GET("/disciplines/:discipline_id", apiController.getStudentDiscipline)
+	student.GET("/disciplines/:discipline_id/scores/:lesson_id", apiController.getStudentDisciplineScore)
+	student.GET("/disciplines/:discipline_id/timeline", apiController.getStudentDisciplineTimeline)
+	student.GET("/disciplines/:discipline_id/deleted-lessons", apiController.getStudentDisciplineDeletedLessons)
+	student.GET("/disciplines/:discipline_id/risk", apiController.getStudentDisciplineRisk)
+	student.GET("/summary", apiController.getStudentSummary)
+	student.GET("/attendance", apiController.getStudentAttendance)
+	student.GET("/changes", apiController.getStudentChanges)
+	student.GET("/export/csv", apiController.exportStudentTranscriptCsv)
+	student.GET("/export/xlsx", apiController.exportStudentTranscriptXlsx)
+	student.GET("/calendar.ics", apiController.getStudentCalendar)
+	if eventHub != nil {
+		student.GET("/events", apiController.getStudentEvents)
+	}
+
+	r.GET("/v1/disciplines/:discipline_id/at-risk", apiController.getDisciplineAtRiskStudents)
+	r.GET("/v1/groups/:group_id/disciplines/:discipline_id/leaderboard", apiController.getGroupLeaderboard)
+
+	if dependencies.adminToken != "" {
+		admin := r.Group("/admin", requireAdminToken(dependencies.adminToken))
+		admin.GET("/anomalies", apiController.getAnomalies)
+	}
 
 	r.GET("/healthcheck", func(c *gin.Context) {
 		c.String(http.StatusOK, "health")
@@ -23,3 +77,17 @@ func setupRouter(out io.Writer, storage StorageInterface) *gin.Engine {
 
 	return r
 }
+
+// requireAdminToken allows requests with `Authorization: Bearer {token}` header only,
+// admin routes expose raw Redis keys and values
+func requireAdminToken(token string) gin.HandlerFunc {
+	expected := []byte("Bearer " + token)
+
+	return func(c *gin.Context) {
+		if subtle.ConstantTimeCompare([]byte(c.GetHeader("Authorization")), expected) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, scoreApi.ErrorResponse{
+				Error: http.StatusText(http.StatusUnauthorized),
+			})
+		}
+	}
+}
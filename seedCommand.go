@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"time"
+)
+
+// runSeedCommand writes generated academic year into Redis, existing keys with the same names are replaced
+func runSeedCommand(args []string, out io.Writer, errStream io.Writer) int {
+	now := time.Now()
+	defaultYear := now.Year()
+	if now.Month() < time.September {
+		defaultYear--
+	}
+
+	options := SeedOptions{}
+	flagSet := newCommandFlagSet("seed", errStream)
+	flagSet.IntVar(&options.Year, "year", defaultYear, "academic year, it starts in September")
+	flagSet.IntVar(&options.Students, "students", 300, "amount of students")
+	flagSet.IntVar(&options.Groups, "groups", 12, "amount of groups, students are split between them")
+	flagSet.IntVar(&options.Disciplines, "disciplines", 6, "disciplines per group in each semester")
+	flagSet.IntVar(&options.Lessons, "lessons", 16, "weekly lessons per discipline")
+	flagSet.Float64Var(&options.ScoreMean, "score-mean", 3.5, "mean of normally distributed scores")
+	flagSet.Float64Var(&options.ScoreStdDev, "score-stddev", 1, "standard deviation of scores")
+	flagSet.Float64Var(&options.MaxScore, "max-score", 5, "max score of one lesson")
+	flagSet.Float64Var(&options.ScoreRate, "score-rate", 0.4, "probability of score for attended lesson")
+	flagSet.Float64Var(&options.AbsenceRate, "absence-rate", 0.1, "probability of absence")
+	flagSet.Float64Var(&options.DeletedRate, "deleted-rate", 0.02, "probability of lesson to be deleted")
+	randomSeed := flagSet.Int64("random-seed", now.UnixNano(), "random seed to repeat the same data")
+	db := flagSet.Int("db", -1, "redis database number, default is database of REDIS_DSN")
+
+	if err := flagSet.Parse(args); err != nil {
+		return ExitCodeMainError
+	}
+
+	// options are checked before connecting, lesson dates of generated data depend on configured timezone
+	if err := options.validate(); err != nil {
+		return handleExitError(errStream, err)
+	}
+
+	redisClient, config, err := newCommandRedisClient(*db)
+	if err != nil {
+		return handleExitError(errStream, err)
+	}
+
+	generator := SeedGenerator{
+		options: options,
+		random:  rand.New(rand.NewSource(*randomSeed)),
+		now:     now,
+		codec:   RedisCodec{location: config.timezone},
+	}
+
+	entries, err := generator.generate()
+	if err != nil {
+		return handleExitError(errStream, err)
+	}
+
+	importer := SnapshotImporter{
+		redis: redisClient,
+	}
+
+	for start := 0; start < len(entries) && err == nil; start += snapshotBatchSize {
+		err = importer.writeEntries(context.Background(), entries[start:min(start+snapshotBatchSize, len(entries))])
+	}
+
+	if err == nil {
+		_, _ = fmt.Fprintf(out, "Seeded %d keys of year %d with random seed %d\n", len(entries), options.Year, *randomSeed)
+	}
+
+	return handleExitError(errStream, err)
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+func TestRunSeedCommand(t *testing.T) {
+	t.Run("wrong_options", func(t *testing.T) {
+		errStream := &bytes.Buffer{}
+
+		assert.Equal(t, ExitCodeMainError, runSeedCommand([]string{"--students=0"}, &bytes.Buffer{}, errStream))
+		assert.Contains(t, errStream.String(), "should be positive")
+	})
+
+	t.Run("empty_config", func(t *testing.T) {
+		_ = os.Setenv("REDIS_DSN", "")
+		errStream := &bytes.Buffer{}
+
+		assert.Equal(t, ExitCodeMainError, runSeedCommand([]string{"--random-seed=1"}, &bytes.Buffer{}, errStream))
+		assert.Contains(t, errStream.String(), "empty REDIS_DSN")
+	})
+}
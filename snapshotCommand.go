@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+)
+
+// runSnapshotCommand dispatches `snapshot export` and `snapshot import` subcommands
+func runSnapshotCommand(args []string, out io.Writer, errStream io.Writer) int {
+	if len(args) != 0 && args[0] == "export" {
+		return runSnapshotExportCommand(args[1:], out, errStream)
+	}
+
+	if len(args) != 0 && args[0] == "import" {
+		return runSnapshotImportCommand(args[1:], out, errStream)
+	}
+
+	return handleExitError(errStream, errors.New("Expected snapshot subcommand: export or import"))
+}
+
+// runSnapshotExportCommand writes snapshot of the year into --output file, "-" writes to out
+func runSnapshotExportCommand(args []string, out io.Writer, errStream io.Writer) int {
+	flagSet := newCommandFlagSet("snapshot export", errStream)
+	year := flagSet.Int("year", 0, "year to export, default is currentYear from redis")
+	output := flagSet.String("output", "", "snapshot file, default is snapshot-<year>.ndjson.gz, - for stdout")
+
+	if err := flagSet.Parse(args); err != nil {
+		return ExitCodeMainError
+	}
+
+	redisClient, exportYear, _, err := connectCommandRedis(*year)
+	if err != nil {
+		return handleExitError(errStream, err)
+	}
+
+	if *output == "" {
+		*output = "snapshot-" + strconv.Itoa(exportYear) + ".ndjson.gz"
+	}
+
+	writer := out
+	if *output != "-" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return handleExitError(errStream, err)
+		}
+		defer file.Close()
+		writer = file
+	}
+
+	exporter := SnapshotExporter{
+		redis: redisClient,
+		year:  exportYear,
+	}
+
+	count, err := exporter.export(context.Background(), writer, time.Now())
+	if err == nil && *output != "-" {
+		_, _ = fmt.Fprintf(errStream, "Exported %d keys of year %d to %s\n", count, exportYear, *output)
+	}
+
+	return handleExitError(errStream, err)
+}
+
+// runSnapshotImportCommand restores snapshot file passed as argument, "-" reads snapshot from stdin
+func runSnapshotImportCommand(args []string, out io.Writer, errStream io.Writer) int {
+	flagSet := newCommandFlagSet("snapshot import", errStream)
+	db := flagSet.Int("db", -1, "redis database number, default is database of REDIS_DSN")
+	prefix := flagSet.String("prefix", "", "prefix prepended to every imported key")
+
+	positionalArgs, err := parseCommandArgs(flagSet, args)
+	if err != nil {
+		return ExitCodeMainError
+	}
+
+	if len(positionalArgs) != 1 {
+		return handleExitError(errStream, errors.New("Expected arguments: <snapshot_file>"))
+	}
+
+	reader := io.Reader(os.Stdin)
+	if positionalArgs[0] != "-" {
+		file, err := os.Open(positionalArgs[0])
+		if err != nil {
+			return handleExitError(errStream, err)
+		}
+		defer file.Close()
+		reader = file
+	}
+
+	redisClient, _, err := newCommandRedisClient(*db)
+	if err != nil {
+		return handleExitError(errStream, err)
+	}
+
+	importer := SnapshotImporter{
+		redis:  redisClient,
+		prefix: *prefix,
+	}
+
+	header, count, err := importer.importSnapshot(context.Background(), reader)
+	if err == nil {
+		_, _ = fmt.Fprintf(out, "Imported %d keys of year %d\n", count, header.Year)
+	}
+
+	return handleExitError(errStream, err)
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunSnapshotCommand(t *testing.T) {
+	t.Run("unknown_subcommand", func(t *testing.T) {
+		errStream := &bytes.Buffer{}
+
+		assert.Equal(t, ExitCodeMainError, runSnapshotCommand([]string{"restore"}, &bytes.Buffer{}, errStream))
+		assert.Contains(t, errStream.String(), "Expected snapshot subcommand: export or import")
+	})
+
+	t.Run("export_empty_config", func(t *testing.T) {
+		_ = os.Setenv("REDIS_DSN", "")
+		errStream := &bytes.Buffer{}
+
+		assert.Equal(t, ExitCodeMainError, runSnapshotCommand([]string{"export", "--year=2026"}, &bytes.Buffer{}, errStream))
+		assert.Contains(t, errStream.String(), "empty REDIS_DSN")
+	})
+
+	t.Run("import_without_file", func(t *testing.T) {
+		errStream := &bytes.Buffer{}
+
+		assert.Equal(t, ExitCodeMainError, runSnapshotCommand([]string{"import", "--db", "3"}, &bytes.Buffer{}, errStream))
+		assert.Contains(t, errStream.String(), "Expected arguments: <snapshot_file>")
+	})
+
+	t.Run("import_missing_file", func(t *testing.T) {
+		errStream := &bytes.Buffer{}
+		filename := filepath.Join(t.TempDir(), "missing.ndjson.gz")
+
+		assert.Equal(t, ExitCodeMainError, runSnapshotCommand([]string{"import", filename}, &bytes.Buffer{}, errStream))
+		assert.Contains(t, errStream.String(), "no such file or directory")
+	})
+
+	t.Run("import_empty_config", func(t *testing.T) {
+		_ = os.Setenv("REDIS_DSN", "")
+		errStream := &bytes.Buffer{}
+		filename := filepath.Join(t.TempDir(), "snapshot.ndjson.gz")
+		_ = os.WriteFile(filename, gzipSnapshot(`{"version":1,"year":2026}`+"\n").Bytes(), 0o600)
+
+		assert.Equal(t, ExitCodeMainError, runSnapshotCommand([]string{"import", filename, "--prefix=restored:"}, &bytes.Buffer{}, errStream))
+		assert.Contains(t, errStream.String(), "empty REDIS_DSN")
+	})
+}